@@ -2,6 +2,10 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"net/http"
+	"time"
+	_ "time/tzdata" // time zones of study timer schedules, the docker image has no zoneinfo
 
 	"github.com/coneno/logger"
 	"github.com/influenzanet/study-service/internal/config"
@@ -13,6 +17,9 @@ import (
 	"github.com/influenzanet/study-service/pkg/types"
 )
 
+// how long to wait for running timer events on shutdown
+const timerShutdownTimeout = 30 * time.Second
+
 func main() {
 	conf := config.InitConfig()
 
@@ -26,6 +33,10 @@ func main() {
 	sTimerService := studytimer.NewStudyTimerService(conf.Study, studyDBService, globalDBService, conf.ExternalServices, conf.Study.GlobalSecret)
 	sTimerService.Run()
 
+	if conf.MetricsPort != "" {
+		go serveMetrics(conf.MetricsPort)
+	}
+
 	clients := &types.APIClients{}
 
 	loggingClient, close := gc.ConnectToLoggingService(conf.ServiceURLs.LoggingService)
@@ -43,6 +54,11 @@ func main() {
 		conf.MaxMsgSize,
 		conf.PersistentStoreConfig,
 		conf.ExternalServices,
+		func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timerShutdownTimeout)
+			defer cancel()
+			sTimerService.Stop(ctx)
+		},
 	); err != nil {
 		logger.Error.Fatal(err)
 	}
@@ -59,7 +75,24 @@ func ensureDBIndexes(gdb *globaldb.GlobalDBService, sdb *studydb.StudyDBService)
 		sdb.CreateMessageScheduledForIndexForAllStudies(i.InstanceID)
 		sdb.CreateParticipantIDIndexForAllStudies(i.InstanceID)
 		sdb.CreateUploadedAtIndexForStudyRulesCollection(i.InstanceID)
+		sdb.CreateParticipantEventsIndexForAllStudies(i.InstanceID)
+		sdb.CreateScheduledEventsIndexForAllStudies(i.InstanceID)
+		sdb.CreateFlagExpiryIndexForAllStudies(i.InstanceID)
+		sdb.CreateRandomisationIndexesForAllStudies(i.InstanceID)
+		sdb.CreateArrivedAtIndexForAllStudies(i.InstanceID)
+		if err := sdb.CreateTimerLeaseIndex(i.InstanceID); err != nil {
+			logger.Error.Printf("unexpected error when creating timer lease index: %v", err)
+		}
 		// TODO: ensure other indexes as well
 	}
 
 }
+
+// serveMetrics publishes the expvar variables, e.g. participant_state_conflicts, at /debug/vars
+func serveMetrics(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	if err := http.ListenAndServe(":"+port, mux); err != nil {
+		logger.Error.Printf("metrics server stopped: %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 // Config is the structure that holds all global configuration data
 type Config struct {
 	Port           string
+	MetricsPort    string // if set, metrics are served with expvar at /debug/vars
 	LogLevel       logger.LogLevel
 	StudyDBConfig  types.DBConfig
 	GlobalDBConfig types.DBConfig
@@ -29,6 +30,7 @@ type Config struct {
 func InitConfig() Config {
 	conf := Config{}
 	conf.Port = os.Getenv("STUDY_SERVICE_LISTEN_PORT")
+	conf.MetricsPort = os.Getenv(ENV_METRICS_LISTEN_PORT)
 
 	conf.MaxMsgSize = defaultGRPCMaxMsgSize
 	ms, err := strconv.Atoi(os.Getenv(ENV_GRPC_MAX_MSG_SIZE))
@@ -103,6 +105,13 @@ func getStudyConfig() types.StudyConfig {
 	if err != nil {
 		logger.Error.Fatal("STUDY_TIMER_EVENT_CHECK_INTERVAL: " + err.Error())
 	}
+
+	leaseDuration, err := strconv.Atoi(os.Getenv(ENV_STUDY_TIMER_LEASE_DURATION))
+	if err != nil || leaseDuration < 1 {
+		leaseDuration = defaultTimerLeaseDuration
+		logger.Info.Printf("Using default value for study timer lease duration: %d seconds", leaseDuration)
+	}
+	studyConf.TimerLeaseDuration = int64(leaseDuration)
 	return studyConf
 }
 
@@ -5,10 +5,13 @@ const (
 	ENV_PERSISTENCE_STORE_ROOT_PATH   = "PERSISTENCE_STORE_ROOT_PATH"
 	ENV_PERSISTENCE_MAX_FILE_SIZE     = "PERSISTENCE_STORE_MAX_FILE_SIZE"
 	ENV_EXTERNAL_SERVICES_CONFIG_PATH = "EXTERNAL_SERVICES_CONFIG_PATH"
+	ENV_STUDY_TIMER_LEASE_DURATION    = "STUDY_TIMER_LEASE_DURATION"
+	ENV_METRICS_LISTEN_PORT           = "STUDY_SERVICE_METRICS_LISTEN_PORT"
 )
 
 const (
 	defaultGRPCMaxMsgSize           = 4194304
 	defaultPersistenceStoreRootPath = "files"
 	maxParticipantFileSize          = 1 << 25
+	defaultTimerLeaseDuration       = 300
 )
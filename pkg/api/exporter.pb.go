@@ -67,12 +67,109 @@ func (ResponseExportQuery_ItemFilter_Mode) EnumDescriptor() ([]byte, []int) {
 	return file_study_service_exporter_proto_rawDescGZIP(), []int{1, 1, 0}
 }
 
+type CodebookExportQuery_Format int32
+
+const (
+	CodebookExportQuery_JSON    CodebookExportQuery_Format = 0
+	CodebookExportQuery_CSV     CodebookExportQuery_Format = 1
+	CodebookExportQuery_DDI_XML CodebookExportQuery_Format = 2
+)
+
+// Enum value maps for CodebookExportQuery_Format.
+var (
+	CodebookExportQuery_Format_name = map[int32]string{
+		0: "JSON",
+		1: "CSV",
+		2: "DDI_XML",
+	}
+	CodebookExportQuery_Format_value = map[string]int32{
+		"JSON":    0,
+		"CSV":     1,
+		"DDI_XML": 2,
+	}
+)
+
+func (x CodebookExportQuery_Format) Enum() *CodebookExportQuery_Format {
+	p := new(CodebookExportQuery_Format)
+	*p = x
+	return p
+}
+
+func (x CodebookExportQuery_Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CodebookExportQuery_Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_study_service_exporter_proto_enumTypes[1].Descriptor()
+}
+
+func (CodebookExportQuery_Format) Type() protoreflect.EnumType {
+	return &file_study_service_exporter_proto_enumTypes[1]
+}
+
+func (x CodebookExportQuery_Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CodebookExportQuery_Format.Descriptor instead.
+func (CodebookExportQuery_Format) EnumDescriptor() ([]byte, []int) {
+	return file_study_service_exporter_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type SyntaxExportQuery_Format int32
+
+const (
+	SyntaxExportQuery_STATA SyntaxExportQuery_Format = 0
+	SyntaxExportQuery_SPSS  SyntaxExportQuery_Format = 1
+)
+
+// Enum value maps for SyntaxExportQuery_Format.
+var (
+	SyntaxExportQuery_Format_name = map[int32]string{
+		0: "STATA",
+		1: "SPSS",
+	}
+	SyntaxExportQuery_Format_value = map[string]int32{
+		"STATA": 0,
+		"SPSS":  1,
+	}
+)
+
+func (x SyntaxExportQuery_Format) Enum() *SyntaxExportQuery_Format {
+	p := new(SyntaxExportQuery_Format)
+	*p = x
+	return p
+}
+
+func (x SyntaxExportQuery_Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyntaxExportQuery_Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_study_service_exporter_proto_enumTypes[2].Descriptor()
+}
+
+func (SyntaxExportQuery_Format) Type() protoreflect.EnumType {
+	return &file_study_service_exporter_proto_enumTypes[2]
+}
+
+func (x SyntaxExportQuery_Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyntaxExportQuery_Format.Descriptor instead.
+func (SyntaxExportQuery_Format) EnumDescriptor() ([]byte, []int) {
+	return file_study_service_exporter_proto_rawDescGZIP(), []int{9, 0}
+}
+
 type Chunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// set in the last message of incremental response exports
+	ResumeToken string `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
 }
 
 func (x *Chunk) Reset() {
@@ -114,6 +211,13 @@ func (x *Chunk) GetChunk() []byte {
 	return nil
 }
 
+func (x *Chunk) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
 type ResponseExportQuery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -130,6 +234,12 @@ type ResponseExportQuery struct {
 	ItemFilter        *ResponseExportQuery_ItemFilter  `protobuf:"bytes,9,opt,name=item_filter,json=itemFilter,proto3" json:"item_filter,omitempty"`
 	Page              int32                            `protobuf:"varint,10,opt,name=page,proto3" json:"page,omitempty"`
 	PageSize          int32                            `protobuf:"varint,11,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// export responses in order of arrival and return a resume token
+	Incremental bool `protobuf:"varint,12,opt,name=incremental,proto3" json:"incremental,omitempty"`
+	// continue an incremental export after the responses of a previous export
+	ResumeToken string `protobuf:"bytes,13,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// add the overflow column to the wide format (values of columns not defined in the survey versions)
+	IncludeOverflowColumn bool `protobuf:"varint,14,opt,name=include_overflow_column,json=includeOverflowColumn,proto3" json:"include_overflow_column,omitempty"`
 }
 
 func (x *ResponseExportQuery) Reset() {
@@ -241,6 +351,27 @@ func (x *ResponseExportQuery) GetPageSize() int32 {
 	return 0
 }
 
+func (x *ResponseExportQuery) GetIncremental() bool {
+	if x != nil {
+		return x.Incremental
+	}
+	return false
+}
+
+func (x *ResponseExportQuery) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *ResponseExportQuery) GetIncludeOverflowColumn() bool {
+	if x != nil {
+		return x.IncludeOverflowColumn
+	}
+	return false
+}
+
 type SurveyInfoExportQuery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -651,6 +782,181 @@ func (x *ResponseOptionPreview) GetLabel() string {
 	return ""
 }
 
+type CodebookExportQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token             *api_types.TokenInfos            `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey          string                           `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	SurveyKey         string                           `protobuf:"bytes,3,opt,name=survey_key,json=surveyKey,proto3" json:"survey_key,omitempty"`
+	Languages         []string                         `protobuf:"bytes,4,rep,name=languages,proto3" json:"languages,omitempty"`
+	ShortQuestionKeys bool                             `protobuf:"varint,5,opt,name=short_question_keys,json=shortQuestionKeys,proto3" json:"short_question_keys,omitempty"`
+	Separator         string                           `protobuf:"bytes,6,opt,name=separator,proto3" json:"separator,omitempty"`
+	ItemFilter        *ResponseExportQuery_ItemFilter  `protobuf:"bytes,7,opt,name=item_filter,json=itemFilter,proto3" json:"item_filter,omitempty"`
+	IncludeMeta       *ResponseExportQuery_IncludeMeta `protobuf:"bytes,8,opt,name=include_meta,json=includeMeta,proto3" json:"include_meta,omitempty"`
+	Format            CodebookExportQuery_Format       `protobuf:"varint,9,opt,name=format,proto3,enum=influenzanet.study_service.CodebookExportQuery_Format" json:"format,omitempty"`
+}
+
+func (x *CodebookExportQuery) Reset() {
+	*x = CodebookExportQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_exporter_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CodebookExportQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodebookExportQuery) ProtoMessage() {}
+
+func (x *CodebookExportQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_exporter_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodebookExportQuery.ProtoReflect.Descriptor instead.
+func (*CodebookExportQuery) Descriptor() ([]byte, []int) {
+	return file_study_service_exporter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CodebookExportQuery) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *CodebookExportQuery) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *CodebookExportQuery) GetSurveyKey() string {
+	if x != nil {
+		return x.SurveyKey
+	}
+	return ""
+}
+
+func (x *CodebookExportQuery) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CodebookExportQuery) GetShortQuestionKeys() bool {
+	if x != nil {
+		return x.ShortQuestionKeys
+	}
+	return false
+}
+
+func (x *CodebookExportQuery) GetSeparator() string {
+	if x != nil {
+		return x.Separator
+	}
+	return ""
+}
+
+func (x *CodebookExportQuery) GetItemFilter() *ResponseExportQuery_ItemFilter {
+	if x != nil {
+		return x.ItemFilter
+	}
+	return nil
+}
+
+func (x *CodebookExportQuery) GetIncludeMeta() *ResponseExportQuery_IncludeMeta {
+	if x != nil {
+		return x.IncludeMeta
+	}
+	return nil
+}
+
+func (x *CodebookExportQuery) GetFormat() CodebookExportQuery_Format {
+	if x != nil {
+		return x.Format
+	}
+	return CodebookExportQuery_JSON
+}
+
+type SyntaxExportQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// same query as for the wide format CSV export the syntax is applied to
+	Query    *ResponseExportQuery     `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Language string                   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Format   SyntaxExportQuery_Format `protobuf:"varint,3,opt,name=format,proto3,enum=influenzanet.study_service.SyntaxExportQuery_Format" json:"format,omitempty"`
+}
+
+func (x *SyntaxExportQuery) Reset() {
+	*x = SyntaxExportQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_exporter_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyntaxExportQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntaxExportQuery) ProtoMessage() {}
+
+func (x *SyntaxExportQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_exporter_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntaxExportQuery.ProtoReflect.Descriptor instead.
+func (*SyntaxExportQuery) Descriptor() ([]byte, []int) {
+	return file_study_service_exporter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyntaxExportQuery) GetQuery() *ResponseExportQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *SyntaxExportQuery) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SyntaxExportQuery) GetFormat() SyntaxExportQuery_Format {
+	if x != nil {
+		return x.Format
+	}
+	return SyntaxExportQuery_STATA
+}
+
 type ResponseExportQuery_IncludeMeta struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -665,7 +971,7 @@ type ResponseExportQuery_IncludeMeta struct {
 func (x *ResponseExportQuery_IncludeMeta) Reset() {
 	*x = ResponseExportQuery_IncludeMeta{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_study_service_exporter_proto_msgTypes[8]
+		mi := &file_study_service_exporter_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -678,7 +984,7 @@ func (x *ResponseExportQuery_IncludeMeta) String() string {
 func (*ResponseExportQuery_IncludeMeta) ProtoMessage() {}
 
 func (x *ResponseExportQuery_IncludeMeta) ProtoReflect() protoreflect.Message {
-	mi := &file_study_service_exporter_proto_msgTypes[8]
+	mi := &file_study_service_exporter_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -734,7 +1040,7 @@ type ResponseExportQuery_ItemFilter struct {
 func (x *ResponseExportQuery_ItemFilter) Reset() {
 	*x = ResponseExportQuery_ItemFilter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_study_service_exporter_proto_msgTypes[9]
+		mi := &file_study_service_exporter_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -747,7 +1053,7 @@ func (x *ResponseExportQuery_ItemFilter) String() string {
 func (*ResponseExportQuery_ItemFilter) ProtoMessage() {}
 
 func (x *ResponseExportQuery_ItemFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_study_service_exporter_proto_msgTypes[9]
+	mi := &file_study_service_exporter_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -785,40 +1091,50 @@ var file_study_service_exporter_proto_rawDesc = []byte{
 	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
 	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x16, 0x73, 0x68, 0x61, 0x72,
 	0x65, 0x64, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0x1d, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x74, 0x6f, 0x22, 0x40, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x63,
 	0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e,
-	0x6b, 0x22, 0xa5, 0x06, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x78,
-	0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
-	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54,
-	0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
-	0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a,
-	0x0a, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04,
-	0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
-	0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x5e, 0x0a, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
-	0x65, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x69,
+	0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xa2, 0x07, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e,
+	0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x4b, 0x65, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x5e, 0x0a, 0x0c, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x3b, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
+	0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x2e, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x0b, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x70,
+	0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65,
+	0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x5b, 0x0a, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x5f,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x69,
 	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
 	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x49, 0x6e,
-	0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x0b, 0x69, 0x6e, 0x63, 0x6c, 0x75,
-	0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
-	0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61,
-	0x74, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72,
-	0x61, 0x74, 0x6f, 0x72, 0x12, 0x5b, 0x0a, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x66, 0x69, 0x6c,
-	0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x69, 0x6e, 0x66, 0x6c,
-	0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45,
-	0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x46,
-	0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65,
-	0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69,
-	0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
-	0x7a, 0x65, 0x1a, 0x9a, 0x01, 0x0a, 0x0b, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65,
+	0x73, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x49, 0x74,
+	0x65, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x61, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x6e, 0x63, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6d,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x36, 0x0a, 0x17, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x63,
+	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77, 0x43, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x1a, 0x9a, 0x01, 0x0a, 0x0b, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65,
 	0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
 	0x0a, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
@@ -900,11 +1216,60 @@ var file_study_service_exporter_proto_rawDesc = []byte{
 	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
 	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
-	0x6e, 0x65, 0x74, 0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0xab, 0x04, 0x0a, 0x13, 0x43, 0x6f, 0x64,
+	0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79,
+	0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x73, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x5b, 0x0a, 0x0b, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x52, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x5e, 0x0a, 0x0c,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x2e, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x52,
+	0x0b, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x4e, 0x0a, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x36, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x62, 0x6f,
+	0x6f, 0x6b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x46, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x28, 0x0a, 0x06,
+	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x08, 0x0a, 0x04, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x00,
+	0x12, 0x07, 0x0a, 0x03, 0x43, 0x53, 0x56, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x44, 0x49,
+	0x5f, 0x58, 0x4d, 0x4c, 0x10, 0x02, 0x22, 0xe3, 0x01, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x74, 0x61,
+	0x78, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x45, 0x0a, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6e,
+	0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12,
+	0x4c, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x34, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
+	0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x79, 0x6e,
+	0x74, 0x61, 0x78, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x1d, 0x0a,
+	0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x54, 0x41, 0x54, 0x41,
+	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x50, 0x53, 0x53, 0x10, 0x01, 0x42, 0x2f, 0x5a, 0x2d,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -919,37 +1284,47 @@ func file_study_service_exporter_proto_rawDescGZIP() []byte {
 	return file_study_service_exporter_proto_rawDescData
 }
 
-var file_study_service_exporter_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_study_service_exporter_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_study_service_exporter_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_study_service_exporter_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_study_service_exporter_proto_goTypes = []interface{}{
 	(ResponseExportQuery_ItemFilter_Mode)(0), // 0: influenzanet.study_service.ResponseExportQuery.ItemFilter.Mode
-	(*Chunk)(nil),                            // 1: influenzanet.study_service.Chunk
-	(*ResponseExportQuery)(nil),              // 2: influenzanet.study_service.ResponseExportQuery
-	(*SurveyInfoExportQuery)(nil),            // 3: influenzanet.study_service.SurveyInfoExportQuery
-	(*SurveyInfoExport)(nil),                 // 4: influenzanet.study_service.SurveyInfoExport
-	(*SurveyVersionPreview)(nil),             // 5: influenzanet.study_service.SurveyVersionPreview
-	(*SurveyQuestionPreview)(nil),            // 6: influenzanet.study_service.SurveyQuestionPreview
-	(*ResponseDefPreview)(nil),               // 7: influenzanet.study_service.ResponseDefPreview
-	(*ResponseOptionPreview)(nil),            // 8: influenzanet.study_service.ResponseOptionPreview
-	(*ResponseExportQuery_IncludeMeta)(nil),  // 9: influenzanet.study_service.ResponseExportQuery.IncludeMeta
-	(*ResponseExportQuery_ItemFilter)(nil),   // 10: influenzanet.study_service.ResponseExportQuery.ItemFilter
-	(*api_types.TokenInfos)(nil),             // 11: influenzanet.shared.TokenInfos
+	(CodebookExportQuery_Format)(0),          // 1: influenzanet.study_service.CodebookExportQuery.Format
+	(SyntaxExportQuery_Format)(0),            // 2: influenzanet.study_service.SyntaxExportQuery.Format
+	(*Chunk)(nil),                            // 3: influenzanet.study_service.Chunk
+	(*ResponseExportQuery)(nil),              // 4: influenzanet.study_service.ResponseExportQuery
+	(*SurveyInfoExportQuery)(nil),            // 5: influenzanet.study_service.SurveyInfoExportQuery
+	(*SurveyInfoExport)(nil),                 // 6: influenzanet.study_service.SurveyInfoExport
+	(*SurveyVersionPreview)(nil),             // 7: influenzanet.study_service.SurveyVersionPreview
+	(*SurveyQuestionPreview)(nil),            // 8: influenzanet.study_service.SurveyQuestionPreview
+	(*ResponseDefPreview)(nil),               // 9: influenzanet.study_service.ResponseDefPreview
+	(*ResponseOptionPreview)(nil),            // 10: influenzanet.study_service.ResponseOptionPreview
+	(*CodebookExportQuery)(nil),              // 11: influenzanet.study_service.CodebookExportQuery
+	(*SyntaxExportQuery)(nil),                // 12: influenzanet.study_service.SyntaxExportQuery
+	(*ResponseExportQuery_IncludeMeta)(nil),  // 13: influenzanet.study_service.ResponseExportQuery.IncludeMeta
+	(*ResponseExportQuery_ItemFilter)(nil),   // 14: influenzanet.study_service.ResponseExportQuery.ItemFilter
+	(*api_types.TokenInfos)(nil),             // 15: influenzanet.shared.TokenInfos
 }
 var file_study_service_exporter_proto_depIdxs = []int32{
-	11, // 0: influenzanet.study_service.ResponseExportQuery.token:type_name -> influenzanet.shared.TokenInfos
-	9,  // 1: influenzanet.study_service.ResponseExportQuery.include_meta:type_name -> influenzanet.study_service.ResponseExportQuery.IncludeMeta
-	10, // 2: influenzanet.study_service.ResponseExportQuery.item_filter:type_name -> influenzanet.study_service.ResponseExportQuery.ItemFilter
-	11, // 3: influenzanet.study_service.SurveyInfoExportQuery.token:type_name -> influenzanet.shared.TokenInfos
-	5,  // 4: influenzanet.study_service.SurveyInfoExport.versions:type_name -> influenzanet.study_service.SurveyVersionPreview
-	6,  // 5: influenzanet.study_service.SurveyVersionPreview.questions:type_name -> influenzanet.study_service.SurveyQuestionPreview
-	7,  // 6: influenzanet.study_service.SurveyQuestionPreview.responses:type_name -> influenzanet.study_service.ResponseDefPreview
-	8,  // 7: influenzanet.study_service.ResponseDefPreview.options:type_name -> influenzanet.study_service.ResponseOptionPreview
-	0,  // 8: influenzanet.study_service.ResponseExportQuery.ItemFilter.mode:type_name -> influenzanet.study_service.ResponseExportQuery.ItemFilter.Mode
-	9,  // [9:9] is the sub-list for method output_type
-	9,  // [9:9] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	15, // 0: influenzanet.study_service.ResponseExportQuery.token:type_name -> influenzanet.shared.TokenInfos
+	13, // 1: influenzanet.study_service.ResponseExportQuery.include_meta:type_name -> influenzanet.study_service.ResponseExportQuery.IncludeMeta
+	14, // 2: influenzanet.study_service.ResponseExportQuery.item_filter:type_name -> influenzanet.study_service.ResponseExportQuery.ItemFilter
+	15, // 3: influenzanet.study_service.SurveyInfoExportQuery.token:type_name -> influenzanet.shared.TokenInfos
+	7,  // 4: influenzanet.study_service.SurveyInfoExport.versions:type_name -> influenzanet.study_service.SurveyVersionPreview
+	8,  // 5: influenzanet.study_service.SurveyVersionPreview.questions:type_name -> influenzanet.study_service.SurveyQuestionPreview
+	9,  // 6: influenzanet.study_service.SurveyQuestionPreview.responses:type_name -> influenzanet.study_service.ResponseDefPreview
+	10, // 7: influenzanet.study_service.ResponseDefPreview.options:type_name -> influenzanet.study_service.ResponseOptionPreview
+	15, // 8: influenzanet.study_service.CodebookExportQuery.token:type_name -> influenzanet.shared.TokenInfos
+	14, // 9: influenzanet.study_service.CodebookExportQuery.item_filter:type_name -> influenzanet.study_service.ResponseExportQuery.ItemFilter
+	13, // 10: influenzanet.study_service.CodebookExportQuery.include_meta:type_name -> influenzanet.study_service.ResponseExportQuery.IncludeMeta
+	1,  // 11: influenzanet.study_service.CodebookExportQuery.format:type_name -> influenzanet.study_service.CodebookExportQuery.Format
+	4,  // 12: influenzanet.study_service.SyntaxExportQuery.query:type_name -> influenzanet.study_service.ResponseExportQuery
+	2,  // 13: influenzanet.study_service.SyntaxExportQuery.format:type_name -> influenzanet.study_service.SyntaxExportQuery.Format
+	0,  // 14: influenzanet.study_service.ResponseExportQuery.ItemFilter.mode:type_name -> influenzanet.study_service.ResponseExportQuery.ItemFilter.Mode
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_study_service_exporter_proto_init() }
@@ -1055,7 +1430,7 @@ func file_study_service_exporter_proto_init() {
 			}
 		}
 		file_study_service_exporter_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResponseExportQuery_IncludeMeta); i {
+			switch v := v.(*CodebookExportQuery); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1067,6 +1442,30 @@ func file_study_service_exporter_proto_init() {
 			}
 		}
 		file_study_service_exporter_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyntaxExportQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_exporter_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseExportQuery_IncludeMeta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_exporter_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseExportQuery_ItemFilter); i {
 			case 0:
 				return &v.state
@@ -1084,8 +1483,8 @@ func file_study_service_exporter_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_study_service_exporter_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   10,
+			NumEnums:      3,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
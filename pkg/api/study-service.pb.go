@@ -3597,9 +3597,10 @@ type StudyRulesReq struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Token    *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	StudyKey string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
-	Rules    []*Expression         `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	Token      *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey   string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	Rules      []*Expression         `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	ActiveFrom int64                 `protobuf:"varint,4,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
 }
 
 func (x *StudyRulesReq) Reset() {
@@ -3655,6 +3656,13 @@ func (x *StudyRulesReq) GetRules() []*Expression {
 	return nil
 }
 
+func (x *StudyRulesReq) GetActiveFrom() int64 {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return 0
+}
+
 type RunRulesForSingleParticipantReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -3664,6 +3672,7 @@ type RunRulesForSingleParticipantReq struct {
 	StudyKey      string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
 	ParticipantId string                `protobuf:"bytes,3,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
 	Rules         []*Expression         `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	WithTrace     bool                  `protobuf:"varint,5,opt,name=with_trace,json=withTrace,proto3" json:"with_trace,omitempty"`
 }
 
 func (x *RunRulesForSingleParticipantReq) Reset() {
@@ -3726,6 +3735,13 @@ func (x *RunRulesForSingleParticipantReq) GetRules() []*Expression {
 	return nil
 }
 
+func (x *RunRulesForSingleParticipantReq) GetWithTrace() bool {
+	if x != nil {
+		return x.WithTrace
+	}
+	return false
+}
+
 type RunRulesForPreviousResponsesReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -3928,9 +3944,10 @@ type RuleRunSummary struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ParticipantCount              int32   `protobuf:"varint,1,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"`
-	ParticipantStateChangePerRule []int32 `protobuf:"varint,2,rep,packed,name=participant_state_change_per_rule,json=participantStateChangePerRule,proto3" json:"participant_state_change_per_rule,omitempty"`
-	Duration                      int64   `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	ParticipantCount              int32             `protobuf:"varint,1,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"`
+	ParticipantStateChangePerRule []int32           `protobuf:"varint,2,rep,packed,name=participant_state_change_per_rule,json=participantStateChangePerRule,proto3" json:"participant_state_change_per_rule,omitempty"`
+	Duration                      int64             `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Trace                         []*RuleTraceEntry `protobuf:"bytes,4,rep,name=trace,proto3" json:"trace,omitempty"`
 }
 
 func (x *RuleRunSummary) Reset() {
@@ -3986,6 +4003,13 @@ func (x *RuleRunSummary) GetDuration() int64 {
 	return 0
 }
 
+func (x *RuleRunSummary) GetTrace() []*RuleTraceEntry {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
 type ConvertTempParticipantReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -4364,25 +4388,28 @@ func (x *ConfidentialResponses) GetResponses() []*SurveyResponse {
 	return nil
 }
 
-type UploadParticipantFileReq_Info struct {
+type ParticipantStateDiff struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Token    *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	StudyKey string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
-	SubStudy string                `protobuf:"bytes,3,opt,name=sub_study,json=subStudy,proto3" json:"sub_study,omitempty"`
-	// Types that are assignable to Participant:
-	//
-	//	*UploadParticipantFileReq_Info_ProfileId
-	//	*UploadParticipantFileReq_Info_ParticipantId
-	Participant          isUploadParticipantFileReq_Info_Participant `protobuf_oneof:"participant"`
-	FileType             *FileType                                   `protobuf:"bytes,6,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
-	VisibleToParticipant bool                                        `protobuf:"varint,7,opt,name=visible_to_participant,json=visibleToParticipant,proto3" json:"visible_to_participant,omitempty"`
-}
-
-func (x *UploadParticipantFileReq_Info) Reset() {
-	*x = UploadParticipantFileReq_Info{}
+	ParticipantId          string                              `protobuf:"bytes,1,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	StudyStatus            *ParticipantStateDiff_ValueChange   `protobuf:"bytes,2,opt,name=study_status,json=studyStatus,proto3" json:"study_status,omitempty"`
+	CurrentStudySession    *ParticipantStateDiff_ValueChange   `protobuf:"bytes,3,opt,name=current_study_session,json=currentStudySession,proto3" json:"current_study_session,omitempty"`
+	AddedFlags             map[string]string                   `protobuf:"bytes,4,rep,name=added_flags,json=addedFlags,proto3" json:"added_flags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	UpdatedFlags           []*ParticipantStateDiff_ValueChange `protobuf:"bytes,5,rep,name=updated_flags,json=updatedFlags,proto3" json:"updated_flags,omitempty"`
+	RemovedFlags           map[string]string                   `protobuf:"bytes,6,rep,name=removed_flags,json=removedFlags,proto3" json:"removed_flags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AddedSurveys           []*AssignedSurvey                   `protobuf:"bytes,7,rep,name=added_surveys,json=addedSurveys,proto3" json:"added_surveys,omitempty"`
+	RemovedSurveys         []*AssignedSurvey                   `protobuf:"bytes,8,rep,name=removed_surveys,json=removedSurveys,proto3" json:"removed_surveys,omitempty"`
+	AddedMessages          []*ParticipantMessage               `protobuf:"bytes,9,rep,name=added_messages,json=addedMessages,proto3" json:"added_messages,omitempty"`
+	RemovedMessages        []*ParticipantMessage               `protobuf:"bytes,10,rep,name=removed_messages,json=removedMessages,proto3" json:"removed_messages,omitempty"`
+	ReportsToCreate        []*Report                           `protobuf:"bytes,11,rep,name=reports_to_create,json=reportsToCreate,proto3" json:"reports_to_create,omitempty"`
+	AddedScheduledEvents   []*ScheduledEvent                   `protobuf:"bytes,12,rep,name=added_scheduled_events,json=addedScheduledEvents,proto3" json:"added_scheduled_events,omitempty"`
+	RemovedScheduledEvents []*ScheduledEvent                   `protobuf:"bytes,13,rep,name=removed_scheduled_events,json=removedScheduledEvents,proto3" json:"removed_scheduled_events,omitempty"`
+}
+
+func (x *ParticipantStateDiff) Reset() {
+	*x = ParticipantStateDiff{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_study_service_study_service_proto_msgTypes[65]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4390,13 +4417,13 @@ func (x *UploadParticipantFileReq_Info) Reset() {
 	}
 }
 
-func (x *UploadParticipantFileReq_Info) String() string {
+func (x *ParticipantStateDiff) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadParticipantFileReq_Info) ProtoMessage() {}
+func (*ParticipantStateDiff) ProtoMessage() {}
 
-func (x *UploadParticipantFileReq_Info) ProtoReflect() protoreflect.Message {
+func (x *ParticipantStateDiff) ProtoReflect() protoreflect.Message {
 	mi := &file_study_service_study_service_proto_msgTypes[65]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4408,112 +4435,214 @@ func (x *UploadParticipantFileReq_Info) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadParticipantFileReq_Info.ProtoReflect.Descriptor instead.
-func (*UploadParticipantFileReq_Info) Descriptor() ([]byte, []int) {
-	return file_study_service_study_service_proto_rawDescGZIP(), []int{2, 0}
+// Deprecated: Use ParticipantStateDiff.ProtoReflect.Descriptor instead.
+func (*ParticipantStateDiff) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *UploadParticipantFileReq_Info) GetToken() *api_types.TokenInfos {
+func (x *ParticipantStateDiff) GetParticipantId() string {
 	if x != nil {
-		return x.Token
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *ParticipantStateDiff) GetStudyStatus() *ParticipantStateDiff_ValueChange {
+	if x != nil {
+		return x.StudyStatus
 	}
 	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetStudyKey() string {
+func (x *ParticipantStateDiff) GetCurrentStudySession() *ParticipantStateDiff_ValueChange {
 	if x != nil {
-		return x.StudyKey
+		return x.CurrentStudySession
 	}
-	return ""
+	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetSubStudy() string {
+func (x *ParticipantStateDiff) GetAddedFlags() map[string]string {
 	if x != nil {
-		return x.SubStudy
+		return x.AddedFlags
 	}
-	return ""
+	return nil
 }
 
-func (m *UploadParticipantFileReq_Info) GetParticipant() isUploadParticipantFileReq_Info_Participant {
-	if m != nil {
-		return m.Participant
+func (x *ParticipantStateDiff) GetUpdatedFlags() []*ParticipantStateDiff_ValueChange {
+	if x != nil {
+		return x.UpdatedFlags
 	}
 	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetProfileId() string {
-	if x, ok := x.GetParticipant().(*UploadParticipantFileReq_Info_ProfileId); ok {
-		return x.ProfileId
+func (x *ParticipantStateDiff) GetRemovedFlags() map[string]string {
+	if x != nil {
+		return x.RemovedFlags
 	}
-	return ""
+	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetParticipantId() string {
-	if x, ok := x.GetParticipant().(*UploadParticipantFileReq_Info_ParticipantId); ok {
-		return x.ParticipantId
+func (x *ParticipantStateDiff) GetAddedSurveys() []*AssignedSurvey {
+	if x != nil {
+		return x.AddedSurveys
 	}
-	return ""
+	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetFileType() *FileType {
+func (x *ParticipantStateDiff) GetRemovedSurveys() []*AssignedSurvey {
 	if x != nil {
-		return x.FileType
+		return x.RemovedSurveys
 	}
 	return nil
 }
 
-func (x *UploadParticipantFileReq_Info) GetVisibleToParticipant() bool {
+func (x *ParticipantStateDiff) GetAddedMessages() []*ParticipantMessage {
 	if x != nil {
-		return x.VisibleToParticipant
+		return x.AddedMessages
 	}
-	return false
+	return nil
 }
 
-type isUploadParticipantFileReq_Info_Participant interface {
-	isUploadParticipantFileReq_Info_Participant()
+func (x *ParticipantStateDiff) GetRemovedMessages() []*ParticipantMessage {
+	if x != nil {
+		return x.RemovedMessages
+	}
+	return nil
 }
 
-type UploadParticipantFileReq_Info_ProfileId struct {
-	ProfileId string `protobuf:"bytes,4,opt,name=profile_id,json=profileId,proto3,oneof"`
+func (x *ParticipantStateDiff) GetReportsToCreate() []*Report {
+	if x != nil {
+		return x.ReportsToCreate
+	}
+	return nil
 }
 
-type UploadParticipantFileReq_Info_ParticipantId struct {
-	ParticipantId string `protobuf:"bytes,5,opt,name=participant_id,json=participantId,proto3,oneof"` // if researcher upload for participant directly
+func (x *ParticipantStateDiff) GetAddedScheduledEvents() []*ScheduledEvent {
+	if x != nil {
+		return x.AddedScheduledEvents
+	}
+	return nil
 }
 
-func (*UploadParticipantFileReq_Info_ProfileId) isUploadParticipantFileReq_Info_Participant() {}
+func (x *ParticipantStateDiff) GetRemovedScheduledEvents() []*ScheduledEvent {
+	if x != nil {
+		return x.RemovedScheduledEvents
+	}
+	return nil
+}
 
-func (*UploadParticipantFileReq_Info_ParticipantId) isUploadParticipantFileReq_Info_Participant() {}
+type RuleDryRunResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-type RunRulesForPreviousResponsesReq_ResponseFilter struct {
+	// Types that are assignable to Result:
+	//	*RuleDryRunResult_ParticipantDiff
+	//	*RuleDryRunResult_Summary
+	Result isRuleDryRunResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *RuleDryRunResult) Reset() {
+	*x = RuleDryRunResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuleDryRunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleDryRunResult) ProtoMessage() {}
+
+func (x *RuleDryRunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleDryRunResult.ProtoReflect.Descriptor instead.
+func (*RuleDryRunResult) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (m *RuleDryRunResult) GetResult() isRuleDryRunResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *RuleDryRunResult) GetParticipantDiff() *ParticipantStateDiff {
+	if x, ok := x.GetResult().(*RuleDryRunResult_ParticipantDiff); ok {
+		return x.ParticipantDiff
+	}
+	return nil
+}
+
+func (x *RuleDryRunResult) GetSummary() *RuleRunSummary {
+	if x, ok := x.GetResult().(*RuleDryRunResult_Summary); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+type isRuleDryRunResult_Result interface {
+	isRuleDryRunResult_Result()
+}
+
+type RuleDryRunResult_ParticipantDiff struct {
+	ParticipantDiff *ParticipantStateDiff `protobuf:"bytes,1,opt,name=participant_diff,json=participantDiff,proto3,oneof"`
+}
+
+type RuleDryRunResult_Summary struct {
+	Summary *RuleRunSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*RuleDryRunResult_ParticipantDiff) isRuleDryRunResult_Result() {}
+
+func (*RuleDryRunResult_Summary) isRuleDryRunResult_Result() {}
+
+type RuleTraceEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SurveyKeys        []string `protobuf:"bytes,1,rep,name=survey_keys,json=surveyKeys,proto3" json:"survey_keys,omitempty"`
-	ParticipantIds    []string `protobuf:"bytes,2,rep,name=participant_ids,json=participantIds,proto3" json:"participant_ids,omitempty"`
-	ParticipantStatus []string `protobuf:"bytes,3,rep,name=participant_status,json=participantStatus,proto3" json:"participant_status,omitempty"`
-	From              int64    `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
-	Until             int64    `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+	RuleIndex    int32                 `protobuf:"varint,1,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	Depth        int32                 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	Type         string                `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Name         string                `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Args         []string              `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	Result       string                `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	Error        string                `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	StateChanges *ParticipantStateDiff `protobuf:"bytes,8,opt,name=state_changes,json=stateChanges,proto3" json:"state_changes,omitempty"`
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) Reset() {
-	*x = RunRulesForPreviousResponsesReq_ResponseFilter{}
+func (x *RuleTraceEntry) Reset() {
+	*x = RuleTraceEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_study_service_study_service_proto_msgTypes[70]
+		mi := &file_study_service_study_service_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) String() string {
+func (x *RuleTraceEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RunRulesForPreviousResponsesReq_ResponseFilter) ProtoMessage() {}
+func (*RuleTraceEntry) ProtoMessage() {}
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_study_service_study_service_proto_msgTypes[70]
+func (x *RuleTraceEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4524,59 +4653,1671 @@ func (x *RunRulesForPreviousResponsesReq_ResponseFilter) ProtoReflect() protoref
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RunRulesForPreviousResponsesReq_ResponseFilter.ProtoReflect.Descriptor instead.
-func (*RunRulesForPreviousResponsesReq_ResponseFilter) Descriptor() ([]byte, []int) {
-	return file_study_service_study_service_proto_rawDescGZIP(), []int{55, 0}
+// Deprecated: Use RuleTraceEntry.ProtoReflect.Descriptor instead.
+func (*RuleTraceEntry) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetSurveyKeys() []string {
+func (x *RuleTraceEntry) GetRuleIndex() int32 {
 	if x != nil {
-		return x.SurveyKeys
+		return x.RuleIndex
 	}
-	return nil
+	return 0
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetParticipantIds() []string {
+func (x *RuleTraceEntry) GetDepth() int32 {
 	if x != nil {
-		return x.ParticipantIds
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *RuleTraceEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RuleTraceEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleTraceEntry) GetArgs() []string {
+	if x != nil {
+		return x.Args
 	}
 	return nil
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetParticipantStatus() []string {
+func (x *RuleTraceEntry) GetResult() string {
 	if x != nil {
-		return x.ParticipantStatus
+		return x.Result
+	}
+	return ""
+}
+
+func (x *RuleTraceEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RuleTraceEntry) GetStateChanges() *ParticipantStateDiff {
+	if x != nil {
+		return x.StateChanges
 	}
 	return nil
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetFrom() int64 {
+type ParticipantEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             string                `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParticipantId  string                `protobuf:"bytes,2,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Timestamp      int64                 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	EventType      string                `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	EventKey       string                `protobuf:"bytes,5,opt,name=event_key,json=eventKey,proto3" json:"event_key,omitempty"`
+	RulesVersionId string                `protobuf:"bytes,6,opt,name=rules_version_id,json=rulesVersionId,proto3" json:"rules_version_id,omitempty"`
+	StateChanges   *ParticipantStateDiff `protobuf:"bytes,7,opt,name=state_changes,json=stateChanges,proto3" json:"state_changes,omitempty"`
+}
+
+func (x *ParticipantEvent) Reset() {
+	*x = ParticipantEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantEvent) ProtoMessage() {}
+
+func (x *ParticipantEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantEvent.ProtoReflect.Descriptor instead.
+func (*ParticipantEvent) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ParticipantEvent) GetId() string {
 	if x != nil {
-		return x.From
+		return x.Id
 	}
-	return 0
+	return ""
 }
 
-func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetUntil() int64 {
+func (x *ParticipantEvent) GetParticipantId() string {
 	if x != nil {
-		return x.Until
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *ParticipantEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
 	}
 	return 0
 }
 
-var File_study_service_study_service_proto protoreflect.FileDescriptor
+func (x *ParticipantEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
 
-var file_study_service_study_service_proto_rawDesc = []byte{
-	0x0a, 0x21, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
-	0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
-	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a,
-	0x16, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x69, 0x6e, 0x66,
-	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x1a, 0x1c, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x1a, 0x1a, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
+func (x *ParticipantEvent) GetEventKey() string {
+	if x != nil {
+		return x.EventKey
+	}
+	return ""
+}
+
+func (x *ParticipantEvent) GetRulesVersionId() string {
+	if x != nil {
+		return x.RulesVersionId
+	}
+	return ""
+}
+
+func (x *ParticipantEvent) GetStateChanges() *ParticipantStateDiff {
+	if x != nil {
+		return x.StateChanges
+	}
+	return nil
+}
+
+type ParticipantEventHistoryQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token         *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey      string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	ParticipantId string                `protobuf:"bytes,3,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Since         int64                 `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until         int64                 `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *ParticipantEventHistoryQuery) Reset() {
+	*x = ParticipantEventHistoryQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantEventHistoryQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantEventHistoryQuery) ProtoMessage() {}
+
+func (x *ParticipantEventHistoryQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantEventHistoryQuery.ProtoReflect.Descriptor instead.
+func (*ParticipantEventHistoryQuery) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ParticipantEventHistoryQuery) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *ParticipantEventHistoryQuery) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *ParticipantEventHistoryQuery) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *ParticipantEventHistoryQuery) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ParticipantEventHistoryQuery) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type StudyTimerScheduleReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token         *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey      string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	TimerSchedule *Study_TimerSchedule  `protobuf:"bytes,3,opt,name=timer_schedule,json=timerSchedule,proto3" json:"timer_schedule,omitempty"`
+}
+
+func (x *StudyTimerScheduleReq) Reset() {
+	*x = StudyTimerScheduleReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudyTimerScheduleReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudyTimerScheduleReq) ProtoMessage() {}
+
+func (x *StudyTimerScheduleReq) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudyTimerScheduleReq.ProtoReflect.Descriptor instead.
+func (*StudyTimerScheduleReq) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *StudyTimerScheduleReq) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *StudyTimerScheduleReq) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *StudyTimerScheduleReq) GetTimerSchedule() *Study_TimerSchedule {
+	if x != nil {
+		return x.TimerSchedule
+	}
+	return nil
+}
+
+type SandboxEvaluationReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token            *api_types.TokenInfos       `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey         string                      `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	ParticipantId    string                      `protobuf:"bytes,3,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	ParticipantState *ParticipantState           `protobuf:"bytes,4,opt,name=participant_state,json=participantState,proto3" json:"participant_state,omitempty"`
+	Event            *SandboxEvaluationReq_Event `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	Rules            []*Expression               `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
+	Now              int64                       `protobuf:"varint,7,opt,name=now,proto3" json:"now,omitempty"`
+	WithTrace        bool                        `protobuf:"varint,8,opt,name=with_trace,json=withTrace,proto3" json:"with_trace,omitempty"`
+	RulesVersionId   string                      `protobuf:"bytes,9,opt,name=rules_version_id,json=rulesVersionId,proto3" json:"rules_version_id,omitempty"`
+}
+
+func (x *SandboxEvaluationReq) Reset() {
+	*x = SandboxEvaluationReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SandboxEvaluationReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxEvaluationReq) ProtoMessage() {}
+
+func (x *SandboxEvaluationReq) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxEvaluationReq.ProtoReflect.Descriptor instead.
+func (*SandboxEvaluationReq) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SandboxEvaluationReq) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationReq) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *SandboxEvaluationReq) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *SandboxEvaluationReq) GetParticipantState() *ParticipantState {
+	if x != nil {
+		return x.ParticipantState
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationReq) GetEvent() *SandboxEvaluationReq_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationReq) GetRules() []*Expression {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationReq) GetNow() int64 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+func (x *SandboxEvaluationReq) GetWithTrace() bool {
+	if x != nil {
+		return x.WithTrace
+	}
+	return false
+}
+
+func (x *SandboxEvaluationReq) GetRulesVersionId() string {
+	if x != nil {
+		return x.RulesVersionId
+	}
+	return ""
+}
+
+type SandboxEvaluationResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParticipantState   *ParticipantState                    `protobuf:"bytes,1,opt,name=participant_state,json=participantState,proto3" json:"participant_state,omitempty"`
+	StateChanges       *ParticipantStateDiff                `protobuf:"bytes,2,opt,name=state_changes,json=stateChanges,proto3" json:"state_changes,omitempty"`
+	ReportsToCreate    []*Report                            `protobuf:"bytes,3,rep,name=reports_to_create,json=reportsToCreate,proto3" json:"reports_to_create,omitempty"`
+	ResearcherMessages []*StudyMessage                      `protobuf:"bytes,4,rep,name=researcher_messages,json=researcherMessages,proto3" json:"researcher_messages,omitempty"`
+	Errors             []*SandboxEvaluationResult_RuleError `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+	Trace              []*RuleTraceEntry                    `protobuf:"bytes,6,rep,name=trace,proto3" json:"trace,omitempty"`
+	RulesVersionId     string                               `protobuf:"bytes,7,opt,name=rules_version_id,json=rulesVersionId,proto3" json:"rules_version_id,omitempty"`
+}
+
+func (x *SandboxEvaluationResult) Reset() {
+	*x = SandboxEvaluationResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SandboxEvaluationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxEvaluationResult) ProtoMessage() {}
+
+func (x *SandboxEvaluationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxEvaluationResult.ProtoReflect.Descriptor instead.
+func (*SandboxEvaluationResult) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *SandboxEvaluationResult) GetParticipantState() *ParticipantState {
+	if x != nil {
+		return x.ParticipantState
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetStateChanges() *ParticipantStateDiff {
+	if x != nil {
+		return x.StateChanges
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetReportsToCreate() []*Report {
+	if x != nil {
+		return x.ReportsToCreate
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetResearcherMessages() []*StudyMessage {
+	if x != nil {
+		return x.ResearcherMessages
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetErrors() []*SandboxEvaluationResult_RuleError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetTrace() []*RuleTraceEntry {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *SandboxEvaluationResult) GetRulesVersionId() string {
+	if x != nil {
+		return x.RulesVersionId
+	}
+	return ""
+}
+
+type RandomisationSchemeReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token    *api_types.TokenInfos      `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey string                     `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	Scheme   *Study_RandomisationScheme `protobuf:"bytes,3,opt,name=scheme,proto3" json:"scheme,omitempty"`
+}
+
+func (x *RandomisationSchemeReq) Reset() {
+	*x = RandomisationSchemeReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RandomisationSchemeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RandomisationSchemeReq) ProtoMessage() {}
+
+func (x *RandomisationSchemeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RandomisationSchemeReq.ProtoReflect.Descriptor instead.
+func (*RandomisationSchemeReq) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *RandomisationSchemeReq) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *RandomisationSchemeReq) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *RandomisationSchemeReq) GetScheme() *Study_RandomisationScheme {
+	if x != nil {
+		return x.Scheme
+	}
+	return nil
+}
+
+type RandomisationAllocationCountsQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token     *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey  string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	SchemeKey string                `protobuf:"bytes,3,opt,name=scheme_key,json=schemeKey,proto3" json:"scheme_key,omitempty"`
+}
+
+func (x *RandomisationAllocationCountsQuery) Reset() {
+	*x = RandomisationAllocationCountsQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RandomisationAllocationCountsQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RandomisationAllocationCountsQuery) ProtoMessage() {}
+
+func (x *RandomisationAllocationCountsQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RandomisationAllocationCountsQuery.ProtoReflect.Descriptor instead.
+func (*RandomisationAllocationCountsQuery) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *RandomisationAllocationCountsQuery) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *RandomisationAllocationCountsQuery) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *RandomisationAllocationCountsQuery) GetSchemeKey() string {
+	if x != nil {
+		return x.SchemeKey
+	}
+	return ""
+}
+
+type RandomisationAllocationCounts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Counts []*RandomisationAllocationCounts_Count `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *RandomisationAllocationCounts) Reset() {
+	*x = RandomisationAllocationCounts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RandomisationAllocationCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RandomisationAllocationCounts) ProtoMessage() {}
+
+func (x *RandomisationAllocationCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RandomisationAllocationCounts.ProtoReflect.Descriptor instead.
+func (*RandomisationAllocationCounts) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *RandomisationAllocationCounts) GetCounts() []*RandomisationAllocationCounts_Count {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type CustomEventReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token          *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey       string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	ParticipantIds []string              `protobuf:"bytes,3,rep,name=participant_ids,json=participantIds,proto3" json:"participant_ids,omitempty"`
+	EventKey       string                `protobuf:"bytes,4,opt,name=event_key,json=eventKey,proto3" json:"event_key,omitempty"`
+	Payload        map[string]*FlagValue `protobuf:"bytes,5,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CustomEventReq) Reset() {
+	*x = CustomEventReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CustomEventReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomEventReq) ProtoMessage() {}
+
+func (x *CustomEventReq) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomEventReq.ProtoReflect.Descriptor instead.
+func (*CustomEventReq) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *CustomEventReq) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *CustomEventReq) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *CustomEventReq) GetParticipantIds() []string {
+	if x != nil {
+		return x.ParticipantIds
+	}
+	return nil
+}
+
+func (x *CustomEventReq) GetEventKey() string {
+	if x != nil {
+		return x.EventKey
+	}
+	return ""
+}
+
+func (x *CustomEventReq) GetPayload() map[string]*FlagValue {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type CustomEventResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParticipantCount int32                                 `protobuf:"varint,1,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"`
+	Errors           []*CustomEventResult_ParticipantError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *CustomEventResult) Reset() {
+	*x = CustomEventResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CustomEventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomEventResult) ProtoMessage() {}
+
+func (x *CustomEventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomEventResult.ProtoReflect.Descriptor instead.
+func (*CustomEventResult) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *CustomEventResult) GetParticipantCount() int32 {
+	if x != nil {
+		return x.ParticipantCount
+	}
+	return 0
+}
+
+func (x *CustomEventResult) GetErrors() []*CustomEventResult_ParticipantError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type RulesWhatIfQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token          *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey       string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	RulesVersionId string                `protobuf:"bytes,3,opt,name=rules_version_id,json=rulesVersionId,proto3" json:"rules_version_id,omitempty"`
+	Since          int64                 `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until          int64                 `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+	SurveyKey      string                `protobuf:"bytes,6,opt,name=survey_key,json=surveyKey,proto3" json:"survey_key,omitempty"`
+	ParticipantId  string                `protobuf:"bytes,7,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+}
+
+func (x *RulesWhatIfQuery) Reset() {
+	*x = RulesWhatIfQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RulesWhatIfQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RulesWhatIfQuery) ProtoMessage() {}
+
+func (x *RulesWhatIfQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RulesWhatIfQuery.ProtoReflect.Descriptor instead.
+func (*RulesWhatIfQuery) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *RulesWhatIfQuery) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *RulesWhatIfQuery) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *RulesWhatIfQuery) GetRulesVersionId() string {
+	if x != nil {
+		return x.RulesVersionId
+	}
+	return ""
+}
+
+func (x *RulesWhatIfQuery) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *RulesWhatIfQuery) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *RulesWhatIfQuery) GetSurveyKey() string {
+	if x != nil {
+		return x.SurveyKey
+	}
+	return ""
+}
+
+func (x *RulesWhatIfQuery) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+type RulesWhatIfResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Result:
+	//	*RulesWhatIfResult_ResponseResult_
+	//	*RulesWhatIfResult_Summary_
+	Result isRulesWhatIfResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *RulesWhatIfResult) Reset() {
+	*x = RulesWhatIfResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RulesWhatIfResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RulesWhatIfResult) ProtoMessage() {}
+
+func (x *RulesWhatIfResult) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RulesWhatIfResult.ProtoReflect.Descriptor instead.
+func (*RulesWhatIfResult) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (m *RulesWhatIfResult) GetResult() isRulesWhatIfResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *RulesWhatIfResult) GetResponseResult() *RulesWhatIfResult_ResponseResult {
+	if x, ok := x.GetResult().(*RulesWhatIfResult_ResponseResult_); ok {
+		return x.ResponseResult
+	}
+	return nil
+}
+
+func (x *RulesWhatIfResult) GetSummary() *RulesWhatIfResult_Summary {
+	if x, ok := x.GetResult().(*RulesWhatIfResult_Summary_); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+type isRulesWhatIfResult_Result interface {
+	isRulesWhatIfResult_Result()
+}
+
+type RulesWhatIfResult_ResponseResult_ struct {
+	ResponseResult *RulesWhatIfResult_ResponseResult `protobuf:"bytes,1,opt,name=response_result,json=responseResult,proto3,oneof"`
+}
+
+type RulesWhatIfResult_Summary_ struct {
+	Summary *RulesWhatIfResult_Summary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*RulesWhatIfResult_ResponseResult_) isRulesWhatIfResult_Result() {}
+
+func (*RulesWhatIfResult_Summary_) isRulesWhatIfResult_Result() {}
+
+type UploadParticipantFileReq_Info struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token    *api_types.TokenInfos `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	StudyKey string                `protobuf:"bytes,2,opt,name=study_key,json=studyKey,proto3" json:"study_key,omitempty"`
+	SubStudy string                `protobuf:"bytes,3,opt,name=sub_study,json=subStudy,proto3" json:"sub_study,omitempty"`
+	// Types that are assignable to Participant:
+	//
+	//	*UploadParticipantFileReq_Info_ProfileId
+	//	*UploadParticipantFileReq_Info_ParticipantId
+	Participant          isUploadParticipantFileReq_Info_Participant `protobuf_oneof:"participant"`
+	FileType             *FileType                                   `protobuf:"bytes,6,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	VisibleToParticipant bool                                        `protobuf:"varint,7,opt,name=visible_to_participant,json=visibleToParticipant,proto3" json:"visible_to_participant,omitempty"`
+}
+
+func (x *UploadParticipantFileReq_Info) Reset() {
+	*x = UploadParticipantFileReq_Info{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadParticipantFileReq_Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadParticipantFileReq_Info) ProtoMessage() {}
+
+func (x *UploadParticipantFileReq_Info) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadParticipantFileReq_Info.ProtoReflect.Descriptor instead.
+func (*UploadParticipantFileReq_Info) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *UploadParticipantFileReq_Info) GetToken() *api_types.TokenInfos {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *UploadParticipantFileReq_Info) GetStudyKey() string {
+	if x != nil {
+		return x.StudyKey
+	}
+	return ""
+}
+
+func (x *UploadParticipantFileReq_Info) GetSubStudy() string {
+	if x != nil {
+		return x.SubStudy
+	}
+	return ""
+}
+
+func (m *UploadParticipantFileReq_Info) GetParticipant() isUploadParticipantFileReq_Info_Participant {
+	if m != nil {
+		return m.Participant
+	}
+	return nil
+}
+
+func (x *UploadParticipantFileReq_Info) GetProfileId() string {
+	if x, ok := x.GetParticipant().(*UploadParticipantFileReq_Info_ProfileId); ok {
+		return x.ProfileId
+	}
+	return ""
+}
+
+func (x *UploadParticipantFileReq_Info) GetParticipantId() string {
+	if x, ok := x.GetParticipant().(*UploadParticipantFileReq_Info_ParticipantId); ok {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *UploadParticipantFileReq_Info) GetFileType() *FileType {
+	if x != nil {
+		return x.FileType
+	}
+	return nil
+}
+
+func (x *UploadParticipantFileReq_Info) GetVisibleToParticipant() bool {
+	if x != nil {
+		return x.VisibleToParticipant
+	}
+	return false
+}
+
+type isUploadParticipantFileReq_Info_Participant interface {
+	isUploadParticipantFileReq_Info_Participant()
+}
+
+type UploadParticipantFileReq_Info_ProfileId struct {
+	ProfileId string `protobuf:"bytes,4,opt,name=profile_id,json=profileId,proto3,oneof"`
+}
+
+type UploadParticipantFileReq_Info_ParticipantId struct {
+	ParticipantId string `protobuf:"bytes,5,opt,name=participant_id,json=participantId,proto3,oneof"` // if researcher upload for participant directly
+}
+
+func (*UploadParticipantFileReq_Info_ProfileId) isUploadParticipantFileReq_Info_Participant() {}
+
+func (*UploadParticipantFileReq_Info_ParticipantId) isUploadParticipantFileReq_Info_Participant() {}
+
+type RunRulesForPreviousResponsesReq_ResponseFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SurveyKeys        []string `protobuf:"bytes,1,rep,name=survey_keys,json=surveyKeys,proto3" json:"survey_keys,omitempty"`
+	ParticipantIds    []string `protobuf:"bytes,2,rep,name=participant_ids,json=participantIds,proto3" json:"participant_ids,omitempty"`
+	ParticipantStatus []string `protobuf:"bytes,3,rep,name=participant_status,json=participantStatus,proto3" json:"participant_status,omitempty"`
+	From              int64    `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	Until             int64    `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) Reset() {
+	*x = RunRulesForPreviousResponsesReq_ResponseFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[85]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRulesForPreviousResponsesReq_ResponseFilter) ProtoMessage() {}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[85]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRulesForPreviousResponsesReq_ResponseFilter.ProtoReflect.Descriptor instead.
+func (*RunRulesForPreviousResponsesReq_ResponseFilter) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{55, 0}
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetSurveyKeys() []string {
+	if x != nil {
+		return x.SurveyKeys
+	}
+	return nil
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetParticipantIds() []string {
+	if x != nil {
+		return x.ParticipantIds
+	}
+	return nil
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetParticipantStatus() []string {
+	if x != nil {
+		return x.ParticipantStatus
+	}
+	return nil
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RunRulesForPreviousResponsesReq_ResponseFilter) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type ParticipantStateDiff_ValueChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	OldValue string `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue string `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+}
+
+func (x *ParticipantStateDiff_ValueChange) Reset() {
+	*x = ParticipantStateDiff_ValueChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[86]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParticipantStateDiff_ValueChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipantStateDiff_ValueChange) ProtoMessage() {}
+
+func (x *ParticipantStateDiff_ValueChange) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[86]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipantStateDiff_ValueChange.ProtoReflect.Descriptor instead.
+func (*ParticipantStateDiff_ValueChange) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{65, 0}
+}
+
+func (x *ParticipantStateDiff_ValueChange) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ParticipantStateDiff_ValueChange) GetOldValue() string {
+	if x != nil {
+		return x.OldValue
+	}
+	return ""
+}
+
+func (x *ParticipantStateDiff_ValueChange) GetNewValue() string {
+	if x != nil {
+		return x.NewValue
+	}
+	return ""
+}
+
+type SandboxEvaluationReq_Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type     string          `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Response *SurveyResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *SandboxEvaluationReq_Event) Reset() {
+	*x = SandboxEvaluationReq_Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[89]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SandboxEvaluationReq_Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxEvaluationReq_Event) ProtoMessage() {}
+
+func (x *SandboxEvaluationReq_Event) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[89]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxEvaluationReq_Event.ProtoReflect.Descriptor instead.
+func (*SandboxEvaluationReq_Event) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{71, 0}
+}
+
+func (x *SandboxEvaluationReq_Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SandboxEvaluationReq_Event) GetResponse() *SurveyResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type SandboxEvaluationResult_RuleError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuleIndex int32  `protobuf:"varint,1,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	Error     string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SandboxEvaluationResult_RuleError) Reset() {
+	*x = SandboxEvaluationResult_RuleError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[90]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SandboxEvaluationResult_RuleError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SandboxEvaluationResult_RuleError) ProtoMessage() {}
+
+func (x *SandboxEvaluationResult_RuleError) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[90]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SandboxEvaluationResult_RuleError.ProtoReflect.Descriptor instead.
+func (*SandboxEvaluationResult_RuleError) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{72, 0}
+}
+
+func (x *SandboxEvaluationResult_RuleError) GetRuleIndex() int32 {
+	if x != nil {
+		return x.RuleIndex
+	}
+	return 0
+}
+
+func (x *SandboxEvaluationResult_RuleError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RandomisationAllocationCounts_Count struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stratum string `protobuf:"bytes,1,opt,name=stratum,proto3" json:"stratum,omitempty"`
+	Arm     string `protobuf:"bytes,2,opt,name=arm,proto3" json:"arm,omitempty"`
+	Count   int64  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *RandomisationAllocationCounts_Count) Reset() {
+	*x = RandomisationAllocationCounts_Count{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[91]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RandomisationAllocationCounts_Count) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RandomisationAllocationCounts_Count) ProtoMessage() {}
+
+func (x *RandomisationAllocationCounts_Count) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[91]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RandomisationAllocationCounts_Count.ProtoReflect.Descriptor instead.
+func (*RandomisationAllocationCounts_Count) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{75, 0}
+}
+
+func (x *RandomisationAllocationCounts_Count) GetStratum() string {
+	if x != nil {
+		return x.Stratum
+	}
+	return ""
+}
+
+func (x *RandomisationAllocationCounts_Count) GetArm() string {
+	if x != nil {
+		return x.Arm
+	}
+	return ""
+}
+
+func (x *RandomisationAllocationCounts_Count) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type CustomEventResult_ParticipantError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParticipantId string `protobuf:"bytes,1,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *CustomEventResult_ParticipantError) Reset() {
+	*x = CustomEventResult_ParticipantError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[93]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CustomEventResult_ParticipantError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomEventResult_ParticipantError) ProtoMessage() {}
+
+func (x *CustomEventResult_ParticipantError) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[93]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomEventResult_ParticipantError.ProtoReflect.Descriptor instead.
+func (*CustomEventResult_ParticipantError) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{77, 0}
+}
+
+func (x *CustomEventResult_ParticipantError) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *CustomEventResult_ParticipantError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RulesWhatIfResult_ResponseResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParticipantId          string                `protobuf:"bytes,1,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	ResponseId             string                `protobuf:"bytes,2,opt,name=response_id,json=responseId,proto3" json:"response_id,omitempty"`
+	SurveyKey              string                `protobuf:"bytes,3,opt,name=survey_key,json=surveyKey,proto3" json:"survey_key,omitempty"`
+	SubmittedAt            int64                 `protobuf:"varint,4,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	OriginalRulesVersionId string                `protobuf:"bytes,5,opt,name=original_rules_version_id,json=originalRulesVersionId,proto3" json:"original_rules_version_id,omitempty"`
+	OriginalChanges        *ParticipantStateDiff `protobuf:"bytes,6,opt,name=original_changes,json=originalChanges,proto3" json:"original_changes,omitempty"`
+	WhatIfChanges          *ParticipantStateDiff `protobuf:"bytes,7,opt,name=what_if_changes,json=whatIfChanges,proto3" json:"what_if_changes,omitempty"`
+}
+
+func (x *RulesWhatIfResult_ResponseResult) Reset() {
+	*x = RulesWhatIfResult_ResponseResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[94]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RulesWhatIfResult_ResponseResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RulesWhatIfResult_ResponseResult) ProtoMessage() {}
+
+func (x *RulesWhatIfResult_ResponseResult) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[94]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RulesWhatIfResult_ResponseResult.ProtoReflect.Descriptor instead.
+func (*RulesWhatIfResult_ResponseResult) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{79, 0}
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetResponseId() string {
+	if x != nil {
+		return x.ResponseId
+	}
+	return ""
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetSurveyKey() string {
+	if x != nil {
+		return x.SurveyKey
+	}
+	return ""
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetSubmittedAt() int64 {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return 0
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetOriginalRulesVersionId() string {
+	if x != nil {
+		return x.OriginalRulesVersionId
+	}
+	return ""
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetOriginalChanges() *ParticipantStateDiff {
+	if x != nil {
+		return x.OriginalChanges
+	}
+	return nil
+}
+
+func (x *RulesWhatIfResult_ResponseResult) GetWhatIfChanges() *ParticipantStateDiff {
+	if x != nil {
+		return x.WhatIfChanges
+	}
+	return nil
+}
+
+type RulesWhatIfResult_Summary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResponseCount int32 `protobuf:"varint,1,opt,name=response_count,json=responseCount,proto3" json:"response_count,omitempty"`
+	ChangedCount  int32 `protobuf:"varint,2,opt,name=changed_count,json=changedCount,proto3" json:"changed_count,omitempty"`
+	ErrorCount    int32 `protobuf:"varint,3,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	Duration      int64 `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// how the results differ from the original evaluation of the submissions
+	Limitations string `protobuf:"bytes,5,opt,name=limitations,proto3" json:"limitations,omitempty"`
+}
+
+func (x *RulesWhatIfResult_Summary) Reset() {
+	*x = RulesWhatIfResult_Summary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_study_service_study_service_proto_msgTypes[95]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RulesWhatIfResult_Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RulesWhatIfResult_Summary) ProtoMessage() {}
+
+func (x *RulesWhatIfResult_Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_study_service_study_service_proto_msgTypes[95]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RulesWhatIfResult_Summary.ProtoReflect.Descriptor instead.
+func (*RulesWhatIfResult_Summary) Descriptor() ([]byte, []int) {
+	return file_study_service_study_service_proto_rawDescGZIP(), []int{79, 1}
+}
+
+func (x *RulesWhatIfResult_Summary) GetResponseCount() int32 {
+	if x != nil {
+		return x.ResponseCount
+	}
+	return 0
+}
+
+func (x *RulesWhatIfResult_Summary) GetChangedCount() int32 {
+	if x != nil {
+		return x.ChangedCount
+	}
+	return 0
+}
+
+func (x *RulesWhatIfResult_Summary) GetErrorCount() int32 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+func (x *RulesWhatIfResult_Summary) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RulesWhatIfResult_Summary) GetLimitations() string {
+	if x != nil {
+		return x.Limitations
+	}
+	return ""
+}
+
+var File_study_service_study_service_proto protoreflect.FileDescriptor
+
+var file_study_service_study_service_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a,
+	0x16, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x69, 0x6e, 0x66,
+	0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1c, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1a, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f,
 	0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x73, 0x74,
 	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x65, 0x78, 0x70, 0x72,
 	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x23, 0x73, 0x74,
@@ -5150,7 +6891,7 @@ var file_study_service_study_service_proto_rawDesc = []byte{
 	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a,
 	0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x79, 0x2e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52,
-	0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x22, 0xa1, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64,
+	0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x22, 0xc2, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64,
 	0x79, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
 	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
 	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54,
@@ -5160,21 +6901,25 @@ var file_study_service_study_service_proto_rawDesc = []byte{
 	0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69,
 	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
 	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xda, 0x01, 0x0a, 0x1f,
-	0x52, 0x75, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x69, 0x6e, 0x67, 0x6c,
-	0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12,
-	0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
-	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68,
-	0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f,
-	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79,
-	0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
-	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72,
-	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x3c, 0x0a, 0x05, 0x72, 0x75,
-	0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6e, 0x66, 0x6c,
-	0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xcd, 0x03, 0x0a, 0x1f, 0x52, 0x75, 0x6e,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0xf9, 0x01, 0x0a,
+	0x1f, 0x52, 0x75, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x46, 0x6f, 0x72, 0x53, 0x69, 0x6e, 0x67,
+	0x6c, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x3c, 0x0a, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6e, 0x66,
+	0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x74,
+	0x68, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x77,
+	0x69, 0x74, 0x68, 0x54, 0x72, 0x61, 0x63, 0x65, 0x22, 0xcd, 0x03, 0x0a, 0x1f, 0x52, 0x75, 0x6e,
 	0x52, 0x75, 0x6c, 0x65, 0x73, 0x46, 0x6f, 0x72, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x05,
 	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e,
@@ -5222,7 +6967,7 @@ var file_study_service_study_service_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e,
 	0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
 	0x2e, 0x53, 0x74, 0x75, 0x64, 0x79, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x73, 0x52, 0x05, 0x70, 0x72,
-	0x6f, 0x70, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6e, 0x53,
+	0x6f, 0x70, 0x73, 0x22, 0xe5, 0x01, 0x0a, 0x0e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6e, 0x53,
 	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
 	0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x05, 0x52, 0x10, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x43, 0x6f,
@@ -5232,68 +6977,446 @@ var file_study_service_study_service_proto_rawDesc = []byte{
 	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
 	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
 	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xe6, 0x01, 0x0a, 0x19, 0x43, 0x6f,
-	0x6e, 0x76, 0x65, 0x72, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
-	0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x05, 0x74, 0x72, 0x61,
+	0x63, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x22, 0xe6, 0x01, 0x0a, 0x19,
+	0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x18,
+	0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16,
+	0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x22, 0x5a, 0x0a, 0x1a, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x54, 0x65, 0x6d, 0x70, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79,
+	0x22, 0x79, 0x0a, 0x1f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x54, 0x65, 0x6d, 0x70,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x18, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79,
+	0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xa6, 0x01, 0x0a, 0x2c,
+	0x47, 0x65, 0x74, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65,
+	0x79, 0x73, 0x46, 0x6f, 0x72, 0x54, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x18, 0x74, 0x65,
+	0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x74, 0x65,
+	0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
+	0x6e, 0x74, 0x49, 0x64, 0x22, 0xfe, 0x01, 0x0a, 0x1a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x73,
+	0x12, 0x44, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e,
+	0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x61, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x48,
+	0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x75, 0x72, 0x76, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0x8a, 0x0b, 0x0a, 0x14, 0x50, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66,
+	0x66, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x5f, 0x0a, 0x0c, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74,
+	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0b, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x70, 0x0a, 0x15, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x13, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x75, 0x64, 0x79, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x61, 0x0a, 0x0b, 0x61,
+	0x64, 0x64, 0x65, 0x64, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x40, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69,
+	0x66, 0x66, 0x2e, 0x41, 0x64, 0x64, 0x65, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0a, 0x61, 0x64, 0x64, 0x65, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x61,
+	0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a,
+	0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x46, 0x6c, 0x61, 0x67,
+	0x73, 0x12, 0x67, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x66, 0x6c, 0x61,
+	0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x4f, 0x0a, 0x0d, 0x61, 0x64,
+	0x64, 0x65, 0x64, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x52, 0x0c, 0x61,
+	0x64, 0x64, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x73, 0x12, 0x53, 0x0a, 0x0f, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79,
+	0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x73,
+	0x12, 0x55, 0x0a, 0x0e, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x61, 0x64, 0x64, 0x65, 0x64, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2e, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x4e, 0x0a, 0x11, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x5f, 0x74, 0x6f,
+	0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
+	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x54, 0x6f, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x12, 0x60, 0x0a, 0x16, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x14,
+	0x61, 0x64, 0x64, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x64, 0x0a, 0x18, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e,
+	0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x16, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x59, 0x0a, 0x0b, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6f,
+	0x6c, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6f, 0x6c, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x65, 0x77,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x3d, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x65, 0x64, 0x46, 0x6c,
+	0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3f, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x46,
+	0x6c, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc3, 0x01, 0x0a, 0x10, 0x52, 0x75, 0x6c, 0x65, 0x44, 0x72,
+	0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x5d, 0x0a, 0x10, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x66, 0x66, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x48, 0x00, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x44, 0x69, 0x66, 0x66, 0x12, 0x46, 0x0a, 0x07, 0x73, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66,
+	0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6e, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x48, 0x00, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x86, 0x02, 0x0a, 0x0e,
+	0x52, 0x75, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a,
+	0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65,
+	0x70, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61,
+	0x72, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x55, 0x0a,
+	0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x22, 0xa4, 0x02, 0x0a, 0x10, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d,
+	0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x75,
+	0x6c, 0x65, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x55, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e,
+	0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x0c, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0xc5, 0x01, 0x0a, 0x1c,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e,
+	0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79,
+	0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75, 0x6e,
+	0x74, 0x69, 0x6c, 0x22, 0xc3, 0x01, 0x0a, 0x15, 0x53, 0x74, 0x75, 0x64, 0x79, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65,
+	0x79, 0x12, 0x56, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6e, 0x66, 0x6c,
+	0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x79, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65,
+	0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0xb8, 0x04, 0x0a, 0x14, 0x53, 0x61,
+	0x6e, 0x64, 0x62, 0x6f, 0x78, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75,
+	0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63,
+	0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x59, 0x0a,
+	0x11, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x10, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x4c, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65,
+	0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x45, 0x76, 0x61, 0x6c,
+	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52,
+	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a,
+	0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x6f, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x03, 0x6e, 0x6f, 0x77, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x74,
+	0x72, 0x61, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x77, 0x69, 0x74, 0x68,
+	0x54, 0x72, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x1a,
+	0x63, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x46, 0x0a, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x75, 0x72, 0x76,
+	0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0xfb, 0x04, 0x0a, 0x17, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x59, 0x0a, 0x11, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x69, 0x6e,
+	0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
+	0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x10, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x55, 0x0a, 0x0d, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x44, 0x69, 0x66, 0x66, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x73, 0x12, 0x4e, 0x0a, 0x11, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x5f, 0x74, 0x6f,
+	0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e,
+	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
+	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x54, 0x6f, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x12, 0x59, 0x0a, 0x13, 0x72, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x65, 0x72,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
+	0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x75,
+	0x64, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x12, 0x72, 0x65, 0x73, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x55, 0x0a,
+	0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e,
+	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
+	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x61, 0x6e, 0x64, 0x62,
+	0x6f, 0x78, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x12, 0x40, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e,
+	0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x1a, 0x40, 0x0a, 0x09, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1d, 0x0a,
+	0x0a, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0xbb, 0x01, 0x0a, 0x16, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65,
+	0x79, 0x12, 0x4d, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x35, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x74, 0x75, 0x64, 0x79, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65,
+	0x22, 0x97, 0x01, 0x0a, 0x22, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e,
 	0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b,
 	0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b,
 	0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
-	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x18, 0x74, 0x65,
-	0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
-	0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x74, 0x65,
-	0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
-	0x6e, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x22, 0x5a, 0x0a, 0x1a, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x54, 0x65,
-	0x6d, 0x70, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71,
-	0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49,
-	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x22, 0x79,
-	0x0a, 0x1f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x54, 0x65, 0x6d, 0x70, 0x50, 0x61,
-	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x38, 0x0a, 0x18, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70,
-	0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x16, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61,
-	0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xa6, 0x01, 0x0a, 0x2c, 0x47, 0x65,
-	0x74, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x73,
-	0x46, 0x6f, 0x72, 0x54, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x72, 0x74,
-	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
-	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73,
+	0x09, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x4b, 0x65, 0x79, 0x22, 0xc3, 0x01, 0x0a, 0x1d, 0x52,
+	0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x57, 0x0a, 0x06,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3f, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x49, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x72, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x72, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0xe0, 0x02, 0x0a, 0x0e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e,
+	0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69,
+	0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x51, 0x0a,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x37,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x2e, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x1a, 0x61, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x3b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x25, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46,
+	0x6c, 0x61, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xe9, 0x01, 0x0a, 0x11, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x56, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e,
+	0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x4f,
+	0x0a, 0x10, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0x82, 0x02, 0x0a, 0x10, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e,
+	0x65, 0x74, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49,
+	0x6e, 0x66, 0x6f, 0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73,
 	0x74, 0x75, 0x64, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x18, 0x74, 0x65, 0x6d, 0x70,
-	0x6f, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
-	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x74, 0x65, 0x6d, 0x70,
-	0x6f, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74,
-	0x49, 0x64, 0x22, 0xfe, 0x01, 0x0a, 0x1a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
-	0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x49, 0x6e, 0x66, 0x6f,
-	0x73, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x75, 0x64,
-	0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x75,
-	0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69,
-	0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e,
-	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x73, 0x12, 0x44,
-	0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
-	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x45,
-	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x74,
-	0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6c,
-	0x74, 0x65, 0x72, 0x22, 0x61, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x48, 0x0a, 0x09,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x2a, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73,
-	0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x75, 0x72,
-	0x76, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x32, 0xc2, 0x3a, 0x0a, 0x0f, 0x53, 0x74, 0x75, 0x64, 0x79,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x75, 0x6c, 0x65,
+	0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a,
+	0x0e, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
+	0x6e, 0x74, 0x49, 0x64, 0x22, 0x9f, 0x06, 0x0a, 0x11, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x57, 0x68,
+	0x61, 0x74, 0x49, 0x66, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x67, 0x0a, 0x0f, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e,
+	0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x48, 0x00, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x51, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x48, 0x00, 0x52, 0x07, 0x73,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x1a, 0x8c, 0x03, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x72, 0x76, 0x65, 0x79, 0x4b, 0x65, 0x79,
+	0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x19, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x5b,
+	0x0a, 0x10, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x0f, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x58, 0x0a, 0x0f, 0x77,
+	0x68, 0x61, 0x74, 0x5f, 0x69, 0x66, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x69, 0x66, 0x66, 0x52, 0x0d, 0x77, 0x68, 0x61, 0x74, 0x49, 0x66, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x1a, 0xb4, 0x01, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x08, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x32, 0xec, 0x44, 0x0a, 0x0f, 0x53, 0x74, 0x75, 0x64, 0x79,
 	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x41, 0x70, 0x69, 0x12, 0x4b, 0x0a, 0x06, 0x53, 0x74,
 	0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x29, 0x2e, 0x69,
@@ -5761,11 +7884,93 @@ var file_study_service_study_service_proto_rawDesc = []byte{
 	0x66, 0x6f, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x2c, 0x2e,
 	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
 	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x75, 0x72, 0x76, 0x65,
-	0x79, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x2f, 0x5a, 0x2d, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65,
-	0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x79, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x6b, 0x0a, 0x0e, 0x52,
+	0x75, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x29, 0x2e,
+	0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75,
+	0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x79,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x2c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x12, 0x89, 0x01, 0x0a, 0x1d, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x38, 0x2e, 0x69, 0x6e, 0x66,
+	0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70,
+	0x61, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x1a, 0x2c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x30, 0x01, 0x12, 0x6e, 0x0a, 0x16, 0x53, 0x61, 0x76, 0x65, 0x53, 0x74, 0x75, 0x64,
+	0x79, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x31,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x75, 0x64,
+	0x79, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x74, 0x75, 0x64, 0x79, 0x12, 0x7f, 0x0a, 0x16, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x12, 0x30,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x61, 0x6e, 0x64,
+	0x62, 0x6f, 0x78, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x1a, 0x33, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x61,
+	0x6e, 0x64, 0x62, 0x6f, 0x78, 0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x70, 0x0a, 0x17, 0x53, 0x61, 0x76, 0x65, 0x52, 0x61, 0x6e,
+	0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65,
+	0x12, 0x32, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x61,
+	0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x65, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61,
+	0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x79, 0x12, 0x9d, 0x01, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x52,
+	0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x3e, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x39, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x69, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x6e, 0x0a, 0x11, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x2e, 0x69,
+	0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x2d, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75,
+	0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x76, 0x0a, 0x15, 0x52, 0x75, 0x6e, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66,
+	0x12, 0x2c, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x2d,
+	0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74,
+	0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x75, 0x6c, 0x65,
+	0x73, 0x57, 0x68, 0x61, 0x74, 0x49, 0x66, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x12,
+	0x6b, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x50,
+	0x61, 0x72, 0x71, 0x75, 0x65, 0x74, 0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e,
+	0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65,
+	0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x69, 0x0a, 0x11,
+	0x47, 0x65, 0x74, 0x53, 0x75, 0x72, 0x76, 0x65, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x62, 0x6f, 0x6f,
+	0x6b, 0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43,
+	0x6f, 0x64, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65,
+	0x74, 0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x6c, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x53, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x46, 0x69, 0x6c,
+	0x65, 0x12, 0x2d, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2e, 0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x79, 0x6e, 0x74, 0x61, 0x78, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74, 0x2e,
+	0x73, 0x74, 0x75, 0x64, 0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x30, 0x01, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x65, 0x6e, 0x7a, 0x61, 0x6e, 0x65, 0x74,
+	0x2f, 0x73, 0x74, 0x75, 0x64, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70,
+	0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -5781,7 +7986,7 @@ func file_study_service_study_service_proto_rawDescGZIP() []byte {
 }
 
 var file_study_service_study_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_study_service_study_service_proto_msgTypes = make([]protoimpl.MessageInfo, 71)
+var file_study_service_study_service_proto_msgTypes = make([]protoimpl.MessageInfo, 96)
 var file_study_service_study_service_proto_goTypes = []interface{}{
 	(ServiceStatus_StatusValue)(0),                         // 0: influenzanet.study_service.ServiceStatus.StatusValue
 	(*StudiesForUser)(nil),                                 // 1: influenzanet.study_service.StudiesForUser
@@ -5849,235 +8054,334 @@ var file_study_service_study_service_proto_goTypes = []interface{}{
 	(*GetAssignedSurveysForTemporaryParticipantReq)(nil),   // 63: influenzanet.study_service.GetAssignedSurveysForTemporaryParticipantReq
 	(*ConfidentialResponsesQuery)(nil),                     // 64: influenzanet.study_service.ConfidentialResponsesQuery
 	(*ConfidentialResponses)(nil),                          // 65: influenzanet.study_service.ConfidentialResponses
-	(*UploadParticipantFileReq_Info)(nil),                  // 66: influenzanet.study_service.UploadParticipantFileReq.Info
-	nil,                                                    // 67: influenzanet.study_service.SurveyResponseQuery.ContextQueryEntry
-	nil,                                                    // 68: influenzanet.study_service.GetPStatesWithPaginationQuery.SortByEntry
-	nil,                                                    // 69: influenzanet.study_service.StudyResponseStatistics.SurveyResponseCountsEntry
-	nil,                                                    // 70: influenzanet.study_service.StudyMessage.PayloadEntry
-	(*RunRulesForPreviousResponsesReq_ResponseFilter)(nil), // 71: influenzanet.study_service.RunRulesForPreviousResponsesReq.ResponseFilter
-	(*StudyForUser)(nil),                                   // 72: influenzanet.study_service.StudyForUser
-	(*api_types.TokenInfos)(nil),                           // 73: influenzanet.shared.TokenInfos
-	(*ParticipantState)(nil),                               // 74: influenzanet.study_service.ParticipantState
-	(*ExpressionArg)(nil),                                  // 75: influenzanet.study_service.ExpressionArg
-	(*Study)(nil),                                          // 76: influenzanet.study_service.Study
-	(*Survey)(nil),                                         // 77: influenzanet.study_service.Survey
-	(*SurveyContext)(nil),                                  // 78: influenzanet.study_service.SurveyContext
-	(*SurveyResponse)(nil),                                 // 79: influenzanet.study_service.SurveyResponse
-	(*SurveyInfo)(nil),                                     // 80: influenzanet.study_service.SurveyInfo
-	(*Report)(nil),                                         // 81: influenzanet.study_service.Report
-	(*Study_Member)(nil),                                   // 82: influenzanet.study_service.Study.Member
-	(*Expression)(nil),                                     // 83: influenzanet.study_service.Expression
-	(*Study_Props)(nil),                                    // 84: influenzanet.study_service.Study.Props
-	(*emptypb.Empty)(nil),                                  // 85: google.protobuf.Empty
-	(*ResponseExportQuery)(nil),                            // 86: influenzanet.study_service.ResponseExportQuery
-	(*SurveyInfoExportQuery)(nil),                          // 87: influenzanet.study_service.SurveyInfoExportQuery
-	(*AssignedSurveys)(nil),                                // 88: influenzanet.study_service.AssignedSurveys
-	(*Chunk)(nil),                                          // 89: influenzanet.study_service.Chunk
-	(*StudyRules)(nil),                                     // 90: influenzanet.study_service.StudyRules
-	(*StudyRulesHistory)(nil),                              // 91: influenzanet.study_service.StudyRulesHistory
-	(*SurveyInfoExport)(nil),                               // 92: influenzanet.study_service.SurveyInfoExport
+	(*ParticipantStateDiff)(nil),                           // 66: influenzanet.study_service.ParticipantStateDiff
+	(*RuleDryRunResult)(nil),                               // 67: influenzanet.study_service.RuleDryRunResult
+	(*RuleTraceEntry)(nil),                                 // 68: influenzanet.study_service.RuleTraceEntry
+	(*ParticipantEvent)(nil),                               // 69: influenzanet.study_service.ParticipantEvent
+	(*ParticipantEventHistoryQuery)(nil),                   // 70: influenzanet.study_service.ParticipantEventHistoryQuery
+	(*StudyTimerScheduleReq)(nil),                          // 71: influenzanet.study_service.StudyTimerScheduleReq
+	(*SandboxEvaluationReq)(nil),                           // 72: influenzanet.study_service.SandboxEvaluationReq
+	(*SandboxEvaluationResult)(nil),                        // 73: influenzanet.study_service.SandboxEvaluationResult
+	(*RandomisationSchemeReq)(nil),                         // 74: influenzanet.study_service.RandomisationSchemeReq
+	(*RandomisationAllocationCountsQuery)(nil),             // 75: influenzanet.study_service.RandomisationAllocationCountsQuery
+	(*RandomisationAllocationCounts)(nil),                  // 76: influenzanet.study_service.RandomisationAllocationCounts
+	(*CustomEventReq)(nil),                                 // 77: influenzanet.study_service.CustomEventReq
+	(*CustomEventResult)(nil),                              // 78: influenzanet.study_service.CustomEventResult
+	(*RulesWhatIfQuery)(nil),                               // 79: influenzanet.study_service.RulesWhatIfQuery
+	(*RulesWhatIfResult)(nil),                              // 80: influenzanet.study_service.RulesWhatIfResult
+	(*UploadParticipantFileReq_Info)(nil),                  // 81: influenzanet.study_service.UploadParticipantFileReq.Info
+	nil,                                                    // 82: influenzanet.study_service.SurveyResponseQuery.ContextQueryEntry
+	nil,                                                    // 83: influenzanet.study_service.GetPStatesWithPaginationQuery.SortByEntry
+	nil,                                                    // 84: influenzanet.study_service.StudyResponseStatistics.SurveyResponseCountsEntry
+	nil,                                                    // 85: influenzanet.study_service.StudyMessage.PayloadEntry
+	(*RunRulesForPreviousResponsesReq_ResponseFilter)(nil), // 86: influenzanet.study_service.RunRulesForPreviousResponsesReq.ResponseFilter
+	(*ParticipantStateDiff_ValueChange)(nil),               // 87: influenzanet.study_service.ParticipantStateDiff.ValueChange
+	nil,                                                    // 88: influenzanet.study_service.ParticipantStateDiff.AddedFlagsEntry
+	nil,                                                    // 89: influenzanet.study_service.ParticipantStateDiff.RemovedFlagsEntry
+	(*SandboxEvaluationReq_Event)(nil),                     // 90: influenzanet.study_service.SandboxEvaluationReq.Event
+	(*SandboxEvaluationResult_RuleError)(nil),              // 91: influenzanet.study_service.SandboxEvaluationResult.RuleError
+	(*RandomisationAllocationCounts_Count)(nil),            // 92: influenzanet.study_service.RandomisationAllocationCounts.Count
+	nil, // 93: influenzanet.study_service.CustomEventReq.PayloadEntry
+	(*CustomEventResult_ParticipantError)(nil), // 94: influenzanet.study_service.CustomEventResult.ParticipantError
+	(*RulesWhatIfResult_ResponseResult)(nil),   // 95: influenzanet.study_service.RulesWhatIfResult.ResponseResult
+	(*RulesWhatIfResult_Summary)(nil),          // 96: influenzanet.study_service.RulesWhatIfResult.Summary
+	(*StudyForUser)(nil),                       // 97: influenzanet.study_service.StudyForUser
+	(*api_types.TokenInfos)(nil),               // 98: influenzanet.shared.TokenInfos
+	(*ParticipantState)(nil),                   // 99: influenzanet.study_service.ParticipantState
+	(*ExpressionArg)(nil),                      // 100: influenzanet.study_service.ExpressionArg
+	(*Study)(nil),                              // 101: influenzanet.study_service.Study
+	(*Survey)(nil),                             // 102: influenzanet.study_service.Survey
+	(*SurveyContext)(nil),                      // 103: influenzanet.study_service.SurveyContext
+	(*SurveyResponse)(nil),                     // 104: influenzanet.study_service.SurveyResponse
+	(*SurveyInfo)(nil),                         // 105: influenzanet.study_service.SurveyInfo
+	(*Report)(nil),                             // 106: influenzanet.study_service.Report
+	(*Study_Member)(nil),                       // 107: influenzanet.study_service.Study.Member
+	(*Expression)(nil),                         // 108: influenzanet.study_service.Expression
+	(*Study_Props)(nil),                        // 109: influenzanet.study_service.Study.Props
+	(*AssignedSurvey)(nil),                     // 110: influenzanet.study_service.AssignedSurvey
+	(*ParticipantMessage)(nil),                 // 111: influenzanet.study_service.ParticipantMessage
+	(*ScheduledEvent)(nil),                     // 112: influenzanet.study_service.ScheduledEvent
+	(*Study_TimerSchedule)(nil),                // 113: influenzanet.study_service.Study.TimerSchedule
+	(*Study_RandomisationScheme)(nil),          // 114: influenzanet.study_service.Study.RandomisationScheme
+	(*FlagValue)(nil),                          // 115: influenzanet.study_service.FlagValue
+	(*emptypb.Empty)(nil),                      // 116: google.protobuf.Empty
+	(*ResponseExportQuery)(nil),                // 117: influenzanet.study_service.ResponseExportQuery
+	(*SurveyInfoExportQuery)(nil),              // 118: influenzanet.study_service.SurveyInfoExportQuery
+	(*CodebookExportQuery)(nil),                // 119: influenzanet.study_service.CodebookExportQuery
+	(*SyntaxExportQuery)(nil),                  // 120: influenzanet.study_service.SyntaxExportQuery
+	(*AssignedSurveys)(nil),                    // 121: influenzanet.study_service.AssignedSurveys
+	(*Chunk)(nil),                              // 122: influenzanet.study_service.Chunk
+	(*StudyRules)(nil),                         // 123: influenzanet.study_service.StudyRules
+	(*StudyRulesHistory)(nil),                  // 124: influenzanet.study_service.StudyRulesHistory
+	(*SurveyInfoExport)(nil),                   // 125: influenzanet.study_service.SurveyInfoExport
 }
 var file_study_service_study_service_proto_depIdxs = []int32{
-	72,  // 0: influenzanet.study_service.StudiesForUser.studies:type_name -> influenzanet.study_service.StudyForUser
-	66,  // 1: influenzanet.study_service.UploadParticipantFileReq.info:type_name -> influenzanet.study_service.UploadParticipantFileReq.Info
+	97,  // 0: influenzanet.study_service.StudiesForUser.studies:type_name -> influenzanet.study_service.StudyForUser
+	81,  // 1: influenzanet.study_service.UploadParticipantFileReq.info:type_name -> influenzanet.study_service.UploadParticipantFileReq.Info
 	5,   // 2: influenzanet.study_service.PaginatedFile.info:type_name -> influenzanet.study_service.PaginationInfo
-	73,  // 3: influenzanet.study_service.GetParticipantFileReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 3: influenzanet.study_service.GetParticipantFileReq.token:type_name -> influenzanet.shared.TokenInfos
 	7,   // 4: influenzanet.study_service.NotificationSubscriptions.subscriptions:type_name -> influenzanet.study_service.Subscription
-	73,  // 5: influenzanet.study_service.UpdateResearcherNotificationSubscriptionsReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 5: influenzanet.study_service.UpdateResearcherNotificationSubscriptionsReq.token:type_name -> influenzanet.shared.TokenInfos
 	7,   // 6: influenzanet.study_service.UpdateResearcherNotificationSubscriptionsReq.subscriptions:type_name -> influenzanet.study_service.Subscription
-	73,  // 7: influenzanet.study_service.GetResearcherNotificationSubscriptionsReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 8: influenzanet.study_service.DeleteParticipantFilesReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 7: influenzanet.study_service.GetResearcherNotificationSubscriptionsReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 8: influenzanet.study_service.DeleteParticipantFilesReq.token:type_name -> influenzanet.shared.TokenInfos
 	12,  // 9: influenzanet.study_service.FileInfo.referenced_in:type_name -> influenzanet.study_service.FileObjectReference
 	13,  // 10: influenzanet.study_service.FileInfos.file_infos:type_name -> influenzanet.study_service.FileInfo
-	73,  // 11: influenzanet.study_service.SurveyResponseQuery.token:type_name -> influenzanet.shared.TokenInfos
-	67,  // 12: influenzanet.study_service.SurveyResponseQuery.context_query:type_name -> influenzanet.study_service.SurveyResponseQuery.ContextQueryEntry
-	73,  // 13: influenzanet.study_service.ReportHistoryQuery.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 14: influenzanet.study_service.FileInfoQuery.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 15: influenzanet.study_service.ParticipantStateQuery.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 16: influenzanet.study_service.ParticipantStateByIDQuery.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 17: influenzanet.study_service.GetPStatesWithPaginationQuery.token:type_name -> influenzanet.shared.TokenInfos
-	68,  // 18: influenzanet.study_service.GetPStatesWithPaginationQuery.sort_by:type_name -> influenzanet.study_service.GetPStatesWithPaginationQuery.SortByEntry
-	74,  // 19: influenzanet.study_service.ParticipantStatesWithPagination.items:type_name -> influenzanet.study_service.ParticipantState
-	69,  // 20: influenzanet.study_service.StudyResponseStatistics.survey_response_counts:type_name -> influenzanet.study_service.StudyResponseStatistics.SurveyResponseCountsEntry
-	75,  // 21: influenzanet.study_service.ProfilesWithConditionReq.condition:type_name -> influenzanet.study_service.ExpressionArg
-	70,  // 22: influenzanet.study_service.StudyMessage.payload:type_name -> influenzanet.study_service.StudyMessage.PayloadEntry
+	98,  // 11: influenzanet.study_service.SurveyResponseQuery.token:type_name -> influenzanet.shared.TokenInfos
+	82,  // 12: influenzanet.study_service.SurveyResponseQuery.context_query:type_name -> influenzanet.study_service.SurveyResponseQuery.ContextQueryEntry
+	98,  // 13: influenzanet.study_service.ReportHistoryQuery.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 14: influenzanet.study_service.FileInfoQuery.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 15: influenzanet.study_service.ParticipantStateQuery.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 16: influenzanet.study_service.ParticipantStateByIDQuery.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 17: influenzanet.study_service.GetPStatesWithPaginationQuery.token:type_name -> influenzanet.shared.TokenInfos
+	83,  // 18: influenzanet.study_service.GetPStatesWithPaginationQuery.sort_by:type_name -> influenzanet.study_service.GetPStatesWithPaginationQuery.SortByEntry
+	99,  // 19: influenzanet.study_service.ParticipantStatesWithPagination.items:type_name -> influenzanet.study_service.ParticipantState
+	84,  // 20: influenzanet.study_service.StudyResponseStatistics.survey_response_counts:type_name -> influenzanet.study_service.StudyResponseStatistics.SurveyResponseCountsEntry
+	100, // 21: influenzanet.study_service.ProfilesWithConditionReq.condition:type_name -> influenzanet.study_service.ExpressionArg
+	85,  // 22: influenzanet.study_service.StudyMessage.payload:type_name -> influenzanet.study_service.StudyMessage.PayloadEntry
 	27,  // 23: influenzanet.study_service.StudyMessages.messages:type_name -> influenzanet.study_service.StudyMessage
 	0,   // 24: influenzanet.study_service.ServiceStatus.status:type_name -> influenzanet.study_service.ServiceStatus.StatusValue
-	73,  // 25: influenzanet.study_service.NewStudyRequest.token:type_name -> influenzanet.shared.TokenInfos
-	76,  // 26: influenzanet.study_service.NewStudyRequest.study:type_name -> influenzanet.study_service.Study
-	77,  // 27: influenzanet.study_service.SurveyAndContext.survey:type_name -> influenzanet.study_service.Survey
-	78,  // 28: influenzanet.study_service.SurveyAndContext.context:type_name -> influenzanet.study_service.SurveyContext
-	79,  // 29: influenzanet.study_service.SurveyAndContext.prefill:type_name -> influenzanet.study_service.SurveyResponse
-	73,  // 30: influenzanet.study_service.StudyReferenceReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 31: influenzanet.study_service.StudyRulesHistoryReq.token:type_name -> influenzanet.shared.TokenInfos
-	80,  // 32: influenzanet.study_service.SurveyInfoResp.infos:type_name -> influenzanet.study_service.SurveyInfo
-	73,  // 33: influenzanet.study_service.AddSurveyReq.token:type_name -> influenzanet.shared.TokenInfos
-	77,  // 34: influenzanet.study_service.AddSurveyReq.survey:type_name -> influenzanet.study_service.Survey
-	73,  // 35: influenzanet.study_service.SubmitResponseReq.token:type_name -> influenzanet.shared.TokenInfos
-	79,  // 36: influenzanet.study_service.SubmitResponseReq.response:type_name -> influenzanet.study_service.SurveyResponse
-	73,  // 37: influenzanet.study_service.EnterStudyRequest.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 38: influenzanet.study_service.LeaveStudyMsg.token:type_name -> influenzanet.shared.TokenInfos
-	77,  // 39: influenzanet.study_service.SurveyVersions.survey_versions:type_name -> influenzanet.study_service.Survey
-	73,  // 40: influenzanet.study_service.SurveyReferenceRequest.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 41: influenzanet.study_service.StudyRulesVersionReferenceReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 42: influenzanet.study_service.SurveyVersionReferenceRequest.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 43: influenzanet.study_service.GetSurveyKeysRequest.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 44: influenzanet.study_service.CreateReportReq.token:type_name -> influenzanet.shared.TokenInfos
-	81,  // 45: influenzanet.study_service.CreateReportReq.report:type_name -> influenzanet.study_service.Report
-	73,  // 46: influenzanet.study_service.GetReportsForUserReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 47: influenzanet.study_service.RemoveConfidentialResponsesForProfilesReq.token:type_name -> influenzanet.shared.TokenInfos
-	81,  // 48: influenzanet.study_service.ReportHistory.reports:type_name -> influenzanet.study_service.Report
-	73,  // 49: influenzanet.study_service.GetStudiesForUserReq.token:type_name -> influenzanet.shared.TokenInfos
-	76,  // 50: influenzanet.study_service.Studies.studies:type_name -> influenzanet.study_service.Study
-	73,  // 51: influenzanet.study_service.StudyMemberReq.token:type_name -> influenzanet.shared.TokenInfos
-	82,  // 52: influenzanet.study_service.StudyMemberReq.member:type_name -> influenzanet.study_service.Study.Member
-	73,  // 53: influenzanet.study_service.StudyRulesReq.token:type_name -> influenzanet.shared.TokenInfos
-	83,  // 54: influenzanet.study_service.StudyRulesReq.rules:type_name -> influenzanet.study_service.Expression
-	73,  // 55: influenzanet.study_service.RunRulesForSingleParticipantReq.token:type_name -> influenzanet.shared.TokenInfos
-	83,  // 56: influenzanet.study_service.RunRulesForSingleParticipantReq.rules:type_name -> influenzanet.study_service.Expression
-	73,  // 57: influenzanet.study_service.RunRulesForPreviousResponsesReq.token:type_name -> influenzanet.shared.TokenInfos
-	83,  // 58: influenzanet.study_service.RunRulesForPreviousResponsesReq.rules:type_name -> influenzanet.study_service.Expression
-	71,  // 59: influenzanet.study_service.RunRulesForPreviousResponsesReq.filter:type_name -> influenzanet.study_service.RunRulesForPreviousResponsesReq.ResponseFilter
-	73,  // 60: influenzanet.study_service.StudyStatusReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 61: influenzanet.study_service.StudyPropsReq.token:type_name -> influenzanet.shared.TokenInfos
-	84,  // 62: influenzanet.study_service.StudyPropsReq.props:type_name -> influenzanet.study_service.Study.Props
-	73,  // 63: influenzanet.study_service.ConvertTempParticipantReq.token:type_name -> influenzanet.shared.TokenInfos
-	73,  // 64: influenzanet.study_service.ConfidentialResponsesQuery.token:type_name -> influenzanet.shared.TokenInfos
-	83,  // 65: influenzanet.study_service.ConfidentialResponsesQuery.condition:type_name -> influenzanet.study_service.Expression
-	79,  // 66: influenzanet.study_service.ConfidentialResponses.responses:type_name -> influenzanet.study_service.SurveyResponse
-	73,  // 67: influenzanet.study_service.UploadParticipantFileReq.Info.token:type_name -> influenzanet.shared.TokenInfos
-	2,   // 68: influenzanet.study_service.UploadParticipantFileReq.Info.file_type:type_name -> influenzanet.study_service.FileType
-	85,  // 69: influenzanet.study_service.StudyServiceApi.Status:input_type -> google.protobuf.Empty
-	39,  // 70: influenzanet.study_service.StudyServiceApi.EnterStudy:input_type -> influenzanet.study_service.EnterStudyRequest
-	73,  // 71: influenzanet.study_service.StudyServiceApi.GetAssignedSurveys:input_type -> influenzanet.shared.TokenInfos
-	42,  // 72: influenzanet.study_service.StudyServiceApi.GetAssignedSurvey:input_type -> influenzanet.study_service.SurveyReferenceRequest
-	38,  // 73: influenzanet.study_service.StudyServiceApi.SubmitResponse:input_type -> influenzanet.study_service.SubmitResponseReq
-	40,  // 74: influenzanet.study_service.StudyServiceApi.LeaveStudy:input_type -> influenzanet.study_service.LeaveStudyMsg
-	73,  // 75: influenzanet.study_service.StudyServiceApi.ProfileDeleted:input_type -> influenzanet.shared.TokenInfos
-	73,  // 76: influenzanet.study_service.StudyServiceApi.DeleteParticipantData:input_type -> influenzanet.shared.TokenInfos
-	3,   // 77: influenzanet.study_service.StudyServiceApi.UploadParticipantFile:input_type -> influenzanet.study_service.UploadParticipantFileReq
-	11,  // 78: influenzanet.study_service.StudyServiceApi.DeleteParticipantFiles:input_type -> influenzanet.study_service.DeleteParticipantFilesReq
-	6,   // 79: influenzanet.study_service.StudyServiceApi.GetParticipantFile:input_type -> influenzanet.study_service.GetParticipantFileReq
-	61,  // 80: influenzanet.study_service.StudyServiceApi.RegisterTemporaryParticipant:input_type -> influenzanet.study_service.RegisterTempParticipantReq
-	60,  // 81: influenzanet.study_service.StudyServiceApi.ConvertTemporaryToParticipant:input_type -> influenzanet.study_service.ConvertTempParticipantReq
-	63,  // 82: influenzanet.study_service.StudyServiceApi.GetAssignedSurveysForTemporaryParticipant:input_type -> influenzanet.study_service.GetAssignedSurveysForTemporaryParticipantReq
-	47,  // 83: influenzanet.study_service.StudyServiceApi.CreateReport:input_type -> influenzanet.study_service.CreateReportReq
-	51,  // 84: influenzanet.study_service.StudyServiceApi.GetStudiesForUser:input_type -> influenzanet.study_service.GetStudiesForUserReq
-	73,  // 85: influenzanet.study_service.StudyServiceApi.GetActiveStudies:input_type -> influenzanet.shared.TokenInfos
-	34,  // 86: influenzanet.study_service.StudyServiceApi.GetStudySurveyInfos:input_type -> influenzanet.study_service.StudyReferenceReq
-	23,  // 87: influenzanet.study_service.StudyServiceApi.HasParticipantStateWithCondition:input_type -> influenzanet.study_service.ProfilesWithConditionReq
-	24,  // 88: influenzanet.study_service.StudyServiceApi.GetParticipantMessages:input_type -> influenzanet.study_service.GetParticipantMessagesReq
-	25,  // 89: influenzanet.study_service.StudyServiceApi.GetResearcherMessages:input_type -> influenzanet.study_service.GetReseacherMessagesReq
-	29,  // 90: influenzanet.study_service.StudyServiceApi.DeleteMessagesFromParticipant:input_type -> influenzanet.study_service.DeleteMessagesFromParticipantReq
-	30,  // 91: influenzanet.study_service.StudyServiceApi.DeleteResearcherMessages:input_type -> influenzanet.study_service.DeleteResearcherMessagesReq
-	48,  // 92: influenzanet.study_service.StudyServiceApi.GetReportsForUser:input_type -> influenzanet.study_service.GetReportsForUserReq
-	49,  // 93: influenzanet.study_service.StudyServiceApi.RemoveConfidentialResponsesForProfiles:input_type -> influenzanet.study_service.RemoveConfidentialResponsesForProfilesReq
-	32,  // 94: influenzanet.study_service.StudyServiceApi.CreateNewStudy:input_type -> influenzanet.study_service.NewStudyRequest
-	73,  // 95: influenzanet.study_service.StudyServiceApi.GetAllStudies:input_type -> influenzanet.shared.TokenInfos
-	34,  // 96: influenzanet.study_service.StudyServiceApi.GetStudy:input_type -> influenzanet.study_service.StudyReferenceReq
-	53,  // 97: influenzanet.study_service.StudyServiceApi.SaveStudyMember:input_type -> influenzanet.study_service.StudyMemberReq
-	53,  // 98: influenzanet.study_service.StudyServiceApi.RemoveStudyMember:input_type -> influenzanet.study_service.StudyMemberReq
-	10,  // 99: influenzanet.study_service.StudyServiceApi.GetResearcherNotificationSubscriptions:input_type -> influenzanet.study_service.GetResearcherNotificationSubscriptionsReq
-	9,   // 100: influenzanet.study_service.StudyServiceApi.UpdateResearcherNotificationSubscriptions:input_type -> influenzanet.study_service.UpdateResearcherNotificationSubscriptionsReq
-	26,  // 101: influenzanet.study_service.StudyServiceApi.GetStudiesWithPendingParticipantMessages:input_type -> influenzanet.study_service.GetStudiesWithPendingParticipantMessagesReq
-	57,  // 102: influenzanet.study_service.StudyServiceApi.SaveStudyStatus:input_type -> influenzanet.study_service.StudyStatusReq
-	58,  // 103: influenzanet.study_service.StudyServiceApi.SaveStudyProps:input_type -> influenzanet.study_service.StudyPropsReq
-	54,  // 104: influenzanet.study_service.StudyServiceApi.SaveStudyRules:input_type -> influenzanet.study_service.StudyRulesReq
-	34,  // 105: influenzanet.study_service.StudyServiceApi.GetCurrentStudyRules:input_type -> influenzanet.study_service.StudyReferenceReq
-	35,  // 106: influenzanet.study_service.StudyServiceApi.GetStudyRulesHistory:input_type -> influenzanet.study_service.StudyRulesHistoryReq
-	43,  // 107: influenzanet.study_service.StudyServiceApi.RemoveStudyRulesVersion:input_type -> influenzanet.study_service.StudyRulesVersionReferenceReq
-	37,  // 108: influenzanet.study_service.StudyServiceApi.SaveSurveyToStudy:input_type -> influenzanet.study_service.AddSurveyReq
-	42,  // 109: influenzanet.study_service.StudyServiceApi.GetSurveyVersionInfos:input_type -> influenzanet.study_service.SurveyReferenceRequest
-	45,  // 110: influenzanet.study_service.StudyServiceApi.GetSurveyKeys:input_type -> influenzanet.study_service.GetSurveyKeysRequest
-	44,  // 111: influenzanet.study_service.StudyServiceApi.GetSurveyDefForStudy:input_type -> influenzanet.study_service.SurveyVersionReferenceRequest
-	44,  // 112: influenzanet.study_service.StudyServiceApi.RemoveSurveyVersion:input_type -> influenzanet.study_service.SurveyVersionReferenceRequest
-	42,  // 113: influenzanet.study_service.StudyServiceApi.UnpublishSurvey:input_type -> influenzanet.study_service.SurveyReferenceRequest
-	34,  // 114: influenzanet.study_service.StudyServiceApi.DeleteStudy:input_type -> influenzanet.study_service.StudyReferenceReq
-	54,  // 115: influenzanet.study_service.StudyServiceApi.RunRules:input_type -> influenzanet.study_service.StudyRulesReq
-	55,  // 116: influenzanet.study_service.StudyServiceApi.RunRulesForSingleParticipant:input_type -> influenzanet.study_service.RunRulesForSingleParticipantReq
-	56,  // 117: influenzanet.study_service.StudyServiceApi.RunRulesForPreviousResponses:input_type -> influenzanet.study_service.RunRulesForPreviousResponsesReq
-	15,  // 118: influenzanet.study_service.StudyServiceApi.GetStudyResponseStatistics:input_type -> influenzanet.study_service.SurveyResponseQuery
-	15,  // 119: influenzanet.study_service.StudyServiceApi.StreamStudyResponses:input_type -> influenzanet.study_service.SurveyResponseQuery
-	18,  // 120: influenzanet.study_service.StudyServiceApi.StreamParticipantStates:input_type -> influenzanet.study_service.ParticipantStateQuery
-	20,  // 121: influenzanet.study_service.StudyServiceApi.GetParticipantStatesWithPagination:input_type -> influenzanet.study_service.GetPStatesWithPaginationQuery
-	19,  // 122: influenzanet.study_service.StudyServiceApi.GetParticipantStateByID:input_type -> influenzanet.study_service.ParticipantStateByIDQuery
-	16,  // 123: influenzanet.study_service.StudyServiceApi.StreamReportHistory:input_type -> influenzanet.study_service.ReportHistoryQuery
-	17,  // 124: influenzanet.study_service.StudyServiceApi.StreamParticipantFileInfos:input_type -> influenzanet.study_service.FileInfoQuery
-	64,  // 125: influenzanet.study_service.StudyServiceApi.GetConfidentialResponses:input_type -> influenzanet.study_service.ConfidentialResponsesQuery
-	86,  // 126: influenzanet.study_service.StudyServiceApi.GetResponsesWideFormatCSV:input_type -> influenzanet.study_service.ResponseExportQuery
-	86,  // 127: influenzanet.study_service.StudyServiceApi.GetResponsesLongFormatCSV:input_type -> influenzanet.study_service.ResponseExportQuery
-	86,  // 128: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSON:input_type -> influenzanet.study_service.ResponseExportQuery
-	86,  // 129: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSONWithPagination:input_type -> influenzanet.study_service.ResponseExportQuery
-	87,  // 130: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreviewCSV:input_type -> influenzanet.study_service.SurveyInfoExportQuery
-	87,  // 131: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreview:input_type -> influenzanet.study_service.SurveyInfoExportQuery
-	31,  // 132: influenzanet.study_service.StudyServiceApi.Status:output_type -> influenzanet.study_service.ServiceStatus
-	88,  // 133: influenzanet.study_service.StudyServiceApi.EnterStudy:output_type -> influenzanet.study_service.AssignedSurveys
-	88,  // 134: influenzanet.study_service.StudyServiceApi.GetAssignedSurveys:output_type -> influenzanet.study_service.AssignedSurveys
-	33,  // 135: influenzanet.study_service.StudyServiceApi.GetAssignedSurvey:output_type -> influenzanet.study_service.SurveyAndContext
-	88,  // 136: influenzanet.study_service.StudyServiceApi.SubmitResponse:output_type -> influenzanet.study_service.AssignedSurveys
-	88,  // 137: influenzanet.study_service.StudyServiceApi.LeaveStudy:output_type -> influenzanet.study_service.AssignedSurveys
-	31,  // 138: influenzanet.study_service.StudyServiceApi.ProfileDeleted:output_type -> influenzanet.study_service.ServiceStatus
-	31,  // 139: influenzanet.study_service.StudyServiceApi.DeleteParticipantData:output_type -> influenzanet.study_service.ServiceStatus
-	13,  // 140: influenzanet.study_service.StudyServiceApi.UploadParticipantFile:output_type -> influenzanet.study_service.FileInfo
-	31,  // 141: influenzanet.study_service.StudyServiceApi.DeleteParticipantFiles:output_type -> influenzanet.study_service.ServiceStatus
-	89,  // 142: influenzanet.study_service.StudyServiceApi.GetParticipantFile:output_type -> influenzanet.study_service.Chunk
-	62,  // 143: influenzanet.study_service.StudyServiceApi.RegisterTemporaryParticipant:output_type -> influenzanet.study_service.RegisterTempParticipantResponse
-	31,  // 144: influenzanet.study_service.StudyServiceApi.ConvertTemporaryToParticipant:output_type -> influenzanet.study_service.ServiceStatus
-	88,  // 145: influenzanet.study_service.StudyServiceApi.GetAssignedSurveysForTemporaryParticipant:output_type -> influenzanet.study_service.AssignedSurveys
-	31,  // 146: influenzanet.study_service.StudyServiceApi.CreateReport:output_type -> influenzanet.study_service.ServiceStatus
-	1,   // 147: influenzanet.study_service.StudyServiceApi.GetStudiesForUser:output_type -> influenzanet.study_service.StudiesForUser
-	52,  // 148: influenzanet.study_service.StudyServiceApi.GetActiveStudies:output_type -> influenzanet.study_service.Studies
-	36,  // 149: influenzanet.study_service.StudyServiceApi.GetStudySurveyInfos:output_type -> influenzanet.study_service.SurveyInfoResp
-	31,  // 150: influenzanet.study_service.StudyServiceApi.HasParticipantStateWithCondition:output_type -> influenzanet.study_service.ServiceStatus
-	28,  // 151: influenzanet.study_service.StudyServiceApi.GetParticipantMessages:output_type -> influenzanet.study_service.StudyMessages
-	28,  // 152: influenzanet.study_service.StudyServiceApi.GetResearcherMessages:output_type -> influenzanet.study_service.StudyMessages
-	31,  // 153: influenzanet.study_service.StudyServiceApi.DeleteMessagesFromParticipant:output_type -> influenzanet.study_service.ServiceStatus
-	31,  // 154: influenzanet.study_service.StudyServiceApi.DeleteResearcherMessages:output_type -> influenzanet.study_service.ServiceStatus
-	50,  // 155: influenzanet.study_service.StudyServiceApi.GetReportsForUser:output_type -> influenzanet.study_service.ReportHistory
-	31,  // 156: influenzanet.study_service.StudyServiceApi.RemoveConfidentialResponsesForProfiles:output_type -> influenzanet.study_service.ServiceStatus
-	76,  // 157: influenzanet.study_service.StudyServiceApi.CreateNewStudy:output_type -> influenzanet.study_service.Study
-	52,  // 158: influenzanet.study_service.StudyServiceApi.GetAllStudies:output_type -> influenzanet.study_service.Studies
-	76,  // 159: influenzanet.study_service.StudyServiceApi.GetStudy:output_type -> influenzanet.study_service.Study
-	76,  // 160: influenzanet.study_service.StudyServiceApi.SaveStudyMember:output_type -> influenzanet.study_service.Study
-	76,  // 161: influenzanet.study_service.StudyServiceApi.RemoveStudyMember:output_type -> influenzanet.study_service.Study
-	8,   // 162: influenzanet.study_service.StudyServiceApi.GetResearcherNotificationSubscriptions:output_type -> influenzanet.study_service.NotificationSubscriptions
-	8,   // 163: influenzanet.study_service.StudyServiceApi.UpdateResearcherNotificationSubscriptions:output_type -> influenzanet.study_service.NotificationSubscriptions
-	52,  // 164: influenzanet.study_service.StudyServiceApi.GetStudiesWithPendingParticipantMessages:output_type -> influenzanet.study_service.Studies
-	76,  // 165: influenzanet.study_service.StudyServiceApi.SaveStudyStatus:output_type -> influenzanet.study_service.Study
-	76,  // 166: influenzanet.study_service.StudyServiceApi.SaveStudyProps:output_type -> influenzanet.study_service.Study
-	76,  // 167: influenzanet.study_service.StudyServiceApi.SaveStudyRules:output_type -> influenzanet.study_service.Study
-	90,  // 168: influenzanet.study_service.StudyServiceApi.GetCurrentStudyRules:output_type -> influenzanet.study_service.StudyRules
-	91,  // 169: influenzanet.study_service.StudyServiceApi.GetStudyRulesHistory:output_type -> influenzanet.study_service.StudyRulesHistory
-	31,  // 170: influenzanet.study_service.StudyServiceApi.RemoveStudyRulesVersion:output_type -> influenzanet.study_service.ServiceStatus
-	77,  // 171: influenzanet.study_service.StudyServiceApi.SaveSurveyToStudy:output_type -> influenzanet.study_service.Survey
-	41,  // 172: influenzanet.study_service.StudyServiceApi.GetSurveyVersionInfos:output_type -> influenzanet.study_service.SurveyVersions
-	46,  // 173: influenzanet.study_service.StudyServiceApi.GetSurveyKeys:output_type -> influenzanet.study_service.SurveyKeys
-	77,  // 174: influenzanet.study_service.StudyServiceApi.GetSurveyDefForStudy:output_type -> influenzanet.study_service.Survey
-	31,  // 175: influenzanet.study_service.StudyServiceApi.RemoveSurveyVersion:output_type -> influenzanet.study_service.ServiceStatus
-	31,  // 176: influenzanet.study_service.StudyServiceApi.UnpublishSurvey:output_type -> influenzanet.study_service.ServiceStatus
-	31,  // 177: influenzanet.study_service.StudyServiceApi.DeleteStudy:output_type -> influenzanet.study_service.ServiceStatus
-	59,  // 178: influenzanet.study_service.StudyServiceApi.RunRules:output_type -> influenzanet.study_service.RuleRunSummary
-	59,  // 179: influenzanet.study_service.StudyServiceApi.RunRulesForSingleParticipant:output_type -> influenzanet.study_service.RuleRunSummary
-	59,  // 180: influenzanet.study_service.StudyServiceApi.RunRulesForPreviousResponses:output_type -> influenzanet.study_service.RuleRunSummary
-	22,  // 181: influenzanet.study_service.StudyServiceApi.GetStudyResponseStatistics:output_type -> influenzanet.study_service.StudyResponseStatistics
-	79,  // 182: influenzanet.study_service.StudyServiceApi.StreamStudyResponses:output_type -> influenzanet.study_service.SurveyResponse
-	74,  // 183: influenzanet.study_service.StudyServiceApi.StreamParticipantStates:output_type -> influenzanet.study_service.ParticipantState
-	21,  // 184: influenzanet.study_service.StudyServiceApi.GetParticipantStatesWithPagination:output_type -> influenzanet.study_service.ParticipantStatesWithPagination
-	74,  // 185: influenzanet.study_service.StudyServiceApi.GetParticipantStateByID:output_type -> influenzanet.study_service.ParticipantState
-	81,  // 186: influenzanet.study_service.StudyServiceApi.StreamReportHistory:output_type -> influenzanet.study_service.Report
-	13,  // 187: influenzanet.study_service.StudyServiceApi.StreamParticipantFileInfos:output_type -> influenzanet.study_service.FileInfo
-	65,  // 188: influenzanet.study_service.StudyServiceApi.GetConfidentialResponses:output_type -> influenzanet.study_service.ConfidentialResponses
-	89,  // 189: influenzanet.study_service.StudyServiceApi.GetResponsesWideFormatCSV:output_type -> influenzanet.study_service.Chunk
-	89,  // 190: influenzanet.study_service.StudyServiceApi.GetResponsesLongFormatCSV:output_type -> influenzanet.study_service.Chunk
-	89,  // 191: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSON:output_type -> influenzanet.study_service.Chunk
-	4,   // 192: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSONWithPagination:output_type -> influenzanet.study_service.PaginatedFile
-	89,  // 193: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreviewCSV:output_type -> influenzanet.study_service.Chunk
-	92,  // 194: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreview:output_type -> influenzanet.study_service.SurveyInfoExport
-	132, // [132:195] is the sub-list for method output_type
-	69,  // [69:132] is the sub-list for method input_type
-	69,  // [69:69] is the sub-list for extension type_name
-	69,  // [69:69] is the sub-list for extension extendee
-	0,   // [0:69] is the sub-list for field type_name
+	98,  // 25: influenzanet.study_service.NewStudyRequest.token:type_name -> influenzanet.shared.TokenInfos
+	101, // 26: influenzanet.study_service.NewStudyRequest.study:type_name -> influenzanet.study_service.Study
+	102, // 27: influenzanet.study_service.SurveyAndContext.survey:type_name -> influenzanet.study_service.Survey
+	103, // 28: influenzanet.study_service.SurveyAndContext.context:type_name -> influenzanet.study_service.SurveyContext
+	104, // 29: influenzanet.study_service.SurveyAndContext.prefill:type_name -> influenzanet.study_service.SurveyResponse
+	98,  // 30: influenzanet.study_service.StudyReferenceReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 31: influenzanet.study_service.StudyRulesHistoryReq.token:type_name -> influenzanet.shared.TokenInfos
+	105, // 32: influenzanet.study_service.SurveyInfoResp.infos:type_name -> influenzanet.study_service.SurveyInfo
+	98,  // 33: influenzanet.study_service.AddSurveyReq.token:type_name -> influenzanet.shared.TokenInfos
+	102, // 34: influenzanet.study_service.AddSurveyReq.survey:type_name -> influenzanet.study_service.Survey
+	98,  // 35: influenzanet.study_service.SubmitResponseReq.token:type_name -> influenzanet.shared.TokenInfos
+	104, // 36: influenzanet.study_service.SubmitResponseReq.response:type_name -> influenzanet.study_service.SurveyResponse
+	98,  // 37: influenzanet.study_service.EnterStudyRequest.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 38: influenzanet.study_service.LeaveStudyMsg.token:type_name -> influenzanet.shared.TokenInfos
+	102, // 39: influenzanet.study_service.SurveyVersions.survey_versions:type_name -> influenzanet.study_service.Survey
+	98,  // 40: influenzanet.study_service.SurveyReferenceRequest.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 41: influenzanet.study_service.StudyRulesVersionReferenceReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 42: influenzanet.study_service.SurveyVersionReferenceRequest.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 43: influenzanet.study_service.GetSurveyKeysRequest.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 44: influenzanet.study_service.CreateReportReq.token:type_name -> influenzanet.shared.TokenInfos
+	106, // 45: influenzanet.study_service.CreateReportReq.report:type_name -> influenzanet.study_service.Report
+	98,  // 46: influenzanet.study_service.GetReportsForUserReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 47: influenzanet.study_service.RemoveConfidentialResponsesForProfilesReq.token:type_name -> influenzanet.shared.TokenInfos
+	106, // 48: influenzanet.study_service.ReportHistory.reports:type_name -> influenzanet.study_service.Report
+	98,  // 49: influenzanet.study_service.GetStudiesForUserReq.token:type_name -> influenzanet.shared.TokenInfos
+	101, // 50: influenzanet.study_service.Studies.studies:type_name -> influenzanet.study_service.Study
+	98,  // 51: influenzanet.study_service.StudyMemberReq.token:type_name -> influenzanet.shared.TokenInfos
+	107, // 52: influenzanet.study_service.StudyMemberReq.member:type_name -> influenzanet.study_service.Study.Member
+	98,  // 53: influenzanet.study_service.StudyRulesReq.token:type_name -> influenzanet.shared.TokenInfos
+	108, // 54: influenzanet.study_service.StudyRulesReq.rules:type_name -> influenzanet.study_service.Expression
+	98,  // 55: influenzanet.study_service.RunRulesForSingleParticipantReq.token:type_name -> influenzanet.shared.TokenInfos
+	108, // 56: influenzanet.study_service.RunRulesForSingleParticipantReq.rules:type_name -> influenzanet.study_service.Expression
+	98,  // 57: influenzanet.study_service.RunRulesForPreviousResponsesReq.token:type_name -> influenzanet.shared.TokenInfos
+	108, // 58: influenzanet.study_service.RunRulesForPreviousResponsesReq.rules:type_name -> influenzanet.study_service.Expression
+	86,  // 59: influenzanet.study_service.RunRulesForPreviousResponsesReq.filter:type_name -> influenzanet.study_service.RunRulesForPreviousResponsesReq.ResponseFilter
+	98,  // 60: influenzanet.study_service.StudyStatusReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 61: influenzanet.study_service.StudyPropsReq.token:type_name -> influenzanet.shared.TokenInfos
+	109, // 62: influenzanet.study_service.StudyPropsReq.props:type_name -> influenzanet.study_service.Study.Props
+	68,  // 63: influenzanet.study_service.RuleRunSummary.trace:type_name -> influenzanet.study_service.RuleTraceEntry
+	98,  // 64: influenzanet.study_service.ConvertTempParticipantReq.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 65: influenzanet.study_service.ConfidentialResponsesQuery.token:type_name -> influenzanet.shared.TokenInfos
+	108, // 66: influenzanet.study_service.ConfidentialResponsesQuery.condition:type_name -> influenzanet.study_service.Expression
+	104, // 67: influenzanet.study_service.ConfidentialResponses.responses:type_name -> influenzanet.study_service.SurveyResponse
+	87,  // 68: influenzanet.study_service.ParticipantStateDiff.study_status:type_name -> influenzanet.study_service.ParticipantStateDiff.ValueChange
+	87,  // 69: influenzanet.study_service.ParticipantStateDiff.current_study_session:type_name -> influenzanet.study_service.ParticipantStateDiff.ValueChange
+	88,  // 70: influenzanet.study_service.ParticipantStateDiff.added_flags:type_name -> influenzanet.study_service.ParticipantStateDiff.AddedFlagsEntry
+	87,  // 71: influenzanet.study_service.ParticipantStateDiff.updated_flags:type_name -> influenzanet.study_service.ParticipantStateDiff.ValueChange
+	89,  // 72: influenzanet.study_service.ParticipantStateDiff.removed_flags:type_name -> influenzanet.study_service.ParticipantStateDiff.RemovedFlagsEntry
+	110, // 73: influenzanet.study_service.ParticipantStateDiff.added_surveys:type_name -> influenzanet.study_service.AssignedSurvey
+	110, // 74: influenzanet.study_service.ParticipantStateDiff.removed_surveys:type_name -> influenzanet.study_service.AssignedSurvey
+	111, // 75: influenzanet.study_service.ParticipantStateDiff.added_messages:type_name -> influenzanet.study_service.ParticipantMessage
+	111, // 76: influenzanet.study_service.ParticipantStateDiff.removed_messages:type_name -> influenzanet.study_service.ParticipantMessage
+	106, // 77: influenzanet.study_service.ParticipantStateDiff.reports_to_create:type_name -> influenzanet.study_service.Report
+	112, // 78: influenzanet.study_service.ParticipantStateDiff.added_scheduled_events:type_name -> influenzanet.study_service.ScheduledEvent
+	112, // 79: influenzanet.study_service.ParticipantStateDiff.removed_scheduled_events:type_name -> influenzanet.study_service.ScheduledEvent
+	66,  // 80: influenzanet.study_service.RuleDryRunResult.participant_diff:type_name -> influenzanet.study_service.ParticipantStateDiff
+	59,  // 81: influenzanet.study_service.RuleDryRunResult.summary:type_name -> influenzanet.study_service.RuleRunSummary
+	66,  // 82: influenzanet.study_service.RuleTraceEntry.state_changes:type_name -> influenzanet.study_service.ParticipantStateDiff
+	66,  // 83: influenzanet.study_service.ParticipantEvent.state_changes:type_name -> influenzanet.study_service.ParticipantStateDiff
+	98,  // 84: influenzanet.study_service.ParticipantEventHistoryQuery.token:type_name -> influenzanet.shared.TokenInfos
+	98,  // 85: influenzanet.study_service.StudyTimerScheduleReq.token:type_name -> influenzanet.shared.TokenInfos
+	113, // 86: influenzanet.study_service.StudyTimerScheduleReq.timer_schedule:type_name -> influenzanet.study_service.Study.TimerSchedule
+	98,  // 87: influenzanet.study_service.SandboxEvaluationReq.token:type_name -> influenzanet.shared.TokenInfos
+	99,  // 88: influenzanet.study_service.SandboxEvaluationReq.participant_state:type_name -> influenzanet.study_service.ParticipantState
+	90,  // 89: influenzanet.study_service.SandboxEvaluationReq.event:type_name -> influenzanet.study_service.SandboxEvaluationReq.Event
+	108, // 90: influenzanet.study_service.SandboxEvaluationReq.rules:type_name -> influenzanet.study_service.Expression
+	99,  // 91: influenzanet.study_service.SandboxEvaluationResult.participant_state:type_name -> influenzanet.study_service.ParticipantState
+	66,  // 92: influenzanet.study_service.SandboxEvaluationResult.state_changes:type_name -> influenzanet.study_service.ParticipantStateDiff
+	106, // 93: influenzanet.study_service.SandboxEvaluationResult.reports_to_create:type_name -> influenzanet.study_service.Report
+	27,  // 94: influenzanet.study_service.SandboxEvaluationResult.researcher_messages:type_name -> influenzanet.study_service.StudyMessage
+	91,  // 95: influenzanet.study_service.SandboxEvaluationResult.errors:type_name -> influenzanet.study_service.SandboxEvaluationResult.RuleError
+	68,  // 96: influenzanet.study_service.SandboxEvaluationResult.trace:type_name -> influenzanet.study_service.RuleTraceEntry
+	98,  // 97: influenzanet.study_service.RandomisationSchemeReq.token:type_name -> influenzanet.shared.TokenInfos
+	114, // 98: influenzanet.study_service.RandomisationSchemeReq.scheme:type_name -> influenzanet.study_service.Study.RandomisationScheme
+	98,  // 99: influenzanet.study_service.RandomisationAllocationCountsQuery.token:type_name -> influenzanet.shared.TokenInfos
+	92,  // 100: influenzanet.study_service.RandomisationAllocationCounts.counts:type_name -> influenzanet.study_service.RandomisationAllocationCounts.Count
+	98,  // 101: influenzanet.study_service.CustomEventReq.token:type_name -> influenzanet.shared.TokenInfos
+	93,  // 102: influenzanet.study_service.CustomEventReq.payload:type_name -> influenzanet.study_service.CustomEventReq.PayloadEntry
+	94,  // 103: influenzanet.study_service.CustomEventResult.errors:type_name -> influenzanet.study_service.CustomEventResult.ParticipantError
+	98,  // 104: influenzanet.study_service.RulesWhatIfQuery.token:type_name -> influenzanet.shared.TokenInfos
+	95,  // 105: influenzanet.study_service.RulesWhatIfResult.response_result:type_name -> influenzanet.study_service.RulesWhatIfResult.ResponseResult
+	96,  // 106: influenzanet.study_service.RulesWhatIfResult.summary:type_name -> influenzanet.study_service.RulesWhatIfResult.Summary
+	98,  // 107: influenzanet.study_service.UploadParticipantFileReq.Info.token:type_name -> influenzanet.shared.TokenInfos
+	2,   // 108: influenzanet.study_service.UploadParticipantFileReq.Info.file_type:type_name -> influenzanet.study_service.FileType
+	104, // 109: influenzanet.study_service.SandboxEvaluationReq.Event.response:type_name -> influenzanet.study_service.SurveyResponse
+	115, // 110: influenzanet.study_service.CustomEventReq.PayloadEntry.value:type_name -> influenzanet.study_service.FlagValue
+	66,  // 111: influenzanet.study_service.RulesWhatIfResult.ResponseResult.original_changes:type_name -> influenzanet.study_service.ParticipantStateDiff
+	66,  // 112: influenzanet.study_service.RulesWhatIfResult.ResponseResult.what_if_changes:type_name -> influenzanet.study_service.ParticipantStateDiff
+	116, // 113: influenzanet.study_service.StudyServiceApi.Status:input_type -> google.protobuf.Empty
+	39,  // 114: influenzanet.study_service.StudyServiceApi.EnterStudy:input_type -> influenzanet.study_service.EnterStudyRequest
+	98,  // 115: influenzanet.study_service.StudyServiceApi.GetAssignedSurveys:input_type -> influenzanet.shared.TokenInfos
+	42,  // 116: influenzanet.study_service.StudyServiceApi.GetAssignedSurvey:input_type -> influenzanet.study_service.SurveyReferenceRequest
+	38,  // 117: influenzanet.study_service.StudyServiceApi.SubmitResponse:input_type -> influenzanet.study_service.SubmitResponseReq
+	40,  // 118: influenzanet.study_service.StudyServiceApi.LeaveStudy:input_type -> influenzanet.study_service.LeaveStudyMsg
+	98,  // 119: influenzanet.study_service.StudyServiceApi.ProfileDeleted:input_type -> influenzanet.shared.TokenInfos
+	98,  // 120: influenzanet.study_service.StudyServiceApi.DeleteParticipantData:input_type -> influenzanet.shared.TokenInfos
+	3,   // 121: influenzanet.study_service.StudyServiceApi.UploadParticipantFile:input_type -> influenzanet.study_service.UploadParticipantFileReq
+	11,  // 122: influenzanet.study_service.StudyServiceApi.DeleteParticipantFiles:input_type -> influenzanet.study_service.DeleteParticipantFilesReq
+	6,   // 123: influenzanet.study_service.StudyServiceApi.GetParticipantFile:input_type -> influenzanet.study_service.GetParticipantFileReq
+	61,  // 124: influenzanet.study_service.StudyServiceApi.RegisterTemporaryParticipant:input_type -> influenzanet.study_service.RegisterTempParticipantReq
+	60,  // 125: influenzanet.study_service.StudyServiceApi.ConvertTemporaryToParticipant:input_type -> influenzanet.study_service.ConvertTempParticipantReq
+	63,  // 126: influenzanet.study_service.StudyServiceApi.GetAssignedSurveysForTemporaryParticipant:input_type -> influenzanet.study_service.GetAssignedSurveysForTemporaryParticipantReq
+	47,  // 127: influenzanet.study_service.StudyServiceApi.CreateReport:input_type -> influenzanet.study_service.CreateReportReq
+	51,  // 128: influenzanet.study_service.StudyServiceApi.GetStudiesForUser:input_type -> influenzanet.study_service.GetStudiesForUserReq
+	98,  // 129: influenzanet.study_service.StudyServiceApi.GetActiveStudies:input_type -> influenzanet.shared.TokenInfos
+	34,  // 130: influenzanet.study_service.StudyServiceApi.GetStudySurveyInfos:input_type -> influenzanet.study_service.StudyReferenceReq
+	23,  // 131: influenzanet.study_service.StudyServiceApi.HasParticipantStateWithCondition:input_type -> influenzanet.study_service.ProfilesWithConditionReq
+	24,  // 132: influenzanet.study_service.StudyServiceApi.GetParticipantMessages:input_type -> influenzanet.study_service.GetParticipantMessagesReq
+	25,  // 133: influenzanet.study_service.StudyServiceApi.GetResearcherMessages:input_type -> influenzanet.study_service.GetReseacherMessagesReq
+	29,  // 134: influenzanet.study_service.StudyServiceApi.DeleteMessagesFromParticipant:input_type -> influenzanet.study_service.DeleteMessagesFromParticipantReq
+	30,  // 135: influenzanet.study_service.StudyServiceApi.DeleteResearcherMessages:input_type -> influenzanet.study_service.DeleteResearcherMessagesReq
+	48,  // 136: influenzanet.study_service.StudyServiceApi.GetReportsForUser:input_type -> influenzanet.study_service.GetReportsForUserReq
+	49,  // 137: influenzanet.study_service.StudyServiceApi.RemoveConfidentialResponsesForProfiles:input_type -> influenzanet.study_service.RemoveConfidentialResponsesForProfilesReq
+	32,  // 138: influenzanet.study_service.StudyServiceApi.CreateNewStudy:input_type -> influenzanet.study_service.NewStudyRequest
+	98,  // 139: influenzanet.study_service.StudyServiceApi.GetAllStudies:input_type -> influenzanet.shared.TokenInfos
+	34,  // 140: influenzanet.study_service.StudyServiceApi.GetStudy:input_type -> influenzanet.study_service.StudyReferenceReq
+	53,  // 141: influenzanet.study_service.StudyServiceApi.SaveStudyMember:input_type -> influenzanet.study_service.StudyMemberReq
+	53,  // 142: influenzanet.study_service.StudyServiceApi.RemoveStudyMember:input_type -> influenzanet.study_service.StudyMemberReq
+	10,  // 143: influenzanet.study_service.StudyServiceApi.GetResearcherNotificationSubscriptions:input_type -> influenzanet.study_service.GetResearcherNotificationSubscriptionsReq
+	9,   // 144: influenzanet.study_service.StudyServiceApi.UpdateResearcherNotificationSubscriptions:input_type -> influenzanet.study_service.UpdateResearcherNotificationSubscriptionsReq
+	26,  // 145: influenzanet.study_service.StudyServiceApi.GetStudiesWithPendingParticipantMessages:input_type -> influenzanet.study_service.GetStudiesWithPendingParticipantMessagesReq
+	57,  // 146: influenzanet.study_service.StudyServiceApi.SaveStudyStatus:input_type -> influenzanet.study_service.StudyStatusReq
+	58,  // 147: influenzanet.study_service.StudyServiceApi.SaveStudyProps:input_type -> influenzanet.study_service.StudyPropsReq
+	54,  // 148: influenzanet.study_service.StudyServiceApi.SaveStudyRules:input_type -> influenzanet.study_service.StudyRulesReq
+	34,  // 149: influenzanet.study_service.StudyServiceApi.GetCurrentStudyRules:input_type -> influenzanet.study_service.StudyReferenceReq
+	35,  // 150: influenzanet.study_service.StudyServiceApi.GetStudyRulesHistory:input_type -> influenzanet.study_service.StudyRulesHistoryReq
+	43,  // 151: influenzanet.study_service.StudyServiceApi.RemoveStudyRulesVersion:input_type -> influenzanet.study_service.StudyRulesVersionReferenceReq
+	37,  // 152: influenzanet.study_service.StudyServiceApi.SaveSurveyToStudy:input_type -> influenzanet.study_service.AddSurveyReq
+	42,  // 153: influenzanet.study_service.StudyServiceApi.GetSurveyVersionInfos:input_type -> influenzanet.study_service.SurveyReferenceRequest
+	45,  // 154: influenzanet.study_service.StudyServiceApi.GetSurveyKeys:input_type -> influenzanet.study_service.GetSurveyKeysRequest
+	44,  // 155: influenzanet.study_service.StudyServiceApi.GetSurveyDefForStudy:input_type -> influenzanet.study_service.SurveyVersionReferenceRequest
+	44,  // 156: influenzanet.study_service.StudyServiceApi.RemoveSurveyVersion:input_type -> influenzanet.study_service.SurveyVersionReferenceRequest
+	42,  // 157: influenzanet.study_service.StudyServiceApi.UnpublishSurvey:input_type -> influenzanet.study_service.SurveyReferenceRequest
+	34,  // 158: influenzanet.study_service.StudyServiceApi.DeleteStudy:input_type -> influenzanet.study_service.StudyReferenceReq
+	54,  // 159: influenzanet.study_service.StudyServiceApi.RunRules:input_type -> influenzanet.study_service.StudyRulesReq
+	55,  // 160: influenzanet.study_service.StudyServiceApi.RunRulesForSingleParticipant:input_type -> influenzanet.study_service.RunRulesForSingleParticipantReq
+	56,  // 161: influenzanet.study_service.StudyServiceApi.RunRulesForPreviousResponses:input_type -> influenzanet.study_service.RunRulesForPreviousResponsesReq
+	15,  // 162: influenzanet.study_service.StudyServiceApi.GetStudyResponseStatistics:input_type -> influenzanet.study_service.SurveyResponseQuery
+	15,  // 163: influenzanet.study_service.StudyServiceApi.StreamStudyResponses:input_type -> influenzanet.study_service.SurveyResponseQuery
+	18,  // 164: influenzanet.study_service.StudyServiceApi.StreamParticipantStates:input_type -> influenzanet.study_service.ParticipantStateQuery
+	20,  // 165: influenzanet.study_service.StudyServiceApi.GetParticipantStatesWithPagination:input_type -> influenzanet.study_service.GetPStatesWithPaginationQuery
+	19,  // 166: influenzanet.study_service.StudyServiceApi.GetParticipantStateByID:input_type -> influenzanet.study_service.ParticipantStateByIDQuery
+	16,  // 167: influenzanet.study_service.StudyServiceApi.StreamReportHistory:input_type -> influenzanet.study_service.ReportHistoryQuery
+	17,  // 168: influenzanet.study_service.StudyServiceApi.StreamParticipantFileInfos:input_type -> influenzanet.study_service.FileInfoQuery
+	64,  // 169: influenzanet.study_service.StudyServiceApi.GetConfidentialResponses:input_type -> influenzanet.study_service.ConfidentialResponsesQuery
+	117, // 170: influenzanet.study_service.StudyServiceApi.GetResponsesWideFormatCSV:input_type -> influenzanet.study_service.ResponseExportQuery
+	117, // 171: influenzanet.study_service.StudyServiceApi.GetResponsesLongFormatCSV:input_type -> influenzanet.study_service.ResponseExportQuery
+	117, // 172: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSON:input_type -> influenzanet.study_service.ResponseExportQuery
+	117, // 173: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSONWithPagination:input_type -> influenzanet.study_service.ResponseExportQuery
+	118, // 174: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreviewCSV:input_type -> influenzanet.study_service.SurveyInfoExportQuery
+	118, // 175: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreview:input_type -> influenzanet.study_service.SurveyInfoExportQuery
+	54,  // 176: influenzanet.study_service.StudyServiceApi.RunRulesDryRun:input_type -> influenzanet.study_service.StudyRulesReq
+	70,  // 177: influenzanet.study_service.StudyServiceApi.StreamParticipantEventHistory:input_type -> influenzanet.study_service.ParticipantEventHistoryQuery
+	71,  // 178: influenzanet.study_service.StudyServiceApi.SaveStudyTimerSchedule:input_type -> influenzanet.study_service.StudyTimerScheduleReq
+	72,  // 179: influenzanet.study_service.StudyServiceApi.EvaluateRulesInSandbox:input_type -> influenzanet.study_service.SandboxEvaluationReq
+	74,  // 180: influenzanet.study_service.StudyServiceApi.SaveRandomisationScheme:input_type -> influenzanet.study_service.RandomisationSchemeReq
+	75,  // 181: influenzanet.study_service.StudyServiceApi.GetRandomisationAllocationCounts:input_type -> influenzanet.study_service.RandomisationAllocationCountsQuery
+	77,  // 182: influenzanet.study_service.StudyServiceApi.SubmitCustomEvent:input_type -> influenzanet.study_service.CustomEventReq
+	79,  // 183: influenzanet.study_service.StudyServiceApi.RunRulesVersionWhatIf:input_type -> influenzanet.study_service.RulesWhatIfQuery
+	117, // 184: influenzanet.study_service.StudyServiceApi.GetResponsesParquet:input_type -> influenzanet.study_service.ResponseExportQuery
+	119, // 185: influenzanet.study_service.StudyServiceApi.GetSurveyCodebook:input_type -> influenzanet.study_service.CodebookExportQuery
+	120, // 186: influenzanet.study_service.StudyServiceApi.GetResponsesSyntaxFile:input_type -> influenzanet.study_service.SyntaxExportQuery
+	31,  // 187: influenzanet.study_service.StudyServiceApi.Status:output_type -> influenzanet.study_service.ServiceStatus
+	121, // 188: influenzanet.study_service.StudyServiceApi.EnterStudy:output_type -> influenzanet.study_service.AssignedSurveys
+	121, // 189: influenzanet.study_service.StudyServiceApi.GetAssignedSurveys:output_type -> influenzanet.study_service.AssignedSurveys
+	33,  // 190: influenzanet.study_service.StudyServiceApi.GetAssignedSurvey:output_type -> influenzanet.study_service.SurveyAndContext
+	121, // 191: influenzanet.study_service.StudyServiceApi.SubmitResponse:output_type -> influenzanet.study_service.AssignedSurveys
+	121, // 192: influenzanet.study_service.StudyServiceApi.LeaveStudy:output_type -> influenzanet.study_service.AssignedSurveys
+	31,  // 193: influenzanet.study_service.StudyServiceApi.ProfileDeleted:output_type -> influenzanet.study_service.ServiceStatus
+	31,  // 194: influenzanet.study_service.StudyServiceApi.DeleteParticipantData:output_type -> influenzanet.study_service.ServiceStatus
+	13,  // 195: influenzanet.study_service.StudyServiceApi.UploadParticipantFile:output_type -> influenzanet.study_service.FileInfo
+	31,  // 196: influenzanet.study_service.StudyServiceApi.DeleteParticipantFiles:output_type -> influenzanet.study_service.ServiceStatus
+	122, // 197: influenzanet.study_service.StudyServiceApi.GetParticipantFile:output_type -> influenzanet.study_service.Chunk
+	62,  // 198: influenzanet.study_service.StudyServiceApi.RegisterTemporaryParticipant:output_type -> influenzanet.study_service.RegisterTempParticipantResponse
+	31,  // 199: influenzanet.study_service.StudyServiceApi.ConvertTemporaryToParticipant:output_type -> influenzanet.study_service.ServiceStatus
+	121, // 200: influenzanet.study_service.StudyServiceApi.GetAssignedSurveysForTemporaryParticipant:output_type -> influenzanet.study_service.AssignedSurveys
+	31,  // 201: influenzanet.study_service.StudyServiceApi.CreateReport:output_type -> influenzanet.study_service.ServiceStatus
+	1,   // 202: influenzanet.study_service.StudyServiceApi.GetStudiesForUser:output_type -> influenzanet.study_service.StudiesForUser
+	52,  // 203: influenzanet.study_service.StudyServiceApi.GetActiveStudies:output_type -> influenzanet.study_service.Studies
+	36,  // 204: influenzanet.study_service.StudyServiceApi.GetStudySurveyInfos:output_type -> influenzanet.study_service.SurveyInfoResp
+	31,  // 205: influenzanet.study_service.StudyServiceApi.HasParticipantStateWithCondition:output_type -> influenzanet.study_service.ServiceStatus
+	28,  // 206: influenzanet.study_service.StudyServiceApi.GetParticipantMessages:output_type -> influenzanet.study_service.StudyMessages
+	28,  // 207: influenzanet.study_service.StudyServiceApi.GetResearcherMessages:output_type -> influenzanet.study_service.StudyMessages
+	31,  // 208: influenzanet.study_service.StudyServiceApi.DeleteMessagesFromParticipant:output_type -> influenzanet.study_service.ServiceStatus
+	31,  // 209: influenzanet.study_service.StudyServiceApi.DeleteResearcherMessages:output_type -> influenzanet.study_service.ServiceStatus
+	50,  // 210: influenzanet.study_service.StudyServiceApi.GetReportsForUser:output_type -> influenzanet.study_service.ReportHistory
+	31,  // 211: influenzanet.study_service.StudyServiceApi.RemoveConfidentialResponsesForProfiles:output_type -> influenzanet.study_service.ServiceStatus
+	101, // 212: influenzanet.study_service.StudyServiceApi.CreateNewStudy:output_type -> influenzanet.study_service.Study
+	52,  // 213: influenzanet.study_service.StudyServiceApi.GetAllStudies:output_type -> influenzanet.study_service.Studies
+	101, // 214: influenzanet.study_service.StudyServiceApi.GetStudy:output_type -> influenzanet.study_service.Study
+	101, // 215: influenzanet.study_service.StudyServiceApi.SaveStudyMember:output_type -> influenzanet.study_service.Study
+	101, // 216: influenzanet.study_service.StudyServiceApi.RemoveStudyMember:output_type -> influenzanet.study_service.Study
+	8,   // 217: influenzanet.study_service.StudyServiceApi.GetResearcherNotificationSubscriptions:output_type -> influenzanet.study_service.NotificationSubscriptions
+	8,   // 218: influenzanet.study_service.StudyServiceApi.UpdateResearcherNotificationSubscriptions:output_type -> influenzanet.study_service.NotificationSubscriptions
+	52,  // 219: influenzanet.study_service.StudyServiceApi.GetStudiesWithPendingParticipantMessages:output_type -> influenzanet.study_service.Studies
+	101, // 220: influenzanet.study_service.StudyServiceApi.SaveStudyStatus:output_type -> influenzanet.study_service.Study
+	101, // 221: influenzanet.study_service.StudyServiceApi.SaveStudyProps:output_type -> influenzanet.study_service.Study
+	101, // 222: influenzanet.study_service.StudyServiceApi.SaveStudyRules:output_type -> influenzanet.study_service.Study
+	123, // 223: influenzanet.study_service.StudyServiceApi.GetCurrentStudyRules:output_type -> influenzanet.study_service.StudyRules
+	124, // 224: influenzanet.study_service.StudyServiceApi.GetStudyRulesHistory:output_type -> influenzanet.study_service.StudyRulesHistory
+	31,  // 225: influenzanet.study_service.StudyServiceApi.RemoveStudyRulesVersion:output_type -> influenzanet.study_service.ServiceStatus
+	102, // 226: influenzanet.study_service.StudyServiceApi.SaveSurveyToStudy:output_type -> influenzanet.study_service.Survey
+	41,  // 227: influenzanet.study_service.StudyServiceApi.GetSurveyVersionInfos:output_type -> influenzanet.study_service.SurveyVersions
+	46,  // 228: influenzanet.study_service.StudyServiceApi.GetSurveyKeys:output_type -> influenzanet.study_service.SurveyKeys
+	102, // 229: influenzanet.study_service.StudyServiceApi.GetSurveyDefForStudy:output_type -> influenzanet.study_service.Survey
+	31,  // 230: influenzanet.study_service.StudyServiceApi.RemoveSurveyVersion:output_type -> influenzanet.study_service.ServiceStatus
+	31,  // 231: influenzanet.study_service.StudyServiceApi.UnpublishSurvey:output_type -> influenzanet.study_service.ServiceStatus
+	31,  // 232: influenzanet.study_service.StudyServiceApi.DeleteStudy:output_type -> influenzanet.study_service.ServiceStatus
+	59,  // 233: influenzanet.study_service.StudyServiceApi.RunRules:output_type -> influenzanet.study_service.RuleRunSummary
+	59,  // 234: influenzanet.study_service.StudyServiceApi.RunRulesForSingleParticipant:output_type -> influenzanet.study_service.RuleRunSummary
+	59,  // 235: influenzanet.study_service.StudyServiceApi.RunRulesForPreviousResponses:output_type -> influenzanet.study_service.RuleRunSummary
+	22,  // 236: influenzanet.study_service.StudyServiceApi.GetStudyResponseStatistics:output_type -> influenzanet.study_service.StudyResponseStatistics
+	104, // 237: influenzanet.study_service.StudyServiceApi.StreamStudyResponses:output_type -> influenzanet.study_service.SurveyResponse
+	99,  // 238: influenzanet.study_service.StudyServiceApi.StreamParticipantStates:output_type -> influenzanet.study_service.ParticipantState
+	21,  // 239: influenzanet.study_service.StudyServiceApi.GetParticipantStatesWithPagination:output_type -> influenzanet.study_service.ParticipantStatesWithPagination
+	99,  // 240: influenzanet.study_service.StudyServiceApi.GetParticipantStateByID:output_type -> influenzanet.study_service.ParticipantState
+	106, // 241: influenzanet.study_service.StudyServiceApi.StreamReportHistory:output_type -> influenzanet.study_service.Report
+	13,  // 242: influenzanet.study_service.StudyServiceApi.StreamParticipantFileInfos:output_type -> influenzanet.study_service.FileInfo
+	65,  // 243: influenzanet.study_service.StudyServiceApi.GetConfidentialResponses:output_type -> influenzanet.study_service.ConfidentialResponses
+	122, // 244: influenzanet.study_service.StudyServiceApi.GetResponsesWideFormatCSV:output_type -> influenzanet.study_service.Chunk
+	122, // 245: influenzanet.study_service.StudyServiceApi.GetResponsesLongFormatCSV:output_type -> influenzanet.study_service.Chunk
+	122, // 246: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSON:output_type -> influenzanet.study_service.Chunk
+	4,   // 247: influenzanet.study_service.StudyServiceApi.GetResponsesFlatJSONWithPagination:output_type -> influenzanet.study_service.PaginatedFile
+	122, // 248: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreviewCSV:output_type -> influenzanet.study_service.Chunk
+	125, // 249: influenzanet.study_service.StudyServiceApi.GetSurveyInfoPreview:output_type -> influenzanet.study_service.SurveyInfoExport
+	67,  // 250: influenzanet.study_service.StudyServiceApi.RunRulesDryRun:output_type -> influenzanet.study_service.RuleDryRunResult
+	69,  // 251: influenzanet.study_service.StudyServiceApi.StreamParticipantEventHistory:output_type -> influenzanet.study_service.ParticipantEvent
+	101, // 252: influenzanet.study_service.StudyServiceApi.SaveStudyTimerSchedule:output_type -> influenzanet.study_service.Study
+	73,  // 253: influenzanet.study_service.StudyServiceApi.EvaluateRulesInSandbox:output_type -> influenzanet.study_service.SandboxEvaluationResult
+	101, // 254: influenzanet.study_service.StudyServiceApi.SaveRandomisationScheme:output_type -> influenzanet.study_service.Study
+	76,  // 255: influenzanet.study_service.StudyServiceApi.GetRandomisationAllocationCounts:output_type -> influenzanet.study_service.RandomisationAllocationCounts
+	78,  // 256: influenzanet.study_service.StudyServiceApi.SubmitCustomEvent:output_type -> influenzanet.study_service.CustomEventResult
+	80,  // 257: influenzanet.study_service.StudyServiceApi.RunRulesVersionWhatIf:output_type -> influenzanet.study_service.RulesWhatIfResult
+	122, // 258: influenzanet.study_service.StudyServiceApi.GetResponsesParquet:output_type -> influenzanet.study_service.Chunk
+	122, // 259: influenzanet.study_service.StudyServiceApi.GetSurveyCodebook:output_type -> influenzanet.study_service.Chunk
+	122, // 260: influenzanet.study_service.StudyServiceApi.GetResponsesSyntaxFile:output_type -> influenzanet.study_service.Chunk
+	187, // [187:261] is the sub-list for method output_type
+	113, // [113:187] is the sub-list for method input_type
+	113, // [113:113] is the sub-list for extension type_name
+	113, // [113:113] is the sub-list for extension extendee
+	0,   // [0:113] is the sub-list for field type_name
 }
 
 func init() { file_study_service_study_service_proto_init() }
@@ -6874,7 +9178,55 @@ func file_study_service_study_service_proto_init() {
 			}
 		}
 		file_study_service_study_service_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadParticipantFileReq_Info); i {
+			switch v := v.(*ParticipantStateDiff); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuleDryRunResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuleTraceEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParticipantEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParticipantEventHistoryQuery); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6886,6 +9238,138 @@ func file_study_service_study_service_proto_init() {
 			}
 		}
 		file_study_service_study_service_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudyTimerScheduleReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SandboxEvaluationReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SandboxEvaluationResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RandomisationSchemeReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RandomisationAllocationCountsQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RandomisationAllocationCounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CustomEventReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CustomEventResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RulesWhatIfQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RulesWhatIfResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadParticipantFileReq_Info); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RunRulesForPreviousResponsesReq_ResponseFilter); i {
 			case 0:
 				return &v.state
@@ -6897,6 +9381,90 @@ func file_study_service_study_service_proto_init() {
 				return nil
 			}
 		}
+		file_study_service_study_service_proto_msgTypes[86].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParticipantStateDiff_ValueChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[89].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SandboxEvaluationReq_Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[90].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SandboxEvaluationResult_RuleError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[91].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RandomisationAllocationCounts_Count); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[93].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CustomEventResult_ParticipantError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[94].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RulesWhatIfResult_ResponseResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_study_service_study_service_proto_msgTypes[95].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RulesWhatIfResult_Summary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_study_service_study_service_proto_msgTypes[2].OneofWrappers = []interface{}{
 		(*UploadParticipantFileReq_Info_)(nil),
@@ -6906,7 +9474,15 @@ func file_study_service_study_service_proto_init() {
 		(*PaginatedFile_Info)(nil),
 		(*PaginatedFile_Chunk)(nil),
 	}
-	file_study_service_study_service_proto_msgTypes[65].OneofWrappers = []interface{}{
+	file_study_service_study_service_proto_msgTypes[66].OneofWrappers = []interface{}{
+		(*RuleDryRunResult_ParticipantDiff)(nil),
+		(*RuleDryRunResult_Summary)(nil),
+	}
+	file_study_service_study_service_proto_msgTypes[79].OneofWrappers = []interface{}{
+		(*RulesWhatIfResult_ResponseResult_)(nil),
+		(*RulesWhatIfResult_Summary_)(nil),
+	}
+	file_study_service_study_service_proto_msgTypes[80].OneofWrappers = []interface{}{
 		(*UploadParticipantFileReq_Info_ProfileId)(nil),
 		(*UploadParticipantFileReq_Info_ParticipantId)(nil),
 	}
@@ -6916,7 +9492,7 @@ func file_study_service_study_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_study_service_study_service_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   71,
+			NumMessages:   96,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -95,6 +95,17 @@ type StudyServiceApiClient interface {
 	GetResponsesFlatJSONWithPagination(ctx context.Context, in *ResponseExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetResponsesFlatJSONWithPaginationClient, error)
 	GetSurveyInfoPreviewCSV(ctx context.Context, in *SurveyInfoExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetSurveyInfoPreviewCSVClient, error)
 	GetSurveyInfoPreview(ctx context.Context, in *SurveyInfoExportQuery, opts ...grpc.CallOption) (*SurveyInfoExport, error)
+	RunRulesDryRun(ctx context.Context, in *StudyRulesReq, opts ...grpc.CallOption) (StudyServiceApi_RunRulesDryRunClient, error)
+	StreamParticipantEventHistory(ctx context.Context, in *ParticipantEventHistoryQuery, opts ...grpc.CallOption) (StudyServiceApi_StreamParticipantEventHistoryClient, error)
+	SaveStudyTimerSchedule(ctx context.Context, in *StudyTimerScheduleReq, opts ...grpc.CallOption) (*Study, error)
+	EvaluateRulesInSandbox(ctx context.Context, in *SandboxEvaluationReq, opts ...grpc.CallOption) (*SandboxEvaluationResult, error)
+	SaveRandomisationScheme(ctx context.Context, in *RandomisationSchemeReq, opts ...grpc.CallOption) (*Study, error)
+	GetRandomisationAllocationCounts(ctx context.Context, in *RandomisationAllocationCountsQuery, opts ...grpc.CallOption) (*RandomisationAllocationCounts, error)
+	SubmitCustomEvent(ctx context.Context, in *CustomEventReq, opts ...grpc.CallOption) (*CustomEventResult, error)
+	RunRulesVersionWhatIf(ctx context.Context, in *RulesWhatIfQuery, opts ...grpc.CallOption) (StudyServiceApi_RunRulesVersionWhatIfClient, error)
+	GetResponsesParquet(ctx context.Context, in *ResponseExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetResponsesParquetClient, error)
+	GetSurveyCodebook(ctx context.Context, in *CodebookExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetSurveyCodebookClient, error)
+	GetResponsesSyntaxFile(ctx context.Context, in *SyntaxExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetResponsesSyntaxFileClient, error)
 }
 
 type studyServiceApiClient struct {
@@ -927,6 +938,243 @@ func (c *studyServiceApiClient) GetSurveyInfoPreview(ctx context.Context, in *Su
 	return out, nil
 }
 
+func (c *studyServiceApiClient) RunRulesDryRun(ctx context.Context, in *StudyRulesReq, opts ...grpc.CallOption) (StudyServiceApi_RunRulesDryRunClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[11], "/influenzanet.study_service.StudyServiceApi/RunRulesDryRun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiRunRulesDryRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_RunRulesDryRunClient interface {
+	Recv() (*RuleDryRunResult, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiRunRulesDryRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiRunRulesDryRunClient) Recv() (*RuleDryRunResult, error) {
+	m := new(RuleDryRunResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *studyServiceApiClient) StreamParticipantEventHistory(ctx context.Context, in *ParticipantEventHistoryQuery, opts ...grpc.CallOption) (StudyServiceApi_StreamParticipantEventHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[12], "/influenzanet.study_service.StudyServiceApi/StreamParticipantEventHistory", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiStreamParticipantEventHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_StreamParticipantEventHistoryClient interface {
+	Recv() (*ParticipantEvent, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiStreamParticipantEventHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiStreamParticipantEventHistoryClient) Recv() (*ParticipantEvent, error) {
+	m := new(ParticipantEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *studyServiceApiClient) SaveStudyTimerSchedule(ctx context.Context, in *StudyTimerScheduleReq, opts ...grpc.CallOption) (*Study, error) {
+	out := new(Study)
+	err := c.cc.Invoke(ctx, "/influenzanet.study_service.StudyServiceApi/SaveStudyTimerSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceApiClient) EvaluateRulesInSandbox(ctx context.Context, in *SandboxEvaluationReq, opts ...grpc.CallOption) (*SandboxEvaluationResult, error) {
+	out := new(SandboxEvaluationResult)
+	err := c.cc.Invoke(ctx, "/influenzanet.study_service.StudyServiceApi/EvaluateRulesInSandbox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceApiClient) SaveRandomisationScheme(ctx context.Context, in *RandomisationSchemeReq, opts ...grpc.CallOption) (*Study, error) {
+	out := new(Study)
+	err := c.cc.Invoke(ctx, "/influenzanet.study_service.StudyServiceApi/SaveRandomisationScheme", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceApiClient) GetRandomisationAllocationCounts(ctx context.Context, in *RandomisationAllocationCountsQuery, opts ...grpc.CallOption) (*RandomisationAllocationCounts, error) {
+	out := new(RandomisationAllocationCounts)
+	err := c.cc.Invoke(ctx, "/influenzanet.study_service.StudyServiceApi/GetRandomisationAllocationCounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceApiClient) SubmitCustomEvent(ctx context.Context, in *CustomEventReq, opts ...grpc.CallOption) (*CustomEventResult, error) {
+	out := new(CustomEventResult)
+	err := c.cc.Invoke(ctx, "/influenzanet.study_service.StudyServiceApi/SubmitCustomEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *studyServiceApiClient) RunRulesVersionWhatIf(ctx context.Context, in *RulesWhatIfQuery, opts ...grpc.CallOption) (StudyServiceApi_RunRulesVersionWhatIfClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[13], "/influenzanet.study_service.StudyServiceApi/RunRulesVersionWhatIf", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiRunRulesVersionWhatIfClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_RunRulesVersionWhatIfClient interface {
+	Recv() (*RulesWhatIfResult, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiRunRulesVersionWhatIfClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiRunRulesVersionWhatIfClient) Recv() (*RulesWhatIfResult, error) {
+	m := new(RulesWhatIfResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *studyServiceApiClient) GetResponsesParquet(ctx context.Context, in *ResponseExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetResponsesParquetClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[14], "/influenzanet.study_service.StudyServiceApi/GetResponsesParquet", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiGetResponsesParquetClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_GetResponsesParquetClient interface {
+	Recv() (*Chunk, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiGetResponsesParquetClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiGetResponsesParquetClient) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *studyServiceApiClient) GetSurveyCodebook(ctx context.Context, in *CodebookExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetSurveyCodebookClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[15], "/influenzanet.study_service.StudyServiceApi/GetSurveyCodebook", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiGetSurveyCodebookClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_GetSurveyCodebookClient interface {
+	Recv() (*Chunk, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiGetSurveyCodebookClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiGetSurveyCodebookClient) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *studyServiceApiClient) GetResponsesSyntaxFile(ctx context.Context, in *SyntaxExportQuery, opts ...grpc.CallOption) (StudyServiceApi_GetResponsesSyntaxFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StudyServiceApi_ServiceDesc.Streams[16], "/influenzanet.study_service.StudyServiceApi/GetResponsesSyntaxFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &studyServiceApiGetResponsesSyntaxFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StudyServiceApi_GetResponsesSyntaxFileClient interface {
+	Recv() (*Chunk, error)
+	grpc.ClientStream
+}
+
+type studyServiceApiGetResponsesSyntaxFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *studyServiceApiGetResponsesSyntaxFileClient) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StudyServiceApiServer is the server API for StudyServiceApi service.
 // All implementations must embed UnimplementedStudyServiceApiServer
 // for forward compatibility
@@ -1002,6 +1250,17 @@ type StudyServiceApiServer interface {
 	GetResponsesFlatJSONWithPagination(*ResponseExportQuery, StudyServiceApi_GetResponsesFlatJSONWithPaginationServer) error
 	GetSurveyInfoPreviewCSV(*SurveyInfoExportQuery, StudyServiceApi_GetSurveyInfoPreviewCSVServer) error
 	GetSurveyInfoPreview(context.Context, *SurveyInfoExportQuery) (*SurveyInfoExport, error)
+	RunRulesDryRun(*StudyRulesReq, StudyServiceApi_RunRulesDryRunServer) error
+	StreamParticipantEventHistory(*ParticipantEventHistoryQuery, StudyServiceApi_StreamParticipantEventHistoryServer) error
+	SaveStudyTimerSchedule(context.Context, *StudyTimerScheduleReq) (*Study, error)
+	EvaluateRulesInSandbox(context.Context, *SandboxEvaluationReq) (*SandboxEvaluationResult, error)
+	SaveRandomisationScheme(context.Context, *RandomisationSchemeReq) (*Study, error)
+	GetRandomisationAllocationCounts(context.Context, *RandomisationAllocationCountsQuery) (*RandomisationAllocationCounts, error)
+	SubmitCustomEvent(context.Context, *CustomEventReq) (*CustomEventResult, error)
+	RunRulesVersionWhatIf(*RulesWhatIfQuery, StudyServiceApi_RunRulesVersionWhatIfServer) error
+	GetResponsesParquet(*ResponseExportQuery, StudyServiceApi_GetResponsesParquetServer) error
+	GetSurveyCodebook(*CodebookExportQuery, StudyServiceApi_GetSurveyCodebookServer) error
+	GetResponsesSyntaxFile(*SyntaxExportQuery, StudyServiceApi_GetResponsesSyntaxFileServer) error
 	mustEmbedUnimplementedStudyServiceApiServer()
 }
 
@@ -1198,6 +1457,39 @@ func (UnimplementedStudyServiceApiServer) GetSurveyInfoPreviewCSV(*SurveyInfoExp
 func (UnimplementedStudyServiceApiServer) GetSurveyInfoPreview(context.Context, *SurveyInfoExportQuery) (*SurveyInfoExport, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSurveyInfoPreview not implemented")
 }
+func (UnimplementedStudyServiceApiServer) RunRulesDryRun(*StudyRulesReq, StudyServiceApi_RunRulesDryRunServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunRulesDryRun not implemented")
+}
+func (UnimplementedStudyServiceApiServer) StreamParticipantEventHistory(*ParticipantEventHistoryQuery, StudyServiceApi_StreamParticipantEventHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamParticipantEventHistory not implemented")
+}
+func (UnimplementedStudyServiceApiServer) SaveStudyTimerSchedule(context.Context, *StudyTimerScheduleReq) (*Study, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveStudyTimerSchedule not implemented")
+}
+func (UnimplementedStudyServiceApiServer) EvaluateRulesInSandbox(context.Context, *SandboxEvaluationReq) (*SandboxEvaluationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateRulesInSandbox not implemented")
+}
+func (UnimplementedStudyServiceApiServer) SaveRandomisationScheme(context.Context, *RandomisationSchemeReq) (*Study, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveRandomisationScheme not implemented")
+}
+func (UnimplementedStudyServiceApiServer) GetRandomisationAllocationCounts(context.Context, *RandomisationAllocationCountsQuery) (*RandomisationAllocationCounts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRandomisationAllocationCounts not implemented")
+}
+func (UnimplementedStudyServiceApiServer) SubmitCustomEvent(context.Context, *CustomEventReq) (*CustomEventResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitCustomEvent not implemented")
+}
+func (UnimplementedStudyServiceApiServer) RunRulesVersionWhatIf(*RulesWhatIfQuery, StudyServiceApi_RunRulesVersionWhatIfServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunRulesVersionWhatIf not implemented")
+}
+func (UnimplementedStudyServiceApiServer) GetResponsesParquet(*ResponseExportQuery, StudyServiceApi_GetResponsesParquetServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetResponsesParquet not implemented")
+}
+func (UnimplementedStudyServiceApiServer) GetSurveyCodebook(*CodebookExportQuery, StudyServiceApi_GetSurveyCodebookServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetSurveyCodebook not implemented")
+}
+func (UnimplementedStudyServiceApiServer) GetResponsesSyntaxFile(*SyntaxExportQuery, StudyServiceApi_GetResponsesSyntaxFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetResponsesSyntaxFile not implemented")
+}
 func (UnimplementedStudyServiceApiServer) mustEmbedUnimplementedStudyServiceApiServer() {}
 
 // UnsafeStudyServiceApiServer may be embedded to opt out of forward compatibility for this service.
@@ -2383,6 +2675,222 @@ func _StudyServiceApi_GetSurveyInfoPreview_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StudyServiceApi_RunRulesDryRun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StudyRulesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).RunRulesDryRun(m, &studyServiceApiRunRulesDryRunServer{stream})
+}
+
+type StudyServiceApi_RunRulesDryRunServer interface {
+	Send(*RuleDryRunResult) error
+	grpc.ServerStream
+}
+
+type studyServiceApiRunRulesDryRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiRunRulesDryRunServer) Send(m *RuleDryRunResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StudyServiceApi_StreamParticipantEventHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ParticipantEventHistoryQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).StreamParticipantEventHistory(m, &studyServiceApiStreamParticipantEventHistoryServer{stream})
+}
+
+type StudyServiceApi_StreamParticipantEventHistoryServer interface {
+	Send(*ParticipantEvent) error
+	grpc.ServerStream
+}
+
+type studyServiceApiStreamParticipantEventHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiStreamParticipantEventHistoryServer) Send(m *ParticipantEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StudyServiceApi_SaveStudyTimerSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudyTimerScheduleReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceApiServer).SaveStudyTimerSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/influenzanet.study_service.StudyServiceApi/SaveStudyTimerSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceApiServer).SaveStudyTimerSchedule(ctx, req.(*StudyTimerScheduleReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyServiceApi_EvaluateRulesInSandbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SandboxEvaluationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceApiServer).EvaluateRulesInSandbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/influenzanet.study_service.StudyServiceApi/EvaluateRulesInSandbox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceApiServer).EvaluateRulesInSandbox(ctx, req.(*SandboxEvaluationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyServiceApi_SaveRandomisationScheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RandomisationSchemeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceApiServer).SaveRandomisationScheme(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/influenzanet.study_service.StudyServiceApi/SaveRandomisationScheme",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceApiServer).SaveRandomisationScheme(ctx, req.(*RandomisationSchemeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyServiceApi_GetRandomisationAllocationCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RandomisationAllocationCountsQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceApiServer).GetRandomisationAllocationCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/influenzanet.study_service.StudyServiceApi/GetRandomisationAllocationCounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceApiServer).GetRandomisationAllocationCounts(ctx, req.(*RandomisationAllocationCountsQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyServiceApi_SubmitCustomEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CustomEventReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StudyServiceApiServer).SubmitCustomEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/influenzanet.study_service.StudyServiceApi/SubmitCustomEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StudyServiceApiServer).SubmitCustomEvent(ctx, req.(*CustomEventReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StudyServiceApi_RunRulesVersionWhatIf_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RulesWhatIfQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).RunRulesVersionWhatIf(m, &studyServiceApiRunRulesVersionWhatIfServer{stream})
+}
+
+type StudyServiceApi_RunRulesVersionWhatIfServer interface {
+	Send(*RulesWhatIfResult) error
+	grpc.ServerStream
+}
+
+type studyServiceApiRunRulesVersionWhatIfServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiRunRulesVersionWhatIfServer) Send(m *RulesWhatIfResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StudyServiceApi_GetResponsesParquet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResponseExportQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).GetResponsesParquet(m, &studyServiceApiGetResponsesParquetServer{stream})
+}
+
+type StudyServiceApi_GetResponsesParquetServer interface {
+	Send(*Chunk) error
+	grpc.ServerStream
+}
+
+type studyServiceApiGetResponsesParquetServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiGetResponsesParquetServer) Send(m *Chunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StudyServiceApi_GetSurveyCodebook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CodebookExportQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).GetSurveyCodebook(m, &studyServiceApiGetSurveyCodebookServer{stream})
+}
+
+type StudyServiceApi_GetSurveyCodebookServer interface {
+	Send(*Chunk) error
+	grpc.ServerStream
+}
+
+type studyServiceApiGetSurveyCodebookServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiGetSurveyCodebookServer) Send(m *Chunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StudyServiceApi_GetResponsesSyntaxFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyntaxExportQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StudyServiceApiServer).GetResponsesSyntaxFile(m, &studyServiceApiGetResponsesSyntaxFileServer{stream})
+}
+
+type StudyServiceApi_GetResponsesSyntaxFileServer interface {
+	Send(*Chunk) error
+	grpc.ServerStream
+}
+
+type studyServiceApiGetResponsesSyntaxFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *studyServiceApiGetResponsesSyntaxFileServer) Send(m *Chunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // StudyServiceApi_ServiceDesc is the grpc.ServiceDesc for StudyServiceApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -2598,6 +3106,26 @@ var StudyServiceApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSurveyInfoPreview",
 			Handler:    _StudyServiceApi_GetSurveyInfoPreview_Handler,
 		},
+		{
+			MethodName: "SaveStudyTimerSchedule",
+			Handler:    _StudyServiceApi_SaveStudyTimerSchedule_Handler,
+		},
+		{
+			MethodName: "EvaluateRulesInSandbox",
+			Handler:    _StudyServiceApi_EvaluateRulesInSandbox_Handler,
+		},
+		{
+			MethodName: "SaveRandomisationScheme",
+			Handler:    _StudyServiceApi_SaveRandomisationScheme_Handler,
+		},
+		{
+			MethodName: "GetRandomisationAllocationCounts",
+			Handler:    _StudyServiceApi_GetRandomisationAllocationCounts_Handler,
+		},
+		{
+			MethodName: "SubmitCustomEvent",
+			Handler:    _StudyServiceApi_SubmitCustomEvent_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2655,6 +3183,36 @@ var StudyServiceApi_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _StudyServiceApi_GetSurveyInfoPreviewCSV_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RunRulesDryRun",
+			Handler:       _StudyServiceApi_RunRulesDryRun_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamParticipantEventHistory",
+			Handler:       _StudyServiceApi_StreamParticipantEventHistory_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RunRulesVersionWhatIf",
+			Handler:       _StudyServiceApi_RunRulesVersionWhatIf_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetResponsesParquet",
+			Handler:       _StudyServiceApi_GetResponsesParquet_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetSurveyCodebook",
+			Handler:       _StudyServiceApi_GetSurveyCodebook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetResponsesSyntaxFile",
+			Handler:       _StudyServiceApi_GetResponsesSyntaxFile_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "study_service/study-service.proto",
 }
@@ -74,6 +74,10 @@ func (dbService *StudyDBService) collectionRefParticipantFiles(instanceID string
 	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_participantFiles")
 }
 
+func (dbService *StudyDBService) collectionRefParticipantEvents(instanceID string, studyKey string) *mongo.Collection {
+	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_participantEvents")
+}
+
 func (dbService *StudyDBService) collectionRefConfidentialResponses(instanceID string, studyKey string) *mongo.Collection {
 	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_confidentialResponses")
 }
@@ -82,10 +86,22 @@ func (dbService *StudyDBService) collectionRefResearcherMessages(instanceID stri
 	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_researcherMessages")
 }
 
+func (dbService *StudyDBService) collectionRefRandomisationBlocks(instanceID string, studyKey string) *mongo.Collection {
+	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_randomisationBlocks")
+}
+
+func (dbService *StudyDBService) collectionRefRandomisationAllocations(instanceID string, studyKey string) *mongo.Collection {
+	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection(studyKey + "_randomisationAllocations")
+}
+
 func (dbService *StudyDBService) collectionRefStudyRules(instanceID string) *mongo.Collection {
 	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection("studyRules")
 }
 
+func (dbService *StudyDBService) collectionRefTimerLeases(instanceID string) *mongo.Collection {
+	return dbService.DBClient.Database(dbService.DBNamePrefix + instanceID + "_studyDB").Collection("timerLeases")
+}
+
 // DB utils
 func (dbService *StudyDBService) getContext() (ctx context.Context, cancel context.CancelFunc) {
 	return context.WithTimeout(context.Background(), time.Duration(dbService.timeout)*time.Second)
@@ -110,7 +110,18 @@ func (dbService *StudyDBService) GetStudyMembers(instanceID string, studyKey str
 }
 
 func (dbService *StudyDBService) GetStudyRules(instanceID string, studyKey string) (rules []types.Expression, err error) {
-	currentRules, err := dbService.GetCurrentStudyRules(instanceID, studyKey)
+	rules, _, err = dbService.GetStudyRulesWithVersionID(instanceID, studyKey)
+	return rules, err
+}
+
+// GetStudyRulesWithVersionID returns the current study rules and the ID of the study rules version (empty if rules are loaded from the study info)
+func (dbService *StudyDBService) GetStudyRulesWithVersionID(instanceID string, studyKey string) (rules []types.Expression, versionID string, err error) {
+	return dbService.GetStudyRulesActiveAtWithVersionID(instanceID, studyKey, time.Now().Unix())
+}
+
+// GetStudyRulesActiveAtWithVersionID returns the study rules active at ts and the ID of the study rules version (empty if rules are loaded from the study info)
+func (dbService *StudyDBService) GetStudyRulesActiveAtWithVersionID(instanceID string, studyKey string, ts int64) (rules []types.Expression, versionID string, err error) {
+	currentRules, err := dbService.GetStudyRulesActiveAt(instanceID, studyKey, ts)
 	if err == nil {
 		// if current rules are found
 		err := currentRules.UnmarshalRules()
@@ -118,7 +129,7 @@ func (dbService *StudyDBService) GetStudyRules(instanceID string, studyKey strin
 			// and can be unmarshalled
 			if len(currentRules.Rules) > 0 {
 				// and are not empty
-				return currentRules.Rules, nil
+				return currentRules.Rules, currentRules.ID.Hex(), nil
 			}
 		}
 	}
@@ -136,9 +147,9 @@ func (dbService *StudyDBService) GetStudyRules(instanceID string, studyKey strin
 		},
 		options.FindOne().SetProjection(projection),
 	).Decode(&study); err != nil {
-		return []types.Expression{}, err
+		return []types.Expression{}, "", err
 	}
-	return study.Rules, nil
+	return study.Rules, "", nil
 }
 
 func (dbService *StudyDBService) CreateStudy(instanceID string, study types.Study) (types.Study, error) {
@@ -216,7 +227,24 @@ func (dbService *StudyDBService) UpdateStudyInfo(instanceID string, study types.
 	return elem, err
 }
 
-func (dbService *StudyDBService) ShouldPerformTimerEvent(instanceID string, studyKey string, timerEventFrequency int64) error {
+// UpdateStudyParticipantCounts replaces the cached participant counts of the study stats
+func (dbService *StudyDBService) UpdateStudyParticipantCounts(instanceID string, studyKey string, participantCounts map[string]int64, updatedAt int64) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{
+		"key": studyKey,
+	}
+	update := bson.M{"$set": bson.M{
+		"studyStats.participantCounts":          participantCounts,
+		"studyStats.participantCountsUpdatedAt": updatedAt,
+	}}
+	_, err := dbService.collectionRefStudyInfos(instanceID).UpdateOne(ctx, filter, update)
+	return err
+}
+
+// ShouldPerformTimerEvent returns nil if the timer event of the study is due, and sets the time of the following run to nextTimerEventAfter
+func (dbService *StudyDBService) ShouldPerformTimerEvent(instanceID string, studyKey string, nextTimerEventAfter int64) error {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
 
@@ -224,7 +252,7 @@ func (dbService *StudyDBService) ShouldPerformTimerEvent(instanceID string, stud
 		"key":                 studyKey,
 		"nextTimerEventAfter": bson.M{"$lt": time.Now().Unix()},
 	}
-	update := bson.M{"$set": bson.M{"nextTimerEventAfter": time.Now().Unix() + timerEventFrequency}}
+	update := bson.M{"$set": bson.M{"nextTimerEventAfter": nextTimerEventAfter}}
 	res, err := dbService.collectionRefStudyInfos(instanceID).UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
@@ -235,6 +263,19 @@ func (dbService *StudyDBService) ShouldPerformTimerEvent(instanceID string, stud
 	return nil
 }
 
+func (dbService *StudyDBService) GetStudyStats(instanceID string, studyKey string) (stats types.StudyStats, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{
+		"key": studyKey,
+	}
+	var study types.Study
+	opts := options.FindOne().SetProjection(bson.M{"studyStats": 1})
+	err = dbService.collectionRefStudyInfos(instanceID).FindOne(ctx, filter, opts).Decode(&study)
+	return study.Stats, err
+}
+
 func (dbService *StudyDBService) UpdateStudyStats(instanceID string, studyKey string, stats types.StudyStats) error {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
@@ -14,8 +14,8 @@ func TestDbParticipantStateTest(t *testing.T) {
 	testPState := types.ParticipantState{
 		ParticipantID: "testPID0990",
 		StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-		Flags: map[string]string{
-			"testKey": "testValue",
+		Flags: types.ParticipantFlags{
+			"testKey": types.StringFlag("testValue"),
 		},
 		LastSubmissions: map[string]int64{
 			"testSurveyKey": time.Now().Unix(),
@@ -30,7 +30,7 @@ func TestDbParticipantStateTest(t *testing.T) {
 	})
 
 	t.Run("Testing update participant state, when not existing", func(t *testing.T) {
-		pState, err := testDBService.SaveParticipantState(testInstanceID, testStudyKey, testPState)
+		pState, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, testPState)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -53,7 +53,7 @@ func TestDbParticipantStateTest(t *testing.T) {
 
 	t.Run("Testing update participant state, when existing", func(t *testing.T) {
 		testPState.StudyStatus = "paused"
-		pState, err := testDBService.SaveParticipantState(testInstanceID, testStudyKey, testPState)
+		pState, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, testPState)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -96,7 +96,7 @@ func TestDbFindParticipantsByStatusTest(t *testing.T) {
 	}
 
 	for _, ps := range pStates {
-		_, err := testDBService.SaveParticipantState(testInstanceID, testStudyKey, ps)
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -156,8 +156,8 @@ func TestFindAndExecuteOnParticipantsStates(t *testing.T) {
 		{
 			ParticipantID: "1",
 			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-			Flags: map[string]string{
-				"test1": "1",
+			Flags: types.ParticipantFlags{
+				"test1": types.StringFlag("1"),
 			},
 		},
 		{
@@ -167,7 +167,7 @@ func TestFindAndExecuteOnParticipantsStates(t *testing.T) {
 	}
 
 	for _, ps := range pStates {
-		_, err := testDBService.SaveParticipantState(testInstanceID, testStudyKey, ps)
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -184,13 +184,13 @@ func TestFindAndExecuteOnParticipantsStates(t *testing.T) {
 			func(dbService *StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
 				_, ok := p.Flags["test1"]
 				if !ok {
-					p.Flags = map[string]string{
-						"test1": "1",
+					p.Flags = types.ParticipantFlags{
+						"test1": types.StringFlag("1"),
 					}
 				} else {
-					p.Flags["test1"] = "newvalue"
+					p.Flags["test1"] = types.StringFlag("newvalue")
 				}
-				_, err := dbService.SaveParticipantState(instanceID, studyKey, p)
+				_, err := dbService.OverwriteParticipantState(instanceID, studyKey, p)
 				return err
 			}, nil)
 		if err != nil {
@@ -204,7 +204,7 @@ func TestFindAndExecuteOnParticipantsStates(t *testing.T) {
 			return
 		}
 		testval, ok := p.Flags["test1"]
-		if !ok || testval != "newvalue" {
+		if !ok || testval.String() != "newvalue" {
 			t.Errorf("unexpected flags for p1: %s", p.Flags)
 		}
 
@@ -214,7 +214,7 @@ func TestFindAndExecuteOnParticipantsStates(t *testing.T) {
 			return
 		}
 		testval, ok = p.Flags["test1"]
-		if !ok || testval != "1" {
+		if !ok || testval.String() != "1" {
 			t.Errorf("unexpected flags for p2: %s", p.Flags)
 		}
 	})
@@ -247,7 +247,7 @@ func TestDeleteMessagesFromParticipant(t *testing.T) {
 	}
 
 	for _, ps := range pStates {
-		_, err := testDBService.SaveParticipantState(testInstanceID, testStudyKey, ps)
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -290,3 +290,152 @@ func TestDeleteMessagesFromParticipant(t *testing.T) {
 		}
 	})
 }
+
+func TestFindAndExecuteOnParticipantsWithDueEvents(t *testing.T) {
+	testStudyKey := "teststudy_dueevents"
+	now := time.Now().Unix()
+
+	pStates := []types.ParticipantState{
+		{
+			ParticipantID:   "due",
+			StudyStatus:     types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			ScheduledEvents: []types.ScheduledEvent{{Name: "reminder", DueAt: now - 10}},
+		},
+		{
+			ParticipantID:   "notdue",
+			StudyStatus:     types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			ScheduledEvents: []types.ScheduledEvent{{Name: "reminder", DueAt: now + 1000}},
+		},
+		{
+			ParticipantID:   "inactive",
+			StudyStatus:     types.PARTICIPANT_STUDY_STATUS_EXITED,
+			ScheduledEvents: []types.ScheduledEvent{{Name: "reminder", DueAt: now - 10}},
+		},
+		{
+			ParticipantID: "noevents",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		},
+	}
+	for _, ps := range pStates {
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	found := []string{}
+	err := testDBService.FindAndExecuteOnParticipantsWithDueEvents(
+		context.Background(),
+		testInstanceID,
+		testStudyKey,
+		now,
+		func(dbService *StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
+			found = append(found, p.ParticipantID)
+			return nil
+		})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if len(found) != 1 || found[0] != "due" {
+		t.Errorf("unexpected participants: %v", found)
+	}
+}
+
+func TestFindAndExecuteOnParticipantsWithExpiredFlags(t *testing.T) {
+	testStudyKey := "teststudy_expiredflags"
+	now := time.Now().Unix()
+
+	pStates := []types.ParticipantState{
+		{
+			ParticipantID: "expired",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags:         types.ParticipantFlags{"episode": types.BoolFlag(true), "group": types.StringFlag("a")},
+			FlagInfos: types.FlagInfos{
+				{Key: "group", SetAt: now - 100},
+				{Key: "episode", SetAt: now - 100, ExpiresAt: now - 10},
+			},
+		},
+		{
+			ParticipantID: "notexpired",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags:         types.ParticipantFlags{"episode": types.BoolFlag(true)},
+			FlagInfos:     types.FlagInfos{{Key: "episode", SetAt: now - 100, ExpiresAt: now + 1000}},
+		},
+		{
+			ParticipantID: "noexpiry",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags:         types.ParticipantFlags{"group": types.StringFlag("a")},
+			FlagInfos:     types.FlagInfos{{Key: "group", SetAt: now - 100}},
+		},
+		{
+			ParticipantID: "inactive",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_EXITED,
+			Flags:         types.ParticipantFlags{"episode": types.BoolFlag(true)},
+			FlagInfos:     types.FlagInfos{{Key: "episode", SetAt: now - 100, ExpiresAt: now - 10}},
+		},
+	}
+	for _, ps := range pStates {
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	found := []string{}
+	err := testDBService.FindAndExecuteOnParticipantsWithExpiredFlags(
+		context.Background(),
+		testInstanceID,
+		testStudyKey,
+		now,
+		func(dbService *StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
+			found = append(found, p.ParticipantID)
+			return nil
+		})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if len(found) != 1 || found[0] != "expired" {
+		t.Errorf("unexpected participants: %v", found)
+	}
+}
+
+func TestCountParticipantsWithCondition(t *testing.T) {
+	testStudyKey := "teststudy_participantcondition"
+
+	pStates := []types.ParticipantState{
+		{ParticipantID: "p1", StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, Flags: types.ParticipantFlags{"age_group": types.StringFlag("65+")}},
+		{ParticipantID: "p2", StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, Flags: types.ParticipantFlags{"age_group": types.StringFlag("65+"), "group": types.NumberFlag(2)}},
+		{ParticipantID: "p3", StudyStatus: types.PARTICIPANT_STUDY_STATUS_EXITED, Flags: types.ParticipantFlags{"age_group": types.StringFlag("65+")}},
+		{ParticipantID: "p4", StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, Flags: types.ParticipantFlags{"age_group": types.StringFlag("18-64")}},
+	}
+	for _, ps := range pStates {
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	for _, tc := range []struct {
+		condition types.ParticipantCondition
+		expected  int64
+	}{
+		{types.ParticipantCondition{StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, FlagKey: "age_group", FlagValue: "65+"}, 2},
+		{types.ParticipantCondition{StudyStatus: types.PARTICIPANT_STUDY_STATUS_EXITED, FlagKey: "age_group", FlagValue: "65+"}, 1},
+		{types.ParticipantCondition{StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, FlagKey: "group", FlagValue: "2"}, 1},
+		{types.ParticipantCondition{StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE, FlagKey: "missing", FlagValue: "a"}, 0},
+	} {
+		count, err := testDBService.CountParticipantsWithCondition(testInstanceID, testStudyKey, tc.condition)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if count != tc.expected {
+			t.Errorf("unexpected count for %s: %d, expected %d", tc.condition.CounterKey(), count, tc.expected)
+		}
+	}
+}
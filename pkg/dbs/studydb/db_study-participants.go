@@ -2,6 +2,8 @@ package studydb
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/coneno/logger"
@@ -149,24 +151,22 @@ func (dbService *StudyDBService) FindParticipantState(instanceID string, studyKe
 	return elem, err
 }
 
-// SaveParticipantState creates or replaces the participant states in the DB
-func (dbService *StudyDBService) SaveParticipantState(instanceID string, studyKey string, pState types.ParticipantState) (types.ParticipantState, error) {
-	ctx, cancel := dbService.getContext()
-	defer cancel()
-
-	filter := bson.M{"participantID": pState.ParticipantID}
+// OverwriteParticipantState creates or replaces the participant state in the DB, discarding concurrent modifications (last writer wins).
+// Only for callers that mean to overwrite the stored state, updates based on a loaded state should use UpdateParticipantStateWithRetry.
+// The version is taken from the state currently in the DB, so that updates based on an older state are still detected as conflicts.
+func (dbService *StudyDBService) OverwriteParticipantState(instanceID string, studyKey string, pState types.ParticipantState) (types.ParticipantState, error) {
+	for attempt := 0; ; attempt++ {
+		current, err := dbService.FindParticipantState(instanceID, studyKey, pState.ParticipantID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return current, err
+		}
+		pState.Version = current.Version
 
-	upsert := true
-	rd := options.After
-	options := options.FindOneAndReplaceOptions{
-		Upsert:         &upsert,
-		ReturnDocument: &rd,
+		saved, err := dbService.UpdateParticipantStateIfUnchanged(instanceID, studyKey, pState)
+		if err != ErrParticipantStateConflict || attempt >= ParticipantStateUpdateRetries {
+			return saved, err
+		}
 	}
-	elem := types.ParticipantState{}
-	err := dbService.collectionRefStudyParticipant(instanceID, studyKey).FindOneAndReplace(
-		ctx, filter, pState, &options,
-	).Decode(&elem)
-	return elem, err
 }
 
 func (dbService *StudyDBService) DeleteParticipantState(instanceID string, studyKey string, pID string) error {
@@ -190,6 +190,28 @@ func (dbService *StudyDBService) GetParticipantCountByStatus(instanceID string,
 	return count, err
 }
 
+// CountParticipantsWithCondition counts participants with the study status and flag value. The flag value is compared as string,
+// and as number or bool if it can be parsed as such (typed flags); list flags containing the value are counted as well.
+func (dbService *StudyDBService) CountParticipantsWithCondition(instanceID string, studyKey string, condition types.ParticipantCondition) (count int64, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	values := bson.A{condition.FlagValue}
+	if num, err := strconv.ParseFloat(condition.FlagValue, 64); err == nil {
+		values = append(values, num)
+	}
+	if condition.FlagValue == "true" || condition.FlagValue == "false" {
+		values = append(values, condition.FlagValue == "true")
+	}
+	filter := bson.M{
+		"studyStatus":                condition.StudyStatus,
+		"flags." + condition.FlagKey: bson.M{"$in": values},
+	}
+
+	count, err = dbService.collectionRefStudyParticipant(instanceID, studyKey).CountDocuments(ctx, filter)
+	return count, err
+}
+
 func (dbService *StudyDBService) FindAndExecuteOnParticipantsStates(
 	ctx context.Context,
 	instanceID string,
@@ -207,8 +229,88 @@ func (dbService *StudyDBService) FindAndExecuteOnParticipantsStates(
 	options := options.FindOptions{
 		BatchSize: &batchSize,
 	}
+	return dbService.executeOnParticipantStates(ctx, instanceID, studyKey, filter, &options, cbk, args...)
+}
+
+// FindAndExecuteOnParticipantsStatesAfter iterates participant states in the order of their _id, starting after afterID.
+// If afterID is zero, all participant states are used.
+func (dbService *StudyDBService) FindAndExecuteOnParticipantsStatesAfter(
+	ctx context.Context,
+	instanceID string,
+	studyKey string,
+	filterByStatus string,
+	afterID primitive.ObjectID,
+	cbk func(dbService *StudyDBService, p types.ParticipantState, instanceID string, studyKey string, args ...interface{}) error,
+	args ...interface{},
+) error {
+	filter := bson.M{}
+	if len(filterByStatus) > 0 {
+		filter["studyStatus"] = filterByStatus
+	}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	batchSize := int32(32)
+	options := options.FindOptions{
+		BatchSize: &batchSize,
+		Sort:      bson.D{{Key: "_id", Value: 1}},
+	}
+	return dbService.executeOnParticipantStates(ctx, instanceID, studyKey, filter, &options, cbk, args...)
+}
+
+// FindAndExecuteOnParticipantsWithDueEvents iterates active participant states with at least one scheduled event due at dueBefore
+func (dbService *StudyDBService) FindAndExecuteOnParticipantsWithDueEvents(
+	ctx context.Context,
+	instanceID string,
+	studyKey string,
+	dueBefore int64,
+	cbk func(dbService *StudyDBService, p types.ParticipantState, instanceID string, studyKey string, args ...interface{}) error,
+	args ...interface{},
+) error {
+	filter := bson.M{
+		"studyStatus":           types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		"scheduledEvents.dueAt": bson.M{"$lte": dueBefore},
+	}
+
+	batchSize := int32(32)
+	options := options.FindOptions{
+		BatchSize: &batchSize,
+	}
+	return dbService.executeOnParticipantStates(ctx, instanceID, studyKey, filter, &options, cbk, args...)
+}
+
+// FindAndExecuteOnParticipantsWithExpiredFlags iterates active participant states with at least one flag expired at expiredBefore
+func (dbService *StudyDBService) FindAndExecuteOnParticipantsWithExpiredFlags(
+	ctx context.Context,
+	instanceID string,
+	studyKey string,
+	expiredBefore int64,
+	cbk func(dbService *StudyDBService, p types.ParticipantState, instanceID string, studyKey string, args ...interface{}) error,
+	args ...interface{},
+) error {
+	filter := bson.M{
+		"studyStatus":         types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		"flagInfos.expiresAt": bson.M{"$lte": expiredBefore},
+	}
+
+	batchSize := int32(32)
+	options := options.FindOptions{
+		BatchSize: &batchSize,
+	}
+	return dbService.executeOnParticipantStates(ctx, instanceID, studyKey, filter, &options, cbk, args...)
+}
 
-	cur, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).Find(ctx, filter, &options)
+func (dbService *StudyDBService) executeOnParticipantStates(
+	ctx context.Context,
+	instanceID string,
+	studyKey string,
+	filter bson.M,
+	options *options.FindOptions,
+	cbk func(dbService *StudyDBService, p types.ParticipantState, instanceID string, studyKey string, args ...interface{}) error,
+	args ...interface{},
+) error {
+	cur, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).Find(ctx, filter, options)
 	if err != nil {
 		return err
 	}
@@ -263,14 +365,151 @@ func (dbService *StudyDBService) CreateMessageScheduledForIndex(instanceID strin
 	return err
 }
 
+// participantIDIndexName is the name of the unique participantID index. Previous versions created a non-unique index with the
+// default name participantID_1, which is dropped once the unique index exists. The partial filter (matching all participant states)
+// distinguishes the key specification from the old index, so that both can exist at the same time.
+const participantIDIndexName = "participantID_unique"
+
+// CreateParticipantIDIndex creates the unique participantID index and drops the non-unique index of previous versions afterwards.
+// If participant IDs are duplicated, the unique index cannot be created: the duplicates are logged and returned in the error, and
+// the old index is kept.
 func (dbService *StudyDBService) CreateParticipantIDIndex(instanceID string, studyKey string) error {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
 
+	indexes := dbService.collectionRefStudyParticipant(instanceID, studyKey).Indexes()
+	uniqueIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "participantID", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName(participantIDIndexName).
+			SetPartialFilterExpression(bson.M{"participantID": bson.M{"$exists": true}}),
+	}
+	_, err := indexes.CreateOne(ctx, uniqueIndex)
+	if isDuplicateKeyIndexError(err) {
+		if dupErr := dbService.duplicateParticipantIDsError(instanceID, studyKey); dupErr != nil {
+			return dupErr
+		}
+		return err
+	}
+	if isIndexConflictError(err) {
+		// servers not supporting both indexes on the same key: the old index is replaced if there are no duplicates
+		if err := dbService.duplicateParticipantIDsError(instanceID, studyKey); err != nil {
+			return err
+		}
+		if _, err := indexes.DropOne(ctx, "participantID_1"); err != nil {
+			return err
+		}
+		if _, err := indexes.CreateOne(ctx, uniqueIndex); err != nil {
+			// participant states inserted in the meantime, restore the old index
+			logger.Error.Printf("cannot create unique participantID index of %s-%s: %v", instanceID, studyKey, err)
+			if _, restoreErr := indexes.CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "participantID", Value: 1}}}); restoreErr != nil {
+				logger.Error.Printf("cannot restore participantID index of %s-%s: %v", instanceID, studyKey, restoreErr)
+			}
+			return err
+		}
+		logger.Info.Printf("replaced participantID index of %s-%s with a unique index", instanceID, studyKey)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = indexes.DropOne(ctx, "participantID_1")
+	if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Code == 27 {
+		// IndexNotFound, already dropped
+		return nil
+	}
+	if err == nil {
+		logger.Info.Printf("replaced participantID index of %s-%s with a unique index", instanceID, studyKey)
+	}
+	return err
+}
+
+// duplicateParticipantIDsError logs and returns the participant IDs stored in more than one participant state, nil if there are none
+func (dbService *StudyDBService) duplicateParticipantIDsError(instanceID string, studyKey string) error {
+	duplicates, err := dbService.FindDuplicateParticipantIDs(instanceID, studyKey)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	logger.Error.Printf("cannot create unique participantID index of %s-%s, participant IDs with several participant states: %v", instanceID, studyKey, duplicates)
+	return fmt.Errorf("participant IDs with several participant states in %s-%s: %v", instanceID, studyKey, duplicates)
+}
+
+// isDuplicateKeyIndexError is true if an index cannot be created because of duplicate keys (code 11000)
+func isDuplicateKeyIndexError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return true
+	}
+	cmdErr, ok := err.(mongo.CommandError)
+	return ok && cmdErr.Code == 11000
+}
+
+// isIndexConflictError is true if an index with the same key already exists with other options (codes 85 and 86)
+func isIndexConflictError(err error) bool {
+	cmdErr, ok := err.(mongo.CommandError)
+	return ok && (cmdErr.Code == 85 || cmdErr.Code == 86)
+}
+
+// FindDuplicateParticipantIDs returns the participant IDs stored in more than one participant state
+func (dbService *StudyDBService) FindDuplicateParticipantIDs(instanceID string, studyKey string) (participantIDs []string, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$participantID", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cur, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).Aggregate(ctx, pipeline)
+	if err != nil {
+		return participantIDs, err
+	}
+	defer cur.Close(ctx)
+
+	participantIDs = []string{}
+	for cur.Next(ctx) {
+		var result struct {
+			ParticipantID string `bson:"_id"`
+		}
+		if err := cur.Decode(&result); err != nil {
+			return participantIDs, err
+		}
+		participantIDs = append(participantIDs, result.ParticipantID)
+	}
+	return participantIDs, cur.Err()
+}
+
+func (dbService *StudyDBService) CreateScheduledEventsIndex(instanceID string, studyKey string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "scheduledEvents.dueAt", Value: 1},
+			},
+		},
+	)
+	return err
+}
+
+func (dbService *StudyDBService) CreateFlagExpiryIndex(instanceID string, studyKey string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
 	_, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).Indexes().CreateOne(
 		ctx, mongo.IndexModel{
 			Keys: bson.D{
-				{Key: "participantID", Value: 1},
+				{Key: "flagInfos.expiresAt", Value: 1},
 			},
 		},
 	)
@@ -307,6 +546,36 @@ func (dbService *StudyDBService) CreateParticipantIDIndexForAllStudies(instanceI
 	}
 }
 
+func (dbService *StudyDBService) CreateScheduledEventsIndexForAllStudies(instanceID string) {
+	studies, err := dbService.GetStudiesByStatus(instanceID, "", true)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching studies in '%s': %v", instanceID, err)
+		return
+	}
+
+	for _, study := range studies {
+		err = dbService.CreateScheduledEventsIndex(instanceID, study.Key)
+		if err != nil {
+			logger.Error.Printf("unexpected error when creating scheduled events indexes: %v", err)
+		}
+	}
+}
+
+func (dbService *StudyDBService) CreateFlagExpiryIndexForAllStudies(instanceID string) {
+	studies, err := dbService.GetStudiesByStatus(instanceID, "", true)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching studies in '%s': %v", instanceID, err)
+		return
+	}
+
+	for _, study := range studies {
+		err = dbService.CreateFlagExpiryIndex(instanceID, study.Key)
+		if err != nil {
+			logger.Error.Printf("unexpected error when creating flag expiry indexes: %v", err)
+		}
+	}
+}
+
 func (dbService *StudyDBService) CheckParticipantsForPendingMessages(instanceID string, studyKey string) (hasMessage bool, err error) {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
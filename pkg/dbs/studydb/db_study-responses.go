@@ -11,6 +11,7 @@ import (
 	"github.com/influenzanet/study-service/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -148,20 +149,7 @@ func (dbService *StudyDBService) GetSurveyResponsesCount(
 	ctx context.Context,
 	instanceID string,
 	studyKey string, surveyKey string, from int64, until int64) (totalCount int32) {
-	filter := bson.M{}
-	if len(surveyKey) > 0 {
-		filter["key"] = surveyKey
-	}
-	if from > 0 && until > 0 {
-		filter["$and"] = bson.A{
-			bson.M{"submittedAt": bson.M{"$gt": from}},
-			bson.M{"submittedAt": bson.M{"$lt": until}},
-		}
-	} else if from > 0 {
-		filter["submittedAt"] = bson.M{"$gt": from}
-	} else if until > 0 {
-		filter["submittedAt"] = bson.M{"$lt": until}
-	}
+	filter := getSurveyResponsesFilter(surveyKey, from, until)
 	count, err := dbService.collectionRefSurveyResponses(instanceID, studyKey).CountDocuments(
 		ctx,
 		filter,
@@ -174,6 +162,39 @@ func (dbService *StudyDBService) GetSurveyResponsesCount(
 	}
 }
 
+// GetSurveyResponseContextKeys returns the keys used in the context of the responses, sorted alphabetically
+func (dbService *StudyDBService) GetSurveyResponseContextKeys(
+	ctx context.Context,
+	instanceID string,
+	studyKey string, surveyKey string, from int64, until int64) (keys []string, err error) {
+	filter := getSurveyResponsesFilter(surveyKey, from, until)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$project", Value: bson.M{"context": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$context", bson.M{}}}}}}},
+		{{Key: "$unwind", Value: "$context"}},
+		{{Key: "$group", Value: bson.M{"_id": "$context.k"}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cur, err := dbService.collectionRefSurveyResponses(instanceID, studyKey).Aggregate(ctx, pipeline)
+	if err != nil {
+		return keys, err
+	}
+	defer cur.Close(ctx)
+
+	keys = []string{}
+	for cur.Next(ctx) {
+		var result struct {
+			Key string `bson:"_id"`
+		}
+		if err := cur.Decode(&result); err != nil {
+			return keys, err
+		}
+		keys = append(keys, result.Key)
+	}
+	return keys, cur.Err()
+}
+
 func (dbService *StudyDBService) PerformActionForSurveyResponses(
 	ctx context.Context,
 	instanceID string,
@@ -181,20 +202,7 @@ func (dbService *StudyDBService) PerformActionForSurveyResponses(
 	cbk func(instanceID string, studyKey string, response types.SurveyResponse, args ...interface{}) error,
 	args ...interface{},
 ) (err error) {
-	filter := bson.M{}
-	if len(surveyKey) > 0 {
-		filter["key"] = surveyKey
-	}
-	if from > 0 && until > 0 {
-		filter["$and"] = bson.A{
-			bson.M{"submittedAt": bson.M{"$gt": from}},
-			bson.M{"submittedAt": bson.M{"$lt": until}},
-		}
-	} else if from > 0 {
-		filter["submittedAt"] = bson.M{"$gt": from}
-	} else if until > 0 {
-		filter["submittedAt"] = bson.M{"$lt": until}
-	}
+	filter := getSurveyResponsesFilter(surveyKey, from, until)
 	count, err := dbService.collectionRefSurveyResponses(instanceID, studyKey).CountDocuments(
 		ctx,
 		filter,
@@ -258,6 +266,122 @@ func (dbService *StudyDBService) PerformActionForSurveyResponses(
 	return nil
 }
 
+func getSurveyResponsesFilter(surveyKey string, from int64, until int64) bson.M {
+	filter := bson.M{}
+	if len(surveyKey) > 0 {
+		filter["key"] = surveyKey
+	}
+	if from > 0 && until > 0 {
+		filter["$and"] = bson.A{
+			bson.M{"submittedAt": bson.M{"$gt": from}},
+			bson.M{"submittedAt": bson.M{"$lt": until}},
+		}
+	} else if from > 0 {
+		filter["submittedAt"] = bson.M{"$gt": from}
+	} else if until > 0 {
+		filter["submittedAt"] = bson.M{"$lt": until}
+	}
+	return filter
+}
+
+// SurveyResponseCursor is the position of a response in the order of arrival (arrivedAt, then _id)
+type SurveyResponseCursor struct {
+	ArrivedAt int64
+	ID        primitive.ObjectID
+}
+
+// PerformActionForSurveyResponsesInArrivalOrder calls the callback for the responses that arrived after the cursor (all
+// responses if nil) and before arrivedBefore, ordered by arrivedAt and _id. Survey key and submission time are filtered
+// as in PerformActionForSurveyResponses.
+func (dbService *StudyDBService) PerformActionForSurveyResponsesInArrivalOrder(
+	ctx context.Context,
+	instanceID string,
+	studyKey string, surveyKey string, from int64, until int64,
+	after *SurveyResponseCursor, arrivedBefore int64,
+	cbk func(instanceID string, studyKey string, response types.SurveyResponse, args ...interface{}) error,
+	args ...interface{},
+) (err error) {
+	cursorFilter := bson.M{"$or": bson.A{
+		bson.M{"arrivedAt": bson.M{"$lt": arrivedBefore}},
+		// responses stored before arrivedAt was introduced
+		bson.M{"arrivedAt": nil},
+	}}
+	if after != nil {
+		cursorFilter = bson.M{"$and": bson.A{
+			bson.M{"arrivedAt": bson.M{"$lt": arrivedBefore}},
+			bson.M{"$or": bson.A{
+				bson.M{"arrivedAt": bson.M{"$gt": after.ArrivedAt}},
+				bson.M{"arrivedAt": after.ArrivedAt, "_id": bson.M{"$gt": after.ID}},
+			}},
+		}}
+	}
+	filter := bson.M{"$and": bson.A{
+		getSurveyResponsesFilter(surveyKey, from, until),
+		cursorFilter,
+	}}
+
+	batchSize := int32(32)
+	opts := options.Find()
+	opts.SetBatchSize(batchSize)
+	opts.SetSort(bson.D{
+		{Key: "arrivedAt", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cur, err := dbService.collectionRefSurveyResponses(instanceID, studyKey).Find(
+		ctx,
+		filter,
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var result types.SurveyResponse
+		err := cur.Decode(&result)
+		if err != nil {
+			return err
+		}
+
+		if err := cbk(instanceID, studyKey, result, args...); err != nil {
+			logger.Error.Println(err)
+		}
+	}
+	return cur.Err()
+}
+
+func (dbService *StudyDBService) CreateArrivedAtIndex(instanceID string, studyKey string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefSurveyResponses(instanceID, studyKey).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "arrivedAt", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		},
+	)
+	return err
+}
+
+func (dbService *StudyDBService) CreateArrivedAtIndexForAllStudies(instanceID string) {
+	studies, err := dbService.GetStudiesByStatus(instanceID, "", true)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching studies in '%s': %v", instanceID, err)
+		return
+	}
+
+	for _, study := range studies {
+		err = dbService.CreateArrivedAtIndex(instanceID, study.Key)
+		if err != nil {
+			logger.Error.Printf("unexpected error when creating response arrivedAt indexes: %v", err)
+		}
+	}
+}
+
 func (dbService *StudyDBService) UpdateParticipantIDonResponses(instanceID string, studyKey string, oldID string, newID string) (count int64, err error) {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
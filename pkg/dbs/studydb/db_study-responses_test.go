@@ -1,11 +1,14 @@
 package studydb
 
 import (
+	"context"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/coneno/logger"
 	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -189,3 +192,117 @@ func TestDbUpdateParticipantID(t *testing.T) {
 		}
 	})
 }
+
+func TestDbGetSurveyResponseContextKeys(t *testing.T) {
+	testStudyKey := "teststudy_for_response_context_keys"
+
+	surveyResps := []types.SurveyResponse{
+		{Key: "s1", ParticipantID: "u1", SubmittedAt: 100, Context: map[string]string{"engineVersion": "1.0.0", "language": "en"}},
+		{Key: "s1", ParticipantID: "u2", SubmittedAt: 200, Context: map[string]string{"engineVersion": "1.0.1", "session": "s"}},
+		{Key: "s1", ParticipantID: "u3", SubmittedAt: 300},
+		{Key: "s2", ParticipantID: "u1", SubmittedAt: 200, Context: map[string]string{"platform": "web"}},
+	}
+	for _, sr := range surveyResps {
+		_, err := testDBService.AddSurveyResponse(testInstanceID, testStudyKey, sr)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	}
+
+	t.Run("all responses of a survey", func(t *testing.T) {
+		keys, err := testDBService.GetSurveyResponseContextKeys(context.Background(), testInstanceID, testStudyKey, "s1", 0, 0)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if !reflect.DeepEqual(keys, []string{"engineVersion", "language", "session"}) {
+			t.Errorf("unexpected keys: %v", keys)
+		}
+	})
+
+	t.Run("with time range", func(t *testing.T) {
+		keys, err := testDBService.GetSurveyResponseContextKeys(context.Background(), testInstanceID, testStudyKey, "", 150, 250)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if !reflect.DeepEqual(keys, []string{"engineVersion", "platform", "session"}) {
+			t.Errorf("unexpected keys: %v", keys)
+		}
+	})
+}
+
+func TestDbPerformActionForSurveyResponsesInArrivalOrder(t *testing.T) {
+	testStudyKey := "teststudy_for_responses_in_arrival_order"
+	ctx := context.Background()
+
+	surveyResps := []types.SurveyResponse{
+		{Key: "s1", ParticipantID: "u4", SubmittedAt: 100, ArrivedAt: 300},
+		{Key: "s1", ParticipantID: "u2", SubmittedAt: 100, ArrivedAt: 200},
+		{Key: "s1", ParticipantID: "u3", SubmittedAt: 100, ArrivedAt: 200},
+		{Key: "s1", ParticipantID: "u1", SubmittedAt: 100, ArrivedAt: 100},
+		{Key: "s2", ParticipantID: "u1", SubmittedAt: 100, ArrivedAt: 150},
+		{Key: "s1", ParticipantID: "u5", SubmittedAt: 100, ArrivedAt: 1000},
+	}
+	collection := testDBService.collectionRefSurveyResponses(testInstanceID, testStudyKey)
+	ids := map[string]primitive.ObjectID{}
+	for _, sr := range surveyResps {
+		res, err := collection.InsertOne(ctx, sr)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		ids[sr.Key+sr.ParticipantID] = res.InsertedID.(primitive.ObjectID)
+	}
+	// response stored without arrivedAt
+	if _, err := collection.InsertOne(ctx, bson.M{"key": "s1", "participantID": "u0", "submittedAt": 100}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if err := testDBService.CreateArrivedAtIndex(testInstanceID, testStudyKey); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	getParticipants := func(after *SurveyResponseCursor) ([]string, error) {
+		participants := []string{}
+		err := testDBService.PerformActionForSurveyResponsesInArrivalOrder(ctx, testInstanceID, testStudyKey, "s1", 0, 0, after, 500,
+			func(instanceID, studyKey string, response types.SurveyResponse, args ...interface{}) error {
+				participants = append(participants, response.ParticipantID)
+				return nil
+			})
+		return participants, err
+	}
+
+	t.Run("without cursor", func(t *testing.T) {
+		participants, err := getParticipants(nil)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if !reflect.DeepEqual(participants, []string{"u0", "u1", "u2", "u3", "u4"}) {
+			t.Errorf("unexpected responses: %v", participants)
+		}
+	})
+
+	t.Run("after cursor", func(t *testing.T) {
+		participants, err := getParticipants(&SurveyResponseCursor{ArrivedAt: 200, ID: ids["s1u2"]})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if !reflect.DeepEqual(participants, []string{"u3", "u4"}) {
+			t.Errorf("unexpected responses: %v", participants)
+		}
+	})
+
+	t.Run("after last response", func(t *testing.T) {
+		participants, err := getParticipants(&SurveyResponseCursor{ArrivedAt: 300, ID: ids["s1u4"]})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(participants) > 0 {
+			t.Errorf("unexpected responses: %v", participants)
+		}
+	})
+}
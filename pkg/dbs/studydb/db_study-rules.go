@@ -1,6 +1,8 @@
 package studydb
 
 import (
+	"time"
+
 	"github.com/coneno/logger"
 	"github.com/influenzanet/study-service/pkg/types"
 	"github.com/influenzanet/study-service/pkg/utils"
@@ -10,10 +12,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// AddStudyRules saves a new study rules version. If activeFrom is not set, the version is active from its upload.
 func (dbService *StudyDBService) AddStudyRules(instanceID string, rules types.StudyRules) (types.StudyRules, error) {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
 
+	if rules.ActiveFrom == 0 {
+		rules.ActiveFrom = rules.UploadedAt
+	}
+
 	res, err := dbService.collectionRefStudyRules(instanceID).InsertOne(ctx, rules)
 	rules.ID = res.InsertedID.(primitive.ObjectID)
 	return rules, err
@@ -54,27 +61,59 @@ func (dbService *StudyDBService) DeleteStudyRulesByStudyKey(instanceID string, s
 	return nil
 }
 
+// GetCurrentStudyRules returns the study rules version active now
 func (dbService *StudyDBService) GetCurrentStudyRules(instanceID string, studyKey string) (*types.StudyRules, error) {
+	return dbService.GetStudyRulesActiveAt(instanceID, studyKey, time.Now().Unix())
+}
+
+// GetStudyRulesActiveAt returns the study rules version with the latest activeFrom before or at ts.
+// Versions saved before activeFrom was introduced are active from their upload.
+func (dbService *StudyDBService) GetStudyRulesActiveAt(instanceID string, studyKey string, ts int64) (*types.StudyRules, error) {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
 
-	sortByPublished := bson.D{
+	// versions without activeFrom are sorted last, they are all older than versions with activeFrom
+	sortByActivation := bson.D{
+		primitive.E{Key: "activeFrom", Value: -1},
 		primitive.E{Key: "uploadedAt", Value: -1},
 	}
 
 	filter := bson.M{
 		"studyKey": studyKey,
+		"$or": bson.A{
+			bson.M{"activeFrom": bson.M{"$lte": ts}},
+			bson.M{"activeFrom": bson.M{"$exists": false}, "uploadedAt": bson.M{"$lte": ts}},
+		},
 	}
 
 	elem := &types.StudyRules{}
 	opts := &options.FindOneOptions{
-		Sort: sortByPublished,
+		Sort: sortByActivation,
 	}
 
 	err := dbService.collectionRefStudyRules(instanceID).FindOne(ctx, filter, opts).Decode(&elem)
 	return elem, err
 }
 
+// GetStudyRulesVersion returns the study rules version with the given ID, if it belongs to the study
+func (dbService *StudyDBService) GetStudyRulesVersion(instanceID string, studyKey string, versionID string) (*types.StudyRules, error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(versionID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"_id":      id,
+		"studyKey": studyKey,
+	}
+
+	elem := &types.StudyRules{}
+	err = dbService.collectionRefStudyRules(instanceID).FindOne(ctx, filter).Decode(&elem)
+	return elem, err
+}
+
 func (dbService *StudyDBService) GetStudyKeyByStudyRulesID(instanceID string, versionID string) (studyKey string, err error) {
 	ctx, cancel := dbService.getContext()
 	defer cancel()
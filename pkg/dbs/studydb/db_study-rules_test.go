@@ -0,0 +1,89 @@
+package studydb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDbGetStudyRulesActiveAt(t *testing.T) {
+	testStudyKey := "teststudy_for_rules_activation"
+
+	// version saved before activeFrom was introduced
+	ctx, cancel := testDBService.getContext()
+	defer cancel()
+	_, err := testDBService.collectionRefStudyRules(testInstanceID).InsertOne(ctx, bson.M{
+		"studyKey":   testStudyKey,
+		"uploadedAt": int64(10),
+		"uploadedBy": "old",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	current, err := testDBService.AddStudyRules(testInstanceID, types.StudyRules{StudyKey: testStudyKey, UploadedAt: 100, UploadedBy: "current"})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if current.ActiveFrom != 100 {
+		t.Errorf("activeFrom should default to uploadedAt: %d", current.ActiveFrom)
+	}
+	scheduled, err := testDBService.AddStudyRules(testInstanceID, types.StudyRules{StudyKey: testStudyKey, UploadedAt: 110, UploadedBy: "scheduled", ActiveFrom: 200})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	_, err = testDBService.AddStudyRules(testInstanceID, types.StudyRules{StudyKey: testStudyKey, UploadedAt: 120, UploadedBy: "hotfix"})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	for _, c := range []struct {
+		ts       int64
+		expected string
+	}{
+		{ts: 50, expected: "old"},
+		{ts: 100, expected: "current"},
+		{ts: 150, expected: "hotfix"},
+		{ts: 200, expected: "scheduled"},
+		{ts: 300, expected: "scheduled"},
+	} {
+		rules, err := testDBService.GetStudyRulesActiveAt(testInstanceID, testStudyKey, c.ts)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			continue
+		}
+		if rules.UploadedBy != c.expected {
+			t.Errorf("unexpected version at %d: %s, expected %s", c.ts, rules.UploadedBy, c.expected)
+		}
+	}
+
+	t.Run("before first version", func(t *testing.T) {
+		_, err := testDBService.GetStudyRulesActiveAt(testInstanceID, testStudyKey, 5)
+		if err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("version by ID", func(t *testing.T) {
+		rules, err := testDBService.GetStudyRulesVersion(testInstanceID, testStudyKey, scheduled.ID.Hex())
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if rules.UploadedBy != "scheduled" || rules.ActiveFrom != 200 {
+			t.Errorf("unexpected version: %v", rules)
+		}
+		_, err = testDBService.GetStudyRulesVersion(testInstanceID, "otherstudy", scheduled.ID.Hex())
+		if err == nil {
+			t.Error("should not find version of another study")
+		}
+	})
+
+	_, _ = testDBService.collectionRefStudyRules(testInstanceID).DeleteMany(context.Background(), bson.M{"studyKey": testStudyKey})
+}
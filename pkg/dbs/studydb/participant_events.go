@@ -0,0 +1,113 @@
+package studydb
+
+import (
+	"context"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ParticipantEventQuery struct {
+	ParticipantID string
+	Since         int64
+	Until         int64
+}
+
+func (dbService *StudyDBService) AddParticipantEvent(instanceID string, studyKey string, event types.ParticipantEvent) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+	_, err := dbService.collectionRefParticipantEvents(instanceID, studyKey).InsertOne(ctx, event)
+	return err
+}
+
+// PerformActionForParticipantEvents calls cbk for each event matching the query, in chronological order
+func (dbService *StudyDBService) PerformActionForParticipantEvents(
+	ctx context.Context,
+	instanceID string,
+	studyKey string,
+	query ParticipantEventQuery,
+	cbk func(instanceID string, studyKey string, event types.ParticipantEvent, args ...interface{}) error,
+	args ...interface{},
+) (err error) {
+	filter := bson.M{}
+	opts := &options.FindOptions{
+		Sort: bson.D{
+			primitive.E{Key: "timestamp", Value: 1},
+		},
+	}
+
+	if len(query.ParticipantID) > 0 {
+		filter["participantID"] = query.ParticipantID
+	}
+
+	if query.Since > 0 && query.Until > 0 {
+		filter["$and"] = bson.A{
+			bson.M{"timestamp": bson.M{"$gt": query.Since}},
+			bson.M{"timestamp": bson.M{"$lt": query.Until}},
+		}
+	} else if query.Since > 0 {
+		filter["timestamp"] = bson.M{"$gt": query.Since}
+	} else if query.Until > 0 {
+		filter["timestamp"] = bson.M{"$lt": query.Until}
+	}
+
+	cur, err := dbService.collectionRefParticipantEvents(instanceID, studyKey).Find(
+		ctx,
+		filter,
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var result types.ParticipantEvent
+		err := cur.Decode(&result)
+		if err != nil {
+			return err
+		}
+
+		if err := cbk(instanceID, studyKey, result, args...); err != nil {
+			return err
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dbService *StudyDBService) CreateParticipantEventsIndex(instanceID string, studyKey string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefParticipantEvents(instanceID, studyKey).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "participantID", Value: 1},
+				{Key: "timestamp", Value: 1},
+			},
+		},
+	)
+	return err
+}
+
+func (dbService *StudyDBService) CreateParticipantEventsIndexForAllStudies(instanceID string) {
+	studies, err := dbService.GetStudiesByStatus(instanceID, "", true)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching studies in '%s': %v", instanceID, err)
+		return
+	}
+
+	for _, study := range studies {
+		err = dbService.CreateParticipantEventsIndex(instanceID, study.Key)
+		if err != nil {
+			logger.Error.Printf("unexpected error when creating participant events indexes: %v", err)
+		}
+	}
+}
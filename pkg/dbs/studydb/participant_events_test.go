@@ -0,0 +1,69 @@
+package studydb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestDbParticipantEvents(t *testing.T) {
+	testStudyKey := "teststudy_for_participant_events"
+
+	now := time.Now()
+	events := []types.ParticipantEvent{
+		{ParticipantID: "u1", EventType: "SUBMIT", EventKey: "weekly", Timestamp: now.Add(-2 * time.Hour).Unix()},
+		{ParticipantID: "u2", EventType: "ENTER", Timestamp: now.Add(-5 * time.Hour).Unix()},
+		{ParticipantID: "u1", EventType: "ENTER", Timestamp: now.Add(-5 * time.Hour).Unix(), StateChanges: types.ParticipantStateDiff{
+			AddedFlags: map[string]string{"group": "a"},
+		}},
+		{ParticipantID: "u1", EventType: "TIMER", Timestamp: now.Add(-1 * time.Hour).Unix()},
+	}
+	for _, e := range events {
+		err := testDBService.AddParticipantEvent(testInstanceID, testStudyKey, e)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	}
+
+	collect := func(query ParticipantEventQuery) ([]types.ParticipantEvent, error) {
+		found := []types.ParticipantEvent{}
+		err := testDBService.PerformActionForParticipantEvents(context.Background(), testInstanceID, testStudyKey, query,
+			func(instanceID string, studyKey string, event types.ParticipantEvent, args ...interface{}) error {
+				found = append(found, event)
+				return nil
+			},
+		)
+		return found, err
+	}
+
+	t.Run("all events of participant in order", func(t *testing.T) {
+		found, err := collect(ParticipantEventQuery{ParticipantID: "u1"})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(found) != 3 {
+			t.Errorf("unexpected number of events found: %d", len(found))
+			return
+		}
+		if found[0].EventType != "ENTER" || found[0].StateChanges.AddedFlags["group"] != "a" {
+			t.Errorf("unexpected first event: %v", found[0])
+		}
+		if found[2].EventType != "TIMER" {
+			t.Errorf("unexpected last event: %v", found[2])
+		}
+	})
+
+	t.Run("events since", func(t *testing.T) {
+		found, err := collect(ParticipantEventQuery{ParticipantID: "u1", Since: now.Add(-3 * time.Hour).Unix()})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(found) != 2 {
+			t.Errorf("unexpected number of events found: %d", len(found))
+		}
+	})
+}
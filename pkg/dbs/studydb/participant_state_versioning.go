@@ -0,0 +1,100 @@
+package studydb
+
+import (
+	"errors"
+	"expvar"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ParticipantStateUpdateRetries defines how often an update is repeated with the reloaded state after a conflict
+const ParticipantStateUpdateRetries = 3
+
+var ErrParticipantStateConflict = errors.New("participant state was modified concurrently")
+
+// participantStateConflicts is published with expvar, see the metrics port of the service
+var participantStateConflicts = expvar.NewInt("participant_state_conflicts")
+
+// ParticipantStateConflictCount returns the number of conflicting participant state updates since the service started
+func ParticipantStateConflictCount() int64 {
+	return participantStateConflicts.Value()
+}
+
+// UpdateParticipantStateIfUnchanged replaces the participant state only if the version in the DB is still the one of pState.
+// Returns ErrParticipantStateConflict if the state was modified in the meantime.
+func (dbService *StudyDBService) UpdateParticipantStateIfUnchanged(instanceID string, studyKey string, pState types.ParticipantState) (types.ParticipantState, error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	expectedVersion := pState.Version
+	filter := bson.M{"participantID": pState.ParticipantID, "version": expectedVersion}
+	if expectedVersion == 0 {
+		// states saved before versioning was introduced have no version attribute
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	pState.Version = expectedVersion + 1
+
+	rd := options.After
+	opts := options.FindOneAndReplaceOptions{
+		ReturnDocument: &rd,
+	}
+	elem := types.ParticipantState{}
+	err := dbService.collectionRefStudyParticipant(instanceID, studyKey).FindOneAndReplace(
+		ctx, filter, pState, &opts,
+	).Decode(&elem)
+	if err != mongo.ErrNoDocuments {
+		return elem, err
+	}
+
+	if expectedVersion == 0 {
+		// new participant, the unique participantID index rejects concurrent inserts
+		res, err := dbService.collectionRefStudyParticipant(instanceID, studyKey).InsertOne(ctx, pState)
+		if err == nil {
+			if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+				pState.ID = id
+			}
+			return pState, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return elem, err
+		}
+	}
+
+	participantStateConflicts.Add(1)
+	logger.Warning.Printf("conflicting update of participant state in %s-%s (version %d), conflicts since start: %d", instanceID, studyKey, expectedVersion, participantStateConflicts.Value())
+	return elem, ErrParticipantStateConflict
+}
+
+// UpdateParticipantStateWithRetry saves the result of update(pState) if the participant state was not modified concurrently.
+// On a conflict, the participant state is reloaded from the DB and update is called again, at most ParticipantStateUpdateRetries times,
+// so that rules are evaluated again on the current state instead of overwriting changes of e.g. the study timer.
+// Since update can be called several times, it should not have side effects, see studyengine.DeferredEffects.
+func (dbService *StudyDBService) UpdateParticipantStateWithRetry(
+	instanceID string,
+	studyKey string,
+	pState types.ParticipantState,
+	update func(pState types.ParticipantState) (types.ParticipantState, error),
+) (types.ParticipantState, error) {
+	for attempt := 0; ; attempt++ {
+		newState, err := update(pState)
+		if err != nil {
+			return newState, err
+		}
+		newState.Version = pState.Version
+
+		saved, err := dbService.UpdateParticipantStateIfUnchanged(instanceID, studyKey, newState)
+		if err != ErrParticipantStateConflict || attempt >= ParticipantStateUpdateRetries {
+			return saved, err
+		}
+
+		pState, err = dbService.FindParticipantState(instanceID, studyKey, pState.ParticipantID)
+		if err != nil {
+			return pState, err
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package studydb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDbParticipantStateVersioning(t *testing.T) {
+	testStudyKey := "teststudy_for_participant_state_versioning"
+
+	t.Run("insert new participant", func(t *testing.T) {
+		saved, err := testDBService.UpdateParticipantStateIfUnchanged(testInstanceID, testStudyKey, types.ParticipantState{
+			ParticipantID: "p1",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if saved.Version != 1 {
+			t.Errorf("unexpected version: %d", saved.Version)
+		}
+	})
+
+	t.Run("update with outdated version", func(t *testing.T) {
+		pState, err := testDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		// concurrent update
+		if _, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, pState); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		conflicts := ParticipantStateConflictCount()
+		pState.Flags = types.ParticipantFlags{"test": types.StringFlag("1")}
+		_, err = testDBService.UpdateParticipantStateIfUnchanged(testInstanceID, testStudyKey, pState)
+		if err != ErrParticipantStateConflict {
+			t.Errorf("expected conflict, got: %v", err)
+		}
+		if ParticipantStateConflictCount() != conflicts+1 {
+			t.Errorf("conflict should be counted")
+		}
+	})
+
+	t.Run("retry after conflict", func(t *testing.T) {
+		pState, err := testDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		calls := 0
+		saved, err := testDBService.UpdateParticipantStateWithRetry(testInstanceID, testStudyKey, pState, func(p types.ParticipantState) (types.ParticipantState, error) {
+			calls += 1
+			if calls == 1 {
+				// concurrent update assigning a survey
+				concurrent := p
+				concurrent.AssignedSurveys = []types.AssignedSurvey{{SurveyKey: "weekly"}}
+				if _, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, concurrent); err != nil {
+					return p, err
+				}
+			}
+			p.Flags = types.ParticipantFlags{"test": types.StringFlag("2")}
+			return p, nil
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if calls != 2 {
+			t.Errorf("unexpected number of calls: %d", calls)
+		}
+		if len(saved.AssignedSurveys) != 1 || saved.Flags["test"].String() != "2" {
+			t.Errorf("concurrent update should not be lost: %v", saved)
+		}
+	})
+
+	t.Run("save without version keeps the version increasing", func(t *testing.T) {
+		current, err := testDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		pState := types.ParticipantState{
+			ParticipantID: "p1",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_EXITED,
+		}
+		saved, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, pState)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if saved.Version != current.Version+1 || pState.Version != 0 {
+			t.Errorf("unexpected version: %d (stored before: %d)", saved.Version, current.Version)
+		}
+
+		// update based on the state before saving
+		current.StudyStatus = types.PARTICIPANT_STUDY_STATUS_ACTIVE
+		if _, err := testDBService.UpdateParticipantStateIfUnchanged(testInstanceID, testStudyKey, current); err != ErrParticipantStateConflict {
+			t.Errorf("expected conflict, got: %v", err)
+		}
+	})
+
+	t.Run("insert of existing participant", func(t *testing.T) {
+		if err := testDBService.CreateParticipantIDIndex(testInstanceID, testStudyKey); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		_, err := testDBService.UpdateParticipantStateIfUnchanged(testInstanceID, testStudyKey, types.ParticipantState{
+			ParticipantID: "p1",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		})
+		if err != ErrParticipantStateConflict {
+			t.Errorf("expected conflict, got: %v", err)
+		}
+	})
+
+	t.Run("update error is returned", func(t *testing.T) {
+		pState, err := testDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		updateErr := errors.New("test")
+		_, err = testDBService.UpdateParticipantStateWithRetry(testInstanceID, testStudyKey, pState, func(p types.ParticipantState) (types.ParticipantState, error) {
+			return p, updateErr
+		})
+		if err != updateErr {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDbCreateParticipantIDIndex(t *testing.T) {
+	testStudyKey := "teststudy_for_participant_id_index"
+	ctx, cancel := testDBService.getContext()
+	defer cancel()
+	collection := testDBService.collectionRefStudyParticipant(testInstanceID, testStudyKey)
+
+	indexNames := func() map[string]bool {
+		names := map[string]bool{}
+		specs, err := collection.Indexes().ListSpecifications(ctx)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		for _, spec := range specs {
+			names[spec.Name] = true
+		}
+		return names
+	}
+
+	// non-unique index and duplicates of previous versions
+	if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "participantID", Value: 1}}}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := collection.InsertOne(ctx, types.ParticipantState{ParticipantID: "dup"}); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+	if _, err := collection.InsertOne(ctx, types.ParticipantState{ParticipantID: "p1"}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	t.Run("with duplicate participant IDs", func(t *testing.T) {
+		err := testDBService.CreateParticipantIDIndex(testInstanceID, testStudyKey)
+		if err == nil || !strings.Contains(err.Error(), "dup") {
+			t.Errorf("duplicates should be reported: %v", err)
+		}
+		duplicates, err := testDBService.FindDuplicateParticipantIDs(testInstanceID, testStudyKey)
+		if err != nil || len(duplicates) != 1 || duplicates[0] != "dup" {
+			t.Errorf("unexpected duplicates: %v, %v", duplicates, err)
+		}
+		if names := indexNames(); !names["participantID_1"] || names[participantIDIndexName] {
+			t.Errorf("old index should be kept: %v", names)
+		}
+	})
+
+	t.Run("without duplicate participant IDs", func(t *testing.T) {
+		if _, err := collection.DeleteOne(ctx, bson.M{"participantID": "dup"}); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if err := testDBService.CreateParticipantIDIndex(testInstanceID, testStudyKey); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if names := indexNames(); names["participantID_1"] || !names[participantIDIndexName] {
+			t.Errorf("old index should be replaced: %v", names)
+		}
+		if _, err := collection.InsertOne(ctx, types.ParticipantState{ParticipantID: "p1"}); !mongo.IsDuplicateKeyError(err) {
+			t.Errorf("expected duplicate key error, got: %v", err)
+		}
+
+		// repeated on startup
+		if err := testDBService.CreateParticipantIDIndex(testInstanceID, testStudyKey); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	})
+}
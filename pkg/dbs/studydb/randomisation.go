@@ -0,0 +1,248 @@
+package studydb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// number of attempts to allocate an arm, if concurrent allocations are competing for the same block
+const maxRandomisationAttempts = 10
+
+var ErrRandomisationConflict = errors.New("could not allocate arm due to concurrent allocations")
+
+func (dbService *StudyDBService) CreateRandomisationIndexes(instanceID string, studyKey string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefRandomisationBlocks(instanceID, studyKey).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "schemeKey", Value: 1},
+				{Key: "stratum", Value: 1},
+				{Key: "blockIndex", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbService.collectionRefRandomisationAllocations(instanceID, studyKey).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "schemeKey", Value: 1},
+				{Key: "participantID", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	return err
+}
+
+func (dbService *StudyDBService) CreateRandomisationIndexesForAllStudies(instanceID string) {
+	studies, err := dbService.GetStudiesByStatus(instanceID, "", true)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching studies in '%s': %v", instanceID, err)
+		return
+	}
+
+	for _, study := range studies {
+		err = dbService.CreateRandomisationIndexes(instanceID, study.Key)
+		if err != nil {
+			logger.Error.Printf("unexpected error when creating randomisation indexes: %v", err)
+		}
+	}
+}
+
+// GetRandomisationScheme finds the randomisation scheme in the configs of the study
+func (dbService *StudyDBService) GetRandomisationScheme(instanceID string, studyKey string, schemeKey string) (types.RandomisationScheme, error) {
+	study, err := dbService.GetStudyByStudyKey(instanceID, studyKey)
+	if err != nil {
+		return types.RandomisationScheme{}, err
+	}
+	scheme, ok := study.Configs.GetRandomisationScheme(schemeKey)
+	if !ok {
+		return scheme, fmt.Errorf("randomisation scheme '%s' not found", schemeKey)
+	}
+	return scheme, nil
+}
+
+// SaveRandomisationScheme replaces the scheme with the same key in the study configs, or adds it to the configs.
+// If scheme.Seed is empty, the seed of the existing scheme is kept, and a new scheme gets a random seed.
+func (dbService *StudyDBService) SaveRandomisationScheme(instanceID string, studyKey string, scheme types.RandomisationScheme) (types.Study, error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	elem := types.Study{}
+	for attempt := 0; attempt < 2; attempt++ {
+		set := bson.M{
+			"configs.randomisationSchemes.$.arms":      scheme.Arms,
+			"configs.randomisationSchemes.$.blockSize": scheme.BlockSize,
+			"configs.randomisationSchemes.$.armFlag":   scheme.ArmFlag,
+		}
+		if scheme.Seed != "" {
+			set["configs.randomisationSchemes.$.seed"] = scheme.Seed
+		}
+		filter := bson.M{"key": studyKey, "configs.randomisationSchemes.key": scheme.Key}
+		err := dbService.collectionRefStudyInfos(instanceID).FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&elem)
+		if err != mongo.ErrNoDocuments {
+			return elem, err
+		}
+
+		newScheme := scheme
+		if newScheme.Seed == "" {
+			newScheme.Seed, err = types.NewRandomisationSeed()
+			if err != nil {
+				return elem, err
+			}
+		}
+		filter = bson.M{"key": studyKey, "configs.randomisationSchemes.key": bson.M{"$ne": scheme.Key}}
+		update := bson.M{"$push": bson.M{"configs.randomisationSchemes": newScheme}}
+		err = dbService.collectionRefStudyInfos(instanceID).FindOneAndUpdate(ctx, filter, update, opts).Decode(&elem)
+		if err != mongo.ErrNoDocuments {
+			return elem, err
+		}
+		// the scheme was added concurrently, or the study does not exist
+	}
+	return elem, mongo.ErrNoDocuments
+}
+
+// FindRandomisationAllocation returns the allocation of the participant, mongo.ErrNoDocuments if not allocated yet
+func (dbService *StudyDBService) FindRandomisationAllocation(instanceID string, studyKey string, schemeKey string, participantID string) (allocation types.RandomisationAllocation, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{"schemeKey": schemeKey, "participantID": participantID}
+	err = dbService.collectionRefRandomisationAllocations(instanceID, studyKey).FindOne(ctx, filter).Decode(&allocation)
+	return
+}
+
+// AllocateRandomisationArm allocates the next arm of the stratum's current block to the participant, and records the allocation.
+// If the participant was already allocated in this scheme, the existing allocation is returned.
+func (dbService *StudyDBService) AllocateRandomisationArm(instanceID string, studyKey string, scheme types.RandomisationScheme, stratum string, participantID string, allocatedAt int64) (types.RandomisationAllocation, error) {
+	for i := 0; i < maxRandomisationAttempts; i++ {
+		allocation, err := dbService.FindRandomisationAllocation(instanceID, studyKey, scheme.Key, participantID)
+		if err == nil {
+			return allocation, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return allocation, err
+		}
+
+		block, err := dbService.takeRandomisationBlockPosition(instanceID, studyKey, scheme.Key, stratum)
+		if err == mongo.ErrNoDocuments {
+			// all blocks of the stratum are used (or none exists yet)
+			err = dbService.createNextRandomisationBlock(instanceID, studyKey, scheme, stratum)
+			if err != nil && !mongo.IsDuplicateKeyError(err) {
+				return allocation, err
+			}
+			continue
+		}
+		if err != nil {
+			return allocation, err
+		}
+
+		allocation = types.RandomisationAllocation{
+			SchemeKey:     scheme.Key,
+			ParticipantID: participantID,
+			Stratum:       stratum,
+			Arm:           block.Arms[block.Position],
+			BlockIndex:    block.BlockIndex,
+			Position:      block.Position,
+			AllocatedAt:   allocatedAt,
+		}
+		err = dbService.saveRandomisationAllocation(instanceID, studyKey, allocation)
+		if mongo.IsDuplicateKeyError(err) {
+			// the participant was allocated concurrently, the taken position stays unused
+			logger.Warning.Printf("participant %s allocated concurrently in scheme %s, position %d of block %d skipped", participantID, scheme.Key, block.Position, block.BlockIndex)
+			continue
+		}
+		return allocation, err
+	}
+	return types.RandomisationAllocation{}, ErrRandomisationConflict
+}
+
+// takeRandomisationBlockPosition increments the position of the first block of the stratum that is not fully allocated,
+// and returns the block as it was before the increment.
+func (dbService *StudyDBService) takeRandomisationBlockPosition(instanceID string, studyKey string, schemeKey string, stratum string) (block types.RandomisationBlock, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{
+		"schemeKey": schemeKey,
+		"stratum":   stratum,
+		"$expr":     bson.M{"$lt": bson.A{"$position", "$size"}},
+	}
+	update := bson.M{"$inc": bson.M{"position": 1}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "blockIndex", Value: 1}}).SetReturnDocument(options.Before)
+
+	err = dbService.collectionRefRandomisationBlocks(instanceID, studyKey).FindOneAndUpdate(ctx, filter, update, opts).Decode(&block)
+	return
+}
+
+// createNextRandomisationBlock inserts the block following the last block of the stratum.
+// Returns a duplicate key error if the block was created concurrently.
+func (dbService *StudyDBService) createNextRandomisationBlock(instanceID string, studyKey string, scheme types.RandomisationScheme, stratum string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{"schemeKey": scheme.Key, "stratum": stratum}
+	opts := options.FindOne().SetSort(bson.D{{Key: "blockIndex", Value: -1}})
+
+	nextIndex := int64(0)
+	var last types.RandomisationBlock
+	err := dbService.collectionRefRandomisationBlocks(instanceID, studyKey).FindOne(ctx, filter, opts).Decode(&last)
+	if err == nil {
+		nextIndex = last.BlockIndex + 1
+	} else if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	_, err = dbService.collectionRefRandomisationBlocks(instanceID, studyKey).InsertOne(ctx, scheme.NewBlock(stratum, nextIndex))
+	return err
+}
+
+func (dbService *StudyDBService) saveRandomisationAllocation(instanceID string, studyKey string, allocation types.RandomisationAllocation) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefRandomisationAllocations(instanceID, studyKey).InsertOne(ctx, allocation)
+	return err
+}
+
+// CountRandomisationAllocations returns the number of participants allocated to each arm, per stratum
+func (dbService *StudyDBService) CountRandomisationAllocations(instanceID string, studyKey string, schemeKey string) (counts []types.RandomisationAllocationCount, err error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"schemeKey": schemeKey}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"stratum": "$stratum", "arm": "$arm"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":     0,
+			"stratum": "$_id.stratum",
+			"arm":     "$_id.arm",
+			"count":   1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "stratum", Value: 1}, {Key: "arm", Value: 1}}}},
+	}
+	cur, err := dbService.collectionRefRandomisationAllocations(instanceID, studyKey).Aggregate(ctx, pipeline)
+	if err != nil {
+		return counts, err
+	}
+	defer cur.Close(ctx)
+
+	counts = []types.RandomisationAllocationCount{}
+	err = cur.All(ctx, &counts)
+	return counts, err
+}
@@ -0,0 +1,63 @@
+package studydb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestDbRandomisationAllocation(t *testing.T) {
+	testStudyKey := "teststudy_for_randomisation"
+	if err := testDBService.CreateRandomisationIndexes(testInstanceID, testStudyKey); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	scheme := types.RandomisationScheme{
+		Key:       "trial",
+		Arms:      []types.RandomisationArm{{Key: "A", Ratio: 1}, {Key: "B", Ratio: 2}},
+		BlockSize: 3,
+		Seed:      "testseed",
+	}
+
+	t.Run("allocate blocks in order", func(t *testing.T) {
+		for i := 0; i < 6; i++ {
+			allocation, err := testDBService.AllocateRandomisationArm(testInstanceID, testStudyKey, scheme, "sex=f", fmt.Sprintf("p%d", i), 100)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+			block := scheme.NewBlock("sex=f", int64(i/3))
+			if allocation.BlockIndex != int64(i/3) || allocation.Position != int64(i%3) || allocation.Arm != block.Arms[i%3] {
+				t.Errorf("unexpected allocation: %v", allocation)
+			}
+		}
+	})
+
+	t.Run("existing allocation is returned", func(t *testing.T) {
+		before, err := testDBService.FindRandomisationAllocation(testInstanceID, testStudyKey, "trial", "p0")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		allocation, err := testDBService.AllocateRandomisationArm(testInstanceID, testStudyKey, scheme, "sex=m", "p0", 200)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if allocation.Arm != before.Arm || allocation.Stratum != "sex=f" || allocation.AllocatedAt != 100 {
+			t.Errorf("unexpected allocation: %v", allocation)
+		}
+	})
+
+	t.Run("count allocations", func(t *testing.T) {
+		counts, err := testDBService.CountRandomisationAllocations(testInstanceID, testStudyKey, "trial")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(counts) != 2 || counts[0].Arm != "A" || counts[0].Count != 2 || counts[1].Arm != "B" || counts[1].Count != 4 {
+			t.Errorf("unexpected counts: %v", counts)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package studydb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrTimerLeaseNotAcquired = errors.New("timer lease is held by another owner")
+	ErrTimerLeaseLost        = errors.New("timer lease is not held anymore")
+)
+
+func (dbService *StudyDBService) CreateTimerLeaseIndex(instanceID string) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	_, err := dbService.collectionRefTimerLeases(instanceID).Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "studyKey", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	return err
+}
+
+// AcquireTimerLease takes the lease of the study if it is expired or already held by owner.
+// Returns ErrTimerLeaseNotAcquired if another owner holds a valid lease.
+func (dbService *StudyDBService) AcquireTimerLease(instanceID string, studyKey string, owner string, leaseDuration int64) (types.TimerLease, error) {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	now := time.Now().Unix()
+	filter := bson.M{
+		"studyKey": studyKey,
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$lt": now}},
+			bson.M{"owner": owner},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":     owner,
+		"expiresAt": now + leaseDuration,
+		"renewedAt": now,
+	}}
+	rd := options.After
+	upsert := true
+	opts := options.FindOneAndUpdateOptions{
+		ReturnDocument: &rd,
+		Upsert:         &upsert,
+	}
+
+	elem := types.TimerLease{}
+	err := dbService.collectionRefTimerLeases(instanceID).FindOneAndUpdate(ctx, filter, update, &opts).Decode(&elem)
+	if mongo.IsDuplicateKeyError(err) {
+		// lease exists, but is held by someone else
+		return elem, ErrTimerLeaseNotAcquired
+	}
+	return elem, err
+}
+
+// RenewTimerLease extends the lease (heartbeat). Returns ErrTimerLeaseLost if owner does not hold the lease anymore.
+func (dbService *StudyDBService) RenewTimerLease(instanceID string, studyKey string, owner string, leaseDuration int64) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	now := time.Now().Unix()
+	filter := bson.M{"studyKey": studyKey, "owner": owner}
+	update := bson.M{"$set": bson.M{
+		"expiresAt": now + leaseDuration,
+		"renewedAt": now,
+	}}
+	res, err := dbService.collectionRefTimerLeases(instanceID).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount < 1 {
+		return ErrTimerLeaseLost
+	}
+	return nil
+}
+
+// SaveTimerLeaseCheckpoint stores the _id of the last processed participant, so that an interrupted run can be resumed
+func (dbService *StudyDBService) SaveTimerLeaseCheckpoint(instanceID string, studyKey string, owner string, lastParticipant primitive.ObjectID) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{"studyKey": studyKey, "owner": owner}
+	update := bson.M{"$set": bson.M{"checkpoint": lastParticipant}}
+	res, err := dbService.collectionRefTimerLeases(instanceID).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount < 1 {
+		return ErrTimerLeaseLost
+	}
+	return nil
+}
+
+// ReleaseTimerLease lets the lease expire immediately. If the run was completed, the checkpoint is removed.
+func (dbService *StudyDBService) ReleaseTimerLease(instanceID string, studyKey string, owner string, runCompleted bool) error {
+	ctx, cancel := dbService.getContext()
+	defer cancel()
+
+	filter := bson.M{"studyKey": studyKey, "owner": owner}
+	update := bson.M{"$set": bson.M{"expiresAt": 0}}
+	if runCompleted {
+		update = bson.M{
+			"$set":   bson.M{"expiresAt": 0, "lastRunCompletedAt": time.Now().Unix()},
+			"$unset": bson.M{"checkpoint": ""},
+		}
+	}
+	_, err := dbService.collectionRefTimerLeases(instanceID).UpdateOne(ctx, filter, update)
+	return err
+}
@@ -0,0 +1,122 @@
+package studydb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDbTimerLeases(t *testing.T) {
+	testStudyKey := "teststudy_for_timer_leases"
+	if err := testDBService.CreateTimerLeaseIndex(testInstanceID); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	t.Run("acquire new lease", func(t *testing.T) {
+		lease, err := testDBService.AcquireTimerLease(testInstanceID, testStudyKey, "owner1", 60)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if lease.Owner != "owner1" || lease.HasCheckpoint() {
+			t.Errorf("unexpected lease: %v", lease)
+		}
+	})
+
+	t.Run("lease held by other owner", func(t *testing.T) {
+		_, err := testDBService.AcquireTimerLease(testInstanceID, testStudyKey, "owner2", 60)
+		if err != ErrTimerLeaseNotAcquired {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := testDBService.RenewTimerLease(testInstanceID, testStudyKey, "owner2", 60); err != ErrTimerLeaseLost {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("renew and checkpoint", func(t *testing.T) {
+		if err := testDBService.RenewTimerLease(testInstanceID, testStudyKey, "owner1", 60); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if err := testDBService.SaveTimerLeaseCheckpoint(testInstanceID, testStudyKey, "owner1", primitive.NewObjectID()); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("take over released lease with checkpoint", func(t *testing.T) {
+		if err := testDBService.ReleaseTimerLease(testInstanceID, testStudyKey, "owner1", false); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		lease, err := testDBService.AcquireTimerLease(testInstanceID, testStudyKey, "owner2", 60)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if lease.Owner != "owner2" || !lease.HasCheckpoint() {
+			t.Errorf("unexpected lease: %v", lease)
+		}
+	})
+
+	t.Run("completed run removes checkpoint", func(t *testing.T) {
+		if err := testDBService.ReleaseTimerLease(testInstanceID, testStudyKey, "owner2", true); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		lease, err := testDBService.AcquireTimerLease(testInstanceID, testStudyKey, "owner1", 60)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if lease.HasCheckpoint() || lease.LastRunCompletedAt == 0 {
+			t.Errorf("unexpected lease: %v", lease)
+		}
+	})
+}
+
+func TestFindAndExecuteOnParticipantsStatesAfter(t *testing.T) {
+	testStudyKey := "teststudy_findandexecute_after"
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, err := testDBService.OverwriteParticipantState(testInstanceID, testStudyKey, types.ParticipantState{
+			ParticipantID: id,
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	collect := func(after primitive.ObjectID) ([]types.ParticipantState, error) {
+		found := []types.ParticipantState{}
+		err := testDBService.FindAndExecuteOnParticipantsStatesAfter(context.Background(), testInstanceID, testStudyKey, types.PARTICIPANT_STUDY_STATUS_ACTIVE, after,
+			func(dbService *StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
+				found = append(found, p)
+				return nil
+			},
+		)
+		return found, err
+	}
+
+	all, err := collect(primitive.NilObjectID)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if len(all) != 3 {
+		t.Errorf("unexpected number of participants: %d", len(all))
+		return
+	}
+
+	rest, err := collect(all[0].ID)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if len(rest) != 2 || rest[0].ID != all[1].ID || rest[1].ID != all[2].ID {
+		t.Errorf("unexpected participants after checkpoint: %v", rest)
+	}
+}
@@ -0,0 +1,528 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	CODEBOOK_VARIABLE_FIXED    = "fixed"
+	CODEBOOK_VARIABLE_CONTEXT  = "context"
+	CODEBOOK_VARIABLE_RESPONSE = "response"
+	CODEBOOK_VARIABLE_META     = "meta"
+	CODEBOOK_VARIABLE_OVERFLOW = "overflow"
+)
+
+// Placeholders used in the name patterns of the codebook variables
+const (
+	NAME_PATTERN_QUESTION_KEY = "{questionKey}"
+	NAME_PATTERN_RESPONSE_KEY = "{responseKey}"
+	NAME_PATTERN_OPTION_KEY   = "{optionKey}"
+	NAME_PATTERN_SEP          = "{sep}"
+)
+
+// Codebook describes the columns of the response exports of a survey, generated from the survey versions
+type Codebook struct {
+	SurveyKey         string             `json:"surveyKey"`
+	QuestionOptionSep string             `json:"questionOptionSep"`
+	OpenFieldSuffix   string             `json:"openFieldSuffix"`
+	Languages         []string           `json:"languages"`
+	Versions          []CodebookVersion  `json:"versions"`
+	Variables         []CodebookVariable `json:"variables"`
+}
+
+type CodebookVersion struct {
+	VersionID   string `json:"versionId"`
+	Published   int64  `json:"published"`
+	Unpublished int64  `json:"unpublished,omitempty"`
+}
+
+type CodebookVariable struct {
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	QuestionKey  string `json:"questionKey,omitempty"`
+	QuestionType string `json:"questionType,omitempty"`
+	ResponseKey  string `json:"responseKey,omitempty"`
+	OptionKey    string `json:"optionKey,omitempty"`
+	OpenField    bool   `json:"openField,omitempty"`
+	DType        string `json:"dtype"`
+	// NamePattern shows how the column name is composed, e.g. "{questionKey}{sep}{optionKey}{sep}open"
+	NamePattern  string            `json:"namePattern"`
+	QuestionText map[string]string `json:"questionText,omitempty"`
+	Label        map[string]string `json:"label,omitempty"`
+	Codes        []CodebookCode    `json:"codes,omitempty"`
+	Versions     []string          `json:"versions"`
+}
+
+// CodebookCode is a value of a column with a defined meaning, e.g. the key of a single choice option
+type CodebookCode struct {
+	Code  string            `json:"code"`
+	Label map[string]string `json:"label,omitempty"`
+}
+
+// codebookColumn describes a response column of a question, labels are filled per language
+type codebookColumn struct {
+	responseKey string
+	optionKey   string
+	openField   bool
+	namePattern string
+	codes       []string
+}
+
+// GetCodebook returns the description of all columns exported for the survey versions, with texts in the languages.
+// Meta columns are listed as selected by includeMeta, followed by the overflow column of the Parquet format (and of the
+// wide format if requested).
+// Context columns depend on the responses and are not listed.
+func (rp ResponseExporter) GetCodebook(languages []string, includeMeta *IncludeMeta) Codebook {
+	cb := Codebook{
+		SurveyKey:         rp.surveyKey,
+		QuestionOptionSep: rp.questionOptionKeySep,
+		OpenFieldSuffix:   OPEN_FIELD_COL_SUFFIX,
+		Languages:         languages,
+		Versions:          []CodebookVersion{},
+		Variables:         []CodebookVariable{},
+	}
+
+	for _, k := range fixedColumnKeys {
+		dtype := COLUMN_TYPE_STRING
+		if k == "opened" || k == "submitted" {
+			dtype = COLUMN_TYPE_TIMESTAMP
+		}
+		cb.Variables = append(cb.Variables, CodebookVariable{
+			Name:        k,
+			Kind:        CODEBOOK_VARIABLE_FIXED,
+			DType:       dtype,
+			NamePattern: k,
+			Versions:    []string{},
+		})
+	}
+
+	previews := map[string][]SurveyVersionPreview{}
+	for _, lang := range languages {
+		previews[lang] = rp.getSurveyVersionPreviews(lang)
+	}
+
+	responseVariables := map[string]*CodebookVariable{}
+	metaVariables := map[string]*CodebookVariable{}
+	for vInd, sv := range rp.surveyVersions {
+		versionID := sv.VersionID
+		if versionID == "" {
+			versionID = fmt.Sprintf("%d", vInd)
+		}
+		cb.Versions = append(cb.Versions, CodebookVersion{
+			VersionID:   versionID,
+			Published:   sv.Published,
+			Unpublished: sv.Unpublished,
+		})
+
+		for qInd, question := range sv.Questions {
+			colTypes := getResponseColumnTypes(question, rp.questionOptionKeySep)
+			columns := describeResponseColumns(question, rp.questionOptionKeySep)
+			for colName := range getResponseColumns(question, nil, rp.questionOptionKeySep) {
+				if v, ok := responseVariables[colName]; ok {
+					v.Versions = append(v.Versions, versionID)
+					continue
+				}
+
+				col, ok := columns[colName]
+				if !ok {
+					col = codebookColumn{namePattern: NAME_PATTERN_QUESTION_KEY}
+				}
+				dtype, ok := colTypes[colName]
+				if !ok {
+					dtype = COLUMN_TYPE_STRING
+				}
+				v := &CodebookVariable{
+					Name:         colName,
+					Kind:         CODEBOOK_VARIABLE_RESPONSE,
+					QuestionKey:  question.ID,
+					QuestionType: question.QuestionType,
+					ResponseKey:  col.responseKey,
+					OptionKey:    col.optionKey,
+					OpenField:    col.openField,
+					DType:        dtype,
+					NamePattern:  col.namePattern,
+					Versions:     []string{versionID},
+				}
+				for _, code := range col.codes {
+					v.Codes = append(v.Codes, CodebookCode{Code: code})
+				}
+				for _, lang := range languages {
+					translatedQuestion := previews[lang][vInd].Questions[qInd]
+					v.addTexts(lang, translatedQuestion)
+				}
+				responseVariables[colName] = v
+			}
+
+			for _, suffix := range []string{"metaInit", "metaDisplayed", "metaResponse", "metaPosition"} {
+				if !includeMetaColumn(suffix, includeMeta) {
+					continue
+				}
+				colName := question.ID + rp.questionOptionKeySep + suffix
+				if v, ok := metaVariables[colName]; ok {
+					v.Versions = append(v.Versions, versionID)
+					continue
+				}
+				dtype := COLUMN_TYPE_STRING
+				if suffix == "metaPosition" {
+					dtype = COLUMN_TYPE_INTEGER
+				}
+				metaVariables[colName] = &CodebookVariable{
+					Name:         colName,
+					Kind:         CODEBOOK_VARIABLE_META,
+					QuestionKey:  question.ID,
+					QuestionType: question.QuestionType,
+					DType:        dtype,
+					NamePattern:  NAME_PATTERN_QUESTION_KEY + NAME_PATTERN_SEP + suffix,
+					Versions:     []string{versionID},
+				}
+			}
+		}
+	}
+
+	cb.Variables = append(cb.Variables, sortedCodebookVariables(responseVariables)...)
+	cb.Variables = append(cb.Variables, sortedCodebookVariables(metaVariables)...)
+	cb.Variables = append(cb.Variables, CodebookVariable{
+		Name:        OVERFLOW_COL_NAME,
+		Kind:        CODEBOOK_VARIABLE_OVERFLOW,
+		DType:       COLUMN_TYPE_STRING,
+		NamePattern: OVERFLOW_COL_NAME,
+		Versions:    []string{},
+	})
+	return cb
+}
+
+// addTexts sets the question text, and the labels of the column and its codes in the language
+func (v *CodebookVariable) addTexts(lang string, question SurveyQuestion) {
+	if question.Title != "" {
+		if v.QuestionText == nil {
+			v.QuestionText = map[string]string{}
+		}
+		v.QuestionText[lang] = question.Title
+	}
+
+	for _, slot := range question.Responses {
+		if slot.ID != v.ResponseKey {
+			continue
+		}
+		label := slot.Label
+		for _, option := range slot.Options {
+			if v.OptionKey != "" && option.ID == v.OptionKey {
+				label = option.Label
+			}
+			for i, code := range v.Codes {
+				if code.Code == option.ID && option.Label != "" {
+					if v.Codes[i].Label == nil {
+						v.Codes[i].Label = map[string]string{}
+					}
+					v.Codes[i].Label[lang] = option.Label
+				}
+			}
+		}
+		if label != "" {
+			if v.Label == nil {
+				v.Label = map[string]string{}
+			}
+			v.Label[lang] = label
+		}
+	}
+}
+
+// describeResponseColumns returns the possible response columns of the question with the composition of their names.
+// The columns actually exported depend on the question type (see getResponseColumns).
+func describeResponseColumns(question SurveyQuestion, questionOptionSep string) map[string]codebookColumn {
+	columns := map[string]codebookColumn{}
+	addColumn := func(name string, col codebookColumn) {
+		if _, ok := columns[name]; !ok {
+			columns[name] = col
+		}
+	}
+
+	singleSlot := len(question.Responses) == 1
+	for _, slot := range question.Responses {
+		codes := []string{}
+		for _, option := range slot.Options {
+			if option.OptionType == OPTION_TYPE_RADIO || option.OptionType == OPTION_TYPE_DROPDOWN_OPTION || option.OptionType == OPTION_TYPE_CLOZE {
+				codes = append(codes, option.ID)
+			}
+		}
+
+		slotCol := codebookColumn{
+			responseKey: slot.ID,
+			namePattern: NAME_PATTERN_QUESTION_KEY + NAME_PATTERN_SEP + NAME_PATTERN_RESPONSE_KEY,
+			codes:       codes,
+		}
+		optionPrefix := question.ID + questionOptionSep + slot.ID + "."
+		optionPattern := NAME_PATTERN_QUESTION_KEY + NAME_PATTERN_SEP + NAME_PATTERN_RESPONSE_KEY + "." + NAME_PATTERN_OPTION_KEY
+		if singleSlot {
+			singleSlotCol := slotCol
+			singleSlotCol.namePattern = NAME_PATTERN_QUESTION_KEY
+			addColumn(question.ID, singleSlotCol)
+			optionPrefix = question.ID + questionOptionSep
+			optionPattern = NAME_PATTERN_QUESTION_KEY + NAME_PATTERN_SEP + NAME_PATTERN_OPTION_KEY
+		}
+		addColumn(question.ID+questionOptionSep+slot.ID, slotCol)
+
+		for _, option := range slot.Options {
+			addColumn(optionPrefix+option.ID, codebookColumn{
+				responseKey: slot.ID,
+				optionKey:   option.ID,
+				namePattern: optionPattern,
+			})
+			addColumn(optionPrefix+option.ID+questionOptionSep+OPEN_FIELD_COL_SUFFIX, codebookColumn{
+				responseKey: slot.ID,
+				optionKey:   option.ID,
+				openField:   true,
+				namePattern: optionPattern + NAME_PATTERN_SEP + OPEN_FIELD_COL_SUFFIX,
+			})
+		}
+	}
+	return columns
+}
+
+func includeMetaColumn(suffix string, includeMeta *IncludeMeta) bool {
+	if includeMeta == nil {
+		return false
+	}
+	switch suffix {
+	case "metaInit":
+		return includeMeta.InitTimes
+	case "metaDisplayed":
+		return includeMeta.DisplayedTimes
+	case "metaResponse":
+		return includeMeta.ResponsedTimes
+	case "metaPosition":
+		return includeMeta.Postion
+	}
+	return false
+}
+
+func sortedCodebookVariables(variables map[string]*CodebookVariable) []CodebookVariable {
+	names := make([]string, 0, len(variables))
+	for k := range variables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	sorted := make([]CodebookVariable, 0, len(names))
+	for _, k := range names {
+		sorted = append(sorted, *variables[k])
+	}
+	return sorted
+}
+
+// GetCodebookJSON writes the codebook as JSON object
+func (rp ResponseExporter) GetCodebookJSON(writer io.Writer, languages []string, includeMeta *IncludeMeta) error {
+	b, err := json.Marshal(rp.GetCodebook(languages, includeMeta))
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(b)
+	return err
+}
+
+// GetCodebookCSV writes one line per variable and code (one line with empty code for variables without codes).
+// Texts have one column per language, e.g. questionText_en.
+func (rp ResponseExporter) GetCodebookCSV(writer io.Writer, languages []string, includeMeta *IncludeMeta) error {
+	cb := rp.GetCodebook(languages, includeMeta)
+
+	header := []string{
+		"variable", "kind", "questionKey", "questionType", "responseKey", "optionKey", "openField", "dtype", "namePattern", "versions",
+	}
+	for _, lang := range languages {
+		header = append(header, "questionText_"+lang)
+	}
+	for _, lang := range languages {
+		header = append(header, "label_"+lang)
+	}
+	header = append(header, "code")
+	for _, lang := range languages {
+		header = append(header, "codeLabel_"+lang)
+	}
+
+	// Init writer
+	w := csv.NewWriter(writer)
+
+	// Write header
+	err := w.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range cb.Variables {
+		variableCols := []string{
+			v.Name,
+			v.Kind,
+			v.QuestionKey,
+			v.QuestionType,
+			v.ResponseKey,
+			v.OptionKey,
+			fmt.Sprintf("%t", v.OpenField),
+			v.DType,
+			v.NamePattern,
+			strings.Join(v.Versions, ";"),
+		}
+		for _, lang := range languages {
+			variableCols = append(variableCols, v.QuestionText[lang])
+		}
+		for _, lang := range languages {
+			variableCols = append(variableCols, v.Label[lang])
+		}
+
+		codes := v.Codes
+		if len(codes) == 0 {
+			codes = []CodebookCode{{}}
+		}
+		for _, code := range codes {
+			line := []string{}
+			line = append(line, variableCols...)
+			line = append(line, code.Code)
+			for _, lang := range languages {
+				line = append(line, code.Label[lang])
+			}
+			err := w.Write(line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// DDI-Codebook (version 2.5) elements used for the XML export
+type ddiCodeBook struct {
+	XMLName  xml.Name    `xml:"ddi:codebook:2_5 codeBook"`
+	Version  string      `xml:"version,attr"`
+	StdyDscr ddiStdyDscr `xml:"stdyDscr"`
+	FileDscr ddiFileDscr `xml:"fileDscr"`
+	DataDscr ddiDataDscr `xml:"dataDscr"`
+}
+
+type ddiStdyDscr struct {
+	Title string `xml:"citation>titlStmt>titl"`
+}
+
+type ddiFileDscr struct {
+	ID    string   `xml:"ID,attr"`
+	Notes []string `xml:"notes"`
+}
+
+type ddiDataDscr struct {
+	Vars []ddiVar `xml:"var"`
+}
+
+type ddiVar struct {
+	ID        string        `xml:"ID,attr"`
+	Name      string        `xml:"name,attr"`
+	Files     string        `xml:"files,attr"`
+	Labels    []ddiText     `xml:"labl"`
+	Questions []ddiText     `xml:"qstn>qstnLit"`
+	Categs    []ddiCategory `xml:"catgry"`
+	VarFormat ddiVarFormat  `xml:"varFormat"`
+	Notes     []ddiVarNotes `xml:"notes"`
+}
+
+type ddiText struct {
+	Lang  string `xml:"xml:lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type ddiCategory struct {
+	Value  string    `xml:"catValu"`
+	Labels []ddiText `xml:"labl"`
+}
+
+type ddiVarFormat struct {
+	Type     string `xml:"type,attr"`
+	Category string `xml:"category,attr,omitempty"`
+}
+
+type ddiVarNotes struct {
+	Subject string `xml:"subject,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// GetCodebookDDI writes the codebook as DDI-Codebook (version 2.5) XML document, with one var element per column
+func (rp ResponseExporter) GetCodebookDDI(writer io.Writer, languages []string, includeMeta *IncludeMeta) error {
+	cb := rp.GetCodebook(languages, includeMeta)
+
+	fileID := "F1"
+	doc := ddiCodeBook{
+		Version:  "2.5",
+		StdyDscr: ddiStdyDscr{Title: cb.SurveyKey},
+		FileDscr: ddiFileDscr{
+			ID: fileID,
+			Notes: []string{
+				"questionOptionSep: " + cb.QuestionOptionSep,
+				"openFieldSuffix: " + cb.OpenFieldSuffix,
+			},
+		},
+	}
+	for _, version := range cb.Versions {
+		doc.FileDscr.Notes = append(doc.FileDscr.Notes, fmt.Sprintf("version %s published: %d", version.VersionID, version.Published))
+	}
+
+	for i, v := range cb.Variables {
+		dv := ddiVar{
+			ID:    fmt.Sprintf("V%d", i+1),
+			Name:  v.Name,
+			Files: fileID,
+			Notes: []ddiVarNotes{
+				{Subject: "kind", Value: v.Kind},
+				{Subject: "dtype", Value: v.DType},
+				{Subject: "namePattern", Value: v.NamePattern},
+			},
+		}
+		for _, lang := range languages {
+			if label, ok := v.Label[lang]; ok {
+				dv.Labels = append(dv.Labels, ddiText{Lang: lang, Value: label})
+			}
+			if text, ok := v.QuestionText[lang]; ok {
+				dv.Questions = append(dv.Questions, ddiText{Lang: lang, Value: text})
+			}
+		}
+		for _, code := range v.Codes {
+			category := ddiCategory{Value: code.Code}
+			for _, lang := range languages {
+				if label, ok := code.Label[lang]; ok {
+					category.Labels = append(category.Labels, ddiText{Lang: lang, Value: label})
+				}
+			}
+			dv.Categs = append(dv.Categs, category)
+		}
+		switch v.DType {
+		case COLUMN_TYPE_NUMBER, COLUMN_TYPE_INTEGER:
+			dv.VarFormat = ddiVarFormat{Type: "numeric"}
+		case COLUMN_TYPE_TIMESTAMP:
+			dv.VarFormat = ddiVarFormat{Type: "numeric", Category: "date"}
+		default:
+			dv.VarFormat = ddiVarFormat{Type: "character"}
+		}
+		if v.QuestionKey != "" {
+			dv.Notes = append(dv.Notes, ddiVarNotes{Subject: "questionKey", Value: v.QuestionKey})
+		}
+		if v.OpenField {
+			dv.Notes = append(dv.Notes, ddiVarNotes{Subject: "openField", Value: "true"})
+		}
+		if len(v.Versions) > 0 {
+			dv.Notes = append(dv.Notes, ddiVarNotes{Subject: "versions", Value: strings.Join(v.Versions, ";")})
+		}
+		doc.DataDscr.Vars = append(doc.DataDscr.Vars, dv)
+	}
+
+	if _, err := writer.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(writer)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,173 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestCodebook(t *testing.T) {
+	logger.SetLevel(logger.LEVEL_ERROR)
+	includeMeta := &IncludeMeta{
+		Postion:        true,
+		InitTimes:      true,
+		DisplayedTimes: true,
+		ResponsedTimes: true,
+	}
+
+	var testSurveyHistory types.SurveyVersionsJSON
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/surveyDef.json"), &testSurveyHistory)
+	var testResponses []types.SurveyResponse
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/responses.json"), &testResponses)
+
+	rp, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err.Error())
+	}
+	cb := rp.GetCodebook([]string{"nl"}, includeMeta)
+
+	variables := map[string]CodebookVariable{}
+	for _, v := range cb.Variables {
+		variables[v.Name] = v
+	}
+
+	t.Run("contains exported columns", func(t *testing.T) {
+		for _, response := range testResponses {
+			r := response
+			if err := rp.AddResponse(&r); err != nil {
+				t.Fatalf("unexpected error: %v", err.Error())
+			}
+		}
+		buf := new(bytes.Buffer)
+		if err := rp.GetResponsesCSV(buf, includeMeta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		header, err := csv.NewReader(buf).Read()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		contextCols := map[string]bool{}
+		for _, k := range rp.contextColNames {
+			contextCols[k] = true
+		}
+		for _, colName := range header {
+			if contextCols[colName] {
+				continue
+			}
+			if _, ok := variables[colName]; !ok {
+				t.Errorf("column %s missing in codebook", colName)
+			}
+		}
+		if len(cb.Versions) != len(testSurveyHistory.SurveyVersions) {
+			t.Errorf("unexpected number of versions: %d", len(cb.Versions))
+		}
+	})
+
+	t.Run("describes columns", func(t *testing.T) {
+		v, ok := variables["SCG1"]
+		if !ok {
+			t.Fatal("single choice column missing")
+		}
+		if v.Kind != CODEBOOK_VARIABLE_RESPONSE || v.DType != COLUMN_TYPE_STRING || v.NamePattern != NAME_PATTERN_QUESTION_KEY {
+			t.Errorf("unexpected variable: %v", v)
+		}
+		if len(v.Codes) < 1 || v.Codes[0].Label["nl"] == "" {
+			t.Errorf("unexpected codes: %v", v.Codes)
+		}
+		if v.QuestionText["nl"] != "Simple single choice question" {
+			t.Errorf("unexpected question text: %v", v.QuestionText)
+		}
+
+		if v := variables["submitted"]; v.Kind != CODEBOOK_VARIABLE_FIXED || v.DType != COLUMN_TYPE_TIMESTAMP {
+			t.Errorf("unexpected variable: %v", v)
+		}
+		if v := variables["SCG1-metaPosition"]; v.Kind != CODEBOOK_VARIABLE_META || v.DType != COLUMN_TYPE_INTEGER {
+			t.Errorf("unexpected variable: %v", v)
+		}
+
+		openFields := 0
+		for _, v := range cb.Variables {
+			if v.OpenField {
+				openFields++
+				if v.Name != v.QuestionKey+"-"+v.ResponseKey+"."+v.OptionKey+"-"+OPEN_FIELD_COL_SUFFIX &&
+					v.Name != v.QuestionKey+"-"+v.OptionKey+"-"+OPEN_FIELD_COL_SUFFIX {
+					t.Errorf("unexpected name for open field: %v", v)
+				}
+			}
+		}
+		if openFields == 0 {
+			t.Error("open fields expected")
+		}
+	})
+
+	t.Run("without meta columns", func(t *testing.T) {
+		for _, v := range rp.GetCodebook([]string{"nl"}, nil).Variables {
+			if v.Kind == CODEBOOK_VARIABLE_META {
+				t.Errorf("unexpected meta column: %s", v.Name)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := rp.GetCodebookJSON(buf, []string{"nl"}, includeMeta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var parsed Codebook
+		if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(parsed.Variables) != len(cb.Variables) || parsed.QuestionOptionSep != "-" {
+			t.Errorf("unexpected codebook: %d variables", len(parsed.Variables))
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := rp.GetCodebookCSV(buf, []string{"nl"}, includeMeta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records, err := csv.NewReader(buf).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if records[0][0] != "variable" || records[0][len(records[0])-1] != "codeLabel_nl" {
+			t.Errorf("unexpected header: %v", records[0])
+		}
+		names := map[string]bool{}
+		for _, line := range records[1:] {
+			names[line[0]] = true
+		}
+		if len(names) != len(cb.Variables) {
+			t.Errorf("unexpected number of variables: %d", len(names))
+		}
+	})
+
+	t.Run("DDI XML", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := rp.GetCodebookDDI(buf, []string{"nl"}, includeMeta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var parsed ddiCodeBook
+		if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(parsed.DataDscr.Vars) != len(cb.Variables) {
+			t.Errorf("unexpected number of variables: %d", len(parsed.DataDscr.Vars))
+		}
+		if !strings.Contains(buf.String(), `<qstnLit xml:lang="nl">Simple single choice question</qstnLit>`) {
+			t.Error("question text with language expected")
+		}
+		for _, v := range parsed.DataDscr.Vars {
+			if v.Name == "SCG1" && (len(v.Categs) < 1 || len(v.Questions) != 1) {
+				t.Errorf("unexpected variable: %v", v)
+			}
+		}
+	})
+}
@@ -0,0 +1,416 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/snappy"
+)
+
+// parquetWriter writes a Parquet file with a flat schema of optional columns. Rows are buffered and written in
+// row groups of parquetRowGroupSize rows, with one snappy compressed data page (PLAIN encoding) per column chunk.
+// Format specification: https://github.com/apache/parquet-format
+// The output is compared with golden files in test_files/parquet, which are checked with pyarrow in the tests.
+type parquetWriter struct {
+	w            io.Writer
+	offset       int64
+	columns      []parquetColumn
+	rows         [][]interface{}
+	rowGroups    []parquetRowGroup
+	rowGroupSize int
+	numRows      int64
+}
+
+type parquetColumn struct {
+	Name string
+	Type string // one of the COLUMN_TYPE_* constants
+}
+
+type parquetRowGroup struct {
+	numRows       int64
+	totalByteSize int64
+	columns       []parquetColumnChunk
+}
+
+type parquetColumnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+const (
+	parquetMagic        = "PAR1"
+	parquetRowGroupSize = 10000
+	parquetCreatedBy    = "influenzanet study-service"
+)
+
+// values of the enums of the Parquet thrift definitions
+const (
+	parquetTypeBoolean   int32 = 0
+	parquetTypeInt64     int32 = 2
+	parquetTypeDouble    int32 = 5
+	parquetTypeByteArray int32 = 6
+
+	parquetConvertedTypeUTF8            int32 = 0
+	parquetConvertedTypeTimestampMillis int32 = 9
+
+	parquetRepetitionOptional int32 = 1
+
+	parquetEncodingPlain int32 = 0
+	parquetEncodingRLE   int32 = 3
+
+	parquetCodecSnappy int32 = 1
+
+	parquetPageTypeData int32 = 0
+)
+
+func newParquetWriter(w io.Writer, columns []parquetColumn) (*parquetWriter, error) {
+	pw := &parquetWriter{
+		w:            w,
+		columns:      columns,
+		rows:         make([][]interface{}, 0, parquetRowGroupSize),
+		rowGroupSize: parquetRowGroupSize,
+	}
+	if err := pw.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+// WriteRow adds a row. Values are nil for missing values, or of the column's type: string, float64 (number),
+// int64 (integer, timestamp in milliseconds) or bool.
+func (pw *parquetWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(pw.columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(pw.columns))
+	}
+	pw.rows = append(pw.rows, values)
+	if len(pw.rows) >= pw.rowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes the remaining rows and the file footer
+func (pw *parquetWriter) Close() error {
+	if err := pw.flushRowGroup(); err != nil {
+		return err
+	}
+	footer := pw.fileMetaData()
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	footerLength := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footerLength, uint32(len(footer)))
+	if err := pw.write(footerLength); err != nil {
+		return err
+	}
+	return pw.write([]byte(parquetMagic))
+}
+
+func (pw *parquetWriter) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+func (pw *parquetWriter) flushRowGroup() error {
+	if len(pw.rows) == 0 {
+		return nil
+	}
+	rowGroup := parquetRowGroup{
+		numRows: int64(len(pw.rows)),
+		columns: make([]parquetColumnChunk, len(pw.columns)),
+	}
+	for i := range pw.columns {
+		chunk, err := pw.writeColumnChunk(i)
+		if err != nil {
+			return err
+		}
+		rowGroup.columns[i] = chunk
+		rowGroup.totalByteSize += chunk.uncompressedSize
+	}
+	pw.rowGroups = append(pw.rowGroups, rowGroup)
+	pw.numRows += rowGroup.numRows
+	pw.rows = pw.rows[:0]
+	return nil
+}
+
+func (pw *parquetWriter) writeColumnChunk(index int) (parquetColumnChunk, error) {
+	column := pw.columns[index]
+
+	defined := make([]bool, len(pw.rows))
+	values := new(bytes.Buffer)
+	bools := []bool{}
+	number := make([]byte, 8)
+	for r, row := range pw.rows {
+		if row[index] == nil {
+			continue
+		}
+		defined[r] = true
+
+		switch column.Type {
+		case COLUMN_TYPE_BOOLEAN:
+			v, ok := row[index].(bool)
+			if !ok {
+				return parquetColumnChunk{}, fmt.Errorf("unexpected value for boolean column %s: %v", column.Name, row[index])
+			}
+			bools = append(bools, v)
+		case COLUMN_TYPE_NUMBER:
+			v, ok := row[index].(float64)
+			if !ok {
+				return parquetColumnChunk{}, fmt.Errorf("unexpected value for number column %s: %v", column.Name, row[index])
+			}
+			binary.LittleEndian.PutUint64(number, math.Float64bits(v))
+			values.Write(number)
+		case COLUMN_TYPE_INTEGER, COLUMN_TYPE_TIMESTAMP:
+			v, ok := row[index].(int64)
+			if !ok {
+				return parquetColumnChunk{}, fmt.Errorf("unexpected value for integer column %s: %v", column.Name, row[index])
+			}
+			binary.LittleEndian.PutUint64(number, uint64(v))
+			values.Write(number)
+		default:
+			v, ok := row[index].(string)
+			if !ok {
+				return parquetColumnChunk{}, fmt.Errorf("unexpected value for string column %s: %v", column.Name, row[index])
+			}
+			length := make([]byte, 4)
+			binary.LittleEndian.PutUint32(length, uint32(len(v)))
+			values.Write(length)
+			values.WriteString(v)
+		}
+	}
+	if column.Type == COLUMN_TYPE_BOOLEAN {
+		values.Write(packBits(bools))
+	}
+
+	// data page v1: definition levels with length prefix, then the non-null values
+	levels := encodeDefinitionLevels(defined)
+	page := new(bytes.Buffer)
+	levelsLength := make([]byte, 4)
+	binary.LittleEndian.PutUint32(levelsLength, uint32(len(levels)))
+	page.Write(levelsLength)
+	page.Write(levels)
+	page.Write(values.Bytes())
+
+	compressed := snappy.Encode(nil, page.Bytes())
+	header := parquetDataPageHeader(len(pw.rows), page.Len(), len(compressed))
+
+	chunk := parquetColumnChunk{
+		offset:           pw.offset,
+		numValues:        int64(len(pw.rows)),
+		uncompressedSize: int64(len(header) + page.Len()),
+		compressedSize:   int64(len(header) + len(compressed)),
+	}
+	if err := pw.write(header); err != nil {
+		return chunk, err
+	}
+	if err := pw.write(compressed); err != nil {
+		return chunk, err
+	}
+	return chunk, nil
+}
+
+// encodeDefinitionLevels encodes the levels (max level 1) as a single bit-packed run of the RLE/bit-packing hybrid
+func encodeDefinitionLevels(defined []bool) []byte {
+	groups := (len(defined) + 7) / 8
+	header := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(header, uint64(groups)<<1|1)
+	return append(header[:n], packBits(defined)...)
+}
+
+// packBits packs the values with one bit each, least significant bit first
+func packBits(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			packed[i/8] |= 1 << (uint(i) % 8)
+		}
+	}
+	return packed
+}
+
+func parquetPhysicalType(columnType string) int32 {
+	switch columnType {
+	case COLUMN_TYPE_BOOLEAN:
+		return parquetTypeBoolean
+	case COLUMN_TYPE_NUMBER:
+		return parquetTypeDouble
+	case COLUMN_TYPE_INTEGER, COLUMN_TYPE_TIMESTAMP:
+		return parquetTypeInt64
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+func parquetDataPageHeader(numValues int, uncompressedSize int, compressedSize int) []byte {
+	t := newThriftCompactWriter()
+	t.i32Field(1, parquetPageTypeData)
+	t.i32Field(2, int32(uncompressedSize))
+	t.i32Field(3, int32(compressedSize))
+	t.structField(5)
+	t.i32Field(1, int32(numValues))
+	t.i32Field(2, parquetEncodingPlain)
+	t.i32Field(3, parquetEncodingRLE)
+	t.i32Field(4, parquetEncodingRLE)
+	t.endStruct()
+	t.endStruct()
+	return t.bytes()
+}
+
+func (pw *parquetWriter) fileMetaData() []byte {
+	t := newThriftCompactWriter()
+	t.i32Field(1, 1)
+
+	// schema: root element followed by the columns
+	t.listField(2, thriftTypeStruct, len(pw.columns)+1)
+	t.beginStruct()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(pw.columns)))
+	t.endStruct()
+	for _, column := range pw.columns {
+		t.beginStruct()
+		t.i32Field(1, parquetPhysicalType(column.Type))
+		t.i32Field(3, parquetRepetitionOptional)
+		t.stringField(4, column.Name)
+		switch column.Type {
+		case COLUMN_TYPE_STRING:
+			t.i32Field(6, parquetConvertedTypeUTF8)
+		case COLUMN_TYPE_TIMESTAMP:
+			t.i32Field(6, parquetConvertedTypeTimestampMillis)
+		}
+		t.endStruct()
+	}
+
+	t.i64Field(3, pw.numRows)
+
+	t.listField(4, thriftTypeStruct, len(pw.rowGroups))
+	for _, rowGroup := range pw.rowGroups {
+		t.beginStruct()
+		t.listField(1, thriftTypeStruct, len(rowGroup.columns))
+		for i, chunk := range rowGroup.columns {
+			t.beginStruct()
+			t.i64Field(2, chunk.offset)
+			t.structField(3)
+			t.i32Field(1, parquetPhysicalType(pw.columns[i].Type))
+			t.listField(2, thriftTypeI32, 2)
+			t.i32(parquetEncodingPlain)
+			t.i32(parquetEncodingRLE)
+			t.listField(3, thriftTypeBinary, 1)
+			t.str(pw.columns[i].Name)
+			t.i32Field(4, parquetCodecSnappy)
+			t.i64Field(5, chunk.numValues)
+			t.i64Field(6, chunk.uncompressedSize)
+			t.i64Field(7, chunk.compressedSize)
+			t.i64Field(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64Field(2, rowGroup.totalByteSize)
+		t.i64Field(3, rowGroup.numRows)
+		t.endStruct()
+	}
+
+	t.stringField(6, parquetCreatedBy)
+	t.endStruct()
+	return t.bytes()
+}
+
+// thriftCompactWriter encodes the structs of the Parquet metadata with the thrift compact protocol
+type thriftCompactWriter struct {
+	buf     bytes.Buffer
+	fieldID []int16 // last field ID of each open struct
+}
+
+const (
+	thriftTypeI32    byte = 5
+	thriftTypeI64    byte = 6
+	thriftTypeBinary byte = 8
+	thriftTypeList   byte = 9
+	thriftTypeStruct byte = 12
+)
+
+// newThriftCompactWriter returns a writer with an open top-level struct
+func newThriftCompactWriter() *thriftCompactWriter {
+	return &thriftCompactWriter{fieldID: []int16{0}}
+}
+
+func (t *thriftCompactWriter) bytes() []byte {
+	return t.buf.Bytes()
+}
+
+func (t *thriftCompactWriter) varint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, v)
+	t.buf.Write(b[:n])
+}
+
+func (t *thriftCompactWriter) zigzag(v int64) {
+	t.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftCompactWriter) fieldHeader(id int16, fieldType byte) {
+	last := t.fieldID[len(t.fieldID)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.zigzag(int64(id))
+	}
+	t.fieldID[len(t.fieldID)-1] = id
+}
+
+func (t *thriftCompactWriter) i32(v int32) {
+	t.zigzag(int64(v))
+}
+
+func (t *thriftCompactWriter) str(s string) {
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftCompactWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftTypeI32)
+	t.i32(v)
+}
+
+func (t *thriftCompactWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftTypeI64)
+	t.zigzag(v)
+}
+
+func (t *thriftCompactWriter) stringField(id int16, s string) {
+	t.fieldHeader(id, thriftTypeBinary)
+	t.str(s)
+}
+
+// listField writes the list header, the size elements must follow
+func (t *thriftCompactWriter) listField(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftTypeList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.varint(uint64(size))
+	}
+}
+
+// structField opens a struct field, closed with endStruct
+func (t *thriftCompactWriter) structField(id int16) {
+	t.fieldHeader(id, thriftTypeStruct)
+	t.beginStruct()
+}
+
+// beginStruct opens a struct as list element
+func (t *thriftCompactWriter) beginStruct() {
+	t.fieldID = append(t.fieldID, 0)
+}
+
+func (t *thriftCompactWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.fieldID = t.fieldID[:len(t.fieldID)-1]
+}
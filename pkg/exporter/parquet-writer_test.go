@@ -0,0 +1,413 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/golang/snappy"
+)
+
+func TestParquetWriter(t *testing.T) {
+	columns := []parquetColumn{
+		{Name: "ID", Type: COLUMN_TYPE_STRING},
+		{Name: "submitted", Type: COLUMN_TYPE_TIMESTAMP},
+		{Name: "Q1", Type: COLUMN_TYPE_NUMBER},
+		{Name: "Q2-1", Type: COLUMN_TYPE_BOOLEAN},
+		{Name: "metaPosition", Type: COLUMN_TYPE_INTEGER},
+	}
+
+	t.Run("with wrong number of values", func(t *testing.T) {
+		pw, err := newParquetWriter(new(bytes.Buffer), columns)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := pw.WriteRow([]interface{}{"id"}); err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("with value not matching column type", func(t *testing.T) {
+		pw, err := newParquetWriter(new(bytes.Buffer), columns)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := pw.WriteRow([]interface{}{"id", nil, "12", nil, nil}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := pw.Close(); err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("with multiple row groups", func(t *testing.T) {
+		rows := [][]interface{}{}
+		for i := 0; i < parquetRowGroupSize+3; i++ {
+			row := []interface{}{"id", int64(1640116902000) + int64(i), float64(i) / 2, i%3 == 0, int64(i)}
+			if i%7 == 0 {
+				row[2] = nil
+				row[3] = nil
+			}
+			if i%5 == 0 {
+				row[0] = nil
+			}
+			rows = append(rows, row)
+		}
+
+		buf := new(bytes.Buffer)
+		pw, err := newParquetWriter(buf, columns)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		for _, row := range rows {
+			if err := pw.WriteRow(row); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+		}
+		if err := pw.Close(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+
+		readColumns, readRows, rowGroups := readParquetFile(t, buf.Bytes())
+		if !reflect.DeepEqual(readColumns, columns) {
+			t.Errorf("unexpected schema: %v", readColumns)
+		}
+		if rowGroups != 2 {
+			t.Errorf("unexpected number of row groups: %d", rowGroups)
+		}
+		if len(readRows) != len(rows) {
+			t.Errorf("unexpected number of rows: %d", len(readRows))
+			return
+		}
+		for i := range rows {
+			if !reflect.DeepEqual(readRows[i], rows[i]) {
+				t.Errorf("unexpected row %d: %v, expected %v", i, readRows[i], rows[i])
+				return
+			}
+		}
+	})
+}
+
+// golden file test_files/parquet/types.parquet: all column types, missing values and three row groups
+var parquetGoldenColumns = []parquetColumn{
+	{Name: "ID", Type: COLUMN_TYPE_STRING},
+	{Name: "submitted", Type: COLUMN_TYPE_TIMESTAMP},
+	{Name: "Q1", Type: COLUMN_TYPE_NUMBER},
+	{Name: "Q2-1", Type: COLUMN_TYPE_BOOLEAN},
+	{Name: "Q2-1-metaPosition", Type: COLUMN_TYPE_INTEGER},
+}
+
+var parquetGoldenRows = [][]interface{}{
+	{"r1", int64(1640116902000), float64(1.5), true, int64(0)},
+	{"r2", int64(1640116903000), float64(-2), false, int64(1)},
+	{nil, nil, nil, nil, nil},
+	{"", int64(0), float64(0), true, int64(-1)},
+	{"Grüße, \"quoted\"\n", int64(4102444800000), float64(123456.789), nil, int64(9007199254740993)},
+	{"r6", nil, float64(1e-7), false, nil},
+	{"r7", int64(1640116909000), nil, true, int64(7)},
+}
+
+const parquetGoldenRowGroupSize = 3
+
+func writeParquetGoldenFile(t *testing.T) []byte {
+	buf := new(bytes.Buffer)
+	pw, err := newParquetWriter(buf, parquetGoldenColumns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pw.rowGroupSize = parquetGoldenRowGroupSize
+	for _, row := range parquetGoldenRows {
+		if err := pw.WriteRow(row); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParquetWriterGoldenFile(t *testing.T) {
+	output := writeParquetGoldenFile(t)
+	if !bytes.Equal(output, readTestFileToBytes(t, "./test_files/parquet/types.parquet")) {
+		t.Errorf("unexpected output")
+		writeBytesToFile(output, "./test_files/error/types.parquet")
+	}
+
+	readColumns, readRows, rowGroups := readParquetFile(t, output)
+	if !reflect.DeepEqual(readColumns, parquetGoldenColumns) || !reflect.DeepEqual(readRows, parquetGoldenRows) || rowGroups != 3 {
+		t.Errorf("unexpected content: %v, %v, %d row groups", readColumns, readRows, rowGroups)
+	}
+}
+
+// pyarrowReadParquet prints the schema, the number of row groups and the rows of the Parquet file as JSON.
+// Timestamps are converted to milliseconds.
+const pyarrowReadParquet = `
+import json, sys
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+f = pq.ParquetFile(sys.argv[1])
+columns = []
+for i in range(len(f.schema)):
+    c = f.schema.column(i)
+    columns.append({"name": c.name, "physicalType": c.physical_type, "convertedType": c.converted_type, "maxDefinitionLevel": c.max_definition_level})
+table = f.read()
+values = []
+for i, field in enumerate(table.schema):
+    column = table.column(i)
+    if pa.types.is_timestamp(field.type):
+        column = column.cast(pa.int64())
+    values.append(column.to_pylist())
+json.dump({"numRowGroups": f.metadata.num_row_groups, "columns": columns, "rows": [list(row) for row in zip(*values)]}, sys.stdout)
+`
+
+// TestParquetGoldenFileWithPyarrow checks the golden file with an independent reader, it is skipped if pyarrow is not installed
+func TestParquetGoldenFileWithPyarrow(t *testing.T) {
+	if err := exec.Command("python3", "-c", "import pyarrow.parquet").Run(); err != nil {
+		t.Skip("python3 with pyarrow not available")
+	}
+	output, err := exec.Command("python3", "-c", pyarrowReadParquet, "./test_files/parquet/types.parquet").Output()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		NumRowGroups int `json:"numRowGroups"`
+		Columns      []struct {
+			Name               string `json:"name"`
+			PhysicalType       string `json:"physicalType"`
+			ConvertedType      string `json:"convertedType"`
+			MaxDefinitionLevel int    `json:"maxDefinitionLevel"`
+		} `json:"columns"`
+		Rows [][]interface{} `json:"rows"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.NumRowGroups != 3 {
+		t.Errorf("unexpected number of row groups: %d", parsed.NumRowGroups)
+	}
+	expectedTypes := map[string][2]string{
+		COLUMN_TYPE_STRING:    {"BYTE_ARRAY", "UTF8"},
+		COLUMN_TYPE_TIMESTAMP: {"INT64", "TIMESTAMP_MILLIS"},
+		COLUMN_TYPE_NUMBER:    {"DOUBLE", "NONE"},
+		COLUMN_TYPE_BOOLEAN:   {"BOOLEAN", "NONE"},
+		COLUMN_TYPE_INTEGER:   {"INT64", "NONE"},
+	}
+	if len(parsed.Columns) != len(parquetGoldenColumns) {
+		t.Fatalf("unexpected columns: %v", parsed.Columns)
+	}
+	for i, c := range parsed.Columns {
+		expected := parquetGoldenColumns[i]
+		types := expectedTypes[expected.Type]
+		if c.Name != expected.Name || c.PhysicalType != types[0] || c.ConvertedType != types[1] || c.MaxDefinitionLevel != 1 {
+			t.Errorf("unexpected column %v, expected %v", c, expected)
+		}
+	}
+
+	if len(parsed.Rows) != len(parquetGoldenRows) {
+		t.Fatalf("unexpected number of rows: %d", len(parsed.Rows))
+	}
+	for i, row := range parsed.Rows {
+		for j, v := range row {
+			expected := parquetGoldenRows[i][j]
+			var value interface{}
+			var err error
+			switch number := v.(type) {
+			case json.Number:
+				if _, isFloat := expected.(float64); isFloat {
+					value, err = number.Float64()
+				} else {
+					value, err = number.Int64()
+				}
+			default:
+				value = v
+			}
+			if err != nil || value != expected {
+				t.Errorf("unexpected value for %s in row %d: %v, expected %v", parquetGoldenColumns[j].Name, i, v, expected)
+			}
+		}
+	}
+}
+
+// readParquetFile decodes the files written by parquetWriter, returns the columns, the rows and the number of row groups
+func readParquetFile(t *testing.T, data []byte) ([]parquetColumn, [][]interface{}, int) {
+	t.Helper()
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatal("missing magic bytes")
+	}
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+	footer := &thriftCompactReader{b: data[len(data)-8-footerLength : len(data)-8]}
+	meta := footer.readStruct()
+
+	columns := []parquetColumn{}
+	schema := meta[2].([]interface{})
+	if n := schema[0].(map[int16]interface{})[5].(int64); int(n) != len(schema)-1 {
+		t.Fatalf("unexpected number of children: %d", n)
+	}
+	for _, el := range schema[1:] {
+		element := el.(map[int16]interface{})
+		if element[3].(int64) != int64(parquetRepetitionOptional) {
+			t.Errorf("column %s is not optional", element[4])
+		}
+		column := parquetColumn{Name: element[4].(string)}
+		convertedType, hasConvertedType := element[6].(int64)
+		switch int32(element[1].(int64)) {
+		case parquetTypeBoolean:
+			column.Type = COLUMN_TYPE_BOOLEAN
+		case parquetTypeDouble:
+			column.Type = COLUMN_TYPE_NUMBER
+		case parquetTypeInt64:
+			column.Type = COLUMN_TYPE_INTEGER
+			if hasConvertedType && int32(convertedType) == parquetConvertedTypeTimestampMillis {
+				column.Type = COLUMN_TYPE_TIMESTAMP
+			}
+		case parquetTypeByteArray:
+			column.Type = COLUMN_TYPE_STRING
+			if !hasConvertedType || int32(convertedType) != parquetConvertedTypeUTF8 {
+				t.Errorf("string column %s without UTF8 annotation", column.Name)
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	rows := [][]interface{}{}
+	rowGroups := meta[4].([]interface{})
+	for _, rg := range rowGroups {
+		rowGroup := rg.(map[int16]interface{})
+		numRows := int(rowGroup[3].(int64))
+		groupRows := make([][]interface{}, numRows)
+		for r := range groupRows {
+			groupRows[r] = make([]interface{}, len(columns))
+		}
+		for c, cc := range rowGroup[1].([]interface{}) {
+			chunkMeta := cc.(map[int16]interface{})[3].(map[int16]interface{})
+			offset := int(chunkMeta[9].(int64))
+			pageReader := &thriftCompactReader{b: data[offset:]}
+			pageHeader := pageReader.readStruct()
+			compressedSize := int(pageHeader[3].(int64))
+			page, err := snappy.Decode(nil, data[offset+pageReader.pos:offset+pageReader.pos+compressedSize])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if int(pageHeader[2].(int64)) != len(page) {
+				t.Errorf("unexpected uncompressed size for column %s", columns[c].Name)
+			}
+			if n := pageHeader[5].(map[int16]interface{})[1].(int64); int(n) != numRows {
+				t.Errorf("unexpected number of values for column %s: %d", columns[c].Name, n)
+			}
+
+			levelsLength := int(binary.LittleEndian.Uint32(page[:4]))
+			levels := page[4 : 4+levelsLength]
+			runHeader, n := binary.Uvarint(levels)
+			if runHeader&1 != 1 || int(runHeader>>1) != (numRows+7)/8 {
+				t.Fatalf("unexpected definition levels header: %d", runHeader)
+			}
+			defined := levels[n:]
+			values := page[4+levelsLength:]
+
+			definedCount := 0
+			for r := 0; r < numRows; r++ {
+				if defined[r/8]&(1<<(uint(r)%8)) == 0 {
+					continue
+				}
+				switch columns[c].Type {
+				case COLUMN_TYPE_BOOLEAN:
+					groupRows[r][c] = values[definedCount/8]&(1<<(uint(definedCount)%8)) != 0
+				case COLUMN_TYPE_NUMBER:
+					groupRows[r][c] = math.Float64frombits(binary.LittleEndian.Uint64(values[:8]))
+					values = values[8:]
+				case COLUMN_TYPE_INTEGER, COLUMN_TYPE_TIMESTAMP:
+					groupRows[r][c] = int64(binary.LittleEndian.Uint64(values[:8]))
+					values = values[8:]
+				default:
+					length := int(binary.LittleEndian.Uint32(values[:4]))
+					groupRows[r][c] = string(values[4 : 4+length])
+					values = values[4+length:]
+				}
+				definedCount++
+			}
+		}
+		rows = append(rows, groupRows...)
+	}
+	if int(meta[3].(int64)) != len(rows) {
+		t.Errorf("unexpected number of rows in metadata: %d", meta[3])
+	}
+	return columns, rows, len(rowGroups)
+}
+
+// thriftCompactReader decodes the thrift compact protocol, structs are returned as maps by field ID
+type thriftCompactReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftCompactReader) varint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftCompactReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftCompactReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var lastID int16
+	for {
+		header := r.b[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.readValue(header & 0x0f)
+		lastID = id
+	}
+}
+
+func (r *thriftCompactReader) readValue(valueType byte) interface{} {
+	switch valueType {
+	case thriftTypeI32, thriftTypeI64:
+		return r.zigzag()
+	case thriftTypeBinary:
+		length := int(r.varint())
+		s := string(r.b[r.pos : r.pos+length])
+		r.pos += length
+		return s
+	case thriftTypeList:
+		header := r.b[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		elems := make([]interface{}, size)
+		for i := range elems {
+			elems[i] = r.readValue(header & 0x0f)
+		}
+		return elems
+	case thriftTypeStruct:
+		return r.readStruct()
+	}
+	panic("unexpected thrift type")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -126,6 +127,72 @@ func getResponseColumns(question SurveyQuestion, response *types.SurveyItemRespo
 	}
 }
 
+// getResponseColumnTypes returns the types of the question's response columns that are not strings, derived from
+// the response definitions. Column names are the ones created by getResponseColumns.
+func getResponseColumnTypes(question SurveyQuestion, questionOptionSep string) map[string]string {
+	colTypes := map[string]string{}
+	switch question.QuestionType {
+	case QUESTION_TYPE_CONSENT:
+		colTypes[question.ID] = COLUMN_TYPE_BOOLEAN
+	case QUESTION_TYPE_MULTIPLE_CHOICE:
+		for _, rSlot := range question.Responses {
+			prefix := question.ID + questionOptionSep
+			if len(question.Responses) > 1 {
+				prefix += rSlot.ID + "."
+			}
+			for _, option := range rSlot.Options {
+				if isEmbeddedCloze(option.OptionType) {
+					// value of the embedded input
+					continue
+				}
+				colTypes[prefix+option.ID] = COLUMN_TYPE_BOOLEAN
+			}
+		}
+	case QUESTION_TYPE_TEXT_INPUT, QUESTION_TYPE_DATE_INPUT, QUESTION_TYPE_NUMBER_INPUT, QUESTION_TYPE_NUMERIC_SLIDER, QUESTION_TYPE_EQ5D_SLIDER:
+		for _, rSlot := range question.Responses {
+			colType := getInputColumnType(rSlot.ResponseType)
+			if colType == "" {
+				continue
+			}
+			if len(question.Responses) == 1 {
+				colTypes[question.ID] = colType
+			} else {
+				colTypes[question.ID+questionOptionSep+rSlot.ID] = colType
+			}
+		}
+	case QUESTION_TYPE_MATRIX:
+		for _, rSlot := range question.Responses {
+			if rSlot.ResponseType == QUESTION_TYPE_MATRIX_NUMBER_INPUT {
+				colTypes[question.ID+questionOptionSep+rSlot.ID] = COLUMN_TYPE_NUMBER
+			}
+		}
+	case QUESTION_TYPE_CLOZE:
+		for _, rSlot := range question.Responses {
+			prefix := question.ID + questionOptionSep
+			if len(question.Responses) > 1 {
+				prefix += rSlot.ID + "."
+			}
+			for _, option := range rSlot.Options {
+				if colType := getInputColumnType(option.OptionType); colType != "" {
+					colTypes[prefix+option.ID] = colType
+				}
+			}
+		}
+	}
+	return colTypes
+}
+
+// getInputColumnType returns the column type for number and date inputs, or an empty string
+func getInputColumnType(inputType string) string {
+	switch inputType {
+	case QUESTION_TYPE_NUMBER_INPUT, QUESTION_TYPE_NUMERIC_SLIDER, QUESTION_TYPE_EQ5D_SLIDER:
+		return COLUMN_TYPE_NUMBER
+	case QUESTION_TYPE_DATE_INPUT:
+		return COLUMN_TYPE_TIMESTAMP
+	}
+	return ""
+}
+
 func processResponseForSingleChoice(question SurveyQuestion, response *types.SurveyItemResponse, questionOptionSep string) map[string]interface{} {
 	var responseCols map[string]interface{}
 
@@ -728,6 +795,39 @@ func responseColToString(responseCol interface{}) string {
 	return str
 }
 
+// typedColumnValue converts the response column value to the value written for the column type: nil for missing
+// values, float64 for numbers, int64 milliseconds for timestamps, bool for booleans and string otherwise.
+// Returns false if the value does not match the type.
+func typedColumnValue(responseCol interface{}, colType string) (interface{}, bool) {
+	str := responseColToString(responseCol)
+	if str == "" {
+		return nil, true
+	}
+	switch colType {
+	case COLUMN_TYPE_NUMBER:
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	case COLUMN_TYPE_TIMESTAMP:
+		v, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return v * 1000, true
+	case COLUMN_TYPE_BOOLEAN:
+		switch str {
+		case TRUE_VALUE:
+			return true, true
+		case FALSE_VALUE:
+			return false, true
+		}
+		return nil, false
+	}
+	return str, true
+}
+
 func isEmbeddedCloze(optionType string) bool {
 	return optionType == OPTION_TYPE_EMBEDDED_CLOZE_DATE_INPUT || optionType == OPTION_TYPE_EMBEDDED_CLOZE_DROPDOWN ||
 		optionType == OPTION_TYPE_EMBEDDED_CLOZE_NUMBER_INPUT || optionType == OPTION_TYPE_EMBEDDED_CLOZE_TEXT_INPUT
@@ -15,6 +15,9 @@ import (
 
 type ResponseExporter struct {
 	surveyKey            string
+	surveyHistory        []*types.Survey
+	includeItemNames     []string
+	excludeItemNames     []string
 	surveyVersions       []SurveyVersionPreview
 	responses            []ParsedResponse
 	contextColNames      []string
@@ -111,28 +114,67 @@ func newResponseExporterBase(
 
 	rp := ResponseExporter{
 		surveyKey:            surveyHistory[0].SurveyDefinition.Key,
-		surveyVersions:       []SurveyVersionPreview{},
+		surveyHistory:        surveyHistory,
+		includeItemNames:     includeItemNames,
+		excludeItemNames:     excludeItemNames,
 		responses:            []ParsedResponse{},
 		shortQuestionKeys:    shortQuestionKeys,
 		questionOptionKeySep: questionOptionSep,
 	}
+	rp.surveyVersions = rp.getSurveyVersionPreviews(previewLang)
 
-	for _, v := range surveyHistory {
-		rp.surveyVersions = append(rp.surveyVersions, surveyDefToVersionPreview(v, previewLang, includeItemNames, excludeItemNames))
+	return &rp, nil
+}
+
+// getSurveyVersionPreviews returns the previews of all survey versions with texts in the language
+func (rp ResponseExporter) getSurveyVersionPreviews(lang string) []SurveyVersionPreview {
+	surveyVersions := []SurveyVersionPreview{}
+	for _, v := range rp.surveyHistory {
+		surveyVersions = append(surveyVersions, surveyDefToVersionPreview(v, lang, rp.includeItemNames, rp.excludeItemNames))
 	}
 
-	if shortQuestionKeys {
-		for versionInd, sv := range rp.surveyVersions {
+	if rp.shortQuestionKeys {
+		for versionInd, sv := range surveyVersions {
 			for qInd, question := range sv.Questions {
-				rp.surveyVersions[versionInd].Questions[qInd].ID = strings.TrimPrefix(question.ID, rp.surveyKey+".")
+				surveyVersions[versionInd].Questions[qInd].ID = strings.TrimPrefix(question.ID, rp.surveyKey+".")
 			}
 		}
 	}
-
-	return &rp, nil
+	return surveyVersions
 }
 
 func (rp *ResponseExporter) AddResponse(rawResp *types.SurveyResponse) error {
+	parsedResponse, err := rp.parseResponse(rawResp)
+	if err != nil {
+		return err
+	}
+
+	// Extend col names:
+	for k := range parsedResponse.Responses {
+		rp.AddResponseColName(k)
+	}
+	for k := range parsedResponse.Context {
+		rp.AddContextColName(k)
+	}
+	for k := range parsedResponse.Meta.Position {
+		rp.AddMetaColName(k)
+	}
+	for k := range parsedResponse.Meta.Initialised {
+		rp.AddMetaColName(k)
+	}
+	for k := range parsedResponse.Meta.Displayed {
+		rp.AddMetaColName(k)
+	}
+	for k := range parsedResponse.Meta.Responded {
+		rp.AddMetaColName(k)
+	}
+
+	rp.responses = append(rp.responses, parsedResponse)
+	return nil
+}
+
+// parseResponse maps the response to the columns of the survey version it was submitted with
+func (rp ResponseExporter) parseResponse(rawResp *types.SurveyResponse) (ParsedResponse, error) {
 	parsedResponse := ParsedResponse{
 		ID:            rawResp.ID.Hex(),
 		ParticipantID: rawResp.ParticipantID,
@@ -151,7 +193,7 @@ func (rp *ResponseExporter) AddResponse(rawResp *types.SurveyResponse) error {
 
 	currentVersion, err := findSurveyVersion(rawResp.VersionID, rawResp.SubmittedAt, rp.surveyVersions)
 	if err != nil {
-		return err
+		return parsedResponse, err
 	}
 	if currentVersion.VersionID != rawResp.VersionID && currentVersion.VersionID != "" {
 		parsedResponse.Version = rawResp.VersionID + " (" + currentVersion.VersionID + ")"
@@ -177,19 +219,15 @@ func (rp *ResponseExporter) AddResponse(rawResp *types.SurveyResponse) error {
 
 		// Set meta infos
 		initColName := question.ID + rp.questionOptionKeySep + "metaInit"
-		rp.AddMetaColName(initColName)
 		parsedResponse.Meta.Initialised[initColName] = []int64{}
 
 		dispColName := question.ID + rp.questionOptionKeySep + "metaDisplayed"
-		rp.AddMetaColName(dispColName)
 		parsedResponse.Meta.Displayed[dispColName] = []int64{}
 
 		respColName := question.ID + rp.questionOptionKeySep + "metaResponse"
-		rp.AddMetaColName(respColName)
 		parsedResponse.Meta.Responded[respColName] = []int64{}
 
 		positionColName := question.ID + rp.questionOptionKeySep + "metaPosition"
-		rp.AddMetaColName(positionColName)
 		parsedResponse.Meta.Position[positionColName] = 0
 
 		if resp != nil {
@@ -205,17 +243,35 @@ func (rp *ResponseExporter) AddResponse(rawResp *types.SurveyResponse) error {
 			parsedResponse.Meta.Position[positionColName] = resp.Meta.Position
 		}
 	}
+	return parsedResponse, nil
+}
 
-	// Extend response col names:
-	for k := range parsedResponse.Responses {
-		rp.AddResponseColName(k)
-	}
-	for k := range parsedResponse.Context {
-		rp.AddContextColName(k)
+// getColumnsFromSurveyVersions returns the response and meta columns of all survey versions, sorted alphabetically
+func (rp ResponseExporter) getColumnsFromSurveyVersions() (responseCols []string, metaCols []string) {
+	responseColSet := map[string]bool{}
+	metaColSet := map[string]bool{}
+	for _, sv := range rp.surveyVersions {
+		for _, question := range sv.Questions {
+			for k := range getResponseColumns(question, nil, rp.questionOptionKeySep) {
+				responseColSet[k] = true
+			}
+			for _, suffix := range []string{"metaInit", "metaDisplayed", "metaResponse", "metaPosition"} {
+				metaColSet[question.ID+rp.questionOptionKeySep+suffix] = true
+			}
+		}
 	}
 
-	rp.responses = append(rp.responses, parsedResponse)
-	return nil
+	responseCols = make([]string, 0, len(responseColSet))
+	for k := range responseColSet {
+		responseCols = append(responseCols, k)
+	}
+	sort.Strings(responseCols)
+	metaCols = make([]string, 0, len(metaColSet))
+	for k := range metaColSet {
+		metaCols = append(metaCols, k)
+	}
+	sort.Strings(metaCols)
+	return responseCols, metaCols
 }
 
 func (rp *ResponseExporter) AddResponseColName(name string) {
@@ -254,81 +310,11 @@ func (rp ResponseExporter) GetResponses() []ParsedResponse {
 }
 
 func (rp ResponseExporter) GetResponsesJSON(writer io.Writer, includeMeta *IncludeMeta) error {
+	metaCols := filterMetaColumns(rp.metaColNames, includeMeta)
+
 	responseArray := []map[string]interface{}{}
 	for _, resp := range rp.responses {
-
-		currentResp := rp.getFixedColumns(resp)
-
-		contextCols := rp.contextColNames
-		for _, colName := range contextCols {
-			v, ok := resp.Context[colName]
-			if !ok {
-				currentResp[colName] = ""
-			} else {
-				currentResp[colName] = v
-			}
-		}
-
-		responseCols := rp.responseColNames
-		for _, colName := range responseCols {
-			r, ok := resp.Responses[colName]
-			if !ok {
-				currentResp[colName] = ""
-			} else {
-				currentResp[colName] = r
-			}
-		}
-
-		metaCols := rp.metaColNames
-		sort.Strings(metaCols)
-
-		if includeMeta != nil {
-			for _, colName := range metaCols {
-				if strings.Contains(colName, "metaInit") {
-					if !includeMeta.InitTimes {
-						continue
-					}
-					v, ok := resp.Meta.Initialised[colName]
-					if !ok {
-						currentResp[colName] = ""
-					} else {
-						currentResp[colName] = v
-					}
-				} else if strings.Contains(colName, "metaDisplayed") {
-					if !includeMeta.DisplayedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Displayed[colName]
-					if !ok {
-						currentResp[colName] = ""
-					} else {
-						currentResp[colName] = v
-					}
-				} else if strings.Contains(colName, "metaResponse") {
-					if !includeMeta.ResponsedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Responded[colName]
-					if !ok {
-						currentResp[colName] = ""
-					} else {
-						currentResp[colName] = v
-					}
-				} else if strings.Contains(colName, "metaPosition") {
-					if !includeMeta.Postion {
-						continue
-					}
-					v, ok := resp.Meta.Position[colName]
-					if !ok {
-						currentResp[colName] = ""
-					} else {
-						currentResp[colName] = v
-					}
-				}
-			}
-		}
-
-		responseArray = append(responseArray, currentResp)
+		responseArray = append(responseArray, rp.getJSONObject(resp, rp.contextColNames, rp.responseColNames, metaCols))
 	}
 	b, err := json.Marshal(responseArray)
 	if err != nil {
@@ -348,109 +334,21 @@ func (rp ResponseExporter) GetResponsesCSV(writer io.Writer, includeMeta *Includ
 	sort.Strings(contextCols)
 	responseCols := rp.responseColNames
 	sort.Strings(responseCols)
-	metaCols := rp.metaColNames
+	metaCols := filterMetaColumns(rp.metaColNames, includeMeta)
 	sort.Strings(metaCols)
 
-	// Prepare csv header
-	header := fixedColumnKeys
-	header = append(header, contextCols...)
-	header = append(header, responseCols...)
-	if includeMeta != nil {
-		for _, c := range metaCols {
-			if !includeMeta.Postion && strings.Contains(c, "metaPosition") {
-				continue
-			}
-			if !includeMeta.InitTimes && strings.Contains(c, "metaInit") {
-				continue
-			}
-			if !includeMeta.DisplayedTimes && strings.Contains(c, "metaDisplayed") {
-				continue
-			}
-			if !includeMeta.ResponsedTimes && strings.Contains(c, "metaResponse") {
-				continue
-			}
-			header = append(header, c)
-		}
-	}
-
 	// Init writer
 	w := csv.NewWriter(writer)
 
 	// Write header
-	err := w.Write(header)
+	err := w.Write(getWideFormatHeader(contextCols, responseCols, metaCols))
 	if err != nil {
 		return err
 	}
 
 	// Write responses
 	for _, resp := range rp.responses {
-		line := rp.getFixedColumnValueStrings(resp)
-
-		for _, colName := range contextCols {
-			v, ok := resp.Context[colName]
-			if !ok {
-				line = append(line, "")
-				continue
-			}
-			line = append(line, v)
-		}
-
-		for _, colName := range responseCols {
-			v, ok := resp.Responses[colName]
-			if !ok {
-				line = append(line, "")
-				continue
-			}
-			line = append(line, responseColToString(v))
-		}
-
-		if includeMeta != nil {
-			for _, colName := range metaCols {
-				if strings.Contains(colName, "metaInit") {
-					if !includeMeta.InitTimes {
-						continue
-					}
-					v, ok := resp.Meta.Initialised[colName]
-					if !ok {
-						line = append(line, "")
-						continue
-					}
-					line = append(line, timestampsToStr(v))
-				} else if strings.Contains(colName, "metaDisplayed") {
-					if !includeMeta.DisplayedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Displayed[colName]
-					if !ok {
-						line = append(line, "")
-						continue
-					}
-					line = append(line, timestampsToStr(v))
-				} else if strings.Contains(colName, "metaResponse") {
-					if !includeMeta.ResponsedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Responded[colName]
-					if !ok {
-						line = append(line, "")
-						continue
-					}
-					line = append(line, timestampsToStr(v))
-				} else if strings.Contains(colName, "metaPosition") {
-					if !includeMeta.Postion {
-						continue
-					}
-					v, ok := resp.Meta.Position[colName]
-					if !ok {
-						line = append(line, "")
-						continue
-					}
-					line = append(line, fmt.Sprintf("%d", v))
-				}
-			}
-		}
-
-		err := w.Write(line)
+		err := w.Write(rp.getWideFormatLine(resp, contextCols, responseCols, metaCols))
 		if err != nil {
 			return err
 		}
@@ -469,106 +367,318 @@ func (rp ResponseExporter) GetResponsesLongFormatCSV(writer io.Writer, metaInfos
 	sort.Strings(contextCols)
 	responseCols := rp.responseColNames
 	sort.Strings(responseCols)
-	metaCols := rp.metaColNames
+	metaCols := filterMetaColumns(rp.metaColNames, metaInfos)
 	sort.Strings(metaCols)
 
-	// Prepare csv header
-	header := fixedColumnKeys
-	header = append(header, contextCols...)
-	header = append(header, "responseSlot")
-	header = append(header, "value")
-
 	// Init writer
 	w := csv.NewWriter(writer)
 
 	// Write header
-	err := w.Write(header)
+	err := w.Write(getLongFormatHeader(contextCols))
 	if err != nil {
 		return err
 	}
 
 	// Write responses
 	for _, resp := range rp.responses {
-		line := rp.getFixedColumnValueStrings(resp)
+		err := w.WriteAll(rp.getLongFormatLines(resp, contextCols, responseCols, metaCols))
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return nil
+}
 
-		for _, colName := range contextCols {
-			v, ok := resp.Context[colName]
-			if !ok {
-				line = append(line, "")
-				continue
-			}
-			line = append(line, v)
+func getWideFormatHeader(contextCols []string, responseCols []string, metaCols []string) []string {
+	header := []string{}
+	header = append(header, fixedColumnKeys...)
+	header = append(header, contextCols...)
+	header = append(header, responseCols...)
+	header = append(header, metaCols...)
+	return header
+}
+
+func getLongFormatHeader(contextCols []string) []string {
+	header := []string{}
+	header = append(header, fixedColumnKeys...)
+	header = append(header, contextCols...)
+	header = append(header, "responseSlot")
+	header = append(header, "value")
+	return header
+}
+
+// getWideFormatLine returns the values of the response for the columns of the wide format
+func (rp ResponseExporter) getWideFormatLine(resp ParsedResponse, contextCols []string, responseCols []string, metaCols []string) []string {
+	line := rp.getFixedColumnValueStrings(resp)
+
+	for _, colName := range contextCols {
+		v, ok := resp.Context[colName]
+		if !ok {
+			line = append(line, "")
+			continue
 		}
+		line = append(line, v)
+	}
 
-		for _, colName := range responseCols {
-			currentRespLine := []string{}
-			currentRespLine = append(currentRespLine, line...)
-			currentRespLine = append(currentRespLine, colName)
-			v, ok := resp.Responses[colName]
-			if !ok {
-				currentRespLine = append(currentRespLine, "")
+	for _, colName := range responseCols {
+		v, ok := resp.Responses[colName]
+		if !ok {
+			line = append(line, "")
+			continue
+		}
+		line = append(line, responseColToString(v))
+	}
+
+	for _, colName := range metaCols {
+		line = append(line, getMetaColumnValueString(resp, colName))
+	}
+	return line
+}
+
+// getLongFormatLines returns one line per response and meta column for the long format
+func (rp ResponseExporter) getLongFormatLines(resp ParsedResponse, contextCols []string, responseCols []string, metaCols []string) [][]string {
+	line := rp.getFixedColumnValueStrings(resp)
+
+	for _, colName := range contextCols {
+		v, ok := resp.Context[colName]
+		if !ok {
+			line = append(line, "")
+			continue
+		}
+		line = append(line, v)
+	}
+
+	lines := make([][]string, 0, len(responseCols)+len(metaCols))
+	for _, colName := range responseCols {
+		currentRespLine := []string{}
+		currentRespLine = append(currentRespLine, line...)
+		currentRespLine = append(currentRespLine, colName)
+		v, ok := resp.Responses[colName]
+		if !ok {
+			currentRespLine = append(currentRespLine, "")
+		} else {
+			currentRespLine = append(currentRespLine, responseColToString(v))
+		}
+		lines = append(lines, currentRespLine)
+	}
+
+	for _, colName := range metaCols {
+		currentRespLine := []string{}
+		currentRespLine = append(currentRespLine, line...)
+		currentRespLine = append(currentRespLine, colName)
+		currentRespLine = append(currentRespLine, getMetaColumnValueString(resp, colName))
+		lines = append(lines, currentRespLine)
+	}
+	return lines
+}
+
+// getJSONObject returns the response as object of the flat JSON format
+func (rp ResponseExporter) getJSONObject(resp ParsedResponse, contextCols []string, responseCols []string, metaCols []string) map[string]interface{} {
+	currentResp := rp.getFixedColumns(resp)
+
+	for _, colName := range contextCols {
+		v, ok := resp.Context[colName]
+		if !ok {
+			currentResp[colName] = ""
+		} else {
+			currentResp[colName] = v
+		}
+	}
+
+	for _, colName := range responseCols {
+		r, ok := resp.Responses[colName]
+		if !ok {
+			currentResp[colName] = ""
+		} else {
+			currentResp[colName] = r
+		}
+	}
+
+	for _, colName := range metaCols {
+		var v interface{}
+		var ok bool
+		if strings.Contains(colName, "metaInit") {
+			v, ok = resp.Meta.Initialised[colName]
+		} else if strings.Contains(colName, "metaDisplayed") {
+			v, ok = resp.Meta.Displayed[colName]
+		} else if strings.Contains(colName, "metaResponse") {
+			v, ok = resp.Meta.Responded[colName]
+		} else if strings.Contains(colName, "metaPosition") {
+			v, ok = resp.Meta.Position[colName]
+		}
+		if !ok {
+			currentResp[colName] = ""
+		} else {
+			currentResp[colName] = v
+		}
+	}
+	return currentResp
+}
+
+func getMetaColumnValueString(resp ParsedResponse, colName string) string {
+	if strings.Contains(colName, "metaInit") {
+		if v, ok := resp.Meta.Initialised[colName]; ok {
+			return timestampsToStr(v)
+		}
+	} else if strings.Contains(colName, "metaDisplayed") {
+		if v, ok := resp.Meta.Displayed[colName]; ok {
+			return timestampsToStr(v)
+		}
+	} else if strings.Contains(colName, "metaResponse") {
+		if v, ok := resp.Meta.Responded[colName]; ok {
+			return timestampsToStr(v)
+		}
+	} else if strings.Contains(colName, "metaPosition") {
+		if v, ok := resp.Meta.Position[colName]; ok {
+			return fmt.Sprintf("%d", v)
+		}
+	}
+	return ""
+}
+
+// getParquetColumns returns the columns of the Parquet format: the columns of the wide format with types derived from
+// the survey definitions, and the overflow column
+func (rp ResponseExporter) getParquetColumns(contextCols []string, responseCols []string, metaCols []string) []parquetColumn {
+	columns := []parquetColumn{}
+	for _, k := range fixedColumnKeys {
+		colType := COLUMN_TYPE_STRING
+		if k == "opened" || k == "submitted" {
+			colType = COLUMN_TYPE_TIMESTAMP
+		}
+		columns = append(columns, parquetColumn{Name: k, Type: colType})
+	}
+	for _, colName := range contextCols {
+		columns = append(columns, parquetColumn{Name: colName, Type: COLUMN_TYPE_STRING})
+	}
+	colTypes := rp.getResponseColumnTypes()
+	for _, colName := range responseCols {
+		colType, ok := colTypes[colName]
+		if !ok {
+			colType = COLUMN_TYPE_STRING
+		}
+		columns = append(columns, parquetColumn{Name: colName, Type: colType})
+	}
+	for _, colName := range metaCols {
+		colType := COLUMN_TYPE_STRING
+		if strings.Contains(colName, "metaPosition") {
+			colType = COLUMN_TYPE_INTEGER
+		}
+		columns = append(columns, parquetColumn{Name: colName, Type: colType})
+	}
+	return append(columns, parquetColumn{Name: OVERFLOW_COL_NAME, Type: COLUMN_TYPE_STRING})
+}
+
+// getParquetRow returns the values of the response for the columns of getParquetColumns, without the overflow column.
+// Response values that do not match the type of their column are added to overflow.
+func (rp ResponseExporter) getParquetRow(resp ParsedResponse, columns []parquetColumn, contextCols []string, responseCols []string, metaCols []string, overflow map[string]interface{}) []interface{} {
+	row := make([]interface{}, 0, len(columns))
+
+	fixedColumns := rp.getFixedColumns(resp)
+	for _, k := range fixedColumnKeys {
+		switch value := fixedColumns[k].(type) {
+		case int64:
+			if value == 0 {
+				row = append(row, nil)
 			} else {
-				currentRespLine = append(currentRespLine, responseColToString(v))
+				row = append(row, value*1000)
 			}
-
-			err := w.Write(currentRespLine)
-			if err != nil {
-				return err
+		case string:
+			if value == "" {
+				row = append(row, nil)
+			} else {
+				row = append(row, value)
 			}
+		default:
+			row = append(row, nil)
 		}
+	}
 
-		if metaInfos != nil {
-			for _, colName := range metaCols {
-				value := ""
-				if strings.Contains(colName, "metaInit") {
-					if !metaInfos.InitTimes {
-						continue
-					}
-					v, ok := resp.Meta.Initialised[colName]
-					if ok {
-						value = timestampsToStr(v)
-					}
-				} else if strings.Contains(colName, "metaDisplayed") {
-					if !metaInfos.DisplayedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Displayed[colName]
-					if ok {
-						value = timestampsToStr(v)
-					}
-				} else if strings.Contains(colName, "metaResponse") {
-					if !metaInfos.ResponsedTimes {
-						continue
-					}
-					v, ok := resp.Meta.Responded[colName]
-					if ok {
-						value = timestampsToStr(v)
-					}
-				} else if strings.Contains(colName, "metaPosition") {
-					if !metaInfos.Postion {
-						continue
-					}
-					v, ok := resp.Meta.Position[colName]
-					if ok {
-						value = fmt.Sprintf("%d", v)
-					}
-				}
+	for _, colName := range contextCols {
+		v, ok := resp.Context[colName]
+		if !ok || v == "" {
+			row = append(row, nil)
+			continue
+		}
+		row = append(row, v)
+	}
+
+	responseColOffset := len(fixedColumnKeys) + len(contextCols)
+	for i, colName := range responseCols {
+		v, ok := typedColumnValue(resp.Responses[colName], columns[responseColOffset+i].Type)
+		if !ok {
+			overflow[colName] = resp.Responses[colName]
+		}
+		row = append(row, v)
+	}
+
+	for _, colName := range metaCols {
+		if strings.Contains(colName, "metaPosition") {
+			v, ok := resp.Meta.Position[colName]
+			if !ok {
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, int64(v))
+			continue
+		}
 
-				currentRespLine := []string{}
-				currentRespLine = append(currentRespLine, line...)
-				currentRespLine = append(currentRespLine, colName)
-				currentRespLine = append(currentRespLine, value)
+		var v []int64
+		if strings.Contains(colName, "metaInit") {
+			v = resp.Meta.Initialised[colName]
+		} else if strings.Contains(colName, "metaDisplayed") {
+			v = resp.Meta.Displayed[colName]
+		} else if strings.Contains(colName, "metaResponse") {
+			v = resp.Meta.Responded[colName]
+		}
+		if len(v) == 0 {
+			row = append(row, nil)
+			continue
+		}
+		row = append(row, timestampsToStr(v))
+	}
+	return row
+}
 
-				err := w.Write(currentRespLine)
-				if err != nil {
-					return err
+// getResponseColumnTypes returns the types of the response columns that are not strings, for all survey versions.
+// Columns with different types in different versions are strings.
+func (rp ResponseExporter) getResponseColumnTypes() map[string]string {
+	colTypes := map[string]string{}
+	for _, sv := range rp.surveyVersions {
+		for _, question := range sv.Questions {
+			for colName, colType := range getResponseColumnTypes(question, rp.questionOptionKeySep) {
+				if existing, ok := colTypes[colName]; ok && existing != colType {
+					colType = COLUMN_TYPE_STRING
 				}
+				colTypes[colName] = colType
 			}
 		}
+	}
+	return colTypes
+}
 
+// filterMetaColumns returns the meta columns selected by includeMeta
+func filterMetaColumns(metaCols []string, includeMeta *IncludeMeta) []string {
+	filtered := []string{}
+	if includeMeta == nil {
+		return filtered
 	}
-	w.Flush()
-	return nil
+	for _, c := range metaCols {
+		if !includeMeta.Postion && strings.Contains(c, "metaPosition") {
+			continue
+		}
+		if !includeMeta.InitTimes && strings.Contains(c, "metaInit") {
+			continue
+		}
+		if !includeMeta.DisplayedTimes && strings.Contains(c, "metaDisplayed") {
+			continue
+		}
+		if !includeMeta.ResponsedTimes && strings.Contains(c, "metaResponse") {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
 }
 
 func (rp ResponseExporter) GetSurveyInfoCSV(writer io.Writer) error {
@@ -0,0 +1,257 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+type StreamFormat int
+
+const (
+	STREAM_FORMAT_JSON     StreamFormat = iota
+	STREAM_FORMAT_WIDE_CSV StreamFormat = iota
+	STREAM_FORMAT_LONG_CSV StreamFormat = iota
+	STREAM_FORMAT_PARQUET  StreamFormat = iota
+)
+
+// OVERFLOW_COL_NAME is the column of streamed Parquet exports, and of wide format exports with SetOverflowColumn, with
+// the values of columns that are not defined in the survey versions, as JSON object
+const OVERFLOW_COL_NAME = "overflow"
+
+// ResponseStream writes each response to the writer when it is added, instead of keeping all responses in memory.
+// Response and meta columns are derived from the survey versions, context columns must be given up front. Values of
+// other columns are written to the OVERFLOW_COL_NAME column of the Parquet format and of the wide format if enabled with
+// SetOverflowColumn (otherwise they are not exported in the wide format), and as additional keys or lines in the JSON and
+// long formats. Parquet files are written in row groups, values that do not match the type of their
+// column are written to the OVERFLOW_COL_NAME column.
+type ResponseStream struct {
+	rp           *ResponseExporter
+	format       StreamFormat
+	writer       io.Writer
+	csvWriter    *csv.Writer
+	parquet      *parquetWriter
+	parquetCols  []parquetColumn
+	contextCols  []string
+	responseCols []string
+	metaCols     []string
+	knownCols    map[string]bool
+	unknownCols  []string
+	mismatchCols map[string]bool
+	overflowCol  bool
+	count        int
+}
+
+// NewResponseStream prepares the columns for the format. The context columns are written in the given order.
+func (rp *ResponseExporter) NewResponseStream(writer io.Writer, format StreamFormat, contextCols []string, includeMeta *IncludeMeta) (*ResponseStream, error) {
+	responseCols, metaCols := rp.getColumnsFromSurveyVersions()
+	rs := &ResponseStream{
+		rp:           rp,
+		format:       format,
+		writer:       writer,
+		contextCols:  contextCols,
+		responseCols: responseCols,
+		metaCols:     filterMetaColumns(metaCols, includeMeta),
+		knownCols:    map[string]bool{},
+		mismatchCols: map[string]bool{},
+	}
+	for _, k := range contextCols {
+		rs.knownCols[k] = true
+	}
+	for _, k := range responseCols {
+		rs.knownCols[k] = true
+	}
+
+	switch format {
+	case STREAM_FORMAT_JSON:
+		if _, err := writer.Write([]byte("[")); err != nil {
+			return nil, err
+		}
+	case STREAM_FORMAT_WIDE_CSV, STREAM_FORMAT_LONG_CSV:
+		rs.csvWriter = csv.NewWriter(writer)
+	case STREAM_FORMAT_PARQUET:
+		rs.parquetCols = rp.getParquetColumns(rs.contextCols, rs.responseCols, rs.metaCols)
+		pw, err := newParquetWriter(writer, rs.parquetCols)
+		if err != nil {
+			return nil, err
+		}
+		rs.parquet = pw
+	default:
+		return nil, errors.New("unknown stream format")
+	}
+	return rs, nil
+}
+
+// SetOverflowColumn adds the OVERFLOW_COL_NAME column to the wide format, it must be called before the first response
+func (rs *ResponseStream) SetOverflowColumn(include bool) {
+	rs.overflowCol = include
+}
+
+// WriteResponse writes the response, the CSV header is written with the first response
+func (rs *ResponseStream) WriteResponse(rawResp *types.SurveyResponse) error {
+	resp, err := rs.rp.parseResponse(rawResp)
+	if err != nil {
+		return err
+	}
+	overflow := rs.getOverflowValues(resp)
+
+	switch rs.format {
+	case STREAM_FORMAT_JSON:
+		obj := rs.rp.getJSONObject(resp, rs.contextCols, rs.responseCols, rs.metaCols)
+		for k, v := range overflow {
+			obj[k] = v
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if rs.count > 0 {
+			b = append([]byte(","), b...)
+		}
+		if _, err := rs.writer.Write(b); err != nil {
+			return err
+		}
+	case STREAM_FORMAT_WIDE_CSV:
+		if rs.count == 0 {
+			header := getWideFormatHeader(rs.contextCols, rs.responseCols, rs.metaCols)
+			if rs.overflowCol {
+				header = append(header, OVERFLOW_COL_NAME)
+			}
+			if err := rs.csvWriter.Write(header); err != nil {
+				return err
+			}
+		}
+		line := rs.rp.getWideFormatLine(resp, rs.contextCols, rs.responseCols, rs.metaCols)
+		if rs.overflowCol {
+			overflowValue := ""
+			if len(overflow) > 0 {
+				b, err := json.Marshal(overflow)
+				if err != nil {
+					return err
+				}
+				overflowValue = string(b)
+			}
+			line = append(line, overflowValue)
+		}
+		if err := rs.csvWriter.Write(line); err != nil {
+			return err
+		}
+	case STREAM_FORMAT_LONG_CSV:
+		if rs.count == 0 {
+			if err := rs.csvWriter.Write(getLongFormatHeader(rs.contextCols)); err != nil {
+				return err
+			}
+		}
+		lines := rs.rp.getLongFormatLines(resp, rs.contextCols, rs.responseCols, rs.metaCols)
+		if len(overflow) > 0 {
+			fixed := rs.rp.getFixedColumnValueStrings(resp)
+			for _, k := range sortedKeys(overflow) {
+				line := append([]string{}, fixed...)
+				for _, colName := range rs.contextCols {
+					line = append(line, resp.Context[colName])
+				}
+				lines = append(lines, append(line, k, responseColToString(overflow[k])))
+			}
+		}
+		for _, line := range lines {
+			if err := rs.csvWriter.Write(line); err != nil {
+				return err
+			}
+		}
+	case STREAM_FORMAT_PARQUET:
+		row := rs.rp.getParquetRow(resp, rs.parquetCols, rs.contextCols, rs.responseCols, rs.metaCols, overflow)
+		for k := range overflow {
+			if rs.knownCols[k] && !rs.mismatchCols[k] {
+				logger.Warning.Printf("value of column %s of response %s does not match the column type, value is exported in column %s", k, resp.ID, OVERFLOW_COL_NAME)
+				rs.mismatchCols[k] = true
+			}
+		}
+		if len(overflow) > 0 {
+			b, err := json.Marshal(overflow)
+			if err != nil {
+				return err
+			}
+			row = append(row, string(b))
+		} else {
+			row = append(row, nil)
+		}
+		if err := rs.parquet.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	rs.count++
+	return nil
+}
+
+// Close writes the remaining data. For CSV and Parquet formats, an error is returned if no response was written.
+func (rs *ResponseStream) Close() error {
+	if rs.format == STREAM_FORMAT_JSON {
+		_, err := rs.writer.Write([]byte("]"))
+		return err
+	}
+	if rs.count < 1 {
+		return errors.New("no responses, nothing is generated")
+	}
+	if rs.format == STREAM_FORMAT_PARQUET {
+		return rs.parquet.Close()
+	}
+	rs.csvWriter.Flush()
+	return rs.csvWriter.Error()
+}
+
+// Count returns the number of responses written
+func (rs *ResponseStream) Count() int {
+	return rs.count
+}
+
+// UnknownColumns returns the columns not defined in the survey versions or context columns, in the order they were found
+func (rs *ResponseStream) UnknownColumns() []string {
+	return rs.unknownCols
+}
+
+// UnknownColumnsExported returns false if the values of unknown columns are not part of the output, i.e. for the wide
+// format without overflow column
+func (rs *ResponseStream) UnknownColumnsExported() bool {
+	return rs.format != STREAM_FORMAT_WIDE_CSV || rs.overflowCol
+}
+
+// getOverflowValues returns the values of the response's columns that are not defined in the survey versions or context columns
+func (rs *ResponseStream) getOverflowValues(resp ParsedResponse) map[string]interface{} {
+	overflow := map[string]interface{}{}
+	for k, v := range resp.Context {
+		if !rs.isKnownColumn(k, resp.ID) {
+			overflow[k] = v
+		}
+	}
+	for k, v := range resp.Responses {
+		if !rs.isKnownColumn(k, resp.ID) {
+			overflow[k] = v
+		}
+	}
+	return overflow
+}
+
+// isKnownColumn logs each unknown column once
+func (rs *ResponseStream) isKnownColumn(k string, responseID string) bool {
+	known, ok := rs.knownCols[k]
+	if !ok {
+		logger.Warning.Printf("column %s of response %s not found in the survey definitions, value is exported in column %s", k, responseID, OVERFLOW_COL_NAME)
+		rs.knownCols[k] = false
+		rs.unknownCols = append(rs.unknownCols, k)
+	}
+	return known
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,422 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestResponseStream(t *testing.T) {
+	logger.SetLevel(logger.LEVEL_ERROR)
+	includeMeta := &IncludeMeta{
+		Postion:        true,
+		InitTimes:      true,
+		DisplayedTimes: true,
+		ResponsedTimes: true,
+	}
+
+	// writes the responses with a new exporter in memory and as stream
+	exportResponses := func(t *testing.T, surveyDefFile string, responsesFile string, format StreamFormat) (string, string) {
+		var testSurveyHistory types.SurveyVersionsJSON
+		json.Unmarshal(readTestFileToBytes(t, surveyDefFile), &testSurveyHistory)
+		var testResponses []types.SurveyResponse
+		json.Unmarshal(readTestFileToBytes(t, responsesFile), &testResponses)
+
+		parser, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err.Error())
+		}
+		streamExporter, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err.Error())
+		}
+
+		contextColSet := map[string]bool{}
+		for _, response := range testResponses {
+			for k := range response.Context {
+				contextColSet[k] = true
+			}
+		}
+		contextCols := []string{}
+		for k := range contextColSet {
+			contextCols = append(contextCols, k)
+		}
+		sort.Strings(contextCols)
+
+		buf := new(bytes.Buffer)
+		rs, err := streamExporter.NewResponseStream(buf, format, contextCols, includeMeta)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, response := range testResponses {
+			r := response
+			if err := parser.AddResponse(&r); err != nil {
+				t.Fatalf("unexpected error: %v", err.Error())
+			}
+			r = response
+			if err := rs.WriteResponse(&r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := rs.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rs.Count() != len(testResponses) {
+			t.Errorf("unexpected count: %d", rs.Count())
+		}
+
+		expected := new(bytes.Buffer)
+		switch format {
+		case STREAM_FORMAT_WIDE_CSV:
+			err = parser.GetResponsesCSV(expected, includeMeta)
+		case STREAM_FORMAT_LONG_CSV:
+			err = parser.GetResponsesLongFormatCSV(expected, includeMeta)
+		case STREAM_FORMAT_JSON:
+			err = parser.GetResponsesJSON(expected, includeMeta)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return buf.String(), expected.String()
+	}
+
+	for _, format := range []struct {
+		name   string
+		format StreamFormat
+	}{
+		{name: "Wide CSV", format: STREAM_FORMAT_WIDE_CSV},
+		{name: "Long CSV", format: STREAM_FORMAT_LONG_CSV},
+		{name: "JSON", format: STREAM_FORMAT_JSON},
+	} {
+		t.Run("question types "+format.name, func(t *testing.T) {
+			output, expected := exportResponses(t, "./test_files/questionTypes/surveyDef.json", "./test_files/questionTypes/responses.json", format.format)
+			if output != expected {
+				t.Errorf("unexpected output:\n%s\nexpected:\n%s", output, expected)
+			}
+		})
+	}
+
+	t.Run("question types Parquet", func(t *testing.T) {
+		output, _ := exportResponses(t, "./test_files/questionTypes/surveyDef.json", "./test_files/questionTypes/responses.json", STREAM_FORMAT_PARQUET)
+		columns, rows, _ := readParquetFile(t, []byte(output))
+
+		expectedTypes := map[string]string{
+			"submitted":   COLUMN_TYPE_TIMESTAMP,
+			"CLZ-number":  COLUMN_TYPE_NUMBER,
+			"DateInput":   COLUMN_TYPE_TIMESTAMP,
+			"MCG1-0":      COLUMN_TYPE_BOOLEAN,
+			"NInput":      COLUMN_TYPE_NUMBER,
+			"SLIDER":      COLUMN_TYPE_NUMBER,
+			"TextInput":   COLUMN_TYPE_STRING,
+			"LIKERT-row1": COLUMN_TYPE_STRING,
+
+			"SCG1-metaPosition": COLUMN_TYPE_INTEGER,
+			OVERFLOW_COL_NAME:   COLUMN_TYPE_STRING,
+		}
+		for _, c := range columns {
+			if expected, ok := expectedTypes[c.Name]; ok && c.Type != expected {
+				t.Errorf("unexpected type for column %s: %s", c.Name, c.Type)
+			}
+		}
+
+		// values must match the wide CSV export, which has no overflow column by default
+		wideCSV, _ := exportResponses(t, "./test_files/questionTypes/surveyDef.json", "./test_files/questionTypes/responses.json", STREAM_FORMAT_WIDE_CSV)
+		records, err := csv.NewReader(strings.NewReader(wideCSV)).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(records) != len(rows)+1 || len(records[0]) != len(columns)-1 || columns[len(columns)-1].Name != OVERFLOW_COL_NAME {
+			t.Fatalf("unexpected dimensions: %d rows, %d columns", len(rows), len(columns))
+		}
+		for i, row := range rows {
+			for j, v := range row[:len(row)-1] {
+				if columns[j].Name != records[0][j] {
+					t.Fatalf("unexpected column %s, expected %s", columns[j].Name, records[0][j])
+				}
+				str := ""
+				switch value := v.(type) {
+				case string:
+					str = value
+				case float64:
+					str = strconv.FormatFloat(value, 'f', -1, 64)
+				case int64:
+					if columns[j].Type == COLUMN_TYPE_TIMESTAMP {
+						value = value / 1000
+					}
+					str = strconv.FormatInt(value, 10)
+				case bool:
+					str = FALSE_VALUE
+					if value {
+						str = TRUE_VALUE
+					}
+				}
+				if v == nil && records[i+1][j] == "[]" {
+					// empty lists of meta timestamps are null
+					continue
+				}
+				if str != records[i+1][j] {
+					t.Errorf("unexpected value for %s in row %d: %v, expected %s", columns[j].Name, i, v, records[i+1][j])
+				}
+			}
+		}
+	})
+
+	t.Run("with columns of survey versions without responses", func(t *testing.T) {
+		output, expected := exportResponses(t, "./test_files/testSurveyDef.json", "./test_files/testResponses.json", STREAM_FORMAT_WIDE_CSV)
+		outputRecords, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		expectedRecords, err := csv.NewReader(strings.NewReader(expected)).ReadAll()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if len(outputRecords) != len(expectedRecords) {
+			t.Errorf("unexpected number of lines: %d", len(outputRecords))
+			return
+		}
+		if len(outputRecords[0]) <= len(expectedRecords[0])+1 {
+			t.Errorf("columns of all survey versions expected: %d", len(outputRecords[0]))
+		}
+
+		expectedCols := map[string]int{}
+		for i, colName := range expectedRecords[0] {
+			expectedCols[colName] = i
+		}
+		for i, colName := range outputRecords[0] {
+			expectedIndex, ok := expectedCols[colName]
+			if colName == OVERFLOW_COL_NAME {
+				continue
+			}
+			for l := 1; l < len(outputRecords); l++ {
+				// columns of other versions are empty
+				expectedValue := ""
+				if ok {
+					expectedValue = expectedRecords[l][expectedIndex]
+				}
+				if outputRecords[l][i] != expectedValue {
+					t.Errorf("unexpected value for %s in line %d: %s, expected %s", colName, l, outputRecords[l][i], expectedValue)
+					return
+				}
+			}
+		}
+	})
+
+	t.Run("with no responses", func(t *testing.T) {
+		var testSurveyHistory types.SurveyVersionsJSON
+		json.Unmarshal(readTestFileToBytes(t, "./test_files/testSurveyDef.json"), &testSurveyHistory)
+		streamExporter, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err.Error())
+			return
+		}
+
+		buf := new(bytes.Buffer)
+		rs, err := streamExporter.NewResponseStream(buf, STREAM_FORMAT_WIDE_CSV, []string{}, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := rs.Close(); err == nil {
+			t.Error("should produce error")
+		}
+
+		buf = new(bytes.Buffer)
+		rs, err = streamExporter.NewResponseStream(buf, STREAM_FORMAT_PARQUET, []string{}, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := rs.Close(); err == nil {
+			t.Error("should produce error")
+		}
+
+		buf = new(bytes.Buffer)
+		rs, err = streamExporter.NewResponseStream(buf, STREAM_FORMAT_JSON, []string{}, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if err := rs.Close(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if buf.String() != "[]" {
+			t.Errorf("unexpected output: %s", buf.String())
+		}
+	})
+}
+
+func TestResponseStreamUnknownColumns(t *testing.T) {
+	logger.SetLevel(logger.LEVEL_ERROR)
+	var testSurveyHistory types.SurveyVersionsJSON
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/surveyDef.json"), &testSurveyHistory)
+	var testResponses []types.SurveyResponse
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/responses.json"), &testResponses)
+	response := testResponses[0]
+	response.Responses = append(response.Responses, types.SurveyItemResponse{
+		Key:      "weekly.removedQuestion",
+		Response: &types.ResponseItem{Key: "rg", Items: []*types.ResponseItem{{Key: "scg", Items: []*types.ResponseItem{{Key: "1"}}}}},
+	})
+
+	writeResponse := func(t *testing.T, format StreamFormat, overflowCol bool) (string, *ResponseStream) {
+		rp, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err.Error())
+		}
+		buf := new(bytes.Buffer)
+		rs, err := rp.NewResponseStream(buf, format, []string{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rs.SetOverflowColumn(overflowCol)
+		r := response
+		if err := rs.WriteResponse(&r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := rs.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rs.UnknownColumns()) < 1 {
+			t.Errorf("unknown columns should be reported")
+		}
+		return buf.String(), rs
+	}
+
+	t.Run("Wide CSV without overflow column", func(t *testing.T) {
+		output, rs := writeResponse(t, STREAM_FORMAT_WIDE_CSV, false)
+		records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, colName := range records[0] {
+			if colName == OVERFLOW_COL_NAME {
+				t.Errorf("unexpected overflow column")
+			}
+		}
+		if rs.UnknownColumnsExported() {
+			t.Errorf("unknown columns should not be exported")
+		}
+	})
+
+	t.Run("Wide CSV", func(t *testing.T) {
+		output, rs := writeResponse(t, STREAM_FORMAT_WIDE_CSV, true)
+		records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		last := len(records[0]) - 1
+		if records[0][last] != OVERFLOW_COL_NAME {
+			t.Errorf("unexpected last column: %s", records[0][last])
+		}
+		overflow := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(records[1][last]), &overflow); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, k := range rs.UnknownColumns() {
+			if _, ok := overflow[k]; !ok {
+				t.Errorf("value of %s missing in %v", k, overflow)
+			}
+		}
+	})
+
+	t.Run("Long CSV", func(t *testing.T) {
+		output, rs := writeResponse(t, STREAM_FORMAT_LONG_CSV, false)
+		for _, k := range rs.UnknownColumns() {
+			if !strings.Contains(output, ","+k+",") {
+				t.Errorf("line for %s missing", k)
+			}
+		}
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		output, rs := writeResponse(t, STREAM_FORMAT_PARQUET, false)
+		columns, rows, _ := readParquetFile(t, []byte(output))
+		last := len(columns) - 1
+		if columns[last].Name != OVERFLOW_COL_NAME {
+			t.Fatalf("unexpected last column: %s", columns[last].Name)
+		}
+		value, _ := rows[0][last].(string)
+		overflow := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(value), &overflow); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, k := range rs.UnknownColumns() {
+			if _, ok := overflow[k]; !ok {
+				t.Errorf("value of %s missing in %v", k, overflow)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		output, rs := writeResponse(t, STREAM_FORMAT_JSON, false)
+		parsed := []map[string]interface{}{}
+		if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, k := range rs.UnknownColumns() {
+			if _, ok := parsed[0][k]; !ok {
+				t.Errorf("value of %s missing", k)
+			}
+		}
+	})
+}
+
+func TestResponseStreamParquetTypeMismatch(t *testing.T) {
+	logger.SetLevel(logger.LEVEL_ERROR)
+	var testSurveyHistory types.SurveyVersionsJSON
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/surveyDef.json"), &testSurveyHistory)
+	response := types.SurveyResponse{
+		Key:           "EXAMPLE",
+		ParticipantID: "p1",
+		VersionID:     testSurveyHistory.SurveyVersions[0].VersionID,
+		SubmittedAt:   1640116869,
+		Responses: []types.SurveyItemResponse{
+			{
+				Key:      "EXAMPLE.NInput",
+				Response: &types.ResponseItem{Key: "rg", Items: []*types.ResponseItem{{Key: "num", Value: "not a number", Dtype: "number"}}},
+			},
+		},
+	}
+
+	rp, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err.Error())
+	}
+	buf := new(bytes.Buffer)
+	rs, err := rp.NewResponseStream(buf, STREAM_FORMAT_PARQUET, []string{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rs.WriteResponse(&response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rs.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	columns, rows, _ := readParquetFile(t, buf.Bytes())
+	for i, c := range columns {
+		switch c.Name {
+		case "NInput":
+			if c.Type != COLUMN_TYPE_NUMBER || rows[0][i] != nil {
+				t.Errorf("unexpected column %v with value %v", c, rows[0][i])
+			}
+		case OVERFLOW_COL_NAME:
+			if rows[0][i] != `{"NInput":"not a number"}` {
+				t.Errorf("unexpected overflow value: %v", rows[0][i])
+			}
+		}
+	}
+	if len(rs.UnknownColumns()) > 0 {
+		t.Errorf("unexpected unknown columns: %v", rs.UnknownColumns())
+	}
+}
@@ -0,0 +1,359 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	STATA_MAX_NAME_LENGTH        = 32
+	STATA_MAX_LABEL_LENGTH       = 80
+	SPSS_MAX_NAME_LENGTH         = 64
+	SPSS_MAX_LABEL_LENGTH        = 255
+	SPSS_MAX_VALUE_LABEL_LENGTH  = 120
+	SPSS_FIXED_STRING_WIDTH      = 255
+	SPSS_RESPONSE_STRING_WIDTH   = 2000
+	SYNTAX_TEMP_ENCODED_VARIABLE = "_encoded"
+)
+
+var stataReservedNames = map[string]bool{
+	"byte": true, "double": true, "float": true, "if": true, "in": true, "int": true, "long": true, "strL": true,
+	"using": true, "with": true,
+}
+
+var stataStrTypeName = regexp.MustCompile(`^str[0-9]+$`)
+
+var spssReservedNames = map[string]bool{
+	"ALL": true, "AND": true, "BY": true, "EQ": true, "GE": true, "GT": true, "LE": true, "LT": true, "NE": true,
+	"NOT": true, "OR": true, "TO": true, "WITH": true,
+}
+
+// getSyntaxVariables returns the variables of the wide format in the order of its columns, with the texts in the language
+func (rp ResponseExporter) getSyntaxVariables(lang string, contextCols []string, includeMeta *IncludeMeta, overflowCol bool) []CodebookVariable {
+	cb := rp.GetCodebook([]string{lang}, includeMeta)
+	if !overflowCol {
+		// the overflow column is the last variable of the codebook
+		cb.Variables = cb.Variables[:len(cb.Variables)-1]
+	}
+
+	variables := []CodebookVariable{}
+	variables = append(variables, cb.Variables[:len(fixedColumnKeys)]...)
+	for _, k := range contextCols {
+		variables = append(variables, CodebookVariable{
+			Name:        k,
+			Kind:        CODEBOOK_VARIABLE_CONTEXT,
+			DType:       COLUMN_TYPE_STRING,
+			NamePattern: k,
+			Versions:    []string{},
+		})
+	}
+	variables = append(variables, cb.Variables[len(fixedColumnKeys):]...)
+	return variables
+}
+
+// syntaxVariableNames converts the column names to unique variable names of letters, digits and '_' starting with a
+// letter, with at most maxLength characters. Other characters are replaced by '_', and reserved names get a '_' suffix.
+func syntaxVariableNames(columns []string, maxLength int, isReserved func(name string) bool, caseSensitive bool) []string {
+	key := func(name string) string {
+		if caseSensitive {
+			return name
+		}
+		return strings.ToLower(name)
+	}
+	truncate := func(name string, length int) string {
+		if len(name) > length {
+			return name[:length]
+		}
+		return name
+	}
+
+	names := make([]string, len(columns))
+	used := map[string]bool{}
+	for i, col := range columns {
+		b := []byte{}
+		for _, r := range col {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				b = append(b, byte(r))
+			} else {
+				b = append(b, '_')
+			}
+		}
+		name := string(b)
+		if name == "" || !((name[0] >= 'a' && name[0] <= 'z') || (name[0] >= 'A' && name[0] <= 'Z')) {
+			name = "v" + name
+		}
+		name = truncate(name, maxLength)
+		if isReserved(name) {
+			name = truncate(name, maxLength-1) + "_"
+		}
+
+		base := name
+		for n := 2; used[key(name)]; n++ {
+			suffix := fmt.Sprintf("_%d", n)
+			name = truncate(base, maxLength-len(suffix)) + suffix
+		}
+		used[key(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// syntaxVariableLabel composes the label of the variable from question text, response slot or option label, and the
+// suffix of open fields and meta columns. The column name is used if there is no text.
+func syntaxVariableLabel(v CodebookVariable, lang string) string {
+	parts := []string{}
+	if text := cleanLabel(v.QuestionText[lang]); text != "" {
+		parts = append(parts, text)
+	}
+	if label := cleanLabel(v.Label[lang]); label != "" {
+		parts = append(parts, label)
+	}
+	if v.OpenField {
+		parts = append(parts, OPEN_FIELD_COL_SUFFIX)
+	}
+	if v.Kind == CODEBOOK_VARIABLE_META {
+		parts = append(parts, strings.TrimPrefix(v.NamePattern, NAME_PATTERN_QUESTION_KEY+NAME_PATTERN_SEP))
+	}
+	if len(parts) == 0 {
+		return v.Name
+	}
+	return strings.Join(parts, " - ")
+}
+
+func syntaxCodeLabel(code CodebookCode, lang string) string {
+	if label := cleanLabel(code.Label[lang]); label != "" {
+		return label
+	}
+	return code.Code
+}
+
+func cleanLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
+
+// truncateLabel shortens the label to maxLength bytes, without splitting characters
+func truncateLabel(label string, maxLength int) string {
+	if len(label) <= maxLength {
+		return label
+	}
+	label = label[:maxLength]
+	for len(label) > 0 && !utf8.ValidString(label) {
+		label = label[:len(label)-1]
+	}
+	return strings.TrimSpace(label)
+}
+
+// hasIntegerCodes is true if all codes can be used as numeric values for value labels
+func hasIntegerCodes(codes []CodebookCode) bool {
+	for _, code := range codes {
+		if _, err := strconv.ParseInt(code.Code, 10, 32); err != nil {
+			return false
+		}
+	}
+	return len(codes) > 0
+}
+
+// stataString quotes s with compound double quotes, so that it can contain double quotes. Backticks would open nested
+// quotes or local macros and are replaced, and a closing sequence "' is separated to keep the string open.
+func stataString(s string) string {
+	s = strings.NewReplacer("`", "'", "$", `\$`).Replace(s)
+	s = strings.ReplaceAll(s, `"'`, `" '`)
+	return "`\"" + s + "\"'"
+}
+
+func spssString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// GetStataSyntax writes a Stata do-file that imports the wide format CSV (with the given context columns, and the
+// overflow column if overflowCol is set), renames the columns to valid variable names, converts numbers, booleans and
+// timestamps, and sets variable and value labels.
+func (rp ResponseExporter) GetStataSyntax(writer io.Writer, lang string, contextCols []string, includeMeta *IncludeMeta, overflowCol bool) error {
+	variables := rp.getSyntaxVariables(lang, contextCols, includeMeta, overflowCol)
+	columns := make([]string, len(variables))
+	for i, v := range variables {
+		columns[i] = v.Name
+	}
+	names := syntaxVariableNames(columns, STATA_MAX_NAME_LENGTH, func(name string) bool {
+		return stataReservedNames[name] || stataStrTypeName.MatchString(name)
+	}, true)
+
+	lines := []string{
+		fmt.Sprintf("* Variable and value labels for the wide format CSV export of survey %s (language: %s)", rp.surveyKey, lang),
+		"* The CSV file must be exported with the same parameters (time range, item filter, separator, meta columns).",
+		"* Usage: do <this file> \"<path of the CSV file>\"",
+		"version 14",
+		"args csvfile",
+		"import delimited using `\"`csvfile'\"', varnames(nonames) rowrange(2) stringcols(_all) bindquotes(strict) encoding(\"utf-8\") clear",
+		fmt.Sprintf("if c(k) != %d {", len(variables)),
+		"    display as error \"unexpected number of columns, the CSV file must be exported with the same parameters\"",
+		"    exit 198",
+		"}",
+		"",
+		"rename ( ///",
+	}
+	for i := range variables {
+		lines = append(lines, fmt.Sprintf("    v%d ///", i+1))
+	}
+	lines = append(lines, "    ) ( ///")
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("    %s ///", name))
+	}
+	lines = append(lines, "    )", "", "label define truefalse 0 \"FALSE\" 1 \"TRUE\"")
+
+	for i, v := range variables {
+		name := names[i]
+		lines = append(lines, "")
+		switch {
+		case v.DType == COLUMN_TYPE_NUMBER || v.DType == COLUMN_TYPE_INTEGER:
+			lines = append(lines, fmt.Sprintf("destring %s, replace", name))
+		case v.DType == COLUMN_TYPE_TIMESTAMP:
+			lines = append(lines,
+				fmt.Sprintf("destring %s, replace", name),
+				fmt.Sprintf("capture confirm numeric variable %s", name),
+				"if !_rc {",
+				fmt.Sprintf("    replace %s = %s * 1000 + tc(01jan1970 00:00:00)", name, name),
+				fmt.Sprintf("    format %s %%tc", name),
+				"}",
+			)
+		case v.DType == COLUMN_TYPE_BOOLEAN:
+			lines = append(lines,
+				fmt.Sprintf("replace %s = cond(%s == \"TRUE\", \"1\", cond(%s == \"FALSE\", \"0\", %s))", name, name, name, name),
+				fmt.Sprintf("destring %s, replace", name),
+				fmt.Sprintf("capture confirm numeric variable %s", name),
+				fmt.Sprintf("if !_rc label values %s truefalse", name),
+			)
+		case hasIntegerCodes(v.Codes):
+			valueLabels := []string{}
+			for _, code := range v.Codes {
+				valueLabels = append(valueLabels, code.Code+" "+stataString(truncateLabel(syntaxCodeLabel(code, lang), STATA_MAX_LABEL_LENGTH)))
+			}
+			lines = append(lines,
+				fmt.Sprintf("label define %s %s", name, strings.Join(valueLabels, " ")),
+				fmt.Sprintf("destring %s, replace", name),
+				fmt.Sprintf("capture confirm numeric variable %s", name),
+				fmt.Sprintf("if !_rc label values %s %s", name, name),
+			)
+		case len(v.Codes) > 0:
+			// codes are encoded with numbers in the order of the options, other values are added by encode
+			codeValues := []string{}
+			valueLabels := []string{}
+			for j, code := range v.Codes {
+				codeValues = append(codeValues, fmt.Sprintf("%d %s", j+1, stataString(code.Code)))
+				valueLabels = append(valueLabels, fmt.Sprintf("%d %s", j+1, stataString(truncateLabel(syntaxCodeLabel(code, lang), STATA_MAX_LABEL_LENGTH))))
+			}
+			lines = append(lines,
+				fmt.Sprintf("label define %s %s", name, strings.Join(codeValues, " ")),
+				fmt.Sprintf("encode %s, generate(%s) label(%s)", name, SYNTAX_TEMP_ENCODED_VARIABLE, name),
+				fmt.Sprintf("order %s, after(%s)", SYNTAX_TEMP_ENCODED_VARIABLE, name),
+				fmt.Sprintf("drop %s", name),
+				fmt.Sprintf("rename %s %s", SYNTAX_TEMP_ENCODED_VARIABLE, name),
+				fmt.Sprintf("label define %s %s, modify", name, strings.Join(valueLabels, " ")),
+			)
+		}
+		lines = append(lines, fmt.Sprintf("label variable %s %s", name, stataString(truncateLabel(syntaxVariableLabel(v, lang), STATA_MAX_LABEL_LENGTH))))
+	}
+
+	_, err := io.WriteString(writer, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// GetSPSSSyntax writes an SPSS syntax file that reads the wide format CSV (with the given context columns, and the
+// overflow column if overflowCol is set) with valid variable names, converts booleans and timestamps, and sets variable
+// and value labels.
+func (rp ResponseExporter) GetSPSSSyntax(writer io.Writer, lang string, contextCols []string, includeMeta *IncludeMeta, overflowCol bool) error {
+	variables := rp.getSyntaxVariables(lang, contextCols, includeMeta, overflowCol)
+	columns := make([]string, len(variables))
+	for i, v := range variables {
+		columns[i] = v.Name
+	}
+	names := syntaxVariableNames(columns, SPSS_MAX_NAME_LENGTH, func(name string) bool {
+		return spssReservedNames[strings.ToUpper(name)]
+	}, false)
+
+	lines := []string{
+		"* Encoding: UTF-8.",
+		fmt.Sprintf("* Variable and value labels for the wide format CSV export of survey %s (language: %s).", rp.surveyKey, lang),
+		"* The CSV file must be exported with the same parameters (time range, item filter, separator, meta columns).",
+		"* Set the path of the CSV file in the FILE HANDLE command.",
+		"FILE HANDLE csvfile /NAME='responses.csv'.",
+		"GET DATA",
+		"  /TYPE=TXT",
+		"  /FILE=csvfile",
+		"  /ENCODING='UTF8'",
+		"  /DELCASE=LINE",
+		"  /DELIMITERS=\",\"",
+		"  /QUALIFIER='\"'",
+		"  /ARRANGEMENT=DELIMITED",
+		"  /FIRSTCASE=2",
+		"  /VARIABLES=",
+	}
+
+	commands := []string{}
+	variableLabels := []string{}
+	for i, v := range variables {
+		name := names[i]
+		numericCodes := hasIntegerCodes(v.Codes)
+		format := fmt.Sprintf("A%d", SPSS_RESPONSE_STRING_WIDTH)
+		switch {
+		case v.DType == COLUMN_TYPE_TIMESTAMP:
+			format = "F20.0"
+			commands = append(commands,
+				fmt.Sprintf("COMPUTE %s = %s + DATE.DMY(1,1,1970).", name, name),
+				fmt.Sprintf("FORMATS %s (DATETIME20).", name),
+			)
+		case v.Kind == CODEBOOK_VARIABLE_FIXED || v.Kind == CODEBOOK_VARIABLE_CONTEXT:
+			format = fmt.Sprintf("A%d", SPSS_FIXED_STRING_WIDTH)
+		case v.DType == COLUMN_TYPE_INTEGER:
+			format = "F10.0"
+		case v.DType == COLUMN_TYPE_NUMBER:
+			// no decimals in the input format, else values without decimal point are scaled
+			format = "F20.0"
+			commands = append(commands, fmt.Sprintf("FORMATS %s (F20.4).", name))
+		case v.DType == COLUMN_TYPE_BOOLEAN:
+			format = "A5"
+			commands = append(commands,
+				fmt.Sprintf("RECODE %s ('TRUE'='1') ('FALSE'='0').", name),
+				fmt.Sprintf("ALTER TYPE %s (F1.0).", name),
+				fmt.Sprintf("VALUE LABELS %s 0 'FALSE' 1 'TRUE'.", name),
+			)
+		case numericCodes:
+			format = "F10.0"
+		case len(v.Codes) > 0:
+			format = fmt.Sprintf("A%d", SPSS_FIXED_STRING_WIDTH)
+		}
+		if len(v.Codes) > 0 && v.DType == COLUMN_TYPE_STRING {
+			valueLabels := []string{"VALUE LABELS " + name}
+			for _, code := range v.Codes {
+				value := code.Code
+				if !numericCodes {
+					value = spssString(code.Code)
+				}
+				valueLabels = append(valueLabels, fmt.Sprintf("  %s %s", value, spssString(truncateLabel(syntaxCodeLabel(code, lang), SPSS_MAX_VALUE_LABEL_LENGTH))))
+			}
+			valueLabels[len(valueLabels)-1] += "."
+			commands = append(commands, valueLabels...)
+		}
+
+		lines = append(lines, fmt.Sprintf("    %s %s", name, format))
+		variableLabels = append(variableLabels, fmt.Sprintf("  /%s %s", name, spssString(truncateLabel(syntaxVariableLabel(v, lang), SPSS_MAX_LABEL_LENGTH))))
+	}
+	lines[len(lines)-1] += "."
+
+	lines = append(lines, "")
+	lines = append(lines, commands...)
+	if len(variableLabels) > 0 {
+		variableLabels[0] = "  " + strings.TrimPrefix(variableLabels[0], "  /")
+		variableLabels[len(variableLabels)-1] += "."
+		lines = append(lines, "VARIABLE LABELS")
+		lines = append(lines, variableLabels...)
+	}
+	lines = append(lines, "EXECUTE.")
+
+	_, err := io.WriteString(writer, strings.Join(lines, "\n")+"\n")
+	return err
+}
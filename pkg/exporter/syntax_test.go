@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestSyntaxVariableNames(t *testing.T) {
+	notReserved := func(name string) bool { return false }
+
+	t.Run("with invalid characters", func(t *testing.T) {
+		names := syntaxVariableNames([]string{"ID", "Q1-1.open", "1abc", "_x", ""}, 32, notReserved, true)
+		expected := []string{"ID", "Q1_1_open", "v1abc", "v_x", "v"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+
+	t.Run("with duplicates after conversion", func(t *testing.T) {
+		names := syntaxVariableNames([]string{"Q1-a", "Q1.a", "q1_a"}, 32, notReserved, false)
+		expected := []string{"Q1_a", "Q1_a_2", "q1_a_3"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("unexpected names: %v", names)
+		}
+		names = syntaxVariableNames([]string{"Q1-a", "Q1.a", "q1_a"}, 32, notReserved, true)
+		expected = []string{"Q1_a", "Q1_a_2", "q1_a"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+
+	t.Run("with long names", func(t *testing.T) {
+		long := strings.Repeat("a", 40)
+		names := syntaxVariableNames([]string{long + "1", long + "2"}, 32, notReserved, true)
+		expected := []string{strings.Repeat("a", 32), strings.Repeat("a", 30) + "_2"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+
+	t.Run("with reserved names", func(t *testing.T) {
+		names := syntaxVariableNames([]string{"if", "TO"}, 32, func(name string) bool {
+			return name == "if" || spssReservedNames[strings.ToUpper(name)]
+		}, true)
+		expected := []string{"if_", "TO_"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+}
+
+func TestStataString(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{input: "Simple question", expected: "`\"Simple question\"'"},
+		{input: `Do you have "fever"?`, expected: "`\"Do you have \"fever\"?\"'"},
+		{input: `ends with a quote"`, expected: "`\"ends with a quote\"\"'"},
+		{input: `closing "' sequence`, expected: "`\"closing \" ' sequence\"'"},
+		{input: "`local' and $global", expected: "`\"'local' and \\$global\"'"},
+		{input: "quote \"` with backtick", expected: "`\"quote \" ' with backtick\"'"},
+	} {
+		if output := stataString(c.input); output != c.expected {
+			t.Errorf("unexpected string for %s: %s", c.input, output)
+		}
+	}
+}
+
+func TestSyntaxExport(t *testing.T) {
+	logger.SetLevel(logger.LEVEL_ERROR)
+	includeMeta := &IncludeMeta{Postion: true}
+	contextCols := []string{"engineVersion", "language"}
+
+	var testSurveyHistory types.SurveyVersionsJSON
+	json.Unmarshal(readTestFileToBytes(t, "./test_files/questionTypes/surveyDef.json"), &testSurveyHistory)
+	rp, err := NewResponseExporter(testSurveyHistory.SurveyVersions, "nl", true, "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err.Error())
+	}
+
+	t.Run("variables in order of wide format columns", func(t *testing.T) {
+		responseCols, metaCols := rp.getColumnsFromSurveyVersions()
+		header := getWideFormatHeader(contextCols, responseCols, filterMetaColumns(metaCols, includeMeta))
+		for _, overflowCol := range []bool{false, true} {
+			expected := header
+			if overflowCol {
+				expected = append(append([]string{}, header...), OVERFLOW_COL_NAME)
+			}
+			variables := rp.getSyntaxVariables("nl", contextCols, includeMeta, overflowCol)
+			names := []string{}
+			for _, v := range variables {
+				names = append(names, v.Name)
+			}
+			if !reflect.DeepEqual(names, expected) {
+				t.Errorf("unexpected variables: %v\nexpected: %v", names, expected)
+			}
+		}
+	})
+
+	t.Run("Stata", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := rp.GetStataSyntax(buf, "nl", contextCols, includeMeta, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{
+			"    v1 ///\n",
+			"    MCGO1_number_open ///\n",
+			"label define SCG1 1 `\"Ja\"' 0 `\"Nee\"'\n",
+			"label values SCG1 SCG1\n",
+			"label variable SCG1 `\"Simple single choice question\"'\n",
+			"if !_rc label values MCG1_0 truefalse\n",
+			"    replace submitted = submitted * 1000 + tc(01jan1970 00:00:00)\n",
+			"destring NInput, replace\n",
+			"destring SCG1_metaPosition, replace\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("missing line: %s", expected)
+			}
+		}
+	})
+
+	t.Run("SPSS", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := rp.GetSPSSSyntax(buf, "nl", contextCols, includeMeta, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		for _, expected := range []string{
+			"    ID A255\n",
+			"    SCG1 F10.0\n",
+			"    MCG1_0 A5\n",
+			"    NInput F20.0\n",
+			"    TextInput_metaPosition F10.0\n",
+			"    overflow A",
+			"VALUE LABELS SCG1\n  1 'Ja'\n  0 'Nee'.\n",
+			"RECODE MCG1_0 ('TRUE'='1') ('FALSE'='0').\n",
+			"COMPUTE submitted = submitted + DATE.DMY(1,1,1970).\n",
+			"VARIABLE LABELS\n  ID 'ID'\n",
+			"  /SCG1 'Simple single choice question'\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("missing line: %s", expected)
+			}
+		}
+	})
+}
@@ -53,6 +53,15 @@ const (
 	FALSE_VALUE           = "FALSE"
 )
 
+// Types of the export columns, used for typed export formats
+const (
+	COLUMN_TYPE_STRING    = "string"
+	COLUMN_TYPE_NUMBER    = "number"
+	COLUMN_TYPE_INTEGER   = "integer"
+	COLUMN_TYPE_TIMESTAMP = "timestamp" // values are unix timestamps in seconds
+	COLUMN_TYPE_BOOLEAN   = "boolean"   // values are TRUE_VALUE or FALSE_VALUE
+)
+
 type SurveyVersionPreview struct {
 	VersionID   string
 	Published   int64
@@ -3,9 +3,12 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/coneno/logger"
 	"github.com/influenzanet/go-utils/pkg/api_types"
@@ -17,6 +20,7 @@ import (
 	"github.com/influenzanet/study-service/pkg/studyengine"
 	"github.com/influenzanet/study-service/pkg/types"
 	"github.com/influenzanet/study-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -25,13 +29,9 @@ import (
 
 const CHUNK_SIZE = 64 * 1024 // 64 KiB
 
-type ResponseFormat int
-
-const (
-	FLAT_JSON       ResponseFormat = iota
-	WIDE_FORMAT_CSV ResponseFormat = iota
-	LONG_FORMAT_CSV ResponseFormat = iota
-)
+// Responses that arrived less than this number of seconds ago are left to the next incremental export, so that responses
+// inserted concurrently with the same arrivedAt are not skipped
+const INCREMENTAL_EXPORT_ARRIVAL_DELAY = 10
 
 func (s *studyServiceServer) GetStudyResponseStatistics(ctx context.Context, req *api.SurveyResponseQuery) (*api.StudyResponseStatistics, error) {
 	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
@@ -238,6 +238,52 @@ func (s *studyServiceServer) StreamReportHistory(req *api.ReportHistoryQuery, st
 	return nil
 }
 
+func (s *studyServiceServer) StreamParticipantEventHistory(req *api.ParticipantEventHistoryQuery, stream api.StudyServiceApi_StreamParticipantEventHistoryServer) error {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.ParticipantId == "" {
+		return s.missingArgumentError()
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id, []string{
+			types.STUDY_ROLE_OWNER,
+			types.STUDY_ROLE_MAINTAINER,
+		})
+		if err != nil {
+			logger.Warning.Printf("unauthorizd access attempt to participant event history in (%s-%s): %v", req.Token.InstanceId, req.StudyKey, err)
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_GET_PARTICIPANT_STATES, "permission denied for event history in "+req.StudyKey)
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	sendEventOverGrpc := func(instanceID string, studyKey string, event types.ParticipantEvent, args ...interface{}) error {
+		if len(args) != 1 {
+			return errors.New("stream callback: unexpected number of args")
+		}
+		stream, ok := args[0].(api.StudyServiceApi_StreamParticipantEventHistoryServer)
+		if !ok {
+			return errors.New(("stream callback: can't parse stream"))
+		}
+		return stream.Send(event.ToAPI())
+	}
+
+	query := studydb.ParticipantEventQuery{
+		ParticipantID: req.ParticipantId,
+		Since:         req.Since,
+		Until:         req.Until,
+	}
+	err := s.studyDBservice.PerformActionForParticipantEvents(
+		stream.Context(),
+		req.Token.InstanceId,
+		req.StudyKey,
+		query,
+		sendEventOverGrpc, stream)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_GET_PARTICIPANT_STATES, fmt.Sprintf("event history for %s in %s", req.ParticipantId, req.StudyKey))
+	return nil
+}
+
 func (s *studyServiceServer) StreamParticipantFileInfos(req *api.FileInfoQuery, stream api.StudyServiceApi_StreamParticipantFileInfosServer) error {
 	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
 		return s.missingArgumentError()
@@ -362,75 +408,68 @@ func (s *studyServiceServer) HasAccessToDownload(t *api_types.TokenInfos, studyK
 }
 
 func (s *studyServiceServer) GetResponsesFlatJSONWithPagination(req *api.ResponseExportQuery, stream api.StudyServiceApi_GetResponsesFlatJSONWithPaginationServer) error {
-	buf, err := s.getResponseExportBuffer(req, FLAT_JSON)
-	if err != nil {
-		return err
+	if req == nil {
+		return s.missingArgumentError()
+	}
+	if token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
+		return s.missingArgumentError()
+	}
+	if req.Incremental || req.ResumeToken != "" {
+		return status.Error(codes.InvalidArgument, "incremental export is not supported with pagination")
 	}
 
-	ctx := context.Background()
+	ctx := stream.Context()
 	itemCount := s.studyDBservice.GetSurveyResponsesCount(ctx, req.Token.InstanceId, req.StudyKey, req.SurveyKey, req.From, req.Until)
 	pageSize, page, pageCount := utils.ComputePaginationParameter(req.PageSize, req.Page, itemCount)
-	// Send pagination infos
-	infos := &api.PaginatedFile{
-		Data: &api.PaginatedFile_Info{
-			Info: &api.PaginationInfo{
-				ItemCount: itemCount,
-				PageCount: pageCount,
-				PageSize:  pageSize,
-				Page:      page,
+	sentInfos := false
+
+	// pagination infos are sent before the first chunk
+	_, err := s.streamResponseExport(ctx, req, exporter.STREAM_FORMAT_JSON, func(chunk []byte) error {
+		if !sentInfos {
+			infos := &api.PaginatedFile{
+				Data: &api.PaginatedFile_Info{
+					Info: &api.PaginationInfo{
+						ItemCount: itemCount,
+						PageCount: pageCount,
+						PageSize:  pageSize,
+						Page:      page,
+					},
+				},
+			}
+			if err := stream.Send(infos); err != nil {
+				return err
+			}
+			sentInfos = true
+		}
+		return stream.Send(&api.PaginatedFile{
+			Data: &api.PaginatedFile_Chunk{
+				Chunk: chunk,
 			},
-		},
-	}
-
-	err = stream.Send(infos)
-	if err != nil {
-		return err
-	}
-
-	data := &api.PaginatedFile{
-		Data: &api.PaginatedFile_Chunk{
-			Chunk: buf.Bytes(),
-		},
-	}
-	err = stream.Send(data)
-	if err != nil {
-		return err
-	}
-
-	return nil
+		})
+	})
+	return err
 }
 
 // TODO: Test GetResponsesFlatJSON
 func (s *studyServiceServer) GetResponsesFlatJSON(req *api.ResponseExportQuery, stream api.StudyServiceApi_GetResponsesFlatJSONServer) error {
-	buf, err := s.getResponseExportBuffer(req, FLAT_JSON)
-
-	if err != nil {
-		return err
-	}
-
-	return StreamFile(stream, buf)
+	return s.streamResponseExportChunks(stream.Context(), req, exporter.STREAM_FORMAT_JSON, stream)
 }
 
 // TODO: Test GetResponsesWideFormatCSV
 func (s *studyServiceServer) GetResponsesWideFormatCSV(req *api.ResponseExportQuery, stream api.StudyServiceApi_GetResponsesWideFormatCSVServer) error {
-	buf, err := s.getResponseExportBuffer(req, WIDE_FORMAT_CSV)
-
-	if err != nil {
-		return err
-	}
-
-	return StreamFile(stream, buf)
+	return s.streamResponseExportChunks(stream.Context(), req, exporter.STREAM_FORMAT_WIDE_CSV, stream)
 }
 
 // TODO: Test GetResponsesLongFormatCSV
 func (s *studyServiceServer) GetResponsesLongFormatCSV(req *api.ResponseExportQuery, stream api.StudyServiceApi_GetResponsesLongFormatCSVServer) error {
-	buf, err := s.getResponseExportBuffer(req, LONG_FORMAT_CSV)
+	return s.streamResponseExportChunks(stream.Context(), req, exporter.STREAM_FORMAT_LONG_CSV, stream)
+}
 
-	if err != nil {
-		return err
+func (s *studyServiceServer) GetResponsesParquet(req *api.ResponseExportQuery, stream api.StudyServiceApi_GetResponsesParquetServer) error {
+	if req != nil && (req.Incremental || req.ResumeToken != "") {
+		return status.Error(codes.InvalidArgument, "incremental export is not supported for this format")
 	}
-
-	return StreamFile(stream, buf)
+	return s.streamResponseExportChunks(stream.Context(), req, exporter.STREAM_FORMAT_PARQUET, stream)
 }
 
 // TODO: Test GetSurveyInfoPreviewCSV
@@ -469,6 +508,65 @@ func (s *studyServiceServer) GetSurveyInfoPreview(ctx context.Context, req *api.
 	}, nil
 }
 
+func (s *studyServiceServer) GetSurveyCodebook(req *api.CodebookExportQuery, stream api.StudyServiceApi_GetSurveyCodebookServer) error {
+	if req == nil || len(req.Languages) < 1 {
+		return s.missingArgumentError()
+	}
+	responseExporter, err := s.getResponseExporter(req.Token, req.StudyKey, req.SurveyKey, req.Languages[0], req.ShortQuestionKeys, req.Separator, req.ItemFilter)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	includeMeta := getIncludeMeta(req.IncludeMeta)
+	switch req.Format {
+	case api.CodebookExportQuery_CSV:
+		err = responseExporter.GetCodebookCSV(buf, req.Languages, includeMeta)
+	case api.CodebookExportQuery_DDI_XML:
+		err = responseExporter.GetCodebookDDI(buf, req.Languages, includeMeta)
+	default:
+		err = responseExporter.GetCodebookJSON(buf, req.Languages, includeMeta)
+	}
+	if err != nil {
+		logger.Info.Println(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return StreamFile(stream, buf)
+}
+
+func (s *studyServiceServer) GetResponsesSyntaxFile(req *api.SyntaxExportQuery, stream api.StudyServiceApi_GetResponsesSyntaxFileServer) error {
+	if req == nil || req.Query == nil || req.Language == "" {
+		return s.missingArgumentError()
+	}
+	responseExporter, err := s.getResponseExporterResponseExport(req.Query)
+	if err != nil {
+		return err
+	}
+
+	// same context columns as the wide format export of the query
+	contextCols, err := s.studyDBservice.GetSurveyResponseContextKeys(stream.Context(), req.Query.Token.InstanceId, req.Query.StudyKey, req.Query.SurveyKey, req.Query.From, req.Query.Until)
+	if err != nil {
+		logger.Info.Print(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	buf := new(bytes.Buffer)
+	includeMeta := getIncludeMeta(req.Query.IncludeMeta)
+	switch req.Format {
+	case api.SyntaxExportQuery_SPSS:
+		err = responseExporter.GetSPSSSyntax(buf, req.Language, contextCols, includeMeta, req.Query.IncludeOverflowColumn)
+	default:
+		err = responseExporter.GetStataSyntax(buf, req.Language, contextCols, includeMeta, req.Query.IncludeOverflowColumn)
+	}
+	if err != nil {
+		logger.Info.Println(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return StreamFile(stream, buf)
+}
+
 type StreamObj interface {
 	Send(*api.Chunk) error
 }
@@ -493,58 +591,199 @@ func StreamFile(stream StreamObj, buf *bytes.Buffer) error {
 	return nil
 }
 
-func (s *studyServiceServer) getResponseExportBuffer(req *api.ResponseExportQuery, fmt ResponseFormat) (*bytes.Buffer, error) {
+// chunkWriter sends the written bytes in chunks of CHUNK_SIZE, remaining bytes are sent with Flush
+type chunkWriter struct {
+	send func(chunk []byte) error
+	buf  []byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= CHUNK_SIZE {
+		if err := w.send(w.buf[:CHUNK_SIZE]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[CHUNK_SIZE:]
+	}
+	return len(p), nil
+}
+
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = nil
+	return err
+}
+
+func chunkSender(stream StreamObj) func(chunk []byte) error {
+	return func(chunk []byte) error {
+		return stream.Send(&api.Chunk{Chunk: chunk})
+	}
+}
+
+// streamResponseExport writes the responses in the format while they are read from the DB, and sends the output in
+// chunks. Incremental exports read the responses in order of arrival, after the cursor of the resume token if set, and
+// return the resume token of the last exported response.
+func (s *studyServiceServer) streamResponseExport(ctx context.Context, req *api.ResponseExportQuery, format exporter.StreamFormat, send func(chunk []byte) error) (string, error) {
 	responseExporter, err := s.getResponseExporterResponseExport(req)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	// Download responses
-	ctx := context.Background()
-	err = s.studyDBservice.PerformActionForSurveyResponses(
-		ctx,
-		req.Token.InstanceId, req.StudyKey, req.SurveyKey,
-		req.From, req.Until, func(instanceID, studyKey string, response types.SurveyResponse, args ...interface{}) error {
-			if len(args) != 3 {
-				return errors.New("[getResponseExportBuffer]: wrong DB method argument")
-			}
-			rExp, ok := args[0].(*exporter.ResponseExporter)
-			if !ok {
-				return errors.New("[getResponseExportBuffer]: wrong DB method argument")
-			}
-			return rExp.AddResponse(&response)
-		},
-		responseExporter, req.Page, req.PageSize,
-	)
+	incremental := req.Incremental || req.ResumeToken != ""
+	var cursor *studydb.SurveyResponseCursor
+	if req.ResumeToken != "" {
+		cursor, err = decodeResumeToken(req.ResumeToken, req.StudyKey, req.SurveyKey)
+		if err != nil {
+			return "", status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// context columns must be known before the first response is written
+	contextCols, err := s.studyDBservice.GetSurveyResponseContextKeys(ctx, req.Token.InstanceId, req.StudyKey, req.SurveyKey, req.From, req.Until)
 	if err != nil {
 		logger.Info.Print(err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return "", status.Error(codes.Internal, err.Error())
 	}
 
-	buf := new(bytes.Buffer)
-	includeMeta := &exporter.IncludeMeta{
-		Postion:        req.IncludeMeta.Position,
-		InitTimes:      req.IncludeMeta.InitTimes,
-		ResponsedTimes: req.IncludeMeta.ResponsedTimes,
-		DisplayedTimes: req.IncludeMeta.DisplayedTimes,
-	}
-
-	switch fmt {
-	case FLAT_JSON:
-		err = responseExporter.GetResponsesJSON(buf, includeMeta)
-	case WIDE_FORMAT_CSV:
-		err = responseExporter.GetResponsesCSV(buf, includeMeta)
-	case LONG_FORMAT_CSV:
-		err = responseExporter.GetResponsesLongFormatCSV(buf, includeMeta)
-	default:
-		return nil, status.Error(codes.Internal, errors.New("[getResponseExportBuffer]: wrong response format").Error())
+	w := &chunkWriter{send: send}
+	responseStream, err := responseExporter.NewResponseStream(w, format, contextCols, getIncludeMeta(req.IncludeMeta))
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
 	}
+	responseStream.SetOverflowColumn(req.IncludeOverflowColumn)
 
+	writeResponse := func(instanceID, studyKey string, response types.SurveyResponse, args ...interface{}) error {
+		if len(args) < 1 {
+			return errors.New("[streamResponseExport]: wrong DB method argument")
+		}
+		rs, ok := args[0].(*exporter.ResponseStream)
+		if !ok {
+			return errors.New("[streamResponseExport]: wrong DB method argument")
+		}
+		return rs.WriteResponse(&response)
+	}
+
+	lastCursor := studydb.SurveyResponseCursor{}
+	if cursor != nil {
+		lastCursor = *cursor
+	}
+	if incremental {
+		err = s.studyDBservice.PerformActionForSurveyResponsesInArrivalOrder(
+			ctx,
+			req.Token.InstanceId, req.StudyKey, req.SurveyKey,
+			req.From, req.Until,
+			cursor, time.Now().Unix()-INCREMENTAL_EXPORT_ARRIVAL_DELAY,
+			func(instanceID, studyKey string, response types.SurveyResponse, args ...interface{}) error {
+				// responses that cannot be exported are skipped, as in full exports
+				lastCursor = studydb.SurveyResponseCursor{ArrivedAt: response.ArrivedAt, ID: response.ID}
+				return writeResponse(instanceID, studyKey, response, args...)
+			},
+			responseStream,
+		)
+	} else {
+		err = s.studyDBservice.PerformActionForSurveyResponses(
+			ctx,
+			req.Token.InstanceId, req.StudyKey, req.SurveyKey,
+			req.From, req.Until, writeResponse,
+			responseStream, req.Page, req.PageSize,
+		)
+	}
 	if err != nil {
-		logger.Info.Println(err)
-		return nil, err
+		logger.Info.Print(err)
+		return "", status.Error(codes.Internal, err.Error())
+	}
+
+	if err := responseStream.Close(); err != nil {
+		// no new responses since the last incremental export is not an error
+		if !incremental || responseStream.Count() > 0 {
+			logger.Info.Println(err)
+			return "", err
+		}
+	}
+	if unknownCols := responseStream.UnknownColumns(); len(unknownCols) > 0 {
+		if responseStream.UnknownColumnsExported() {
+			logger.Warning.Printf("export of %s in %s: columns not defined in the survey versions exported in column %s: %v", req.SurveyKey, req.StudyKey, exporter.OVERFLOW_COL_NAME, unknownCols)
+		} else {
+			logger.Warning.Printf("export of %s in %s: columns not defined in the survey versions are not exported, use includeOverflowColumn to export them: %v", req.SurveyKey, req.StudyKey, unknownCols)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	if !incremental {
+		return "", nil
+	}
+
+	resumeToken, err := encodeResumeToken(req.StudyKey, req.SurveyKey, lastCursor)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	return resumeToken, nil
+}
+
+// streamResponseExportChunks sends the export as chunks, followed by a chunk with the resume token for incremental exports
+func (s *studyServiceServer) streamResponseExportChunks(ctx context.Context, req *api.ResponseExportQuery, format exporter.StreamFormat, stream StreamObj) error {
+	resumeToken, err := s.streamResponseExport(ctx, req, format, chunkSender(stream))
+	if err != nil {
+		return err
+	}
+	if resumeToken == "" {
+		return nil
+	}
+	return stream.Send(&api.Chunk{ResumeToken: resumeToken})
+}
+
+// responseExportCursor is the content of the resume token of incremental response exports
+type responseExportCursor struct {
+	StudyKey  string `json:"studyKey"`
+	SurveyKey string `json:"surveyKey"`
+	ArrivedAt int64  `json:"arrivedAt"`
+	ID        string `json:"id"`
+}
+
+func encodeResumeToken(studyKey string, surveyKey string, cursor studydb.SurveyResponseCursor) (string, error) {
+	b, err := json.Marshal(responseExportCursor{
+		StudyKey:  studyKey,
+		SurveyKey: surveyKey,
+		ArrivedAt: cursor.ArrivedAt,
+		ID:        cursor.ID.Hex(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeResumeToken returns the cursor of the token, which must be created for the same study and survey
+func decodeResumeToken(token string, studyKey string, surveyKey string) (*studydb.SurveyResponseCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid resume token")
+	}
+	var cursor responseExportCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, errors.New("invalid resume token")
+	}
+	if cursor.StudyKey != studyKey || cursor.SurveyKey != surveyKey {
+		return nil, errors.New("resume token of another study or survey")
+	}
+	id, err := primitive.ObjectIDFromHex(cursor.ID)
+	if err != nil {
+		return nil, errors.New("invalid resume token")
+	}
+	return &studydb.SurveyResponseCursor{ArrivedAt: cursor.ArrivedAt, ID: id}, nil
+}
+
+func getIncludeMeta(includeMeta *api.ResponseExportQuery_IncludeMeta) *exporter.IncludeMeta {
+	return &exporter.IncludeMeta{
+		Postion:        includeMeta.GetPosition(),
+		InitTimes:      includeMeta.GetInitTimes(),
+		ResponsedTimes: includeMeta.GetResponsedTimes(),
+		DisplayedTimes: includeMeta.GetDisplayedTimes(),
 	}
-	return buf, nil
 }
 
 func (s *studyServiceServer) getResponseExporterSurveyInfo(req *api.SurveyInfoExportQuery) (*exporter.ResponseExporter, error) {
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/influenzanet/study-service/pkg/api"
 	"github.com/influenzanet/study-service/pkg/dbs/studydb"
+	"github.com/influenzanet/study-service/pkg/exporter"
 	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc"
 
 	loggingMock "github.com/influenzanet/study-service/test/mocks/logging_service"
@@ -422,3 +428,493 @@ func TestStreamStudyResponsesEndpoint(t *testing.T) {
 		}
 	})
 }
+
+func TestChunkWriter(t *testing.T) {
+	chunks := [][]byte{}
+	w := &chunkWriter{send: func(chunk []byte) error {
+		chunks = append(chunks, append([]byte{}, chunk...))
+		return nil
+	}}
+
+	data := make([]byte, CHUNK_SIZE*2+10)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	if _, err := w.Write(data[:10]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(chunks) != 0 {
+		t.Errorf("unexpected number of chunks: %d", len(chunks))
+	}
+	if _, err := w.Write(data[10:]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Errorf("unexpected number of chunks: %d", len(chunks))
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(chunks) != 3 || len(chunks[2]) != 10 {
+		t.Errorf("unexpected chunks: %d", len(chunks))
+		return
+	}
+	if !bytes.Equal(bytes.Join(chunks, nil), data) {
+		t.Error("unexpected data")
+	}
+
+	// nothing left to send
+	if err := w.Flush(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Errorf("unexpected number of chunks: %d", len(chunks))
+	}
+}
+
+func TestResumeToken(t *testing.T) {
+	cursor := studydb.SurveyResponseCursor{ArrivedAt: 1640116902, ID: primitive.NewObjectID()}
+	token, err := encodeResumeToken("study1", "survey1", cursor)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	t.Run("with same study and survey", func(t *testing.T) {
+		decoded, err := decodeResumeToken(token, "study1", "survey1")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if *decoded != cursor {
+			t.Errorf("unexpected cursor: %v", decoded)
+		}
+	})
+
+	t.Run("with other survey", func(t *testing.T) {
+		if _, err := decodeResumeToken(token, "study1", "survey2"); err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("with invalid token", func(t *testing.T) {
+		if _, err := decodeResumeToken("not a token", "study1", "survey1"); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
+
+type studyServiceAPI_ChunkStream struct {
+	grpc.ServerStream
+	Results []*api.Chunk
+}
+
+func (_m *studyServiceAPI_ChunkStream) Context() context.Context {
+	return context.Background()
+}
+
+func (_m *studyServiceAPI_ChunkStream) Send(r *api.Chunk) error {
+	_m.Results = append(_m.Results, r)
+	return nil
+}
+
+func (_m *studyServiceAPI_ChunkStream) content() []byte {
+	content := []byte{}
+	for _, chunk := range _m.Results {
+		content = append(content, chunk.Chunk...)
+	}
+	return content
+}
+
+func testExportSurvey(surveyKey string, questionText string) types.Survey {
+	text := func(str string) []types.LocalisedObject {
+		return []types.LocalisedObject{{Code: "en", Parts: []types.ExpressionArg{{Str: str}}}}
+	}
+	return types.Survey{
+		Published: 1640291512,
+		VersionID: "v1",
+		SurveyDefinition: types.SurveyItem{
+			Key: surveyKey,
+			Items: []types.SurveyItem{
+				{
+					Key: surveyKey + ".Q1",
+					Components: &types.ItemComponent{
+						Role: "root",
+						Items: []types.ItemComponent{
+							{Role: "title", Content: text(questionText)},
+							{
+								Role: "responseGroup",
+								Key:  "rg",
+								Items: []types.ItemComponent{
+									{
+										Role: "singleChoiceGroup",
+										Key:  "scg",
+										Items: []types.ItemComponent{
+											{Role: "option", Key: "1", Content: text("Yes")},
+											{Role: "option", Key: "0", Content: text("No")},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetSurveyCodebookEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_getsurveycodebook"
+	testSurveyKey := "codebooksurvey"
+	testUser := "testuser"
+	testStudy := types.Study{
+		Key: testStudyKey,
+		Members: []types.StudyMember{
+			{
+				UserID: testUser,
+				Role:   "maintainer",
+			},
+		},
+	}
+
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	_, err = testStudyDBService.SaveSurvey(testInstanceID, testStudyKey, testExportSurvey(testSurveyKey, "Do you have fever?"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	researcherToken := &api_types.TokenInfos{
+		Id:         testUser,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles": "PARTICIPANT,RESEARCHER",
+		},
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		err := s.GetSurveyCodebook(nil, nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without languages", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token:     researcherToken,
+			StudyKey:  testStudyKey,
+			SurveyKey: testSurveyKey,
+		}
+		err := s.GetSurveyCodebook(req, mock)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without researcher role", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token: &api_types.TokenInfos{
+				Id:         testUser,
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles": "PARTICIPANT",
+				},
+			},
+			StudyKey:  testStudyKey,
+			SurveyKey: testSurveyKey,
+			Languages: []string{"en"},
+		}
+		err := s.GetSurveyCodebook(req, mock)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "unexpected roles PARTICIPANT")
+		if !ok {
+			t.Error(msg)
+		}
+		if len(mock.Results) > 0 {
+			t.Error("should not send the codebook")
+		}
+	})
+
+	t.Run("as non study member", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token: &api_types.TokenInfos{
+				Id:         testUser + "wrong",
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles": "PARTICIPANT,RESEARCHER",
+				},
+			},
+			StudyKey:  testStudyKey,
+			SurveyKey: testSurveyKey,
+			Languages: []string{"en"},
+		}
+		err := s.GetSurveyCodebook(req, mock)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "not authorized to access this study")
+		if !ok {
+			t.Error(msg)
+		}
+		if len(mock.Results) > 0 {
+			t.Error("should not send the codebook")
+		}
+	})
+
+	t.Run("as JSON", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token:             researcherToken,
+			StudyKey:          testStudyKey,
+			SurveyKey:         testSurveyKey,
+			Languages:         []string{"en"},
+			ShortQuestionKeys: true,
+			Separator:         "-",
+		}
+		if err := s.GetSurveyCodebook(req, mock); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		var codebook exporter.Codebook
+		if err := json.Unmarshal(mock.content(), &codebook); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		found := false
+		for _, v := range codebook.Variables {
+			if v.Name == "Q1" {
+				found = true
+				if v.QuestionText["en"] != "Do you have fever?" || len(v.Codes) != 2 {
+					t.Errorf("unexpected variable: %v", v)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("question missing in codebook: %v", codebook.Variables)
+		}
+	})
+
+	t.Run("as CSV", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token:             researcherToken,
+			StudyKey:          testStudyKey,
+			SurveyKey:         testSurveyKey,
+			Languages:         []string{"en"},
+			ShortQuestionKeys: true,
+			Separator:         "-",
+			Format:            api.CodebookExportQuery_CSV,
+		}
+		if err := s.GetSurveyCodebook(req, mock); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		records, err := csv.NewReader(bytes.NewReader(mock.content())).ReadAll()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if len(records) < 2 || records[0][0] != "variable" {
+			t.Errorf("unexpected codebook: %v", records)
+		}
+	})
+
+	t.Run("as DDI XML", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.CodebookExportQuery{
+			Token:             researcherToken,
+			StudyKey:          testStudyKey,
+			SurveyKey:         testSurveyKey,
+			Languages:         []string{"en"},
+			ShortQuestionKeys: true,
+			Separator:         "-",
+			Format:            api.CodebookExportQuery_DDI_XML,
+		}
+		if err := s.GetSurveyCodebook(req, mock); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if !strings.Contains(string(mock.content()), `<qstnLit xml:lang="en">Do you have fever?</qstnLit>`) {
+			t.Errorf("unexpected codebook: %s", mock.content())
+		}
+	})
+}
+
+func TestGetResponsesSyntaxFileEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_getresponsessyntaxfile"
+	testSurveyKey := "syntaxsurvey"
+	testUser := "testuser"
+	testStudy := types.Study{
+		Key: testStudyKey,
+		Members: []types.StudyMember{
+			{
+				UserID: testUser,
+				Role:   "maintainer",
+			},
+		},
+	}
+
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	_, err = testStudyDBService.SaveSurvey(testInstanceID, testStudyKey, testExportSurvey(testSurveyKey, `Do you have "fever"?`))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	err = addTestSurveyResponses(s.studyDBservice, testInstanceID, testStudyKey, []types.SurveyResponse{
+		{Key: testSurveyKey, ParticipantID: "p1", SubmittedAt: 10, Context: map[string]string{"engineVersion": "1.0"}},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	query := &api.ResponseExportQuery{
+		Token: &api_types.TokenInfos{
+			Id:         testUser,
+			InstanceId: testInstanceID,
+			Payload: map[string]string{
+				"roles": "PARTICIPANT,RESEARCHER",
+			},
+		},
+		StudyKey:          testStudyKey,
+		SurveyKey:         testSurveyKey,
+		ShortQuestionKeys: true,
+		Separator:         "-",
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		err := s.GetResponsesSyntaxFile(nil, nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without language", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		err := s.GetResponsesSyntaxFile(&api.SyntaxExportQuery{Query: query}, mock)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without researcher role", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.SyntaxExportQuery{
+			Query: &api.ResponseExportQuery{
+				Token: &api_types.TokenInfos{
+					Id:         testUser,
+					InstanceId: testInstanceID,
+					Payload: map[string]string{
+						"roles": "PARTICIPANT",
+					},
+				},
+				StudyKey:  testStudyKey,
+				SurveyKey: testSurveyKey,
+			},
+			Language: "en",
+		}
+		err := s.GetResponsesSyntaxFile(req, mock)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "unexpected roles PARTICIPANT")
+		if !ok {
+			t.Error(msg)
+		}
+		if len(mock.Results) > 0 {
+			t.Error("should not send the syntax file")
+		}
+	})
+
+	t.Run("for Stata", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.SyntaxExportQuery{
+			Query:    query,
+			Language: "en",
+		}
+		if err := s.GetResponsesSyntaxFile(req, mock); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		output := string(mock.content())
+		for _, expected := range []string{
+			"    engineVersion ///\n",
+			"label define Q1 1 `\"Yes\"' 0 `\"No\"'\n",
+			"label variable Q1 `\"Do you have \"fever\"?\"'\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("missing line: %s", expected)
+			}
+		}
+	})
+
+	t.Run("for SPSS", func(t *testing.T) {
+		mock := &studyServiceAPI_ChunkStream{}
+		req := &api.SyntaxExportQuery{
+			Query:    query,
+			Language: "en",
+			Format:   api.SyntaxExportQuery_SPSS,
+		}
+		if err := s.GetResponsesSyntaxFile(req, mock); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		output := string(mock.content())
+		for _, expected := range []string{
+			"    engineVersion A",
+			"VALUE LABELS Q1\n  1 'Yes'\n  0 'No'.\n",
+			"  /Q1 'Do you have \"fever\"?'\n",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("missing line: %s", expected)
+			}
+		}
+	})
+}
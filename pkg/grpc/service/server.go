@@ -76,6 +76,7 @@ func RunServer(ctx context.Context, port string,
 	maxMsgSize int,
 	persistenStorageConfig types.PersistentStoreConfig,
 	studyEngineExternalServices []types.ExternalService,
+	onShutdown func(),
 ) error {
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
@@ -99,17 +100,28 @@ func RunServer(ctx context.Context, port string,
 	// graceful shutdown
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
+	shutdownDone := make(chan struct{})
 	go func() {
-		for range c {
-			// sig is a ^C, handle it
-			logger.Info.Println("shutting down gRPC server...")
-			server.GracefulStop()
-			<-ctx.Done()
+		<-c
+		// sig is a ^C, handle it
+		logger.Info.Println("shutting down gRPC server...")
+		server.GracefulStop()
+		// stop background tasks (e.g. study timer) after pending requests are finished
+		if onShutdown != nil {
+			onShutdown()
 		}
+		close(shutdownDone)
 	}()
 
 	// start gRPC server
 	logger.Info.Println("starting gRPC server...")
 	logger.Info.Println("wait connections on port " + port)
-	return server.Serve(lis)
+	if err := server.Serve(lis); err != nil {
+		return err
+	}
+	select {
+	case <-shutdownDone:
+	case <-ctx.Done():
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
@@ -31,6 +32,21 @@ func (s *studyServiceServer) CreateNewStudy(ctx context.Context, req *api.NewStu
 	}
 
 	study := types.StudyFromAPI(req.Study)
+	if study.Configs.TimerSchedule != nil {
+		if err := study.Configs.TimerSchedule.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid timer schedule: "+err.Error())
+		}
+	}
+	for i, rs := range study.Configs.RandomisationSchemes {
+		if rs.Seed != "" {
+			continue
+		}
+		seed, err := types.NewRandomisationSeed()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		study.Configs.RandomisationSchemes[i].Seed = seed
+	}
 	study.Members = []types.StudyMember{
 		{
 			Role:     types.STUDY_ROLE_OWNER,
@@ -50,6 +66,10 @@ func (s *studyServiceServer) CreateNewStudy(ctx context.Context, req *api.NewStu
 	if err != nil {
 		logger.Error.Printf("unexpected error when creating survey definition indexes: %v", err)
 	}
+	err = s.studyDBservice.CreateParticipantIDIndex(req.Token.InstanceId, study.Key)
+	if err != nil {
+		logger.Error.Printf("unexpected error when creating participant ID indexes: %v", err)
+	}
 
 	return cStudy.ToAPI(), nil
 }
@@ -136,6 +156,12 @@ func (s *studyServiceServer) SaveSurveyToStudy(ctx context.Context, req *api.Add
 	}
 
 	newSurvey := types.SurveyFromAPI(req.Survey)
+	validationErrs := studyengine.ValidateSurveyPrefillRules(newSurvey.PrefillRules)
+	validationErrs = append(validationErrs, studyengine.ValidateSurveyContextRules(newSurvey.ContextRules)...)
+	if len(validationErrs) > 0 {
+		return nil, rulesValidationError(validationErrs)
+	}
+
 	if newSurvey.VersionID == "" {
 		surveyHistory, err := s.studyDBservice.FindSurveyDefHistory(req.Token.InstanceId, req.StudyKey, req.Survey.SurveyDefinition.Key, true)
 		if err != nil {
@@ -521,24 +547,44 @@ func (s *studyServiceServer) SaveStudyRules(ctx context.Context, req *api.StudyR
 	for _, exp := range req.Rules {
 		rules = append(rules, *types.ExpressionFromAPI(exp))
 	}
-	study.Rules = rules
-	uStudy, err := s.studyDBservice.UpdateStudyInfo(req.Token.InstanceId, study)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if validationErrs := studyengine.ValidateStudyRules(rules); len(validationErrs) > 0 {
+		return nil, rulesValidationError(validationErrs)
+	}
+
+	now := time.Now().Unix()
+	if req.ActiveFrom > 0 && req.ActiveFrom < now {
+		return nil, status.Error(codes.InvalidArgument, "activeFrom must not be in the past")
+	}
+	scheduled := req.ActiveFrom > now
+
+	uStudy := study
+	if !scheduled {
+		// study info keeps the rules as fallback, scheduled versions are only stored in the rules history
+		study.Rules = rules
+		uStudy, err = s.studyDBservice.UpdateStudyInfo(req.Token.InstanceId, study)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
 
 	studyRules := types.StudyRules{
 		StudyKey:   req.StudyKey,
-		UploadedAt: time.Now().Unix(),
+		UploadedAt: now,
 		UploadedBy: req.Token.ProfilId,
+		ActiveFrom: req.ActiveFrom,
 		Rules:      rules,
 	}
 	_, err = s.studyDBservice.AddStudyRules(req.Token.InstanceId, studyRules)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	s.updateParticipantCounts(req.Token.InstanceId, req.StudyKey, rules)
 
-	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_STUDY_UPDATE, fmt.Sprintf("rules updated for %s", req.StudyKey))
+	if scheduled {
+		s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_STUDY_UPDATE, fmt.Sprintf("rules scheduled for %s, active from %d", req.StudyKey, req.ActiveFrom))
+	} else {
+		s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_STUDY_UPDATE, fmt.Sprintf("rules updated for %s", req.StudyKey))
+	}
 	return uStudy.ToAPI(), nil
 }
 
@@ -599,6 +645,105 @@ func (s *studyServiceServer) SaveStudyProps(ctx context.Context, req *api.StudyP
 	return uStudy.ToAPI(), nil
 }
 
+func (s *studyServiceServer) SaveStudyTimerSchedule(ctx context.Context, req *api.StudyTimerScheduleReq) (*api.Study, error) {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_STUDY_UPDATE, "permission denied for updating timer schedule for "+req.StudyKey)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	schedule := types.TimerScheduleFromAPI(req.TimerSchedule)
+	if schedule != nil {
+		if err := schedule.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid timer schedule: "+err.Error())
+		}
+	}
+
+	study, err := s.studyDBservice.GetStudyByStudyKey(req.Token.InstanceId, req.StudyKey)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	study.Configs.TimerSchedule = schedule
+	// next run is computed by the timer service at its next check within the new schedule's window
+	study.NextTimerEvent = 0
+
+	uStudy, err := s.studyDBservice.UpdateStudyInfo(req.Token.InstanceId, study)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_STUDY_UPDATE, "timer schedule for "+req.StudyKey)
+	return uStudy.ToAPI(), nil
+}
+
+func (s *studyServiceServer) SaveRandomisationScheme(ctx context.Context, req *api.RandomisationSchemeReq) (*api.Study, error) {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.Scheme == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_STUDY_UPDATE, "permission denied for updating randomisation scheme for "+req.StudyKey)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	scheme := types.RandomisationSchemeFromAPI(req.Scheme)
+	if err := scheme.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid randomisation scheme: "+err.Error())
+	}
+
+	if err := s.studyDBservice.CreateRandomisationIndexes(req.Token.InstanceId, req.StudyKey); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	uStudy, err := s.studyDBservice.SaveRandomisationScheme(req.Token.InstanceId, req.StudyKey, scheme)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_STUDY_UPDATE, "randomisation scheme "+scheme.Key+" for "+req.StudyKey)
+	return uStudy.ToAPI(), nil
+}
+
+func (s *studyServiceServer) GetRandomisationAllocationCounts(ctx context.Context, req *api.RandomisationAllocationCountsQuery) (*api.RandomisationAllocationCounts, error) {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.SchemeKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_STUDY_UPDATE, "permission denied for fetching randomisation allocations for "+req.StudyKey)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	counts, err := s.studyDBservice.CountRandomisationAllocations(req.Token.InstanceId, req.StudyKey, req.SchemeKey)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &api.RandomisationAllocationCounts{
+		Counts: make([]*api.RandomisationAllocationCounts_Count, len(counts)),
+	}
+	for i, c := range counts {
+		resp.Counts[i] = c.ToAPI()
+	}
+	return resp, nil
+}
+
 func (s *studyServiceServer) GetResearcherNotificationSubscriptions(ctx context.Context, req *api.GetResearcherNotificationSubscriptionsReq) (*api.NotificationSubscriptions, error) {
 	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
 		return nil, status.Error(codes.InvalidArgument, "missing argument")
@@ -689,96 +834,64 @@ func (s *studyServiceServer) RunRules(ctx context.Context, req *api.StudyRulesRe
 		}
 	}
 
-	start := time.Now().Unix()
-
-	type Counters struct {
-		Participants                  int32
-		ParticipantStateChangePerRule []int32
-	}
-	counters := &Counters{
-		Participants:                  0,
-		ParticipantStateChangePerRule: make([]int32, len(req.Rules)),
-	}
-
 	// Convert rules from API type:
 	rules := make([]*types.Expression, len(req.Rules))
 	for index, rule := range req.Rules {
 		rules[index] = types.ExpressionFromAPI(rule)
 	}
+	if validationErrs := validateRuleList(rules); len(validationErrs) > 0 {
+		return nil, rulesValidationError(validationErrs)
+	}
 
-	err := s.studyDBservice.FindAndExecuteOnParticipantsStates(
-		ctx,
-		req.Token.InstanceId,
-		req.StudyKey,
-		"",
-		func(dbService *studydb.StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
-			if p.StudyStatus == types.PARTICIPANT_STUDY_STATUS_TEMPORARY {
-				// ignore temporary participants
-				return nil
-			}
+	summary, err := s.runCustomRules(ctx, req.Token.InstanceId, req.StudyKey, req.Token.Id, rules, false, nil)
+	if err != nil {
+		logger.Error.Println(err)
+	}
 
-			counters.Participants += 1
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("rules run for study %s: %v", req.StudyKey, req.Rules))
+	return summary, nil
+}
 
-			participantID2, _, err := s.profileIDToParticipantID(instanceID, studyKey, p.ParticipantID, true)
-			if err != nil {
-				logger.Error.Printf("RunRules: %v", err)
-				return status.Error(codes.Internal, err.Error())
-			}
+func (s *studyServiceServer) RunRulesDryRun(req *api.StudyRulesReq, stream api.StudyServiceApi_RunRulesDryRunServer) error {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
+		return status.Error(codes.InvalidArgument, "missing argument")
+	}
 
-			actionData := studyengine.ActionData{
-				PState:          p,
-				ReportsToCreate: map[string]types.Report{},
-			}
-			anyChange := false
-			for index, rule := range rules {
-				if rule == nil {
-					continue
-				}
-
-				event := types.StudyEvent{
-					InstanceID:                            instanceID,
-					StudyKey:                              studyKey,
-					ParticipantIDForConfidentialResponses: participantID2,
-				}
-				newState, err := studyengine.ActionEval(*rule, actionData, event, studyengine.ActionConfigs{
-					DBService:              s.studyDBservice,
-					ExternalServiceConfigs: s.studyEngineExternalServices,
-				})
-				if err != nil {
-					return err
-				}
-
-				if !reflect.DeepEqual(newState.PState, actionData.PState) {
-					counters.ParticipantStateChangePerRule[index] += 1
-					anyChange = true
-				}
-				actionData = newState
-			}
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("permission denied for dry run of custom rules in study %s  ", req.StudyKey))
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
 
-			if anyChange {
-				// save state back to DB
-				_, err := s.studyDBservice.SaveParticipantState(instanceID, studyKey, actionData.PState)
-				if err != nil {
-					logger.Error.Printf("RunRules: %v", err)
-					return status.Error(codes.Internal, err.Error())
-				}
+	// Convert rules from API type:
+	rules := make([]*types.Expression, len(req.Rules))
+	for index, rule := range req.Rules {
+		rules[index] = types.ExpressionFromAPI(rule)
+	}
+	if validationErrs := validateRuleList(rules); len(validationErrs) > 0 {
+		return rulesValidationError(validationErrs)
+	}
 
-			}
-			s.saveReports(instanceID, req.StudyKey, actionData.ReportsToCreate, "")
+	summary, err := s.runCustomRules(stream.Context(), req.Token.InstanceId, req.StudyKey, req.Token.Id, rules, true, func(run customRulesRun) error {
+		if types.DiffParticipantStates(run.OldState, run.ActionData.PState).IsEmpty() && len(run.ActionData.ReportsToCreate) == 0 {
 			return nil
-		},
-	)
+		}
+		return stream.Send(&api.RuleDryRunResult{
+			Result: &api.RuleDryRunResult_ParticipantDiff{ParticipantDiff: dryRunChangesToAPI(run.OldState, run.ActionData)},
+		})
+	})
 	if err != nil {
 		logger.Error.Println(err)
+		return status.Error(codes.Internal, err.Error())
 	}
 
-	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("rules run for study %s: %v", req.StudyKey, req.Rules))
-	resp := api.RuleRunSummary{
-		ParticipantCount:              counters.Participants,
-		ParticipantStateChangePerRule: counters.ParticipantStateChangePerRule,
-		Duration:                      time.Now().Unix() - start,
-	}
-	return &resp, nil
+	return stream.Send(&api.RuleDryRunResult{
+		Result: &api.RuleDryRunResult_Summary{Summary: summary},
+	})
 }
 
 func (s *studyServiceServer) RunRulesForSingleParticipant(ctx context.Context, req *api.RunRulesForSingleParticipantReq) (*api.RuleRunSummary, error) {
@@ -798,20 +911,14 @@ func (s *studyServiceServer) RunRulesForSingleParticipant(ctx context.Context, r
 
 	start := time.Now().Unix()
 
-	type Counters struct {
-		Participants                  int32
-		ParticipantStateChangePerRule []int32
-	}
-	counters := &Counters{
-		Participants:                  0,
-		ParticipantStateChangePerRule: make([]int32, len(req.Rules)),
-	}
-
 	// Convert rules from API type:
 	rules := make([]*types.Expression, len(req.Rules))
 	for index, rule := range req.Rules {
 		rules[index] = types.ExpressionFromAPI(rule)
 	}
+	if validationErrs := validateRuleList(rules); len(validationErrs) > 0 {
+		return nil, rulesValidationError(validationErrs)
+	}
 
 	p, err := s.studyDBservice.FindParticipantState(req.Token.InstanceId, req.StudyKey, req.ParticipantId)
 	if err != nil {
@@ -819,72 +926,356 @@ func (s *studyServiceServer) RunRulesForSingleParticipant(ctx context.Context, r
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	resp := api.RuleRunSummary{
+		ParticipantStateChangePerRule: make([]int32, len(rules)),
+	}
 	if p.StudyStatus == types.PARTICIPANT_STUDY_STATUS_TEMPORARY {
 		// ignore temporary participants
-		resp := api.RuleRunSummary{
-			ParticipantCount:              counters.Participants,
-			ParticipantStateChangePerRule: counters.ParticipantStateChangePerRule,
-			Duration:                      time.Now().Unix() - start,
-		}
+		resp.Duration = time.Now().Unix() - start
 		return &resp, nil
 	}
 
-	participantID2, _, err := s.profileIDToParticipantID(req.Token.InstanceId, req.StudyKey, p.ParticipantID, true)
+	run, err := s.runCustomRulesForParticipant(req.Token.InstanceId, req.StudyKey, req.Token.Id, p, rules, false, req.WithTrace)
 	if err != nil {
 		logger.Debug.Printf("unexpected error: %v", err)
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	counters.Participants += 1
-	actionData := studyengine.ActionData{
-		PState:          p,
-		ReportsToCreate: map[string]types.Report{},
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("rules run for study %s: %v", req.StudyKey, req.Rules))
+	resp.ParticipantCount = 1
+	resp.ParticipantStateChangePerRule = run.ChangesPerRule
+	resp.Duration = time.Now().Unix() - start
+	if req.WithTrace {
+		resp.Trace = run.Trace
 	}
-	anyChange := false
-	for index, rule := range rules {
-		if rule == nil {
+	return &resp, nil
+}
+
+// SubmitCustomEvent runs the study rules for each participant with an event of type CUSTOM:<eventKey> and the payload of the request
+func (s *studyServiceServer) SubmitCustomEvent(ctx context.Context, req *api.CustomEventReq) (*api.CustomEventResult, error) {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.EventKey == "" || len(req.ParticipantIds) < 1 {
+		return nil, status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckIfAnyRolesInToken(req.Token, []string{constants.USER_ROLE_ADMIN, constants.USER_ROLE_SERVICE_ACCOUNT}) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_PARTICIPANT_ACTION, fmt.Sprintf("permission denied for submitting custom event %s in study %s", req.EventKey, req.StudyKey))
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	payload := make(map[string]types.FlagValue, len(req.Payload))
+	for k, v := range req.Payload {
+		payload[k] = types.FlagValueFromAPI(v)
+	}
+
+	resp := &api.CustomEventResult{
+		Errors: []*api.CustomEventResult_ParticipantError{},
+	}
+	addError := func(participantID string, err error) {
+		resp.Errors = append(resp.Errors, &api.CustomEventResult_ParticipantError{ParticipantId: participantID, Error: err.Error()})
+	}
+	for _, participantID := range req.ParticipantIds {
+		pState, err := s.studyDBservice.FindParticipantState(req.Token.InstanceId, req.StudyKey, participantID)
+		if err != nil {
+			addError(participantID, err)
+			continue
+		}
+		if pState.StudyStatus != types.PARTICIPANT_STUDY_STATUS_ACTIVE {
+			addError(participantID, errors.New("participant is not active in the study"))
+			continue
+		}
+		participantID2, _, err := s.profileIDToParticipantID(req.Token.InstanceId, req.StudyKey, pState.ParticipantID, true)
+		if err != nil {
+			addError(participantID, err)
 			continue
 		}
 
 		event := types.StudyEvent{
+			Type:                                  types.CUSTOM_EVENT_PREFIX + req.EventKey,
 			InstanceID:                            req.Token.InstanceId,
 			StudyKey:                              req.StudyKey,
 			ParticipantIDForConfidentialResponses: participantID2,
+			Payload:                               payload,
 		}
-		newState, err := studyengine.ActionEval(*rule, actionData, event, studyengine.ActionConfigs{
-			DBService:              s.studyDBservice,
-			ExternalServiceConfigs: s.studyEngineExternalServices,
-		})
+		oldState, actionResult, rulesVersionID, err := s.performStudyRulesAndSaveState(req.Token.InstanceId, req.StudyKey, req.Token.Id, pState, event)
 		if err != nil {
-			logger.Debug.Printf("unexpected error: %v", err)
+			addError(participantID, err)
+			continue
+		}
+		s.saveParticipantEvent(req.Token.InstanceId, req.StudyKey, event, rulesVersionID, oldState, actionResult.PState)
+		s.saveReports(req.Token.InstanceId, req.StudyKey, actionResult.ReportsToCreate, "")
+		resp.ParticipantCount += 1
+	}
+
+	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_PARTICIPANT_ACTION, fmt.Sprintf("custom event %s submitted in study %s for %d participants", req.EventKey, req.StudyKey, resp.ParticipantCount))
+	return resp, nil
+}
+
+// EvaluateRulesInSandbox evaluates study rules for a given or stored participant state with a synthetic event, without persisting anything
+func (s *studyServiceServer) EvaluateRulesInSandbox(ctx context.Context, req *api.SandboxEvaluationReq) (*api.SandboxEvaluationResult, error) {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.Event == nil || req.Event.Type == "" ||
+		(req.ParticipantId == "" && req.ParticipantState == nil) {
+		return nil, status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("permission denied for sandbox evaluation of rules in study %s  ", req.StudyKey))
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+	}
 
-		if !reflect.DeepEqual(newState.PState, actionData.PState) {
-			counters.ParticipantStateChangePerRule[index] += 1
-			anyChange = true
+	var rules []types.Expression
+	rulesVersionID := ""
+	if len(req.Rules) > 0 {
+		ruleRefs := make([]*types.Expression, len(req.Rules))
+		for index, rule := range req.Rules {
+			ruleRefs[index] = types.ExpressionFromAPI(rule)
+		}
+		if validationErrs := validateRuleList(ruleRefs); len(validationErrs) > 0 {
+			return nil, rulesValidationError(validationErrs)
+		}
+		for _, rule := range ruleRefs {
+			if rule != nil {
+				rules = append(rules, *rule)
+			}
+		}
+	} else if req.RulesVersionId != "" {
+		studyRules, err := s.studyDBservice.GetStudyRulesVersion(req.Token.InstanceId, req.StudyKey, req.RulesVersionId)
+		if err != nil {
+			logger.Debug.Printf("study rules version not found: %v", err)
+			return nil, status.Error(codes.NotFound, "study rules version not found")
+		}
+		if err := studyRules.UnmarshalRules(); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		rules = studyRules.Rules
+		rulesVersionID = req.RulesVersionId
+	} else {
+		// rules version active at the simulated time, e.g. to test a scheduled version
+		activeAt := time.Now().Unix()
+		if req.Now > 0 {
+			activeAt = req.Now
+		}
+		var err error
+		rules, rulesVersionID, err = s.studyDBservice.GetStudyRulesActiveAtWithVersionID(req.Token.InstanceId, req.StudyKey, activeAt)
+		if err != nil {
+			logger.Debug.Printf("study rules not found: %v", err)
+			return nil, status.Error(codes.Internal, err.Error())
 		}
-		actionData = newState
 	}
 
-	if anyChange {
-		// save state back to DB
-		_, err := s.studyDBservice.SaveParticipantState(req.Token.InstanceId, req.StudyKey, actionData.PState)
+	var pState types.ParticipantState
+	if req.ParticipantState != nil {
+		pState = types.ParticipantStateFromAPI(req.ParticipantState)
+	} else {
+		var err error
+		pState, err = s.studyDBservice.FindParticipantState(req.Token.InstanceId, req.StudyKey, req.ParticipantId)
+		if err != nil {
+			logger.Debug.Printf("participant not found: %v", err)
+			return nil, status.Error(codes.NotFound, "participant not found")
+		}
+	}
+
+	event := types.StudyEvent{
+		Type:       req.Event.Type,
+		InstanceID: req.Token.InstanceId,
+		StudyKey:   req.StudyKey,
+	}
+	if req.Event.Response != nil {
+		event.Response = types.SurveyResponseFromAPI(req.Event.Response)
+		event.Response.ParticipantID = pState.ParticipantID
+	}
+	if pState.ParticipantID != "" {
+		participantID2, _, err := s.profileIDToParticipantID(req.Token.InstanceId, req.StudyKey, pState.ParticipantID, true)
 		if err != nil {
 			logger.Debug.Printf("unexpected error: %v", err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+		event.ParticipantIDForConfidentialResponses = participantID2
+	}
 
+	configs := studyengine.ActionConfigs{
+		DBService:              s.studyDBservice,
+		ExternalServiceConfigs: s.studyEngineExternalServices,
+		DryRun:                 true,
+	}
+	if req.Now > 0 {
+		simulatedNow := time.Unix(req.Now, 0)
+		configs.Now = func() time.Time { return simulatedNow }
 	}
-	s.saveReports(req.Token.InstanceId, req.StudyKey, actionData.ReportsToCreate, "")
 
-	s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_LOG, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("rules run for study %s: %v", req.StudyKey, req.Rules))
-	resp := api.RuleRunSummary{
-		ParticipantCount:              counters.Participants,
-		ParticipantStateChangePerRule: counters.ParticipantStateChangePerRule,
-		Duration:                      time.Now().Unix() - start,
+	resp := &api.SandboxEvaluationResult{RulesVersionId: rulesVersionID}
+	actionData := studyengine.ActionData{
+		PState:          pState,
+		ReportsToCreate: map[string]types.Report{},
 	}
-	return &resp, nil
+	for index, rule := range rules {
+		if req.WithTrace {
+			configs.Tracer = studyengine.NewTracer()
+		}
+		newState, err := studyengine.ActionEval(rule, actionData, event, configs)
+		if req.WithTrace {
+			resp.Trace = append(resp.Trace, traceToAPI(index, configs.Tracer)...)
+		}
+		if err != nil {
+			// errors are reported per rule, the evaluation continues with the next rule (as for timer events)
+			resp.Errors = append(resp.Errors, &api.SandboxEvaluationResult_RuleError{
+				RuleIndex: int32(index),
+				Error:     err.Error(),
+			})
+			continue
+		}
+		actionData = newState
+	}
+
+	resp.ParticipantState = actionData.PState.ToAPI()
+	resp.StateChanges = types.DiffParticipantStates(pState, actionData.PState).ToAPI()
+	for _, report := range actionData.ReportsToCreate {
+		resp.ReportsToCreate = append(resp.ReportsToCreate, report.ToAPI())
+	}
+	for _, message := range actionData.ResearcherMessages {
+		resp.ResearcherMessages = append(resp.ResearcherMessages, message.ToAPI())
+	}
+	return resp, nil
+}
+
+// RunRulesVersionWhatIf re-runs the submissions of a time window with the given rules version and with the version
+// that was active at each submission, and streams the submissions for which the resulting state changes differ.
+// Both runs are dry runs starting from the participant's current state: the participant event history only records
+// changes of some attributes (with flag values as strings), so the state at the submission time cannot be rebuilt.
+// The summary describes this limitation.
+func (s *studyServiceServer) RunRulesVersionWhatIf(req *api.RulesWhatIfQuery, stream api.StudyServiceApi_RunRulesVersionWhatIfServer) error {
+	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" || req.RulesVersionId == "" {
+		return status.Error(codes.InvalidArgument, "missing argument")
+	}
+
+	if !token_checks.CheckRoleInToken(req.Token, constants.USER_ROLE_ADMIN) {
+		err := s.HasRoleInStudy(req.Token.InstanceId, req.StudyKey, req.Token.Id,
+			[]string{types.STUDY_ROLE_MAINTAINER, types.STUDY_ROLE_OWNER},
+		)
+		if err != nil {
+			s.SaveLogEvent(req.Token.InstanceId, req.Token.Id, loggingAPI.LogEventType_SECURITY, constants.LOG_EVENT_RUN_CUSTOM_RULES, fmt.Sprintf("permission denied for what-if run of rules in study %s  ", req.StudyKey))
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	start := time.Now().Unix()
+
+	whatIfRules, err := s.studyDBservice.GetStudyRulesVersion(req.Token.InstanceId, req.StudyKey, req.RulesVersionId)
+	if err != nil {
+		logger.Debug.Printf("study rules version not found: %v", err)
+		return status.Error(codes.NotFound, "study rules version not found")
+	}
+	if err := whatIfRules.UnmarshalRules(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	summary := &api.RulesWhatIfResult_Summary{
+		Limitations: rulesWhatIfLimitations,
+	}
+	participantIDs2 := map[string]string{}
+
+	handleResponse := func(instanceID string, studyKey string, response types.SurveyResponse, args ...interface{}) error {
+		summary.ResponseCount += 1
+
+		pState, err := s.studyDBservice.FindParticipantState(instanceID, studyKey, response.ParticipantID)
+		if err != nil {
+			summary.ErrorCount += 1
+			logger.Debug.Printf("RunRulesVersionWhatIf: participant %s not found: %v", response.ParticipantID, err)
+			return nil
+		}
+		participantID2, ok := participantIDs2[pState.ParticipantID]
+		if !ok {
+			participantID2, _, err = s.profileIDToParticipantID(instanceID, studyKey, pState.ParticipantID, true)
+			if err != nil {
+				return err
+			}
+			participantIDs2[pState.ParticipantID] = participantID2
+		}
+
+		originalRules, originalVersionID, err := s.studyDBservice.GetStudyRulesActiveAtWithVersionID(instanceID, studyKey, response.SubmittedAt)
+		if err != nil {
+			summary.ErrorCount += 1
+			logger.Debug.Printf("RunRulesVersionWhatIf: no rules active at %d: %v", response.SubmittedAt, err)
+			return nil
+		}
+
+		event := types.StudyEvent{
+			Type:                                  "SUBMIT",
+			Response:                              response,
+			InstanceID:                            instanceID,
+			StudyKey:                              studyKey,
+			ParticipantIDForConfidentialResponses: participantID2,
+		}
+		originalResult, err := s.performStudyRulesDryRun(originalRules, pState, event, response.SubmittedAt)
+		if err != nil {
+			summary.ErrorCount += 1
+			logger.Debug.Printf("RunRulesVersionWhatIf: error with rules version %s: %v", originalVersionID, err)
+			return nil
+		}
+		whatIfResult, err := s.performStudyRulesDryRun(whatIfRules.Rules, pState, event, response.SubmittedAt)
+		if err != nil {
+			summary.ErrorCount += 1
+			logger.Debug.Printf("RunRulesVersionWhatIf: error with rules version %s: %v", req.RulesVersionId, err)
+			return nil
+		}
+
+		if reflect.DeepEqual(originalResult.PState, whatIfResult.PState) && reflect.DeepEqual(originalResult.ReportsToCreate, whatIfResult.ReportsToCreate) {
+			return nil
+		}
+		summary.ChangedCount += 1
+
+		return stream.Send(&api.RulesWhatIfResult{
+			Result: &api.RulesWhatIfResult_ResponseResult_{ResponseResult: &api.RulesWhatIfResult_ResponseResult{
+				ParticipantId:          pState.ParticipantID,
+				ResponseId:             response.ID.Hex(),
+				SurveyKey:              response.Key,
+				SubmittedAt:            response.SubmittedAt,
+				OriginalRulesVersionId: originalVersionID,
+				OriginalChanges:        dryRunChangesToAPI(pState, originalResult),
+				WhatIfChanges:          dryRunChangesToAPI(pState, whatIfResult),
+			}},
+		})
+	}
+
+	if req.ParticipantId != "" {
+		responses, err := s.studyDBservice.FindSurveyResponses(req.Token.InstanceId, req.StudyKey, studydb.ResponseQuery{
+			ParticipantID: req.ParticipantId,
+			SurveyKey:     req.SurveyKey,
+			Since:         req.Since,
+			Until:         req.Until,
+		})
+		if err != nil {
+			logger.Error.Println(err)
+			return status.Error(codes.Internal, err.Error())
+		}
+		// responses are sorted by descending submission time
+		for i := len(responses) - 1; i >= 0; i-- {
+			if err := handleResponse(req.Token.InstanceId, req.StudyKey, responses[i]); err != nil {
+				logger.Error.Println(err)
+				return status.Error(codes.Internal, err.Error())
+			}
+		}
+	} else {
+		err = s.studyDBservice.PerformActionForSurveyResponses(stream.Context(), req.Token.InstanceId, req.StudyKey, req.SurveyKey, req.Since, req.Until, handleResponse)
+		if err != nil {
+			logger.Error.Println(err)
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	summary.Duration = time.Now().Unix() - start
+	return stream.Send(&api.RulesWhatIfResult{
+		Result: &api.RulesWhatIfResult_Summary_{Summary: summary},
+	})
 }
 
 func (s *studyServiceServer) DeleteStudy(ctx context.Context, req *api.StudyReferenceReq) (*api.ServiceStatus, error) {
@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/influenzanet/go-utils/pkg/api_types"
 	"github.com/influenzanet/study-service/pkg/api"
+	"github.com/influenzanet/study-service/pkg/dbs/studydb"
 	"github.com/influenzanet/study-service/pkg/types"
 	loggingMock "github.com/influenzanet/study-service/test/mocks/logging_service"
+	"google.golang.org/grpc"
 )
 
 func TestCreateNewStudyEndpoint(t *testing.T) {
@@ -1028,6 +1031,27 @@ func TestSaveStudyRulesEndpoint(t *testing.T) {
 		}
 	})
 
+	t.Run("with invalid rules", func(t *testing.T) {
+		_, err := s.SaveStudyRules(context.Background(), &api.StudyRulesReq{
+			Token: &api_types.TokenInfos{
+				Id:         testUserID,
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles":    "PARTICIPANT,RESEARCHER,ADMIN",
+					"username": "testuser",
+				},
+			},
+			StudyKey: testStudyKey,
+			Rules: []*api.Expression{
+				{Name: "test"},
+			},
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "invalid rules: rules[0] (test): action name not known")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
 	t.Run("with study member", func(t *testing.T) {
 		mockLoggingClient.EXPECT().SaveLogEvent(
 			gomock.Any(),
@@ -1044,7 +1068,7 @@ func TestSaveStudyRulesEndpoint(t *testing.T) {
 			},
 			StudyKey: testStudyKey,
 			Rules: []*api.Expression{
-				{Name: "test"},
+				{Name: "START_NEW_STUDY_SESSION"},
 			},
 		})
 		if err != nil {
@@ -1055,6 +1079,72 @@ func TestSaveStudyRulesEndpoint(t *testing.T) {
 			t.Error("unexpected number of rules")
 		}
 	})
+
+	t.Run("with activeFrom in the past", func(t *testing.T) {
+		_, err := s.SaveStudyRules(context.Background(), &api.StudyRulesReq{
+			Token: &api_types.TokenInfos{
+				Id:         testUserID,
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles":    "PARTICIPANT,RESEARCHER,ADMIN",
+					"username": "testuser",
+				},
+			},
+			StudyKey: testStudyKey,
+			Rules: []*api.Expression{
+				{Name: "START_NEW_STUDY_SESSION"},
+			},
+			ActiveFrom: 100,
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "activeFrom must not be in the past")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with scheduled rules", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		activeFrom := time.Now().Unix() + 3600
+		_, err := s.SaveStudyRules(context.Background(), &api.StudyRulesReq{
+			Token: &api_types.TokenInfos{
+				Id:         testUserID,
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles":    "PARTICIPANT,RESEARCHER,ADMIN",
+					"username": "testuser",
+				},
+			},
+			StudyKey: testStudyKey,
+			Rules: []*api.Expression{
+				{Name: "START_NEW_STUDY_SESSION"},
+				{Name: "START_NEW_STUDY_SESSION"},
+			},
+			ActiveFrom: activeFrom,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		current, err := testStudyDBService.GetCurrentStudyRules(testInstanceID, testStudyKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if current.UnmarshalRules() != nil || len(current.Rules) != 1 {
+			t.Errorf("scheduled rules should not be active yet: %v", current)
+		}
+		scheduled, err := testStudyDBService.GetStudyRulesActiveAt(testInstanceID, testStudyKey, activeFrom)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if scheduled.UnmarshalRules() != nil || len(scheduled.Rules) != 2 {
+			t.Errorf("unexpected rules at activeFrom: %v", scheduled)
+		}
+	})
 }
 
 func TestSaveStudyStatusEndpoint(t *testing.T) {
@@ -1372,3 +1462,847 @@ func TestDeleteStudyEndpoint(t *testing.T) {
 		}
 	})
 }
+
+func TestSaveStudyTimerScheduleEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_savetimerschedule"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key: testStudyKey,
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status:         types.STUDY_STATUS_ACTIVE,
+		NextTimerEvent: 1000,
+	}
+
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		_, err := s.SaveStudyTimerSchedule(context.Background(), nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with invalid schedule", func(t *testing.T) {
+		_, err := s.SaveStudyTimerSchedule(context.Background(), &api.StudyTimerScheduleReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			TimerSchedule: &api.Study_TimerSchedule{
+				Interval:    3600,
+				WindowStart: "08:00",
+			},
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "invalid timer schedule: window start and end must be both defined or both empty")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with study member", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		resp, err := s.SaveStudyTimerSchedule(context.Background(), &api.StudyTimerScheduleReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			TimerSchedule: &api.Study_TimerSchedule{
+				Interval:    3600,
+				WindowStart: "08:00",
+				WindowEnd:   "20:00",
+				Timezone:    "Europe/Paris",
+			},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if resp.Configs.TimerSchedule == nil || resp.Configs.TimerSchedule.Interval != 3600 {
+			t.Errorf("unexpected configs: %v", resp.Configs)
+		}
+		if resp.NextTimerEvent != 0 {
+			t.Errorf("next timer event should be reset: %d", resp.NextTimerEvent)
+		}
+	})
+}
+
+func TestRandomisationEndpoints(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_randomisation"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key: testStudyKey,
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status: types.STUDY_STATUS_ACTIVE,
+	}
+
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+	scheme := &api.Study_RandomisationScheme{
+		Key: "trial",
+		Arms: []*api.Study_RandomisationScheme_Arm{
+			{Key: "control", Ratio: 1},
+			{Key: "intervention", Ratio: 1},
+		},
+		BlockSize: 4,
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		_, err := s.SaveRandomisationScheme(context.Background(), nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with invalid scheme", func(t *testing.T) {
+		_, err := s.SaveRandomisationScheme(context.Background(), &api.RandomisationSchemeReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			Scheme:   &api.Study_RandomisationScheme{Key: "trial", Arms: scheme.Arms, BlockSize: 3},
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "invalid randomisation scheme: block size must be a multiple of the sum of the ratios (2)")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("save scheme and keep seed", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil).Times(2)
+		resp, err := s.SaveRandomisationScheme(context.Background(), &api.RandomisationSchemeReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			Scheme:   scheme,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(resp.Configs.RandomisationSchemes) != 1 || resp.Configs.RandomisationSchemes[0].Seed != "" {
+			t.Errorf("unexpected configs: %v", resp.Configs)
+			return
+		}
+		saved, err := testStudyDBService.GetRandomisationScheme(testInstanceID, testStudyKey, "trial")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if saved.Seed == "" {
+			t.Error("seed should be generated")
+			return
+		}
+
+		resp, err = s.SaveRandomisationScheme(context.Background(), &api.RandomisationSchemeReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			Scheme:   &api.Study_RandomisationScheme{Key: "trial", Arms: scheme.Arms, BlockSize: 2},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(resp.Configs.RandomisationSchemes) != 1 || resp.Configs.RandomisationSchemes[0].BlockSize != 2 {
+			t.Errorf("unexpected configs: %v", resp.Configs)
+		}
+		updated, err := testStudyDBService.GetRandomisationScheme(testInstanceID, testStudyKey, "trial")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if updated.Seed != saved.Seed {
+			t.Error("seed should be kept")
+		}
+	})
+
+	t.Run("allocation counts", func(t *testing.T) {
+		rs, err := testStudyDBService.GetRandomisationScheme(testInstanceID, testStudyKey, "trial")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		for _, pID := range []string{"p1", "p2", "p3", "p4"} {
+			if _, err := testStudyDBService.AllocateRandomisationArm(testInstanceID, testStudyKey, rs, "", pID, 10); err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+		resp, err := s.GetRandomisationAllocationCounts(context.Background(), &api.RandomisationAllocationCountsQuery{
+			Token:     token,
+			StudyKey:  testStudyKey,
+			SchemeKey: "trial",
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(resp.Counts) != 2 || resp.Counts[0].Count != 2 || resp.Counts[1].Count != 2 {
+			t.Errorf("unexpected counts: %v", resp.Counts)
+		}
+	})
+}
+
+func TestValidateRuleList(t *testing.T) {
+	t.Run("empty rules are skipped", func(t *testing.T) {
+		errs := validateRuleList([]*types.Expression{nil, {Name: "START_NEW_STUDY_SESSION"}})
+		if len(errs) > 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("paths use the index in the request", func(t *testing.T) {
+		errs := validateRuleList([]*types.Expression{nil, {Name: "wrong"}})
+		if len(errs) != 1 || errs[0].Path != "rules[1]" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+}
+
+func TestRunRulesForSingleParticipantEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_customrules"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key:       testStudyKey,
+		SecretKey: "testsecret",
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status: types.STUDY_STATUS_ACTIVE,
+	}
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if _, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, types.ParticipantState{
+		ParticipantID: "p1",
+		StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+	}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+	rules := []*api.Expression{
+		{Name: "START_NEW_STUDY_SESSION"},
+	}
+
+	t.Run("event history of the run", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		resp, err := s.RunRulesForSingleParticipant(context.Background(), &api.RunRulesForSingleParticipantReq{
+			Token:         token,
+			StudyKey:      testStudyKey,
+			ParticipantId: "p1",
+			Rules:         rules,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if resp.ParticipantStateChangePerRule[0] != 1 {
+			t.Errorf("unexpected result: %v", resp)
+		}
+
+		events := []types.ParticipantEvent{}
+		err = testStudyDBService.PerformActionForParticipantEvents(context.Background(), testInstanceID, testStudyKey, studydb.ParticipantEventQuery{ParticipantID: "p1"},
+			func(instanceID string, studyKey string, event types.ParticipantEvent, args ...interface{}) error {
+				events = append(events, event)
+				return nil
+			},
+		)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if len(events) != 1 {
+			t.Errorf("unexpected events: %v", events)
+			return
+		}
+		expectedVersionID := customRulesVersionID([]*types.Expression{types.ExpressionFromAPI(rules[0])})
+		if events[0].EventType != types.PARTICIPANT_EVENT_TYPE_CUSTOM_RULES || events[0].EventKey != testUserID || events[0].RulesVersionID != expectedVersionID {
+			t.Errorf("unexpected event: %v", events[0])
+		}
+		if expectedVersionID == customRulesVersionID([]*types.Expression{{Name: "REMOVE_ALL_MESSAGES"}}) {
+			t.Errorf("different rules should have different version IDs")
+		}
+	})
+}
+
+func TestSubmitCustomEventEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_customevent"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key:       testStudyKey,
+		SecretKey: "testsecret",
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status: types.STUDY_STATUS_ACTIVE,
+	}
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	_, err = testStudyDBService.AddStudyRules(testInstanceID, types.StudyRules{
+		StudyKey:   testStudyKey,
+		UploadedAt: time.Now().Unix(),
+		Rules: []types.Expression{
+			{Name: "IFTHEN", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "checkEventType", Data: []types.ExpressionArg{
+					{DType: "str", Str: "CUSTOM:swab_result_received"},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{
+					{DType: "str", Str: "swab"},
+					{DType: "exp", Exp: &types.Expression{Name: "getEventPayloadValue", Data: []types.ExpressionArg{
+						{DType: "str", Str: "result"},
+					}}},
+				}}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	for _, pState := range []types.ParticipantState{
+		{ParticipantID: "active", StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE},
+		{ParticipantID: "exited", StudyStatus: types.PARTICIPANT_STUDY_STATUS_EXITED},
+	} {
+		if _, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, pState); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		_, err := s.SubmitCustomEvent(context.Background(), nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without participants", func(t *testing.T) {
+		_, err := s.SubmitCustomEvent(context.Background(), &api.CustomEventReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			EventKey: "swab_result_received",
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with non member", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		_, err := s.SubmitCustomEvent(context.Background(), &api.CustomEventReq{
+			Token: &api_types.TokenInfos{
+				Id:         "otheruser",
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles": "PARTICIPANT",
+				},
+			},
+			StudyKey:       testStudyKey,
+			EventKey:       "swab_result_received",
+			ParticipantIds: []string{"active"},
+		})
+		if err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("with service account", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		resp, err := s.SubmitCustomEvent(context.Background(), &api.CustomEventReq{
+			Token: &api_types.TokenInfos{
+				Id:         "lab-pipeline",
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles": "SERVICE",
+				},
+			},
+			StudyKey:       testStudyKey,
+			EventKey:       "swab_result_received",
+			ParticipantIds: []string{"active", "exited", "notexisting"},
+			Payload: map[string]*api.FlagValue{
+				"result": {Dtype: types.FLAG_DTYPE_STRING, Str: "positive"},
+			},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if resp.ParticipantCount != 1 || len(resp.Errors) != 2 {
+			t.Errorf("unexpected result: %v", resp)
+		}
+		pState, err := testStudyDBService.FindParticipantState(testInstanceID, testStudyKey, "active")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if !pState.Flags["swab"].Equal(types.StringFlag("positive")) {
+			t.Errorf("unexpected flags: %v", pState.Flags)
+		}
+	})
+}
+
+func TestEvaluateRulesInSandboxEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_sandbox"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key:       testStudyKey,
+		SecretKey: "testsecret",
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status: types.STUDY_STATUS_ACTIVE,
+	}
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+
+	rules := []*api.Expression{
+		{Name: "IFTHEN", Data: []*api.ExpressionArg{
+			{Dtype: "exp", Data: &api.ExpressionArg_Exp{Exp: &api.Expression{Name: "checkEventType", Data: []*api.ExpressionArg{
+				{Dtype: "str", Data: &api.ExpressionArg_Str{Str: "CUSTOM"}},
+			}}}},
+			{Dtype: "exp", Data: &api.ExpressionArg_Exp{Exp: &api.Expression{Name: "UPDATE_FLAG", Data: []*api.ExpressionArg{
+				{Dtype: "str", Data: &api.ExpressionArg_Str{Str: "seen"}},
+				{Dtype: "exp", Data: &api.ExpressionArg_Exp{Exp: &api.Expression{Name: "timestampWithOffset", Data: []*api.ExpressionArg{
+					{Dtype: "num", Data: &api.ExpressionArg_Num{Num: 0}},
+				}}}},
+			}}}},
+			{Dtype: "exp", Data: &api.ExpressionArg_Exp{Exp: &api.Expression{Name: "NOTIFY_RESEARCHER", Data: []*api.ExpressionArg{
+				{Dtype: "str", Data: &api.ExpressionArg_Str{Str: "testmessage"}},
+			}}}},
+			{Dtype: "exp", Data: &api.ExpressionArg_Exp{Exp: &api.Expression{Name: "INIT_REPORT", Data: []*api.ExpressionArg{
+				{Dtype: "str", Data: &api.ExpressionArg_Str{Str: "testreport"}},
+			}}}},
+		}},
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		_, err := s.EvaluateRulesInSandbox(context.Background(), nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without participant", func(t *testing.T) {
+		_, err := s.EvaluateRulesInSandbox(context.Background(), &api.SandboxEvaluationReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			Event:    &api.SandboxEvaluationReq_Event{Type: "CUSTOM"},
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with not existing participant", func(t *testing.T) {
+		_, err := s.EvaluateRulesInSandbox(context.Background(), &api.SandboxEvaluationReq{
+			Token:         token,
+			StudyKey:      testStudyKey,
+			ParticipantId: "notexisting",
+			Event:         &api.SandboxEvaluationReq_Event{Type: "CUSTOM"},
+			Rules:         rules,
+		})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "participant not found")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with given participant state and simulated time", func(t *testing.T) {
+		resp, err := s.EvaluateRulesInSandbox(context.Background(), &api.SandboxEvaluationReq{
+			Token:    token,
+			StudyKey: testStudyKey,
+			ParticipantState: &api.ParticipantState{
+				ParticipantId: "sandboxparticipant",
+				StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			},
+			Event: &api.SandboxEvaluationReq_Event{Type: "CUSTOM"},
+			Rules: rules,
+			Now:   1000000,
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if resp.ParticipantState.Flags["seen"] != "1000000.000000" {
+			t.Errorf("unexpected flags: %v", resp.ParticipantState.Flags)
+		}
+		if resp.StateChanges.AddedFlags["seen"] != "1000000.000000" {
+			t.Errorf("unexpected state changes: %v", resp.StateChanges)
+		}
+		if len(resp.ResearcherMessages) != 1 || resp.ResearcherMessages[0].Type != "testmessage" {
+			t.Errorf("unexpected researcher messages: %v", resp.ResearcherMessages)
+		}
+		if len(resp.ReportsToCreate) != 1 || resp.ReportsToCreate[0].Timestamp != 999960 {
+			t.Errorf("unexpected reports: %v", resp.ReportsToCreate)
+		}
+
+		_, err = testStudyDBService.FindParticipantState(testInstanceID, testStudyKey, "sandboxparticipant")
+		if err == nil {
+			t.Error("participant state should not be saved")
+		}
+	})
+}
+
+type studyServiceAPI_RunRulesVersionWhatIf struct {
+	grpc.ServerStream
+	Results []*api.RulesWhatIfResult
+}
+
+func (_m *studyServiceAPI_RunRulesVersionWhatIf) Context() context.Context {
+	return context.Background()
+}
+
+func (_m *studyServiceAPI_RunRulesVersionWhatIf) Send(r *api.RulesWhatIfResult) error {
+	_m.Results = append(_m.Results, r)
+	return nil
+}
+
+func TestRunRulesVersionWhatIfEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLoggingClient := loggingMock.NewMockLoggingServiceApiClient(mockCtrl)
+
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+		clients: &types.APIClients{
+			LoggingService: mockLoggingClient,
+		},
+	}
+
+	testStudyKey := "testStudyfor_ruleswhatif"
+	testUserID := "testuserid"
+	testStudy := types.Study{
+		Key:       testStudyKey,
+		SecretKey: "testsecret",
+		Members: []types.StudyMember{
+			{
+				UserID: testUserID,
+				Role:   "maintainer",
+			},
+		},
+		Status: types.STUDY_STATUS_ACTIVE,
+	}
+	_, err := testStudyDBService.CreateStudy(testInstanceID, testStudy)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+
+	rulesVersion := func(uploadedAt int64, value string) types.StudyRules {
+		return types.StudyRules{
+			StudyKey:   testStudyKey,
+			UploadedAt: uploadedAt,
+			Rules: []types.Expression{
+				{Name: "IFTHEN", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "checkEventType", Data: []types.ExpressionArg{
+						{DType: "str", Str: "SUBMIT"},
+					}}},
+					{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{
+						{DType: "str", Str: "version"},
+						{DType: "str", Str: value},
+					}}},
+				}},
+			},
+		}
+	}
+	v1, err := testStudyDBService.AddStudyRules(testInstanceID, rulesVersion(100, "1"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	v2, err := testStudyDBService.AddStudyRules(testInstanceID, rulesVersion(200, "2"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	if _, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, types.ParticipantState{
+		ParticipantID: "p1", StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+	}); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	err = addTestSurveyResponses(s.studyDBservice, testInstanceID, testStudyKey, []types.SurveyResponse{
+		{Key: "s1", ParticipantID: "p1", SubmittedAt: 150},
+		{Key: "s1", ParticipantID: "p1", SubmittedAt: 250},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	token := &api_types.TokenInfos{
+		Id:         testUserID,
+		InstanceId: testInstanceID,
+		Payload: map[string]string{
+			"roles":    "PARTICIPANT,RESEARCHER",
+			"username": "testuser",
+		},
+	}
+
+	checkResults := func(t *testing.T, results []*api.RulesWhatIfResult) {
+		if len(results) != 2 {
+			t.Errorf("unexpected number of results: %d", len(results))
+			return
+		}
+		r := results[0].GetResponseResult()
+		if r == nil || r.SubmittedAt != 150 || r.OriginalRulesVersionId != v1.ID.Hex() {
+			t.Errorf("unexpected result: %v", results[0])
+			return
+		}
+		if r.OriginalChanges.AddedFlags["version"] != "1" || r.WhatIfChanges.AddedFlags["version"] != "2" {
+			t.Errorf("unexpected changes: %v", r)
+		}
+		summary := results[1].GetSummary()
+		if summary == nil || summary.ResponseCount != 2 || summary.ChangedCount != 1 || summary.ErrorCount != 0 {
+			t.Errorf("unexpected summary: %v", results[1])
+		}
+	}
+
+	t.Run("with missing request", func(t *testing.T) {
+		err := s.RunRulesVersionWhatIf(nil, nil)
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("without rules version", func(t *testing.T) {
+		err := s.RunRulesVersionWhatIf(&api.RulesWhatIfQuery{Token: token, StudyKey: testStudyKey}, &studyServiceAPI_RunRulesVersionWhatIf{})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "missing argument")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with non study member", func(t *testing.T) {
+		mockLoggingClient.EXPECT().SaveLogEvent(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, nil)
+		err := s.RunRulesVersionWhatIf(&api.RulesWhatIfQuery{
+			Token: &api_types.TokenInfos{
+				Id:         "otheruser",
+				InstanceId: testInstanceID,
+				Payload: map[string]string{
+					"roles":    "PARTICIPANT",
+					"username": "testuser2",
+				},
+			},
+			StudyKey:       testStudyKey,
+			RulesVersionId: v2.ID.Hex(),
+		}, &studyServiceAPI_RunRulesVersionWhatIf{})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "not authorized to access this study")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("with unknown rules version", func(t *testing.T) {
+		err := s.RunRulesVersionWhatIf(&api.RulesWhatIfQuery{
+			Token:          token,
+			StudyKey:       testStudyKey,
+			RulesVersionId: "000000000000000000000000",
+		}, &studyServiceAPI_RunRulesVersionWhatIf{})
+		ok, msg := shouldHaveGrpcErrorStatus(err, "study rules version not found")
+		if !ok {
+			t.Error(msg)
+		}
+	})
+
+	t.Run("for all participants", func(t *testing.T) {
+		mock := &studyServiceAPI_RunRulesVersionWhatIf{}
+		err := s.RunRulesVersionWhatIf(&api.RulesWhatIfQuery{
+			Token:          token,
+			StudyKey:       testStudyKey,
+			RulesVersionId: v2.ID.Hex(),
+		}, mock)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		checkResults(t, mock.Results)
+	})
+
+	t.Run("for one participant", func(t *testing.T) {
+		mock := &studyServiceAPI_RunRulesVersionWhatIf{}
+		err := s.RunRulesVersionWhatIf(&api.RulesWhatIfQuery{
+			Token:          token,
+			StudyKey:       testStudyKey,
+			RulesVersionId: v2.ID.Hex(),
+			ParticipantId:  "p1",
+		}, mock)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		checkResults(t, mock.Results)
+
+		pState, err := testStudyDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if _, ok := pState.Flags["version"]; ok {
+			t.Error("participant state should not be saved")
+		}
+	})
+}
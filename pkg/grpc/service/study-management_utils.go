@@ -1,11 +1,36 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"time"
 
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/api"
+	"github.com/influenzanet/study-service/pkg/dbs/studydb"
+	"github.com/influenzanet/study-service/pkg/studyengine"
+	"github.com/influenzanet/study-service/pkg/types"
 	"github.com/influenzanet/study-service/pkg/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// rulesWhatIfLimitations is returned in the summary of RunRulesVersionWhatIf
+const rulesWhatIfLimitations = "submissions are evaluated with the current participant states instead of the states at their submission time, " +
+	"so both runs can differ from the original evaluation for participants whose state changed since"
+
+// CUSTOM_RULES_VERSION_ID_PREFIX is followed by a hash of the rules in the rules version ID of custom rule runs
+const CUSTOM_RULES_VERSION_ID_PREFIX = "custom-"
+
+// errParticipantStateUnchanged stops a participant state update, if the rules did not modify the state
+var errParticipantStateUnchanged = errors.New("participant state unchanged")
+
 func (s *studyServiceServer) HasRoleInStudy(instanceID string, studyKey string, userID string, hasAnyOfRoles []string) error {
 	members, err := s.studyDBservice.GetStudyMembers(instanceID, studyKey)
 	if err != nil {
@@ -16,3 +41,213 @@ func (s *studyServiceServer) HasRoleInStudy(instanceID string, studyKey string,
 	}
 	return nil
 }
+
+// rulesValidationError converts validation errors into an InvalidArgument status, listing each problem as field violation
+func rulesValidationError(errs studyengine.ValidationErrors) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(errs))
+	for i, e := range errs {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       e.Path,
+			Description: e.Error(),
+		}
+	}
+	st, err := status.New(codes.InvalidArgument, "invalid rules: "+errs.Error()).WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid rules: "+errs.Error())
+	}
+	return st.Err()
+}
+
+// dryRunChangesToAPI converts the state changes and the reports of a dry run
+func dryRunChangesToAPI(oldState types.ParticipantState, result studyengine.ActionData) *api.ParticipantStateDiff {
+	changes := types.DiffParticipantStates(oldState, result.PState).ToAPI()
+	for _, report := range result.ReportsToCreate {
+		changes.ReportsToCreate = append(changes.ReportsToCreate, report.ToAPI())
+	}
+	return changes
+}
+
+// validateRuleList checks the rules of a request. Empty rules are skipped, as when the rules are run.
+func validateRuleList(rules []*types.Expression) studyengine.ValidationErrors {
+	errs := studyengine.ValidationErrors{}
+	for i, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		errs = append(errs, studyengine.ValidateStudyRule(*rule, fmt.Sprintf("rules[%d]", i))...)
+	}
+	return errs
+}
+
+// traceToAPI converts the entries recorded while evaluating the rule with the given index
+func traceToAPI(ruleIndex int, tracer *studyengine.Tracer) []*api.RuleTraceEntry {
+	if tracer == nil {
+		return nil
+	}
+	entries := make([]*api.RuleTraceEntry, len(tracer.Entries))
+	for i, e := range tracer.Entries {
+		args := make([]string, len(e.Args))
+		for j, arg := range e.Args {
+			args[j] = fmt.Sprintf("%v", arg)
+		}
+		entry := &api.RuleTraceEntry{
+			RuleIndex: int32(ruleIndex),
+			Depth:     int32(e.Depth),
+			Type:      e.Type,
+			Name:      e.Name,
+			Args:      args,
+			Error:     e.Error,
+		}
+		if e.Result != nil {
+			entry.Result = fmt.Sprintf("%v", e.Result)
+		}
+		if e.StateChanges != nil {
+			entry.StateChanges = e.StateChanges.ToAPI()
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// customRulesRun is the result of running custom rules for a participant
+type customRulesRun struct {
+	OldState       types.ParticipantState
+	ActionData     studyengine.ActionData
+	ChangesPerRule []int32 // number of times each rule changed the participant state
+	Trace          []*api.RuleTraceEntry
+}
+
+// evaluateCustomRules evaluates the rules one after the other on the participant state
+func evaluateCustomRules(pState types.ParticipantState, rules []*types.Expression, event types.StudyEvent, configs studyengine.ActionConfigs, withTrace bool) (customRulesRun, error) {
+	run := customRulesRun{
+		OldState: pState,
+		ActionData: studyengine.ActionData{
+			PState:          pState,
+			ReportsToCreate: map[string]types.Report{},
+		},
+		ChangesPerRule: make([]int32, len(rules)),
+		Trace:          []*api.RuleTraceEntry{},
+	}
+	for index, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		configs.Tracer = nil
+		if withTrace {
+			configs.Tracer = studyengine.NewTracer()
+		}
+		newState, err := studyengine.ActionEval(*rule, run.ActionData, event, configs)
+		run.Trace = append(run.Trace, traceToAPI(index, configs.Tracer)...)
+		if err != nil {
+			return run, err
+		}
+
+		if !reflect.DeepEqual(newState.PState, run.ActionData.PState) {
+			run.ChangesPerRule[index] += 1
+		}
+		run.ActionData = newState
+	}
+	return run, nil
+}
+
+// runCustomRulesForParticipant evaluates the rules for the participant. In dry run mode nothing is saved, and the side
+// effects of the rules are only collected in the action data. Otherwise the participant state is saved if a rule changed
+// it, and the side effects and reports are performed once after the state is saved.
+func (s *studyServiceServer) runCustomRulesForParticipant(instanceID string, studyKey string, userID string, pState types.ParticipantState, rules []*types.Expression, dryRun bool, withTrace bool) (customRulesRun, error) {
+	participantID2, _, err := s.profileIDToParticipantID(instanceID, studyKey, pState.ParticipantID, true)
+	if err != nil {
+		return customRulesRun{}, err
+	}
+	event := types.StudyEvent{
+		InstanceID:                            instanceID,
+		StudyKey:                              studyKey,
+		ParticipantIDForConfidentialResponses: participantID2,
+	}
+	configs := studyengine.ActionConfigs{
+		DBService:              s.studyDBservice,
+		ExternalServiceConfigs: s.studyEngineExternalServices,
+		DryRun:                 dryRun,
+	}
+	if dryRun {
+		return evaluateCustomRules(pState, rules, event, configs, withTrace)
+	}
+
+	configs.Deferred = studyengine.NewDeferredEffects()
+	var run customRulesRun
+	_, err = s.studyDBservice.UpdateParticipantStateWithRetry(instanceID, studyKey, pState, func(current types.ParticipantState) (types.ParticipantState, error) {
+		var evalErr error
+		run, evalErr = evaluateCustomRules(current, rules, event, configs, withTrace)
+		if evalErr != nil {
+			return current, evalErr
+		}
+		for _, c := range run.ChangesPerRule {
+			if c > 0 {
+				return run.ActionData.PState, nil
+			}
+		}
+		return current, errParticipantStateUnchanged
+	})
+	if err != nil && err != errParticipantStateUnchanged {
+		s.logParticipantStateConflict(instanceID, studyKey, userID, err)
+		return run, err
+	}
+	if err == nil {
+		historyEvent := event
+		historyEvent.Type = types.PARTICIPANT_EVENT_TYPE_CUSTOM_RULES
+		historyEvent.UserID = userID
+		s.saveParticipantEvent(instanceID, studyKey, historyEvent, customRulesVersionID(rules), run.OldState, run.ActionData.PState)
+	}
+	studyengine.PerformDeferredEffects(s.studyDBservice, instanceID, studyKey, run.ActionData)
+	s.saveReports(instanceID, studyKey, run.ActionData.ReportsToCreate, "")
+	return run, nil
+}
+
+// customRulesVersionID identifies custom rules in the participant event history, since they are not stored as a study
+// rules version. The ID is derived from the rules, so that runs of the same rules have the same ID.
+func customRulesVersionID(rules []*types.Expression) string {
+	b, err := json.Marshal(rules)
+	if err != nil {
+		logger.Error.Printf("unexpected error when encoding custom rules: %v", err)
+		return types.PARTICIPANT_EVENT_TYPE_CUSTOM_RULES
+	}
+	hash := sha256.Sum256(b)
+	return CUSTOM_RULES_VERSION_ID_PREFIX + hex.EncodeToString(hash[:8])
+}
+
+// runCustomRules runs the rules for each participant of the study except temporary participants, and calls onParticipant
+// with the result of each participant if set
+func (s *studyServiceServer) runCustomRules(ctx context.Context, instanceID string, studyKey string, userID string, rules []*types.Expression, dryRun bool, onParticipant func(run customRulesRun) error) (*api.RuleRunSummary, error) {
+	start := time.Now().Unix()
+	summary := &api.RuleRunSummary{
+		ParticipantStateChangePerRule: make([]int32, len(rules)),
+	}
+	err := s.studyDBservice.FindAndExecuteOnParticipantsStates(
+		ctx,
+		instanceID,
+		studyKey,
+		"",
+		func(dbService *studydb.StudyDBService, p types.ParticipantState, instanceID, studyKey string, args ...interface{}) error {
+			if p.StudyStatus == types.PARTICIPANT_STUDY_STATUS_TEMPORARY {
+				// ignore temporary participants
+				return nil
+			}
+
+			summary.ParticipantCount += 1
+
+			run, err := s.runCustomRulesForParticipant(instanceID, studyKey, userID, p, rules, dryRun, false)
+			if err != nil {
+				logger.Error.Printf("runCustomRules: %v", err)
+				return status.Error(codes.Internal, err.Error())
+			}
+			for index, c := range run.ChangesPerRule {
+				summary.ParticipantStateChangePerRule[index] += c
+			}
+			if onParticipant == nil {
+				return nil
+			}
+			return onParticipant(run)
+		},
+	)
+	summary.Duration = time.Now().Unix() - start
+	return summary, err
+}
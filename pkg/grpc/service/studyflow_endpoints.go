@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -24,6 +25,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+var (
+	errParticipantExists    = errors.New("participant already exists for this study")
+	errParticipantNotActive = errors.New("not active in the study")
+)
+
 func (s *studyServiceServer) EnterStudy(ctx context.Context, req *api.EnterStudyRequest) (*api.AssignedSurveys, error) {
 	if req == nil || token_checks.IsTokenEmpty(req.Token) || req.StudyKey == "" {
 		return nil, status.Error(codes.InvalidArgument, "missing argument")
@@ -41,10 +47,13 @@ func (s *studyServiceServer) EnterStudy(ctx context.Context, req *api.EnterStudy
 	}
 
 	// Exists already?
-	exists := s.checkIfParticipantExists(req.Token.InstanceId, req.StudyKey, participantID, types.PARTICIPANT_STUDY_STATUS_ACTIVE)
-	if exists {
+	current, err := s.findParticipantStateForUpdate(req.Token.InstanceId, req.StudyKey, participantID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if current.StudyStatus == types.PARTICIPANT_STUDY_STATUS_ACTIVE {
 		logger.Debug.Printf("error: participant (%s) already exists for this study", participantID)
-		return nil, status.Error(codes.Internal, "participant already exists for this study")
+		return nil, status.Error(codes.Internal, errParticipantExists.Error())
 	}
 
 	// To improve privace, we reduce resolution of the timestamp to the day
@@ -64,18 +73,21 @@ func (s *studyServiceServer) EnterStudy(ctx context.Context, req *api.EnterStudy
 		StudyKey:                              req.StudyKey,
 		ParticipantIDForConfidentialResponses: participantID2,
 	}
-	actionResult, err := s.getAndPerformStudyRules(req.Token.InstanceId, req.StudyKey, pState, currentEvent)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	// save state back to DB
-	pState, err = s.studyDBservice.SaveParticipantState(req.Token.InstanceId, req.StudyKey, actionResult.PState)
+	// a previous state of the participant (e.g. after leaving the study) is replaced
+	actionResult, rulesVersionID, err := s.updateParticipantStateWithRules(req.Token.InstanceId, req.StudyKey, req.Token.Id, current, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+		if current.StudyStatus == types.PARTICIPANT_STUDY_STATUS_ACTIVE {
+			return studyengine.ActionData{}, "", errParticipantExists
+		}
+		return s.performStudyRules(req.Token.InstanceId, req.StudyKey, pState, currentEvent, deferred)
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	oldPState := pState
+	pState = actionResult.PState
 
 	s.saveReports(req.Token.InstanceId, req.StudyKey, actionResult.ReportsToCreate, "ENTER")
+	s.saveParticipantEvent(req.Token.InstanceId, req.StudyKey, currentEvent, rulesVersionID, oldPState, actionResult.PState)
 
 	// Prepare response
 	resp := api.AssignedSurveys{
@@ -122,19 +134,19 @@ func (s *studyServiceServer) RegisterTemporaryParticipant(ctx context.Context, r
 		InstanceID: req.InstanceId,
 		StudyKey:   req.StudyKey,
 	}
-	actionResult, err := s.getAndPerformStudyRules(req.InstanceId, req.StudyKey, pState, currentEvent)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	// save state back to DB
-	_, err = s.studyDBservice.SaveParticipantState(req.InstanceId, req.StudyKey, actionResult.PState)
+	actionResult, rulesVersionID, err := s.updateParticipantStateWithRules(req.InstanceId, req.StudyKey, "", types.ParticipantState{ParticipantID: participantID}, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+		if !current.ID.IsZero() {
+			return studyengine.ActionData{}, "", errParticipantExists
+		}
+		return s.performStudyRules(req.InstanceId, req.StudyKey, pState, currentEvent, deferred)
+	})
 	if err != nil {
 		logger.Error.Println(err)
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	s.saveReports(req.InstanceId, req.StudyKey, actionResult.ReportsToCreate, "ENTER")
+	s.saveParticipantEvent(req.InstanceId, req.StudyKey, currentEvent, rulesVersionID, pState, actionResult.PState)
 
 	// Prepare response
 	resp := api.RegisterTempParticipantResponse{
@@ -185,28 +197,35 @@ func (s *studyServiceServer) ConvertTemporaryToParticipant(ctx context.Context,
 			MergeWithParticipant:                  pState,
 			ParticipantIDForConfidentialResponses: realParticipantID2,
 		}
-		mergeResult, err := s.getAndPerformStudyRules(req.Token.InstanceId, req.StudyKey, existingPState, event)
-		if err != nil {
-			logger.Error.Println(err)
-			return nil, status.Error(codes.Internal, err.Error())
-		}
-
-		_, err = s.studyDBservice.SaveParticipantState(req.Token.InstanceId, req.StudyKey, mergeResult.PState)
+		mergeResult, rulesVersionID, err := s.updateParticipantStateWithRules(req.Token.InstanceId, req.StudyKey, req.Token.Id, existingPState, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+			existingPState = current
+			return s.performStudyRules(req.Token.InstanceId, req.StudyKey, current, event, deferred)
+		})
 		if err != nil {
 			logger.Error.Println(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+		s.saveParticipantEvent(req.Token.InstanceId, req.StudyKey, event, rulesVersionID, existingPState, mergeResult.PState)
 	} else {
 		// Participant did not exist before:
+		tempPState := pState
 		pState.ID = primitive.ObjectID{}
 		pState.ParticipantID = realParticipantID
 		pState.StudyStatus = types.PARTICIPANT_STUDY_STATUS_ACTIVE
 
-		_, err = s.studyDBservice.SaveParticipantState(req.Token.InstanceId, req.StudyKey, pState)
+		_, _, err = s.updateParticipantStateWithRules(req.Token.InstanceId, req.StudyKey, req.Token.Id, types.ParticipantState{ParticipantID: realParticipantID}, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+			if !current.ID.IsZero() {
+				// entered the study concurrently
+				return studyengine.ActionData{}, "", errParticipantExists
+			}
+			return studyengine.ActionData{PState: pState}, "", nil
+		})
 		if err != nil {
 			logger.Error.Println(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+		// no rules are run, but the history records the conversion
+		s.saveParticipantEvent(req.Token.InstanceId, req.StudyKey, types.StudyEvent{Type: "MERGE"}, "", tempPState, pState)
 	}
 
 	err = s.studyDBservice.DeleteParticipantState(req.Token.InstanceId, req.StudyKey, req.TemporaryParticipantId)
@@ -458,16 +477,16 @@ func (s *studyServiceServer) SubmitResponse(ctx context.Context, req *api.Submit
 		StudyKey:                              req.StudyKey,
 		ParticipantIDForConfidentialResponses: participantID2,
 	}
-	actionResult, err := s.getAndPerformStudyRules(instanceID, req.StudyKey, pState, currentEvent)
-	if err != nil {
-		logger.Error.Printf("unexpected error_ %v", err)
+	userID := ""
+	if req.Token != nil {
+		userID = req.Token.Id
 	}
-
-	// save state back to DB
-	pState, err = s.studyDBservice.SaveParticipantState(instanceID, req.StudyKey, actionResult.PState)
+	oldPState, actionResult, rulesVersionID, err := s.performStudyRulesAndSaveState(instanceID, req.StudyKey, userID, pState, currentEvent)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	pState = actionResult.PState
+	s.saveParticipantEvent(instanceID, req.StudyKey, currentEvent, rulesVersionID, oldPState, pState)
 
 	/**
 	 * Save response
@@ -580,20 +599,27 @@ func (s *studyServiceServer) ProfileDeleted(ctx context.Context, req *api_types.
 			StudyKey:                              study.Key,
 			ParticipantIDForConfidentialResponses: participantID2,
 		}
-		actionResult, err := s.getAndPerformStudyRules(instanceID, study.Key, pState, currentEvent)
-		if err != nil {
-			logger.Error.Printf("unexpected error_ %v", err)
+		var rulesErr error
+		actionResult, rulesVersionID, err := s.updateParticipantStateWithRules(instanceID, study.Key, req.Id, pState, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+			pState = current
+			result, versionID, err := s.performStudyRules(instanceID, study.Key, current, currentEvent, deferred)
+			if err != nil {
+				rulesErr = err
+				return result, versionID, err
+			}
+			result.PState.StudyStatus = types.PARTICIPANT_STUDY_STATUS_ACCOUNT_DELETED
+			return result, versionID, nil
+		})
+		if rulesErr != nil {
+			logger.Error.Printf("unexpected error_ %v", rulesErr)
 			continue
 		}
-
-		actionResult.PState.StudyStatus = types.PARTICIPANT_STUDY_STATUS_ACCOUNT_DELETED
-
-		_, err = s.studyDBservice.SaveParticipantState(instanceID, study.Key, actionResult.PState)
 		if err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 
 		s.saveReports(instanceID, study.Key, actionResult.ReportsToCreate, "LEAVE")
+		s.saveParticipantEvent(instanceID, study.Key, currentEvent, rulesVersionID, pState, actionResult.PState)
 
 		// Remove confidential data:
 		_, err = s.studyDBservice.DeleteConfidentialResponses(instanceID, study.Key, participantID2, "")
@@ -628,10 +654,11 @@ func (s *studyServiceServer) LeaveStudy(ctx context.Context, req *api.LeaveStudy
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	if pState.StudyStatus != types.PARTICIPANT_STUDY_STATUS_ACTIVE {
-		return nil, status.Error(codes.Internal, "not active in the study")
+		return nil, status.Error(codes.Internal, errParticipantNotActive.Error())
 	}
 
 	// Init state and perform rules
+	oldPState := pState
 	pState = types.ParticipantState{
 		ParticipantID: participantID,
 		StudyStatus:   types.PARTICIPANT_STUDY_STATUS_EXITED,
@@ -643,17 +670,19 @@ func (s *studyServiceServer) LeaveStudy(ctx context.Context, req *api.LeaveStudy
 		StudyKey:                              req.StudyKey,
 		ParticipantIDForConfidentialResponses: participantID2,
 	}
-	actionResult, err := s.getAndPerformStudyRules(req.Token.InstanceId, req.StudyKey, pState, currentEvent)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	_, err = s.studyDBservice.SaveParticipantState(req.Token.InstanceId, req.StudyKey, actionResult.PState)
+	actionResult, rulesVersionID, err := s.updateParticipantStateWithRules(req.Token.InstanceId, req.StudyKey, req.Token.Id, oldPState, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+		if current.StudyStatus != types.PARTICIPANT_STUDY_STATUS_ACTIVE {
+			return studyengine.ActionData{}, "", errParticipantNotActive
+		}
+		oldPState = current
+		return s.performStudyRules(req.Token.InstanceId, req.StudyKey, pState, currentEvent, deferred)
+	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	s.saveReports(req.Token.InstanceId, req.StudyKey, actionResult.ReportsToCreate, "LEAVE")
+	s.saveParticipantEvent(req.Token.InstanceId, req.StudyKey, currentEvent, rulesVersionID, oldPState, actionResult.PState)
 
 	// Prepare response
 	resp := api.AssignedSurveys{
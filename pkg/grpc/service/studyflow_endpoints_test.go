@@ -9,6 +9,7 @@ import (
 	"github.com/coneno/logger"
 	"github.com/influenzanet/go-utils/pkg/api_types"
 	"github.com/influenzanet/study-service/pkg/api"
+	"github.com/influenzanet/study-service/pkg/studyengine"
 	"github.com/influenzanet/study-service/pkg/types"
 	"google.golang.org/grpc"
 )
@@ -34,7 +35,7 @@ func TestCheckIfParticipantExists(t *testing.T) {
 	}
 
 	for _, ps := range pStates {
-		_, err := testStudyDBService.SaveParticipantState(testInstanceID, testStudyKey, ps)
+		_, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, ps)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -138,7 +139,7 @@ func TestGetAndPerformStudyRules(t *testing.T) {
 			Type: "ENTER",
 		}
 
-		actionResult, err := s.getAndPerformStudyRules(testInstanceID, testStudy.Key, pState, testEvent)
+		actionResult, _, err := s.getAndPerformStudyRules(testInstanceID, testStudy.Key, pState, testEvent)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -147,7 +148,7 @@ func TestGetAndPerformStudyRules(t *testing.T) {
 		if !ok {
 			t.Error("testKey not found")
 		}
-		if v != "testValue" {
+		if v.String() != "testValue" {
 			t.Errorf("testValue not matches %s", v)
 		}
 		pState = actionResult.PState
@@ -160,7 +161,7 @@ func TestGetAndPerformStudyRules(t *testing.T) {
 			},
 		}
 
-		actionResult, err := s.getAndPerformStudyRules(testInstanceID, testStudy.Key, pState, testEvent)
+		actionResult, _, err := s.getAndPerformStudyRules(testInstanceID, testStudy.Key, pState, testEvent)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 			return
@@ -169,12 +170,82 @@ func TestGetAndPerformStudyRules(t *testing.T) {
 		if !ok {
 			t.Error("testKey not found")
 		}
-		if v != "testValue2" {
+		if v.String() != "testValue2" {
 			t.Errorf("testValue not matches %s", v)
 		}
 	})
 }
 
+func TestUpdateParticipantStateWithRules(t *testing.T) {
+	s := studyServiceServer{
+		globalDBService:   testGlobalDBService,
+		studyDBservice:    testStudyDBService,
+		StudyGlobalSecret: "globsecretfortest1234",
+	}
+	testStudyKey := "studyforupdatestatewithrules"
+
+	t.Run("with concurrent modification", func(t *testing.T) {
+		current, err := s.findParticipantStateForUpdate(testInstanceID, testStudyKey, "p1")
+		if err != nil || current.ParticipantID != "p1" || !current.ID.IsZero() {
+			t.Errorf("unexpected state: %v, %v", current, err)
+			return
+		}
+		current.StudyStatus = types.PARTICIPANT_STUDY_STATUS_ACTIVE
+		if _, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, current); err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		current, err = s.findParticipantStateForUpdate(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+
+		calls := 0
+		result, _, err := s.updateParticipantStateWithRules(testInstanceID, testStudyKey, "", current, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+			calls++
+			if calls == 1 {
+				// e.g. a timer event assigning a survey
+				concurrent := current
+				concurrent.AssignedSurveys = []types.AssignedSurvey{{SurveyKey: "weekly"}}
+				if _, err := testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, concurrent); err != nil {
+					return studyengine.ActionData{}, "", err
+				}
+			}
+			current.Flags = types.ParticipantFlags{"left": types.StringFlag("1")}
+			return studyengine.ActionData{PState: current}, "", nil
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if calls != 2 {
+			t.Errorf("rules should be run again on the reloaded state: %d", calls)
+		}
+		if _, ok := result.PState.Flags["left"]; len(result.PState.AssignedSurveys) != 1 || !ok {
+			t.Errorf("concurrent modification lost: %v", result.PState)
+		}
+	})
+
+	t.Run("with rejected update", func(t *testing.T) {
+		current, err := s.findParticipantStateForUpdate(testInstanceID, testStudyKey, "p1")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		_, _, err = s.updateParticipantStateWithRules(testInstanceID, testStudyKey, "", current, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+			return studyengine.ActionData{}, "", errParticipantExists
+		})
+		if err != errParticipantExists {
+			t.Errorf("unexpected error: %v", err)
+		}
+		stored, err := testStudyDBService.FindParticipantState(testInstanceID, testStudyKey, "p1")
+		if err != nil || stored.Version != current.Version {
+			t.Errorf("state should not be saved: %v", stored)
+		}
+	})
+}
+
 func TestEnterStudyEndpoint(t *testing.T) {
 	s := studyServiceServer{
 		globalDBService:   testGlobalDBService,
@@ -374,12 +445,12 @@ func TestGetAssignedSurveysEndpoint(t *testing.T) {
 		},
 	}
 
-	_, err = testStudyDBService.SaveParticipantState(testInstanceID, "studyforassignedsurvey1", pState1)
+	_, err = testStudyDBService.OverwriteParticipantState(testInstanceID, "studyforassignedsurvey1", pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = testStudyDBService.SaveParticipantState(testInstanceID, "studyforassignedsurvey2", pState2)
+	_, err = testStudyDBService.OverwriteParticipantState(testInstanceID, "studyforassignedsurvey2", pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
@@ -535,8 +606,8 @@ func TestGetAssignedSurveyEndpoint(t *testing.T) {
 		t.Errorf("unexpected error: %s", err.Error())
 	}
 
-	pState.Flags = map[string]string{"test": "testValue"}
-	_, err = testStudyDBService.SaveParticipantState(testInstanceID, testStudyKey, pState)
+	pState.Flags = types.ParticipantFlags{"test": types.StringFlag("testValue")}
+	_, err = testStudyDBService.OverwriteParticipantState(testInstanceID, testStudyKey, pState)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 	}
@@ -654,12 +725,12 @@ func TestSubmitResponseEndpoint(t *testing.T) {
 		},
 	}
 
-	_, err = testStudyDBService.SaveParticipantState(testInstanceID, "studyfor_submitsurvey1", pState1)
+	_, err = testStudyDBService.OverwriteParticipantState(testInstanceID, "studyfor_submitsurvey1", pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = testStudyDBService.SaveParticipantState(testInstanceID, "studyfor_submitsurvey2", pState2)
+	_, err = testStudyDBService.OverwriteParticipantState(testInstanceID, "studyfor_submitsurvey2", pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
@@ -819,12 +890,12 @@ func TestLeaveStudyEndpoint(t *testing.T) {
 		StudyStatus:   types.PARTICIPANT_STUDY_STATUS_EXITED,
 	}
 
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState1)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState2)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
@@ -15,6 +15,7 @@ import (
 	"github.com/influenzanet/study-service/pkg/studyengine"
 	"github.com/influenzanet/study-service/pkg/types"
 	"github.com/influenzanet/study-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -48,13 +49,18 @@ func (s *studyServiceServer) checkIfParticipantExists(instanceID string, studyKe
 	return err == nil
 }
 
-func (s *studyServiceServer) getAndPerformStudyRules(instanceID string, studyKey string, pState types.ParticipantState, event types.StudyEvent) (newState studyengine.ActionData, err error) {
+func (s *studyServiceServer) getAndPerformStudyRules(instanceID string, studyKey string, pState types.ParticipantState, event types.StudyEvent) (newState studyengine.ActionData, rulesVersionID string, err error) {
+	return s.performStudyRules(instanceID, studyKey, pState, event, nil)
+}
+
+// performStudyRules evaluates the current study rules for the event, side effects are collected if deferred is set
+func (s *studyServiceServer) performStudyRules(instanceID string, studyKey string, pState types.ParticipantState, event types.StudyEvent, deferred *studyengine.DeferredEffects) (newState studyengine.ActionData, rulesVersionID string, err error) {
 	newState = studyengine.ActionData{
 		PState:          pState,
 		ReportsToCreate: map[string]types.Report{},
 	}
 
-	rules, err := s.studyDBservice.GetStudyRules(instanceID, studyKey)
+	rules, rulesVersionID, err := s.studyDBservice.GetStudyRulesWithVersionID(instanceID, studyKey)
 	if err != nil {
 		return
 	}
@@ -62,20 +68,114 @@ func (s *studyServiceServer) getAndPerformStudyRules(instanceID string, studyKey
 		newState, err = studyengine.ActionEval(rule, newState, event, studyengine.ActionConfigs{
 			DBService:              s.studyDBservice,
 			ExternalServiceConfigs: s.studyEngineExternalServices,
+			Deferred:               deferred,
 		})
 		if err != nil {
 			return
 		}
 	}
 
+	return newState, rulesVersionID, nil
+}
+
+// performStudyRulesDryRun evaluates the rules for the event without saving or sending anything, at the simulated time now
+func (s *studyServiceServer) performStudyRulesDryRun(rules []types.Expression, pState types.ParticipantState, event types.StudyEvent, now int64) (newState studyengine.ActionData, err error) {
+	newState = studyengine.ActionData{
+		PState:          pState,
+		ReportsToCreate: map[string]types.Report{},
+	}
+	configs := studyengine.ActionConfigs{
+		DBService:              s.studyDBservice,
+		ExternalServiceConfigs: s.studyEngineExternalServices,
+		DryRun:                 true,
+	}
+	if now > 0 {
+		simulatedNow := time.Unix(now, 0)
+		configs.Now = func() time.Time { return simulatedNow }
+	}
+	for _, rule := range rules {
+		newState, err = studyengine.ActionEval(rule, newState, event, configs)
+		if err != nil {
+			return
+		}
+	}
 	return newState, nil
 }
 
+// saveParticipantEvent adds the event and the resulting state changes to the participant's event history
+func (s *studyServiceServer) saveParticipantEvent(instanceID string, studyKey string, event types.StudyEvent, rulesVersionID string, oldState types.ParticipantState, newState types.ParticipantState) {
+	err := s.studyDBservice.AddParticipantEvent(instanceID, studyKey, types.NewParticipantEvent(event, rulesVersionID, oldState, newState))
+	if err != nil {
+		logger.Error.Printf("unexpected error while saving participant event: %v", err)
+	}
+}
+
+// performStudyRulesAndSaveState runs the study rules for the event and saves the resulting participant state.
+// Side effects of the rules are performed once the state is saved.
+func (s *studyServiceServer) performStudyRulesAndSaveState(instanceID string, studyKey string, userID string, pState types.ParticipantState, event types.StudyEvent) (oldState types.ParticipantState, actionResult studyengine.ActionData, rulesVersionID string, err error) {
+	oldState = pState
+	actionResult, rulesVersionID, err = s.updateParticipantStateWithRules(instanceID, studyKey, userID, pState, func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error) {
+		oldState = current
+		result, versionID, rulesErr := s.performStudyRules(instanceID, studyKey, current, event, deferred)
+		if rulesErr != nil {
+			logger.Error.Printf("unexpected error_ %v", rulesErr)
+		}
+		return result, versionID, nil
+	})
+	return
+}
+
+// updateParticipantStateWithRules saves the participant state resulting from run if the stored state is still current.
+// On a conflict, run is called again with the reloaded state, so that the rules are evaluated on the current state instead of
+// overwriting concurrent changes (e.g. of the study timer). For participants not stored yet, current only has the participant ID.
+// Side effects of the rules are collected with deferred and performed once the state is saved.
+func (s *studyServiceServer) updateParticipantStateWithRules(
+	instanceID string,
+	studyKey string,
+	userID string,
+	current types.ParticipantState,
+	run func(current types.ParticipantState, deferred *studyengine.DeferredEffects) (studyengine.ActionData, string, error),
+) (actionResult studyengine.ActionData, rulesVersionID string, err error) {
+	deferred := studyengine.NewDeferredEffects()
+	savedState, err := s.studyDBservice.UpdateParticipantStateWithRetry(instanceID, studyKey, current, func(current types.ParticipantState) (types.ParticipantState, error) {
+		var runErr error
+		actionResult, rulesVersionID, runErr = run(current, deferred)
+		if runErr != nil {
+			return current, runErr
+		}
+		return actionResult.PState, nil
+	})
+	if err != nil {
+		s.logParticipantStateConflict(instanceID, studyKey, userID, err)
+		return
+	}
+	studyengine.PerformDeferredEffects(s.studyDBservice, instanceID, studyKey, actionResult)
+	actionResult.PState = savedState
+	return
+}
+
+// findParticipantStateForUpdate returns the stored participant state, or a state with only the participant ID if there is none
+func (s *studyServiceServer) findParticipantStateForUpdate(instanceID string, studyKey string, participantID string) (types.ParticipantState, error) {
+	pState, err := s.studyDBservice.FindParticipantState(instanceID, studyKey, participantID)
+	if err == mongo.ErrNoDocuments {
+		return types.ParticipantState{ParticipantID: participantID}, nil
+	}
+	return pState, err
+}
+
+// logParticipantStateConflict saves a log event if a participant state could not be updated because of concurrent modifications
+func (s *studyServiceServer) logParticipantStateConflict(instanceID string, studyKey string, userID string, err error) {
+	if err != studydb.ErrParticipantStateConflict {
+		return
+	}
+	s.SaveLogEvent(instanceID, userID, loggingAPI.LogEventType_ERROR, constants.LOG_EVENT_PARTICIPANT_ACTION, fmt.Sprintf("participant state update in study %s failed after %d retries: %v", studyKey, studydb.ParticipantStateUpdateRetries, err))
+}
+
 func (s *studyServiceServer) resolveContextRules(instanceID string, studyKey string, pState types.ParticipantState, rules *types.SurveyContextDef) (sCtx types.SurveyContext, err error) {
 	participantID := pState.ParticipantID
 
 	// participant flags:
-	sCtx.ParticipantFlags = pState.Flags
+	sCtx.ParticipantFlags = pState.Flags.StringValues()
 
 	if rules == nil {
 		return sCtx, nil
@@ -272,6 +372,19 @@ func (s *studyServiceServer) saveReports(instanceID string, studyKey string, rep
 	}
 }
 
+// updateParticipantCounts refreshes the cached participant counts for the conditions of the active rules and of the saved rules
+func (s *studyServiceServer) updateParticipantCounts(instanceID string, studyKey string, savedRules []types.Expression) {
+	rules, _, err := s.studyDBservice.GetStudyRulesWithVersionID(instanceID, studyKey)
+	if err != nil {
+		logger.Error.Printf("unexpected error when fetching rules: %v", err)
+	}
+	rules = append(rules, savedRules...)
+	participantCounts := studyengine.CountParticipantConditions(s.studyDBservice, instanceID, studyKey, rules)
+	if err := s.studyDBservice.UpdateStudyParticipantCounts(instanceID, studyKey, participantCounts, time.Now().Unix()); err != nil {
+		logger.Error.Printf("unexpected error when updating participant counts: %v", err)
+	}
+}
+
 func (s *studyServiceServer) prepareSurveyWithoutParticipant(instanceID string, studyKey string, surveyDef *types.Survey) (*api.SurveyAndContext, error) {
 	// empty irrelevant fields for this purpose
 	surveyDef.ContextRules = nil
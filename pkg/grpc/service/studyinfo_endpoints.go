@@ -82,11 +82,14 @@ func (s *studyServiceServer) GetActiveStudies(ctx context.Context, req *api_type
 	resp := &api.Studies{Studies: []*api.Study{}}
 	for _, study := range studies {
 		// at least one profile in the study:
+		stats := study.Stats
+		// counts of the study rules' conditions are only for the rules
+		stats.ParticipantCounts = nil
 		resp.Studies = append(resp.Studies, &api.Study{
 			Key:    study.Key,
 			Status: study.Status,
 			Props:  study.Props.ToAPI(),
-			Stats:  study.Stats.ToAPI(),
+			Stats:  stats.ToAPI(),
 		})
 
 	}
@@ -278,7 +281,7 @@ func (s *studyServiceServer) GetParticipantMessages(ctx context.Context, req *ap
 		resp.Messages = append(resp.Messages, &api.StudyMessage{
 			Id:      message.ID,
 			Type:    message.Type,
-			Payload: pState.Flags,
+			Payload: pState.Flags.StringValues(),
 		})
 	}
 	return resp, nil
@@ -95,22 +95,22 @@ func TestGetStudiesForUserEndpoint(t *testing.T) {
 		},
 	}
 
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState1)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[1].Key, pState2)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[1].Key, pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[2].Key, pState3)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[2].Key, pState3)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[3].Key, pState4)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[3].Key, pState4)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
@@ -274,12 +274,12 @@ func TestHasParticipantStateWithConditionEndpoint(t *testing.T) {
 		StudyStatus:   types.PARTICIPANT_STUDY_STATUS_EXITED,
 	}
 
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState1)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState2)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
@@ -442,12 +442,12 @@ func TestGetParticipantMessages(t *testing.T) {
 		},
 	}
 
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState1)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
 	}
-	_, err = s.studyDBservice.SaveParticipantState(testInstanceID, testStudies[0].Key, pState2)
+	_, err = s.studyDBservice.OverwriteParticipantState(testInstanceID, testStudies[0].Key, pState2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err.Error())
 		return
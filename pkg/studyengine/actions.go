@@ -15,25 +15,57 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// DRY_RUN_ARM_PLACEHOLDER is written to the arm flag by ASSIGN_ARM in dry run mode for participants that are not allocated yet
+const DRY_RUN_ARM_PLACEHOLDER = "<would be allocated>"
+
 type StudyDBService interface {
 	FindSurveyResponses(instanceID string, studyKey string, query studydb.ResponseQuery) (responses []types.SurveyResponse, err error)
 	DeleteConfidentialResponses(instanceID string, studyKey string, participantID string, key string) (count int64, err error)
 	SaveResearcherMessage(instanceID string, studyKey string, message types.StudyMessage) error
+	GetRandomisationScheme(instanceID string, studyKey string, schemeKey string) (types.RandomisationScheme, error)
+	FindRandomisationAllocation(instanceID string, studyKey string, schemeKey string, participantID string) (types.RandomisationAllocation, error)
+	AllocateRandomisationArm(instanceID string, studyKey string, scheme types.RandomisationScheme, stratum string, participantID string, allocatedAt int64) (types.RandomisationAllocation, error)
+	GetStudyStats(instanceID string, studyKey string) (types.StudyStats, error)
+	CountParticipantsWithCondition(instanceID string, studyKey string, condition types.ParticipantCondition) (count int64, err error)
 }
 
 type ActionData struct {
-	PState          types.ParticipantState
-	ReportsToCreate map[string]types.Report
+	PState             types.ParticipantState
+	ReportsToCreate    map[string]types.Report
+	ResearcherMessages []types.StudyMessage   // messages to researchers, collected in dry run or deferred mode
+	Variables          map[string]interface{} // values defined with LET, for the evaluation of the current event
+	submissionCounted  bool                   // the submission of the current event is already in the submission history
+
+	ConfidentialResponseRemovals []ConfidentialResponseRemoval // collected in dry run or deferred mode
+	externalCalls                int                           // number of external event handler calls of the evaluation
 }
 
 type ActionConfigs struct {
 	DBService              StudyDBService
 	ExternalServiceConfigs []types.ExternalService
+	DryRun                 bool             // if true, actions with side effects outside the participant state (DB writes, external calls) are skipped
+	Tracer                 *Tracer          // if set, each evaluated action and expression is recorded
+	Now                    func() time.Time // if set, used instead of the package level Now as current time (e.g. to simulate a point in time)
+	Deferred               *DeferredEffects // if set, side effects are collected to be performed after the participant state is saved
+}
+
+// now returns the current time for the evaluation
+func (c ActionConfigs) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return Now()
 }
 
 func ActionEval(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	traceIndex := configs.Tracer.begin(TRACE_ENTRY_ACTION, action.Name)
+	stateBefore := oldState.PState
+	defer func() {
+		configs.Tracer.endAction(traceIndex, stateBefore, newState.PState, err)
+	}()
+
 	if event.Type == "SUBMIT" {
-		oldState, err = updateLastSubmissionForSurvey(oldState, event)
+		oldState, err = updateLastSubmissionForSurvey(oldState, event, configs)
 		if err != nil {
 			return oldState, err
 		}
@@ -46,6 +78,8 @@ func ActionEval(action types.Expression, oldState ActionData, event types.StudyE
 		newState, err = doAction(action, oldState, event, configs)
 	case "IFTHEN":
 		newState, err = ifThenAction(action, oldState, event, configs)
+	case "LET":
+		newState, err = letAction(action, oldState, event, configs)
 	case "UPDATE_STUDY_STATUS":
 		newState, err = updateStudyStatusAction(action, oldState, event, configs)
 	case "START_NEW_STUDY_SESSION":
@@ -54,6 +88,14 @@ func ActionEval(action types.Expression, oldState ActionData, event types.StudyE
 		newState, err = updateFlagAction(action, oldState, event, configs)
 	case "REMOVE_FLAG":
 		newState, err = removeFlagAction(action, oldState, event, configs)
+	case "INCREMENT_FLAG":
+		newState, err = incrementFlagAction(action, oldState, event, configs)
+	case "APPEND_TO_LIST_FLAG":
+		newState, err = appendToListFlagAction(action, oldState, event, configs)
+	case "REMOVE_FROM_LIST_FLAG":
+		newState, err = removeFromListFlagAction(action, oldState, event, configs)
+	case "ASSIGN_ARM":
+		newState, err = assignArmAction(action, oldState, event, configs)
 	case "ADD_NEW_SURVEY":
 		newState, err = addNewSurveyAction(action, oldState, event, configs)
 	case "REMOVE_ALL_SURVEYS":
@@ -68,6 +110,10 @@ func ActionEval(action types.Expression, oldState ActionData, event types.StudyE
 		newState, err = removeAllMessages(action, oldState, event, configs)
 	case "REMOVE_MESSAGES_BY_TYPE":
 		newState, err = removeMessagesByType(action, oldState, event, configs)
+	case "SCHEDULE_EVENT":
+		newState, err = scheduleEvent(action, oldState, event, configs)
+	case "CANCEL_SCHEDULED_EVENT":
+		newState, err = cancelScheduledEvent(action, oldState, event, configs)
 	case "NOTIFY_RESEARCHER":
 		newState, err = notifyResearcher(action, oldState, event, configs)
 	case "INIT_REPORT":
@@ -94,7 +140,7 @@ func ActionEval(action types.Expression, oldState ActionData, event types.StudyE
 	return
 }
 
-func updateLastSubmissionForSurvey(oldState ActionData, event types.StudyEvent) (newState ActionData, err error) {
+func updateLastSubmissionForSurvey(oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
 	if event.Response.Key == "" {
 		return newState, errors.New("no response key found")
@@ -102,17 +148,62 @@ func updateLastSubmissionForSurvey(oldState ActionData, event types.StudyEvent)
 	if newState.PState.LastSubmissions == nil {
 		newState.PState.LastSubmissions = map[string]int64{}
 	}
-	newState.PState.LastSubmissions[event.Response.Key] = Now().Unix()
+	now := configs.now().Unix()
+
+	// this runs for each evaluated action, but the submission is counted once per event
+	if !newState.submissionCounted {
+		history := make(types.SubmissionHistories, len(newState.PState.SubmissionHistory)+1)
+		for k, h := range newState.PState.SubmissionHistory {
+			history[k] = h
+		}
+		h, err := submissionHistoryFor(newState.PState, event.Response.Key, event, configs)
+		if err != nil {
+			// counted from the responses again with the next submission
+			logger.Error.Printf("unexpected error when counting submissions of %s: %v", event.Response.Key, err)
+		}
+		history[event.Response.Key] = h.Add(now)
+		newState.PState.SubmissionHistory = history
+		newState.submissionCounted = true
+	}
+	newState.PState.LastSubmissions[event.Response.Key] = now
 	return
 }
 
+// submissionHistoryFor returns the submission history of the survey. Incomplete histories of surveys submitted before
+// submission histories were recorded are counted from the stored responses of the participant instead; the result is
+// saved with the next submission of the survey. Without DB service, the incomplete history is returned.
+func submissionHistoryFor(pState types.ParticipantState, surveyKey string, event types.StudyEvent, configs ActionConfigs) (types.SubmissionHistory, error) {
+	history := pState.SubmissionHistoryFor(surveyKey)
+	if !history.Incomplete || configs.DBService == nil {
+		return history, nil
+	}
+	responses, err := configs.DBService.FindSurveyResponses(event.InstanceID, event.StudyKey, studydb.ResponseQuery{
+		ParticipantID: pState.ParticipantID,
+		SurveyKey:     surveyKey,
+	})
+	if err != nil {
+		return history, err
+	}
+	if len(responses) == 0 {
+		// responses removed, e.g. with the participant's data
+		return history, nil
+	}
+	timestamps := make([]int64, len(responses))
+	for i, r := range responses {
+		timestamps[i] = r.SubmittedAt
+	}
+	return types.SubmissionHistoryFromTimestamps(timestamps), nil
+}
+
 func checkCondition(condition types.ExpressionArg, EvalContext EvalContext) bool {
 	if !condition.IsExpression() {
 		return condition.Num != 0
 	}
 	val, err := ExpressionEval(*condition.Exp, EvalContext)
 	bVal, ok := val.(bool)
-	return bVal && ok && err == nil
+	result := bVal && ok && err == nil
+	EvalContext.Configs.Tracer.addArg(result)
+	return result
 }
 
 // ifAction is used to conditionally perform actions
@@ -125,6 +216,7 @@ func ifAction(action types.Expression, oldState ActionData, event types.StudyEve
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	var task types.ExpressionArg
 	if checkCondition(action.Data[0], EvalContext) {
@@ -166,6 +258,7 @@ func ifThenAction(action types.Expression, oldState ActionData, event types.Stud
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	if !checkCondition(action.Data[0], EvalContext) {
 		return
@@ -181,6 +274,41 @@ func ifThenAction(action types.Expression, oldState ActionData, event types.Stud
 	return
 }
 
+// letAction evaluates the expression once and stores the value as variable for the evaluation of the current event, to be read with getVar
+func letAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	if len(action.Data) != 2 {
+		return newState, errors.New("letAction must have exactly two arguments")
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: newState.PState,
+		Configs:          configs,
+		Variables:        newState.Variables,
+	}
+	k, err := EvalContext.expressionArgResolver(action.Data[0])
+	if err != nil {
+		return newState, err
+	}
+	name, ok := k.(string)
+	if !ok || name == "" {
+		return newState, errors.New("could not parse variable name")
+	}
+	value, err := EvalContext.expressionArgResolver(action.Data[1])
+	if err != nil {
+		return newState, err
+	}
+
+	// copy, so that the variables of the previous state are not modified
+	variables := make(map[string]interface{}, len(oldState.Variables)+1)
+	for key, v := range oldState.Variables {
+		variables[key] = v
+	}
+	variables[name] = value
+	newState.Variables = variables
+	return
+}
+
 // updateStudyStatusAction is used to update if user is active in the study
 func updateStudyStatusAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
@@ -191,6 +319,7 @@ func updateStudyStatusAction(action types.Expression, oldState ActionData, event
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -215,20 +344,22 @@ func startNewStudySession(action types.Expression, oldState ActionData, event ty
 		logger.Error.Println(err)
 	}
 
-	newState.PState.CurrentStudySession = strconv.FormatInt(time.Now().Unix(), 16) + hex.EncodeToString(bytes)
+	newState.PState.CurrentStudySession = strconv.FormatInt(configs.now().Unix(), 16) + hex.EncodeToString(bytes)
 	return
 }
 
-// updateFlagAction is used to update one of the string flags from the participant state
+// updateFlagAction is used to update one of the flags from the participant state, the flag keeps the type of the value
+// unless a dtype is given as optional third argument
 func updateFlagAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
-	if len(action.Data) != 2 {
-		return newState, errors.New("updateFlagAction must have exactly two arguments")
+	if len(action.Data) < 2 || len(action.Data) > 4 {
+		return newState, errors.New("updateFlagAction must have two to four arguments")
 	}
 	EvalContext := EvalContext{
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -244,28 +375,80 @@ func updateFlagAction(action types.Expression, oldState ActionData, event types.
 		return newState, errors.New("could not parse flag key")
 	}
 
-	value := ""
-	switch flagVal := v.(type) {
-	case string:
-		value = flagVal
-	case float64:
-		value = fmt.Sprintf("%f", flagVal)
-	case bool:
-		value = fmt.Sprintf("%t", flagVal)
+	value, err := types.FlagValueFromInterface(v)
+	if err != nil {
+		return newState, err
+	}
+	if len(action.Data) > 2 {
+		d, err := EvalContext.expressionArgResolver(action.Data[2])
+		if err != nil {
+			return newState, err
+		}
+		dtype, ok := d.(string)
+		if !ok {
+			return newState, errors.New("could not parse flag dtype")
+		}
+		// an empty dtype keeps the type of the value
+		if dtype != "" {
+			value, err = convertFlagValue(value, dtype)
+			if err != nil {
+				return newState, err
+			}
+		}
 	}
 
-	if newState.PState.Flags == nil {
-		newState.PState.Flags = map[string]string{}
-	} else {
-		newState.PState.Flags = make(map[string]string)
-		for k, v := range oldState.PState.Flags {
-			newState.PState.Flags[k] = v
+	now := configs.now().Unix()
+	info := types.FlagInfo{Key: key, SetAt: now}
+	if len(action.Data) > 3 {
+		t, err := EvalContext.expressionArgResolver(action.Data[3])
+		if err != nil {
+			return newState, err
+		}
+		ttl, ok := t.(float64)
+		if !ok || ttl < 0 {
+			return newState, errors.New("flag TTL should be a positive number of seconds")
+		}
+		if ttl > 0 {
+			info.ExpiresAt = now + int64(ttl)
 		}
 	}
-	newState.PState.Flags[key] = value
+
+	newState.PState = setFlag(newState.PState, key, value, info)
 	return
 }
 
+// setFlag returns the participant state with the flag and its info set, the flags of the given state are not modified
+func setFlag(pState types.ParticipantState, key string, value types.FlagValue, info types.FlagInfo) types.ParticipantState {
+	flags := make(types.ParticipantFlags, len(pState.Flags)+1)
+	for k, v := range pState.Flags {
+		flags[k] = v
+	}
+	flags[key] = value
+	pState.Flags = flags
+	pState.FlagInfos = pState.FlagInfos.Set(info)
+	return pState
+}
+
+// updatedFlagInfo returns the info of a flag modified at now, the expiry of the flag is kept
+func updatedFlagInfo(pState types.ParticipantState, key string, now int64) types.FlagInfo {
+	info, _ := pState.FlagInfos.Get(key)
+	return types.FlagInfo{Key: key, SetAt: now, ExpiresAt: info.ExpiresAt}
+}
+
+// convertFlagValue converts a flag value to the dtype, numbers can be stored as timestamps and all values as strings
+func convertFlagValue(value types.FlagValue, dtype string) (types.FlagValue, error) {
+	switch {
+	case dtype == value.DType:
+		return value, nil
+	case dtype == types.FLAG_DTYPE_STRING:
+		return types.StringFlag(value.String()), nil
+	case dtype == types.FLAG_DTYPE_TIMESTAMP && value.DType == types.FLAG_DTYPE_NUMBER:
+		return types.TimestampFlag(int64(value.Num)), nil
+	default:
+		return value, fmt.Errorf("cannot store value of type %s as %s flag", value.DType, dtype)
+	}
+}
+
 // removeFlagAction is used to update one of the string flags from the participant state
 func removeFlagAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
@@ -276,6 +459,7 @@ func removeFlagAction(action types.Expression, oldState ActionData, event types.
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -288,13 +472,213 @@ func removeFlagAction(action types.Expression, oldState ActionData, event types.
 	}
 
 	if newState.PState.Flags != nil {
-		newState.PState.Flags = make(map[string]string)
+		newState.PState.Flags = make(types.ParticipantFlags)
 		for k, v := range oldState.PState.Flags {
 			newState.PState.Flags[k] = v
 		}
 	}
 
 	delete(newState.PState.Flags, key)
+	if _, ok := oldState.PState.FlagInfos.Get(key); ok {
+		newState.PState.FlagInfos = oldState.PState.FlagInfos.Remove(key)
+	}
+	return
+}
+
+// incrementFlagAction adds the optional amount (default 1) to a number flag, a missing flag is counted from 0
+func incrementFlagAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	if len(action.Data) != 1 && len(action.Data) != 2 {
+		return newState, errors.New("incrementFlagAction must have one or two arguments")
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: newState.PState,
+		Configs:          configs,
+		Variables:        newState.Variables,
+	}
+	k, err := EvalContext.expressionArgResolver(action.Data[0])
+	if err != nil {
+		return newState, err
+	}
+	key, ok := k.(string)
+	if !ok {
+		return newState, errors.New("could not parse flag key")
+	}
+	amount := 1.0
+	if len(action.Data) == 2 {
+		a, err := EvalContext.expressionArgResolver(action.Data[1])
+		if err != nil {
+			return newState, err
+		}
+		amount, ok = a.(float64)
+		if !ok {
+			return newState, errors.New("could not parse amount")
+		}
+	}
+
+	count := 0.0
+	if value, ok := newState.PState.Flags[key]; ok {
+		switch value.DType {
+		case types.FLAG_DTYPE_NUMBER:
+			count = value.Num
+		case types.FLAG_DTYPE_STRING:
+			// counters stored as string flags before typed flags
+			count, err = strconv.ParseFloat(value.Str, 64)
+			if err != nil {
+				return newState, fmt.Errorf("flag %s is not a number", key)
+			}
+		default:
+			return newState, fmt.Errorf("flag %s is not a number", key)
+		}
+	}
+
+	now := configs.now().Unix()
+	newState.PState = setFlag(newState.PState, key, types.NumberFlag(count+amount), updatedFlagInfo(newState.PState, key, now))
+	return
+}
+
+// resolveListFlagArgs resolves the key and the item of list flag actions, and returns the items of the current list flag
+func resolveListFlagArgs(actionName string, action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (key string, item interface{}, items []types.FlagValue, err error) {
+	if len(action.Data) != 2 {
+		return key, item, items, fmt.Errorf("%s must have exactly two arguments", actionName)
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: oldState.PState,
+		Configs:          configs,
+		Variables:        oldState.Variables,
+	}
+	k, err := EvalContext.expressionArgResolver(action.Data[0])
+	if err != nil {
+		return key, item, items, err
+	}
+	key, ok := k.(string)
+	if !ok {
+		return key, item, items, errors.New("could not parse flag key")
+	}
+	item, err = EvalContext.expressionArgResolver(action.Data[1])
+	if err != nil {
+		return key, item, items, err
+	}
+
+	if value, ok := oldState.PState.Flags[key]; ok {
+		if value.DType != types.FLAG_DTYPE_LIST {
+			return key, item, items, fmt.Errorf("flag %s is not a list", key)
+		}
+		items = value.List
+	}
+	return key, item, items, nil
+}
+
+// appendToListFlagAction appends a value to a list flag, a missing flag is created
+func appendToListFlagAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	key, item, items, err := resolveListFlagArgs("appendToListFlagAction", action, oldState, event, configs)
+	if err != nil {
+		return newState, err
+	}
+	value, err := types.FlagValueFromInterface(item)
+	if err != nil {
+		return newState, err
+	}
+
+	newItems := make([]types.FlagValue, len(items), len(items)+1)
+	copy(newItems, items)
+	newItems = append(newItems, value)
+
+	now := configs.now().Unix()
+	newState.PState = setFlag(newState.PState, key, types.ListFlag(newItems...), updatedFlagInfo(newState.PState, key, now))
+	return
+}
+
+// removeFromListFlagAction removes all items matching the value from a list flag, nothing is done if the flag is missing
+func removeFromListFlagAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	key, item, items, err := resolveListFlagArgs("removeFromListFlagAction", action, oldState, event, configs)
+	if err != nil {
+		return newState, err
+	}
+	if _, ok := oldState.PState.Flags[key]; !ok {
+		return
+	}
+
+	newItems := []types.FlagValue{}
+	for _, v := range items {
+		matches, err := flagValueMatches(v, item)
+		if err != nil {
+			return newState, err
+		}
+		if !matches {
+			newItems = append(newItems, v)
+		}
+	}
+	if len(newItems) == len(items) {
+		return
+	}
+
+	now := configs.now().Unix()
+	newState.PState = setFlag(newState.PState, key, types.ListFlag(newItems...), updatedFlagInfo(newState.PState, key, now))
+	return
+}
+
+// assignArmAction allocates the participant to an arm of a randomisation scheme, stratified by the values of the given flags,
+// and writes the arm to the scheme's flag. A participant keeps the arm of the first allocation.
+func assignArmAction(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	if len(action.Data) < 1 {
+		return newState, errors.New("assignArmAction must have at least one argument")
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: newState.PState,
+		Configs:          configs,
+		Variables:        newState.Variables,
+	}
+	keys := make([]string, len(action.Data))
+	for i, d := range action.Data {
+		k, err := EvalContext.expressionArgResolver(d)
+		if err != nil {
+			return newState, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return newState, errors.New("could not parse arguments")
+		}
+		keys[i] = key
+	}
+	schemeKey := keys[0]
+	strataFlags := keys[1:]
+
+	if configs.DBService == nil {
+		return newState, errors.New("assignArmAction: DBService is not available")
+	}
+	scheme, err := configs.DBService.GetRandomisationScheme(event.InstanceID, event.StudyKey, schemeKey)
+	if err != nil {
+		return newState, err
+	}
+	stratum := types.RandomisationStratum(strataFlags, newState.PState.Flags)
+
+	var allocation types.RandomisationAllocation
+	if configs.DryRun {
+		allocation, err = configs.DBService.FindRandomisationAllocation(event.InstanceID, event.StudyKey, schemeKey, newState.PState.ParticipantID)
+		if err != nil {
+			// the arm is unknown until the participant is allocated, the placeholder shows the change in the state diff
+			logger.Debug.Printf("dry run: skip allocating arm of %s", schemeKey)
+			allocation = types.RandomisationAllocation{Arm: DRY_RUN_ARM_PLACEHOLDER}
+			err = nil
+		}
+	} else {
+		allocation, err = configs.DBService.AllocateRandomisationArm(event.InstanceID, event.StudyKey, scheme, stratum, newState.PState.ParticipantID, configs.now().Unix())
+		if err != nil {
+			logger.Error.Printf("unexpected error when allocating arm of %s: %v", schemeKey, err)
+			return newState, err
+		}
+	}
+
+	flagKey := scheme.FlagKey()
+	now := configs.now().Unix()
+	newState.PState = setFlag(newState.PState, flagKey, types.StringFlag(allocation.Arm), updatedFlagInfo(newState.PState, flagKey, now))
 	return
 }
 
@@ -308,6 +692,7 @@ func addNewSurveyAction(action types.Expression, oldState ActionData, event type
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -369,6 +754,7 @@ func removeSurveyByKey(action types.Expression, oldState ActionData, event types
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -429,6 +815,7 @@ func removeSurveysByKey(action types.Expression, oldState ActionData, event type
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -461,6 +848,7 @@ func addMessage(action types.Expression, oldState ActionData, event types.StudyE
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	arg1, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -508,6 +896,7 @@ func removeMessagesByType(action types.Expression, oldState ActionData, event ty
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -530,6 +919,77 @@ func removeMessagesByType(action types.Expression, oldState ActionData, event ty
 	return
 }
 
+// scheduleEvent adds a named timer event to the participant state, an existing event with the same name is replaced
+func scheduleEvent(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	if len(action.Data) != 2 {
+		return newState, errors.New("scheduleEvent must have exactly two arguments")
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: newState.PState,
+		Configs:          configs,
+		Variables:        newState.Variables,
+	}
+	arg1, err := EvalContext.expressionArgResolver(action.Data[0])
+	if err != nil {
+		return newState, err
+	}
+	arg2, err := EvalContext.expressionArgResolver(action.Data[1])
+	if err != nil {
+		return newState, err
+	}
+
+	name, ok1 := arg1.(string)
+	timestamp, ok2 := arg2.(float64)
+	if !ok1 || !ok2 || name == "" {
+		return newState, errors.New("could not parse arguments")
+	}
+
+	scheduledEvents := []types.ScheduledEvent{}
+	for _, e := range oldState.PState.ScheduledEvents {
+		if e.Name != name {
+			scheduledEvents = append(scheduledEvents, e)
+		}
+	}
+	newState.PState.ScheduledEvents = append(scheduledEvents, types.ScheduledEvent{
+		Name:  name,
+		DueAt: int64(timestamp),
+	})
+	return
+}
+
+// cancelScheduledEvent removes the named timer event from the participant state
+func cancelScheduledEvent(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
+	newState = oldState
+	if len(action.Data) != 1 {
+		return newState, errors.New("cancelScheduledEvent must have exactly one argument")
+	}
+	EvalContext := EvalContext{
+		Event:            event,
+		ParticipantState: newState.PState,
+		Configs:          configs,
+		Variables:        newState.Variables,
+	}
+	arg1, err := EvalContext.expressionArgResolver(action.Data[0])
+	if err != nil {
+		return newState, err
+	}
+	name, ok := arg1.(string)
+	if !ok {
+		return newState, errors.New("could not parse arguments")
+	}
+
+	scheduledEvents := []types.ScheduledEvent{}
+	for _, e := range oldState.PState.ScheduledEvents {
+		if e.Name != name {
+			scheduledEvents = append(scheduledEvents, e)
+		}
+	}
+	newState.PState.ScheduledEvents = scheduledEvents
+	return
+}
+
 // notifyResearcher can save a specific message with a payload, that should be sent out to the researcher
 func notifyResearcher(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
@@ -540,6 +1000,7 @@ func notifyResearcher(action types.Expression, oldState ActionData, event types.
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -581,6 +1042,11 @@ func notifyResearcher(action types.Expression, oldState ActionData, event types.
 		Payload:       payload,
 	}
 
+	if configs.DryRun || configs.Deferred != nil {
+		logger.Debug.Printf("skip saving researcher message %s until the participant state is saved", messageType)
+		newState.ResearcherMessages = append(newState.ResearcherMessages, message)
+		return
+	}
 	err = configs.DBService.SaveResearcherMessage(event.InstanceID, event.StudyKey, message)
 	if err != nil {
 		logger.Error.Printf("unexpected error when saving researcher message: %v", err)
@@ -598,6 +1064,7 @@ func initReport(action types.Expression, oldState ActionData, event types.StudyE
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -612,7 +1079,7 @@ func initReport(action types.Expression, oldState ActionData, event types.StudyE
 	newState.ReportsToCreate[reportKey] = types.Report{
 		Key:           reportKey,
 		ParticipantID: oldState.PState.ParticipantID,
-		Timestamp:     Now().Truncate(time.Minute).Unix(),
+		Timestamp:     configs.now().Truncate(time.Minute).Unix(),
 	}
 	return
 }
@@ -627,6 +1094,7 @@ func updateReportData(action types.Expression, oldState ActionData, event types.
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -645,7 +1113,7 @@ func updateReportData(action types.Expression, oldState ActionData, event types.
 		report = types.Report{
 			Key:           reportKey,
 			ParticipantID: oldState.PState.ParticipantID,
-			Timestamp:     Now().Truncate(time.Minute).Unix(),
+			Timestamp:     configs.now().Truncate(time.Minute).Unix(),
 		}
 	}
 
@@ -729,6 +1197,7 @@ func removeReportData(action types.Expression, oldState ActionData, event types.
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -783,6 +1252,7 @@ func cancelReport(action types.Expression, oldState ActionData, event types.Stud
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -811,6 +1281,7 @@ func removeConfidentialResponseByKey(action types.Expression, oldState ActionDat
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -822,6 +1293,14 @@ func removeConfidentialResponseByKey(action types.Expression, oldState ActionDat
 		return newState, errors.New("could not parse arguments")
 	}
 
+	if configs.DryRun || configs.Deferred != nil {
+		logger.Debug.Printf("skip removing confidential responses for %s until the participant state is saved", key)
+		newState.ConfidentialResponseRemovals = append(newState.ConfidentialResponseRemovals, ConfidentialResponseRemoval{
+			ParticipantID: event.ParticipantIDForConfidentialResponses,
+			Key:           key,
+		})
+		return
+	}
 	_, err = configs.DBService.DeleteConfidentialResponses(event.InstanceID, event.StudyKey, event.ParticipantIDForConfidentialResponses, key)
 	if err != nil {
 		logger.Error.Printf("unexpected error: %v", err)
@@ -832,6 +1311,13 @@ func removeConfidentialResponseByKey(action types.Expression, oldState ActionDat
 // delete confidential responses for this participant
 func removeAllConfidentialResponses(action types.Expression, oldState ActionData, event types.StudyEvent, configs ActionConfigs) (newState ActionData, err error) {
 	newState = oldState
+	if configs.DryRun || configs.Deferred != nil {
+		logger.Debug.Printf("skip removing confidential responses until the participant state is saved")
+		newState.ConfidentialResponseRemovals = append(newState.ConfidentialResponseRemovals, ConfidentialResponseRemoval{
+			ParticipantID: event.ParticipantIDForConfidentialResponses,
+		})
+		return
+	}
 	_, err = configs.DBService.DeleteConfidentialResponses(event.InstanceID, event.StudyKey, event.ParticipantIDForConfidentialResponses, "")
 	if err != nil {
 		logger.Error.Printf("unexpected error: %v", err)
@@ -852,6 +1338,7 @@ func externalEventHandler(action types.Expression, oldState ActionData, event ty
 		Event:            event,
 		ParticipantState: newState.PState,
 		Configs:          configs,
+		Variables:        newState.Variables,
 	}
 	k, err := EvalContext.expressionArgResolver(action.Data[0])
 	if err != nil {
@@ -880,6 +1367,11 @@ func externalEventHandler(action types.Expression, oldState ActionData, event ty
 		serviceConfig.URL = fmt.Sprintf("%s/%s", serviceConfig.URL, route)
 	}
 
+	if configs.DryRun {
+		logger.Debug.Printf("dry run: skip calling external service '%s'", serviceName)
+		return
+	}
+
 	payload := ExternalEventPayload{
 		ParticipantState: newState.PState,
 		EventType:        event.Type,
@@ -892,7 +1384,10 @@ func externalEventHandler(action types.Expression, oldState ActionData, event ty
 		Timeout:    time.Duration(serviceConfig.Timeout) * time.Second,
 		mTLSConfig: serviceConfig.MutualTLSConfig,
 	}
-	response, err := runHTTPcall(serviceConfig.URL, payload, clientConf)
+	newState.externalCalls += 1
+	response, err := configs.Deferred.callExternalService(newState.externalCalls, event.Type, serviceConfig.URL, func() (map[string]interface{}, error) {
+		return runHTTPcall(serviceConfig.URL, payload, clientConf)
+	})
 	if err != nil {
 		logger.Error.Printf("error when handling response for '%s': %v", serviceName, err)
 		return newState, err
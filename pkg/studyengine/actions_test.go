@@ -1,6 +1,9 @@
 package studyengine
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
@@ -15,8 +18,8 @@ func TestActions(t *testing.T) {
 		PState: types.ParticipantState{
 			ParticipantID: "participant1234",
 			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-			Flags: map[string]string{
-				"health": "test",
+			Flags: types.ParticipantFlags{
+				"health": types.StringFlag("test"),
 			},
 		},
 		ReportsToCreate: map[string]types.Report{},
@@ -160,7 +163,7 @@ func TestActions(t *testing.T) {
 			t.Error("could not find new flag")
 			return
 		}
-		if v != action.Data[1].Str {
+		if !v.Equal(types.StringFlag(action.Data[1].Str)) {
 			t.Errorf("updated status error -> expected: %s, have: %s", action.Data[1].Str, v)
 		}
 	})
@@ -182,12 +185,166 @@ func TestActions(t *testing.T) {
 			t.Error("could not find new flag")
 			return
 		}
-		res, err := strconv.ParseFloat(v, 64)
+		if !v.Equal(types.NumberFlag(action.Data[1].Num)) {
+			t.Errorf("updated status error -> expected: %f, have: %v", action.Data[1].Num, v)
+		}
+		res, err := strconv.ParseFloat(v.String(), 64)
 		if err != nil {
 			t.Errorf("unexpected error: %s", err.Error())
 		}
 		if res != action.Data[1].Num {
-			t.Errorf("updated status error -> expected: %s, have: %s", action.Data[1].Str, v)
+			t.Errorf("unexpected string value: %s", v.String())
+		}
+	})
+
+	t.Run("UPDATE_FLAG with bool", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "keyBool"},
+				{DType: "exp", Exp: &types.Expression{Name: "gt", Data: []types.ExpressionArg{
+					{DType: "num", Num: 2},
+					{DType: "num", Num: 1},
+				}}},
+			},
+		}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if v := newState.PState.Flags["keyBool"]; !v.Equal(types.BoolFlag(true)) {
+			t.Errorf("unexpected flag value: %v", v)
+		}
+	})
+
+	t.Run("UPDATE_FLAG with dtype timestamp", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "keyTs"},
+				{DType: "num", Num: 1700000000},
+				{DType: "str", Str: "timestamp"},
+			},
+		}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if v := newState.PState.Flags["keyTs"]; !v.Equal(types.TimestampFlag(1700000000)) {
+			t.Errorf("unexpected flag value: %v", v)
+		}
+	})
+
+	t.Run("UPDATE_FLAG with dtype string", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "keyStr"},
+				{DType: "num", Num: 3},
+				{DType: "str", Str: "string"},
+			},
+		}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if v := newState.PState.Flags["keyStr"]; !v.Equal(types.StringFlag("3.000000")) {
+			t.Errorf("unexpected flag value: %v", v)
+		}
+	})
+
+	t.Run("START_NEW_STUDY_SESSION with configured time", func(t *testing.T) {
+		action := types.Expression{Name: "START_NEW_STUDY_SESSION"}
+		configs := ActionConfigs{Now: func() time.Time { return time.Unix(1000000, 0) }}
+		newState, err := ActionEval(action, actionData, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		session := newState.PState.CurrentStudySession
+		if len(session) != 13 || session[:5] != strconv.FormatInt(1000000, 16) {
+			t.Errorf("unexpected study session: %s", session)
+		}
+	})
+
+	t.Run("UPDATE_FLAG records set time and expiry", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "episode"},
+				{DType: "str", Str: "fever"},
+				{DType: "str", Str: ""},
+				{DType: "num", Num: 3600},
+			},
+		}
+		configs := ActionConfigs{Now: func() time.Time { return time.Unix(1000000, 0) }}
+		newState, err := ActionEval(action, actionData, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if v := newState.PState.Flags["episode"]; !v.Equal(types.StringFlag("fever")) {
+			t.Errorf("unexpected flag value: %v", v)
+		}
+		info, ok := newState.PState.FlagInfos.Get("episode")
+		if !ok || info.SetAt != 1000000 || info.ExpiresAt != 1003600 {
+			t.Errorf("unexpected flag info: %v", info)
+		}
+
+		// updating without TTL removes the expiry
+		action.Data = action.Data[:2]
+		configs.Now = func() time.Time { return time.Unix(1000500, 0) }
+		newState, err = ActionEval(action, newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		info, ok = newState.PState.FlagInfos.Get("episode")
+		if !ok || info.SetAt != 1000500 || info.ExpiresAt != 0 || len(newState.PState.FlagInfos) != 1 {
+			t.Errorf("unexpected flag infos: %v", newState.PState.FlagInfos)
+		}
+
+		newState, err = ActionEval(types.Expression{
+			Name: "REMOVE_FLAG",
+			Data: []types.ExpressionArg{{DType: "str", Str: "episode"}},
+		}, newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if _, ok := newState.PState.FlagInfos.Get("episode"); ok {
+			t.Errorf("flag info should be removed: %v", newState.PState.FlagInfos)
+		}
+	})
+
+	t.Run("UPDATE_FLAG with negative TTL", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "episode"},
+				{DType: "str", Str: "fever"},
+				{DType: "str", Str: ""},
+				{DType: "num", Num: -1},
+			},
+		}
+		_, err := ActionEval(action, actionData, event, testActionConfig)
+		if err == nil {
+			t.Error("should return an error")
+		}
+	})
+
+	t.Run("UPDATE_FLAG with wrong dtype", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_FLAG",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "keyWrong"},
+				{DType: "str", Str: "abc"},
+				{DType: "str", Str: "number"},
+			},
+		}
+		_, err := ActionEval(action, actionData, event, testActionConfig)
+		if err == nil {
+			t.Error("should return an error")
 		}
 	})
 
@@ -520,6 +677,60 @@ func TestActions(t *testing.T) {
 			return
 		}
 	})
+
+	t.Run("SCHEDULE_EVENT", func(t *testing.T) {
+		action := types.Expression{
+			Name: "SCHEDULE_EVENT",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "reminder"},
+				{DType: "num", Num: 1000},
+			},
+		}
+		actionData.PState = types.ParticipantState{
+			ScheduledEvents: []types.ScheduledEvent{
+				{Name: "reminder", DueAt: 500},
+				{Name: "followup", DueAt: 2000},
+			},
+		}
+
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if len(newState.PState.ScheduledEvents) != 2 {
+			t.Errorf("unexpected number of scheduled events: %d", len(newState.PState.ScheduledEvents))
+			return
+		}
+		for _, e := range newState.PState.ScheduledEvents {
+			if e.Name == "reminder" && e.DueAt != 1000 {
+				t.Errorf("unexpected due time: %d", e.DueAt)
+			}
+		}
+
+		action.Data[0].Str = ""
+		_, err = ActionEval(action, actionData, event, testActionConfig)
+		if err == nil {
+			t.Error("should return error for empty event name")
+		}
+		actionData = newState
+	})
+
+	t.Run("CANCEL_SCHEDULED_EVENT", func(t *testing.T) {
+		action := types.Expression{
+			Name: "CANCEL_SCHEDULED_EVENT",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "reminder"},
+			},
+		}
+
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+		}
+		if len(newState.PState.ScheduledEvents) != 1 || newState.PState.ScheduledEvents[0].Name != "followup" {
+			t.Errorf("unexpected scheduled events: %v", newState.PState.ScheduledEvents)
+		}
+	})
 }
 
 func TestReportActions(t *testing.T) {
@@ -658,3 +869,464 @@ func TestReportActions(t *testing.T) {
 		}
 	})
 }
+
+func TestDryRunActions(t *testing.T) {
+	// no DB service configured: dry run must not access it
+	testActionConfig := ActionConfigs{DryRun: true}
+
+	actionData := ActionData{
+		PState: types.ParticipantState{
+			ParticipantID: "participant1234",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		},
+		ReportsToCreate: map[string]types.Report{},
+	}
+	event := types.StudyEvent{Type: "TIMER"}
+
+	t.Run("NOTIFY_RESEARCHER", func(t *testing.T) {
+		action := types.Expression{
+			Name: "NOTIFY_RESEARCHER",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "testmessage"},
+			},
+		}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if newState.PState.StudyStatus != types.PARTICIPANT_STUDY_STATUS_ACTIVE {
+			t.Error("participant state should not change")
+		}
+		if len(newState.ResearcherMessages) != 1 || newState.ResearcherMessages[0].Type != "testmessage" {
+			t.Errorf("researcher message should be collected: %v", newState.ResearcherMessages)
+		}
+	})
+
+	t.Run("REMOVE_CONFIDENTIAL_RESPONSE_BY_KEY", func(t *testing.T) {
+		action := types.Expression{
+			Name: "REMOVE_CONFIDENTIAL_RESPONSE_BY_KEY",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: "key1"},
+			},
+		}
+		_, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("REMOVE_ALL_CONFIDENTIAL_RESPONSES", func(t *testing.T) {
+		action := types.Expression{
+			Name: "REMOVE_ALL_CONFIDENTIAL_RESPONSES",
+		}
+		_, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("state changes are still applied", func(t *testing.T) {
+		action := types.Expression{
+			Name: "UPDATE_STUDY_STATUS",
+			Data: []types.ExpressionArg{
+				{DType: "str", Str: types.PARTICIPANT_STUDY_STATUS_EXITED},
+			},
+		}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		diff := types.DiffParticipantStates(actionData.PState, newState.PState)
+		if diff.StudyStatus == nil || diff.StudyStatus.NewValue != types.PARTICIPANT_STUDY_STATUS_EXITED {
+			t.Errorf("unexpected diff: %v", diff)
+		}
+	})
+}
+
+func TestDeferredEffects(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	deferred := NewDeferredEffects()
+	testActionConfig := ActionConfigs{
+		DBService:              MockStudyDBService{},
+		ExternalServiceConfigs: []types.ExternalService{{Name: "testservice", URL: server.URL, Timeout: 5}},
+		Deferred:               deferred,
+	}
+	event := types.StudyEvent{Type: "TIMER", ParticipantIDForConfidentialResponses: "confidential1234"}
+	rules := []types.Expression{
+		{Name: "NOTIFY_RESEARCHER", Data: []types.ExpressionArg{{DType: "str", Str: "testmessage"}}},
+		{Name: "REMOVE_CONFIDENTIAL_RESPONSE_BY_KEY", Data: []types.ExpressionArg{{DType: "str", Str: "key1"}}},
+		{Name: "EXTERNAL_EVENT_HANDLER", Data: []types.ExpressionArg{{DType: "str", Str: "testservice"}}},
+	}
+
+	// evaluated twice, as after a conflicting update of the participant state
+	for i := 0; i < 2; i++ {
+		actionData := ActionData{
+			PState:          types.ParticipantState{ParticipantID: "participant1234"},
+			ReportsToCreate: map[string]types.Report{},
+		}
+		for _, rule := range rules {
+			var err error
+			actionData, err = ActionEval(rule, actionData, event, testActionConfig)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(actionData.ResearcherMessages) != 1 || actionData.ResearcherMessages[0].Type != "testmessage" {
+			t.Errorf("researcher message should be collected: %v", actionData.ResearcherMessages)
+		}
+		if len(actionData.ConfidentialResponseRemovals) != 1 ||
+			actionData.ConfidentialResponseRemovals[0] != (ConfidentialResponseRemoval{ParticipantID: "confidential1234", Key: "key1"}) {
+			t.Errorf("removal should be collected: %v", actionData.ConfidentialResponseRemovals)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("external service should be called once, got %d calls", calls)
+	}
+}
+
+func TestLetAction(t *testing.T) {
+	testActionConfig := ActionConfigs{}
+	event := types.StudyEvent{Type: "TIMER"}
+	actionData := ActionData{
+		PState: types.ParticipantState{
+			ParticipantID: "participant1234",
+			Flags:         types.ParticipantFlags{},
+		},
+		ReportsToCreate: map[string]types.Report{},
+	}
+
+	t.Run("with missing arguments", func(t *testing.T) {
+		action := types.Expression{Name: "LET", Data: []types.ExpressionArg{
+			{DType: "str", Str: "x"},
+		}}
+		_, err := ActionEval(action, actionData, event, testActionConfig)
+		if err == nil {
+			t.Error("should return error")
+		}
+	})
+
+	t.Run("with expression error", func(t *testing.T) {
+		action := types.Expression{Name: "LET", Data: []types.ExpressionArg{
+			{DType: "str", Str: "x"},
+			{DType: "exp", Exp: &types.Expression{Name: "div", Data: []types.ExpressionArg{
+				{DType: "num", Num: 1}, {DType: "num", Num: 0},
+			}}},
+		}}
+		newState, err := ActionEval(action, actionData, event, testActionConfig)
+		if err == nil {
+			t.Error("should return error")
+		}
+		if _, ok := newState.Variables["x"]; ok {
+			t.Error("variable should not be defined")
+		}
+	})
+
+	t.Run("variables are available in following rules", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "LET", Data: []types.ExpressionArg{
+				{DType: "str", Str: "score"},
+				{DType: "exp", Exp: &types.Expression{Name: "sum", Data: []types.ExpressionArg{
+					{DType: "num", Num: 2}, {DType: "num", Num: 3},
+				}}},
+			}},
+			{Name: "IFTHEN", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "gt", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "getVar", Data: []types.ExpressionArg{{DType: "str", Str: "score"}}}},
+					{DType: "num", Num: 4},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{
+					{DType: "str", Str: "score"},
+					{DType: "exp", Exp: &types.Expression{Name: "getVar", Data: []types.ExpressionArg{{DType: "str", Str: "score"}}}},
+				}}},
+			}},
+		}
+		newState := actionData
+		for _, rule := range rules {
+			var err error
+			newState, err = ActionEval(rule, newState, event, testActionConfig)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+		if !newState.PState.Flags["score"].Equal(types.NumberFlag(5)) {
+			t.Errorf("unexpected flags: %v", newState.PState.Flags)
+		}
+		if actionData.Variables != nil {
+			t.Errorf("variables of the previous state should not be modified: %v", actionData.Variables)
+		}
+	})
+
+	t.Run("redefine variable", func(t *testing.T) {
+		state := actionData
+		for _, value := range []string{"a", "b"} {
+			var err error
+			state, err = ActionEval(types.Expression{Name: "LET", Data: []types.ExpressionArg{
+				{DType: "str", Str: "x"},
+				{DType: "str", Str: value},
+			}}, state, event, testActionConfig)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+		if state.Variables["x"] != "b" {
+			t.Errorf("unexpected variables: %v", state.Variables)
+		}
+	})
+}
+
+func TestCounterAndListFlagActions(t *testing.T) {
+	event := types.StudyEvent{Type: "SUBMIT", Response: types.SurveyResponse{Key: "weekly"}}
+	configs := ActionConfigs{Now: func() time.Time { return time.Unix(1000000, 0) }}
+	flagAction := func(name string, args ...types.ExpressionArg) types.Expression {
+		return types.Expression{Name: name, Data: args}
+	}
+	str := func(v string) types.ExpressionArg { return types.ExpressionArg{DType: "str", Str: v} }
+	num := func(v float64) types.ExpressionArg { return types.ExpressionArg{DType: "num", Num: v} }
+
+	t.Run("INCREMENT_FLAG", func(t *testing.T) {
+		actionData := ActionData{PState: types.ParticipantState{
+			Flags:     types.ParticipantFlags{"legacy": types.StringFlag("2.000000"), "group": types.StringFlag("a")},
+			FlagInfos: types.FlagInfos{{Key: "legacy", SetAt: 10, ExpiresAt: 2000000}},
+		}}
+
+		newState, err := ActionEval(flagAction("INCREMENT_FLAG", str("count")), actionData, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		newState, err = ActionEval(flagAction("INCREMENT_FLAG", str("count"), num(2.5)), newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if v := newState.PState.Flags["count"]; !v.Equal(types.NumberFlag(3.5)) {
+			t.Errorf("unexpected counter: %v", v)
+		}
+
+		newState, err = ActionEval(flagAction("INCREMENT_FLAG", str("legacy")), newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if v := newState.PState.Flags["legacy"]; !v.Equal(types.NumberFlag(3)) {
+			t.Errorf("unexpected counter: %v", v)
+		}
+		if info, _ := newState.PState.FlagInfos.Get("legacy"); info.SetAt != 1000000 || info.ExpiresAt != 2000000 {
+			t.Errorf("unexpected flag info: %v", info)
+		}
+		if _, ok := actionData.PState.Flags["count"]; ok {
+			t.Error("original state should not be modified")
+		}
+
+		if _, err := ActionEval(flagAction("INCREMENT_FLAG", str("group")), newState, event, configs); err == nil {
+			t.Error("should return an error for a non-numeric flag")
+		}
+	})
+
+	t.Run("APPEND_TO_LIST_FLAG and REMOVE_FROM_LIST_FLAG", func(t *testing.T) {
+		actionData := ActionData{PState: types.ParticipantState{
+			Flags: types.ParticipantFlags{"group": types.StringFlag("a")},
+		}}
+
+		newState, err := ActionEval(flagAction("APPEND_TO_LIST_FLAG", str("episodes"), str("fever")), actionData, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		stateWithOneItem := newState
+		for _, item := range []types.ExpressionArg{num(3), str("fever")} {
+			newState, err = ActionEval(flagAction("APPEND_TO_LIST_FLAG", str("episodes"), item), newState, event, configs)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+		expected := types.ListFlag(types.StringFlag("fever"), types.NumberFlag(3), types.StringFlag("fever"))
+		if v := newState.PState.Flags["episodes"]; !v.Equal(expected) {
+			t.Errorf("unexpected list: %v", v)
+		}
+		if v := stateWithOneItem.PState.Flags["episodes"]; len(v.List) != 1 {
+			t.Errorf("previous state should not be modified: %v", v)
+		}
+
+		newState, err = ActionEval(flagAction("REMOVE_FROM_LIST_FLAG", str("episodes"), str("fever")), newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if v := newState.PState.Flags["episodes"]; !v.Equal(types.ListFlag(types.NumberFlag(3))) {
+			t.Errorf("unexpected list: %v", v)
+		}
+
+		newState, err = ActionEval(flagAction("REMOVE_FROM_LIST_FLAG", str("missing"), str("fever")), newState, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if _, ok := newState.PState.Flags["missing"]; ok {
+			t.Error("missing flag should not be created")
+		}
+
+		if _, err := ActionEval(flagAction("APPEND_TO_LIST_FLAG", str("group"), str("b")), newState, event, configs); err == nil {
+			t.Error("should return an error for a non-list flag")
+		}
+	})
+}
+
+func TestSubmissionHistoryUpdate(t *testing.T) {
+	event := types.StudyEvent{Type: "SUBMIT", Response: types.SurveyResponse{Key: "weekly"}}
+	configs := ActionConfigs{Now: func() time.Time { return time.Unix(1000000, 0) }}
+	rules := []types.Expression{
+		{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{{DType: "str", Str: "a"}, {DType: "str", Str: "1"}}},
+		{Name: "DO", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{{DType: "str", Str: "b"}, {DType: "str", Str: "1"}}}},
+		}},
+	}
+	pState := types.ParticipantState{
+		LastSubmissions: map[string]int64{"weekly": 500000},
+	}
+
+	for i, expectedCount := range []int64{2, 3} {
+		actionData := ActionData{PState: pState}
+		for _, rule := range rules {
+			var err error
+			actionData, err = ActionEval(rule, actionData, event, configs)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+		}
+		h := actionData.PState.SubmissionHistory["weekly"]
+		if h.Count != expectedCount || len(h.Recent) != int(expectedCount) || h.Recent[0] != 500000 || h.Recent[len(h.Recent)-1] != 1000000 || !h.Incomplete {
+			t.Errorf("unexpected submission history after submission %d: %v", i+1, h)
+		}
+		pState = actionData.PState
+	}
+}
+
+func TestSubmissionHistoryUpdateOfLegacyParticipant(t *testing.T) {
+	event := types.StudyEvent{Type: "SUBMIT", InstanceID: "inst", StudyKey: "study", Response: types.SurveyResponse{Key: "weekly"}}
+	configs := ActionConfigs{
+		DBService: MockStudyDBService{
+			Responses: []types.SurveyResponse{
+				{Key: "weekly", ParticipantID: "p1", SubmittedAt: 400000},
+				{Key: "weekly", ParticipantID: "p1", SubmittedAt: 500000},
+			},
+		},
+		Now: func() time.Time { return time.Unix(1000000, 0) },
+	}
+	pState := types.ParticipantState{
+		ParticipantID:   "p1",
+		LastSubmissions: map[string]int64{"weekly": 500000},
+	}
+
+	actionData, err := ActionEval(types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{{DType: "str", Str: "a"}, {DType: "str", Str: "1"}}}, ActionData{PState: pState}, event, configs)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	h := actionData.PState.SubmissionHistory["weekly"]
+	if h.Count != 3 || h.Incomplete || len(h.Recent) != 3 || h.Recent[0] != 400000 || h.Recent[2] != 1000000 {
+		t.Errorf("unexpected submission history: %v", h)
+	}
+	if c, err := h.TotalCount(); err != nil || c != 3 {
+		t.Errorf("unexpected count: %d, %v", c, err)
+	}
+}
+
+func TestAssignArmAction(t *testing.T) {
+	event := types.StudyEvent{Type: "ENTER"}
+	str := func(v string) types.ExpressionArg { return types.ExpressionArg{DType: "str", Str: v} }
+	action := types.Expression{Name: "ASSIGN_ARM", Data: []types.ExpressionArg{str("trial"), str("sex")}}
+	db := MockStudyDBService{
+		Schemes: []types.RandomisationScheme{
+			{Key: "trial", Arms: []types.RandomisationArm{{Key: "A", Ratio: 1}, {Key: "B", Ratio: 1}}, BlockSize: 4, ArmFlag: "arm", Seed: "s"},
+		},
+		Allocations: map[string]types.RandomisationAllocation{},
+	}
+	configs := ActionConfigs{DBService: db, Now: func() time.Time { return time.Unix(1000000, 0) }}
+
+	t.Run("balanced allocation within a stratum", func(t *testing.T) {
+		counts := map[string]int{}
+		for i := 0; i < 8; i++ {
+			actionData := ActionData{PState: types.ParticipantState{
+				ParticipantID: fmt.Sprintf("P%d", i),
+				Flags:         types.ParticipantFlags{"sex": types.StringFlag("f")},
+			}}
+			newState, err := ActionEval(action, actionData, event, configs)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+			counts[newState.PState.Flags["arm"].Str]++
+			if info, ok := newState.PState.FlagInfos.Get("arm"); !ok || info.SetAt != 1000000 {
+				t.Errorf("unexpected flag info: %v", info)
+			}
+		}
+		if counts["A"] != 4 || counts["B"] != 4 {
+			t.Errorf("unexpected allocation counts: %v", counts)
+		}
+	})
+
+	t.Run("participant keeps the first arm", func(t *testing.T) {
+		actionData := ActionData{PState: types.ParticipantState{
+			ParticipantID: "P0",
+			Flags:         types.ParticipantFlags{"sex": types.StringFlag("m")},
+		}}
+		newState, err := ActionEval(action, actionData, event, configs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if arm := newState.PState.Flags["arm"].Str; arm != db.Allocations["P0"].Arm || db.Allocations["P0"].Stratum != "sex=f" {
+			t.Errorf("unexpected arm: %s, %v", arm, db.Allocations["P0"])
+		}
+	})
+
+	t.Run("dry run does not allocate", func(t *testing.T) {
+		dryRunConfigs := configs
+		dryRunConfigs.DryRun = true
+		actionData := ActionData{PState: types.ParticipantState{ParticipantID: "new"}}
+		newState, err := ActionEval(action, actionData, event, dryRunConfigs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if arm := newState.PState.Flags["arm"]; arm.Str != DRY_RUN_ARM_PLACEHOLDER {
+			t.Errorf("unexpected arm: %v", arm)
+		}
+		if _, ok := db.Allocations["new"]; ok {
+			t.Error("allocation should not be saved")
+		}
+	})
+
+	t.Run("dry run uses existing allocation", func(t *testing.T) {
+		dryRunConfigs := configs
+		dryRunConfigs.DryRun = true
+		actionData := ActionData{PState: types.ParticipantState{ParticipantID: "P0"}}
+		newState, err := ActionEval(action, actionData, event, dryRunConfigs)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if arm := newState.PState.Flags["arm"].Str; arm != db.Allocations["P0"].Arm {
+			t.Errorf("unexpected arm: %s", arm)
+		}
+	})
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		wrongAction := types.Expression{Name: "ASSIGN_ARM", Data: []types.ExpressionArg{str("other")}}
+		if _, err := ActionEval(wrongAction, ActionData{}, event, configs); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package studyengine
+
+import (
+	"fmt"
+
+	"github.com/coneno/logger"
+)
+
+// DeferredEffects makes the evaluation of rules repeatable, e.g. when the participant state has to be updated again after a concurrent
+// modification. Researcher messages and removals of confidential responses are collected in ActionData, and performed once with
+// PerformDeferredEffects after the participant state is saved. External event handlers are only called in the first evaluation,
+// repeated evaluations reuse their responses. Randomisation arms are stored per participant, so ASSIGN_ARM allocates only once.
+type DeferredEffects struct {
+	externalResponses map[string]map[string]interface{}
+}
+
+func NewDeferredEffects() *DeferredEffects {
+	return &DeferredEffects{
+		externalResponses: map[string]map[string]interface{}{},
+	}
+}
+
+// ConfidentialResponseRemoval is a removal of confidential responses collected in dry run or deferred mode
+type ConfidentialResponseRemoval struct {
+	ParticipantID string
+	Key           string // empty to remove all confidential responses of the participant
+}
+
+// callExternalService returns the response of the external service for the n-th call of the evaluation, and calls the service only if there is none yet
+func (d *DeferredEffects) callExternalService(n int, eventType string, url string, call func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if d == nil {
+		return call()
+	}
+	key := fmt.Sprintf("%d:%s:%s", n, eventType, url)
+	if response, ok := d.externalResponses[key]; ok {
+		logger.Debug.Printf("reusing response of %s from previous evaluation", url)
+		return response, nil
+	}
+	response, err := call()
+	if err != nil {
+		return response, err
+	}
+	d.externalResponses[key] = response
+	return response, nil
+}
+
+// PerformDeferredEffects saves the researcher messages and removes the confidential responses collected in data
+func PerformDeferredEffects(dbService StudyDBService, instanceID string, studyKey string, data ActionData) {
+	for _, message := range data.ResearcherMessages {
+		if err := dbService.SaveResearcherMessage(instanceID, studyKey, message); err != nil {
+			logger.Error.Printf("unexpected error when saving researcher message: %v", err)
+		}
+	}
+	for _, removal := range data.ConfidentialResponseRemovals {
+		if _, err := dbService.DeleteConfidentialResponses(instanceID, studyKey, removal.ParticipantID, removal.Key); err != nil {
+			logger.Error.Printf("unexpected error: %v", err)
+		}
+	}
+}
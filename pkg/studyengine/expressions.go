@@ -19,12 +19,24 @@ type EvalContext struct {
 	Event            types.StudyEvent
 	ParticipantState types.ParticipantState
 	Configs          ActionConfigs
+	Variables        map[string]interface{} // values defined with LET for the current event
 }
 
 func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val interface{}, err error) {
+	traceIndex := evalCtx.Configs.Tracer.begin(TRACE_ENTRY_EXPRESSION, expression.Name)
+	defer func() {
+		evalCtx.Configs.Tracer.end(traceIndex, val, err)
+	}()
+
 	switch expression.Name {
 	case "checkEventType":
 		val, err = evalCtx.checkEventType(expression)
+	case "getEventName":
+		val, err = evalCtx.getEventName()
+	case "getEventPayloadValue":
+		val, err = evalCtx.getEventPayloadValue(expression)
+	case "hasEventPayloadKey":
+		val, err = evalCtx.hasEventPayloadKey(expression)
 	// Response checkers:
 	case "checkSurveyResponseKey":
 		val, err = evalCtx.checkSurveyResponseKey(expression)
@@ -47,6 +59,11 @@ func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val inter
 	// Old responses:
 	case "checkConditionForOldResponses":
 		val, err = evalCtx.checkConditionForOldResponses(expression)
+	// Study stats:
+	case "getStudyStat":
+		val, err = evalCtx.getStudyStat(expression)
+	case "countParticipantsWithCondition":
+		val, err = evalCtx.countParticipantsWithCondition(expression)
 	// Participant state:
 	case "getStudyEntryTime":
 		val, err = evalCtx.getStudyEntryTime(expression, false)
@@ -64,8 +81,22 @@ func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val inter
 		val, err = evalCtx.hasParticipantFlagKey(expression, false)
 	case "getParticipantFlagValue":
 		val, err = evalCtx.getParticipantFlagValue(expression, false)
+	case "getParticipantFlagTypedValue":
+		val, err = evalCtx.getParticipantFlagTypedValue(expression, false)
+	case "getParticipantFlagSetAt":
+		val, err = evalCtx.getParticipantFlagSetAt(expression, false)
+	case "participantFlagOlderThan":
+		val, err = evalCtx.participantFlagOlderThan(expression, false)
+	case "getListFlagLength":
+		val, err = evalCtx.getListFlagLength(expression, false)
+	case "listFlagContains":
+		val, err = evalCtx.listFlagContains(expression, false)
 	case "lastSubmissionDateOlderThan":
 		val, err = evalCtx.lastSubmissionDateOlderThan(expression, false)
+	case "getSubmissionCount":
+		val, err = evalCtx.getSubmissionCount(expression, false)
+	case "getLastSubmissionTs":
+		val, err = evalCtx.getLastSubmissionTs(expression, false)
 	case "hasMessageTypeAssigned":
 		val, err = evalCtx.hasMessageTypeAssigned(expression, false)
 	case "getMessageNextTime":
@@ -87,8 +118,22 @@ func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val inter
 		val, err = evalCtx.hasParticipantFlagKey(expression, true)
 	case "incomingState:getParticipantFlagValue":
 		val, err = evalCtx.getParticipantFlagValue(expression, true)
+	case "incomingState:getParticipantFlagTypedValue":
+		val, err = evalCtx.getParticipantFlagTypedValue(expression, true)
+	case "incomingState:getParticipantFlagSetAt":
+		val, err = evalCtx.getParticipantFlagSetAt(expression, true)
+	case "incomingState:participantFlagOlderThan":
+		val, err = evalCtx.participantFlagOlderThan(expression, true)
+	case "incomingState:getListFlagLength":
+		val, err = evalCtx.getListFlagLength(expression, true)
+	case "incomingState:listFlagContains":
+		val, err = evalCtx.listFlagContains(expression, true)
 	case "incomingState:lastSubmissionDateOlderThan":
 		val, err = evalCtx.lastSubmissionDateOlderThan(expression, true)
+	case "incomingState:getSubmissionCount":
+		val, err = evalCtx.getSubmissionCount(expression, true)
+	case "incomingState:getLastSubmissionTs":
+		val, err = evalCtx.getLastSubmissionTs(expression, true)
 	case "incomingState:hasMessageTypeAssigned":
 		val, err = evalCtx.hasMessageTypeAssigned(expression, true)
 	case "incomingState:getMessageNextTime":
@@ -117,6 +162,46 @@ func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val inter
 
 	case "neg":
 		val, err = evalCtx.neg(expression)
+	case "mul":
+		val, err = evalCtx.mul(expression)
+	case "div":
+		val, err = evalCtx.div(expression)
+	case "mod":
+		val, err = evalCtx.mod(expression)
+	case "min":
+		val, err = evalCtx.min(expression)
+	case "max":
+		val, err = evalCtx.max(expression)
+	case "round":
+		val, err = evalCtx.round(expression)
+	case "floor":
+		val, err = evalCtx.floor(expression)
+	case "abs":
+		val, err = evalCtx.abs(expression)
+
+	// String operators
+	case "concat":
+		val, err = evalCtx.concat(expression)
+	case "substr":
+		val, err = evalCtx.substr(expression)
+	case "contains":
+		val, err = evalCtx.contains(expression)
+	case "regexMatch":
+		val, err = evalCtx.regexMatch(expression)
+	case "toLower":
+		val, err = evalCtx.toLower(expression)
+
+	// Date operators
+	case "dateDiffDays":
+		val, err = evalCtx.dateDiffDays(expression)
+	case "startOfDay":
+		val, err = evalCtx.startOfDay(expression)
+	case "addMonths":
+		val, err = evalCtx.addMonths(expression)
+
+	// Variables
+	case "getVar":
+		val, err = evalCtx.getVar(expression)
 
 	// Other
 	case "timestampWithOffset":
@@ -139,7 +224,13 @@ func ExpressionEval(expression types.Expression, evalCtx EvalContext) (val inter
 	return
 }
 
-func (ctx EvalContext) expressionArgResolver(arg types.ExpressionArg) (interface{}, error) {
+func (ctx EvalContext) expressionArgResolver(arg types.ExpressionArg) (val interface{}, err error) {
+	defer func() {
+		if err == nil {
+			ctx.Configs.Tracer.addArg(val)
+		}
+	}()
+
 	switch arg.DType {
 	case "num":
 		return arg.Num, nil
@@ -173,6 +264,50 @@ func (ctx EvalContext) checkEventType(exp types.Expression) (val bool, err error
 	return ctx.Event.Type == arg1Val, nil
 }
 
+// getEventName returns the name of a named timer event (TIMER:<name>), the key of a custom event (CUSTOM:<key>),
+// the key of the expired flag for FLAG_EXPIRED events, or an empty string for other events
+func (ctx EvalContext) getEventName() (val string, err error) {
+	if ctx.Event.Type == types.FLAG_EXPIRED_EVENT {
+		return ctx.Event.FlagKey, nil
+	}
+	if strings.HasPrefix(ctx.Event.Type, types.CUSTOM_EVENT_PREFIX) {
+		return strings.TrimPrefix(ctx.Event.Type, types.CUSTOM_EVENT_PREFIX), nil
+	}
+	if !strings.HasPrefix(ctx.Event.Type, types.TIMER_EVENT_PREFIX) {
+		return "", nil
+	}
+	return strings.TrimPrefix(ctx.Event.Type, types.TIMER_EVENT_PREFIX), nil
+}
+
+// getEventPayloadValue returns the value of the payload of a custom event, an empty string if the key is not in the payload
+func (ctx EvalContext) getEventPayloadValue(exp types.Expression) (val interface{}, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	key, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	v, ok := ctx.Event.Payload[key]
+	if !ok {
+		return "", nil
+	}
+	return v.Value(), nil
+}
+
+// hasEventPayloadKey checks if the payload of a custom event contains the key
+func (ctx EvalContext) hasEventPayloadKey(exp types.Expression) (val bool, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	key, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	_, ok := ctx.Event.Payload[key]
+	return ok, nil
+}
+
 // checkSurveyResponseKey compares the key of the submitted survey response (if any)
 func (ctx EvalContext) checkSurveyResponseKey(exp types.Expression) (val bool, err error) {
 	if len(exp.Data) != 1 {
@@ -293,6 +428,7 @@ func (ctx EvalContext) checkConditionForOldResponses(exp types.Expression) (val
 	for _, resp := range responses {
 		oldEvalContext := EvalContext{
 			ParticipantState: ctx.ParticipantState,
+			Variables:        ctx.Variables,
 			Event: types.StudyEvent{
 				Response: resp,
 			},
@@ -448,33 +584,101 @@ func (ctx EvalContext) hasParticipantFlagKey(exp types.Expression, withIncomingP
 	return true, nil
 }
 
+// getParticipantFlagValue returns the value of the flag formatted as string (e.g. "5.000000" for numbers), or an empty string if not set
 func (ctx EvalContext) getParticipantFlagValue(exp types.Expression, withIncomingParticipantState bool) (val string, err error) {
+	res, ok, err := ctx.findParticipantFlag(exp, withIncomingParticipantState)
+	if err != nil || !ok {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+// getParticipantFlagTypedValue returns the value of the flag with its type (string, float64, bool or a list), or an empty string if not set
+func (ctx EvalContext) getParticipantFlagTypedValue(exp types.Expression, withIncomingParticipantState bool) (val interface{}, err error) {
+	res, ok, err := ctx.findParticipantFlag(exp, withIncomingParticipantState)
+	if err != nil || !ok {
+		return "", err
+	}
+	return res.Value(), nil
+}
+
+func (ctx EvalContext) findParticipantFlag(exp types.Expression, withIncomingParticipantState bool) (flag types.FlagValue, found bool, err error) {
 	pState := ctx.ParticipantState
 	if withIncomingParticipantState {
 		pState = ctx.Event.MergeWithParticipant
 	}
 	if len(exp.Data) != 1 {
-		return val, errors.New("unexpected numbers of arguments")
+		return flag, false, errors.New("unexpected numbers of arguments")
 	}
 
 	if exp.Data[0].IsNumber() {
-		return val, errors.New("unexpected argument types")
+		return flag, false, errors.New("unexpected argument types")
 	}
 
 	arg1, err := ctx.expressionArgResolver(exp.Data[0])
 	if err != nil {
-		return val, err
+		return flag, false, err
 	}
 	arg1Val, ok := arg1.(string)
 	if !ok {
-		return val, errors.New("could not cast argument 1")
+		return flag, false, errors.New("could not cast argument 1")
+	}
+
+	flag, found = pState.Flags[arg1Val]
+	return flag, found, nil
+}
+
+// getParticipantFlagSetAt returns when the flag was last set, 0 if the flag is not set or was set before set times were recorded
+func (ctx EvalContext) getParticipantFlagSetAt(exp types.Expression, withIncomingParticipantState bool) (val float64, err error) {
+	pState := ctx.ParticipantState
+	if withIncomingParticipantState {
+		pState = ctx.Event.MergeWithParticipant
+	}
+	if len(exp.Data) != 1 {
+		return val, errors.New("unexpected numbers of arguments")
 	}
 
-	res, ok := pState.Flags[arg1Val]
+	key, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	if _, ok := pState.Flags[key]; !ok {
+		return 0, nil
+	}
+	info, ok := pState.FlagInfos.Get(key)
 	if !ok {
-		return "", nil
+		return 0, nil
 	}
-	return res, nil
+	return float64(info.SetAt), nil
+}
+
+// participantFlagOlderThan checks if the flag is set and was last set before the timestamp.
+// Flags set before set times were recorded are considered older than any timestamp.
+func (ctx EvalContext) participantFlagOlderThan(exp types.Expression, withIncomingParticipantState bool) (val bool, err error) {
+	pState := ctx.ParticipantState
+	if withIncomingParticipantState {
+		pState = ctx.Event.MergeWithParticipant
+	}
+	if len(exp.Data) != 2 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+
+	key, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	ts, err := ctx.resolveNumArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	if _, ok := pState.Flags[key]; !ok {
+		return false, nil
+	}
+	info, ok := pState.FlagInfos.Get(key)
+	if !ok {
+		return true, nil
+	}
+	return float64(info.SetAt) < ts, nil
 }
 
 func (ctx EvalContext) hasParticipantFlag(exp types.Expression, withIncomingParticipantState bool) (val bool, err error) {
@@ -486,7 +690,7 @@ func (ctx EvalContext) hasParticipantFlag(exp types.Expression, withIncomingPart
 		return val, errors.New("unexpected numbers of arguments")
 	}
 
-	if exp.Data[0].IsNumber() || exp.Data[1].IsNumber() {
+	if exp.Data[0].IsNumber() {
 		return val, errors.New("unexpected argument types")
 	}
 
@@ -503,16 +707,84 @@ func (ctx EvalContext) hasParticipantFlag(exp types.Expression, withIncomingPart
 	if err != nil {
 		return val, err
 	}
-	arg2Val, ok := arg2.(string)
-	if !ok {
-		return val, errors.New("could not cast argument 2")
-	}
 
 	value, ok := pState.Flags[arg1Val]
-	if !ok || value != arg2Val {
+	if !ok {
 		return false, nil
 	}
-	return true, nil
+	return flagValueMatches(value, arg2)
+}
+
+// flagValueMatches compares a flag value (or list item) with a resolved value: strings are compared with the string format
+// of the flag, as for string flags, numbers match number and timestamp flags, and bools match bool flags
+func flagValueMatches(value types.FlagValue, v interface{}) (bool, error) {
+	switch arg := v.(type) {
+	case string:
+		return value.String() == arg, nil
+	case float64:
+		return (value.DType == types.FLAG_DTYPE_NUMBER || value.DType == types.FLAG_DTYPE_TIMESTAMP) && value.Num == arg, nil
+	case bool:
+		return value.DType == types.FLAG_DTYPE_BOOL && value.Bool == arg, nil
+	default:
+		return false, errors.New("value should be a string, number or bool")
+	}
+}
+
+// resolveListFlag returns the items of the list flag, nil if the flag is not set
+func (ctx EvalContext) resolveListFlag(exp types.Expression, withIncomingParticipantState bool) ([]types.FlagValue, error) {
+	pState := ctx.ParticipantState
+	if withIncomingParticipantState {
+		pState = ctx.Event.MergeWithParticipant
+	}
+	key, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := pState.Flags[key]
+	if !ok {
+		return nil, nil
+	}
+	if value.DType != types.FLAG_DTYPE_LIST {
+		return nil, fmt.Errorf("flag %s is not a list", key)
+	}
+	return value.List, nil
+}
+
+// getListFlagLength returns the number of items of a list flag, 0 if the flag is not set
+func (ctx EvalContext) getListFlagLength(exp types.Expression, withIncomingParticipantState bool) (val float64, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	items, err := ctx.resolveListFlag(exp, withIncomingParticipantState)
+	if err != nil {
+		return val, err
+	}
+	return float64(len(items)), nil
+}
+
+// listFlagContains checks if a list flag has an item matching the value, false if the flag is not set
+func (ctx EvalContext) listFlagContains(exp types.Expression, withIncomingParticipantState bool) (val bool, err error) {
+	if len(exp.Data) != 2 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	items, err := ctx.resolveListFlag(exp, withIncomingParticipantState)
+	if err != nil {
+		return val, err
+	}
+	item, err := ctx.expressionArgResolver(exp.Data[1])
+	if err != nil {
+		return val, err
+	}
+	for _, v := range items {
+		matches, err := flagValueMatches(v, item)
+		if err != nil {
+			return val, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (ctx EvalContext) lastSubmissionDateOlderThan(exp types.Expression, withIncomingParticipantState bool) (val bool, err error) {
@@ -555,6 +827,196 @@ func (ctx EvalContext) lastSubmissionDateOlderThan(exp types.Expression, withInc
 	return true, nil
 }
 
+// getSubmissionCount returns the number of submissions of the survey, or the number of submissions at or after the optional
+// timestamp. An error is returned if the count includes submissions that are not recorded in the submission history: older
+// than the recent submission timestamps, or submitted before submission histories were recorded.
+func (ctx EvalContext) getSubmissionCount(exp types.Expression, withIncomingParticipantState bool) (val float64, err error) {
+	pState := ctx.ParticipantState
+	if withIncomingParticipantState {
+		pState = ctx.Event.MergeWithParticipant
+	}
+	if len(exp.Data) != 1 && len(exp.Data) != 2 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	surveyKey, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	history, err := submissionHistoryFor(pState, surveyKey, ctx.Event, ctx.Configs)
+	if err != nil {
+		return val, err
+	}
+	var count int64
+	if len(exp.Data) == 1 {
+		count, err = history.TotalCount()
+	} else {
+		var since float64
+		since, err = ctx.resolveNumArg(exp, 1)
+		if err != nil {
+			return val, err
+		}
+		count, err = history.CountSince(int64(since))
+	}
+	if err != nil {
+		return val, fmt.Errorf("%s: %w", surveyKey, err)
+	}
+	return float64(count), nil
+}
+
+// getLastSubmissionTs returns the time of the last submission of the survey, or of any survey if no key is given, 0 if none
+func (ctx EvalContext) getLastSubmissionTs(exp types.Expression, withIncomingParticipantState bool) (val float64, err error) {
+	pState := ctx.ParticipantState
+	if withIncomingParticipantState {
+		pState = ctx.Event.MergeWithParticipant
+	}
+	if len(exp.Data) > 1 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	if len(exp.Data) == 1 {
+		surveyKey, err := ctx.resolveStrArg(exp, 0)
+		if err != nil {
+			return val, err
+		}
+		return float64(pState.LastSubmissions[surveyKey]), nil
+	}
+	for _, ts := range pState.LastSubmissions {
+		if float64(ts) > val {
+			val = float64(ts)
+		}
+	}
+	return val, nil
+}
+
+func (ctx EvalContext) getStudyStats() (stats types.StudyStats, err error) {
+	if ctx.Configs.DBService == nil {
+		return stats, errors.New("DB connection not available in the context")
+	}
+	if ctx.Event.InstanceID == "" || ctx.Event.StudyKey == "" {
+		return stats, errors.New("instanceID or study key missing from context")
+	}
+	return ctx.Configs.DBService.GetStudyStats(ctx.Event.InstanceID, ctx.Event.StudyKey)
+}
+
+// getStudyStat returns one of the study stats (participantCount, tempParticipantCount or responseCount), as of the last stats update
+func (ctx EvalContext) getStudyStat(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	name, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	stats, err := ctx.getStudyStats()
+	if err != nil {
+		return val, err
+	}
+	v, ok := stats.Get(name)
+	if !ok {
+		return val, fmt.Errorf("unknown study stat: %s", name)
+	}
+	return float64(v), nil
+}
+
+// ParticipantCountsMaxAge is the age in seconds up to which cached participant counts are used, older counts are counted again
+var ParticipantCountsMaxAge int64 = 60
+
+// countParticipantsWithCondition returns the number of participants with the flag value and study status (active if not given).
+// The counts of the conditions found in the study rules are cached with the study stats; if the cached count is missing
+// or older than ParticipantCountsMaxAge, participants are counted in the DB.
+func (ctx EvalContext) countParticipantsWithCondition(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 2 && len(exp.Data) != 3 {
+		return val, errors.New("unexpected numbers of arguments")
+	}
+	args := make([]string, len(exp.Data))
+	for i := range exp.Data {
+		args[i], err = ctx.resolveStrArg(exp, i)
+		if err != nil {
+			return val, err
+		}
+	}
+	condition := participantConditionFromArgs(args)
+
+	stats, err := ctx.getStudyStats()
+	if err != nil {
+		return val, err
+	}
+	count, ok := stats.ParticipantCounts[condition.CounterKey()]
+	// the age of the cache is measured in real time, also when the evaluation simulates another time
+	if !ok || Now().Unix()-stats.ParticipantCountsUpdatedAt > ParticipantCountsMaxAge {
+		count, err = ctx.Configs.DBService.CountParticipantsWithCondition(ctx.Event.InstanceID, ctx.Event.StudyKey, condition)
+		if err != nil {
+			return val, err
+		}
+	}
+	return float64(count), nil
+}
+
+func participantConditionFromArgs(args []string) types.ParticipantCondition {
+	condition := types.ParticipantCondition{
+		StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+		FlagKey:     args[0],
+		FlagValue:   args[1],
+	}
+	if len(args) > 2 {
+		condition.StudyStatus = args[2]
+	}
+	return condition
+}
+
+// ParticipantConditionsInRules lists the conditions of the countParticipantsWithCondition expressions used in the rules,
+// so that the participant counts can be updated with the study stats
+func ParticipantConditionsInRules(rules []types.Expression) []types.ParticipantCondition {
+	conditions := []types.ParticipantCondition{}
+	found := map[string]bool{}
+	var walk func(exp *types.Expression)
+	walk = func(exp *types.Expression) {
+		if exp == nil {
+			return
+		}
+		if exp.Name == "countParticipantsWithCondition" && (len(exp.Data) == 2 || len(exp.Data) == 3) {
+			args := make([]string, len(exp.Data))
+			literals := true
+			for i, arg := range exp.Data {
+				literals = literals && arg.IsString()
+				args[i] = arg.Str
+			}
+			if literals {
+				condition := participantConditionFromArgs(args)
+				if !found[condition.CounterKey()] {
+					found[condition.CounterKey()] = true
+					conditions = append(conditions, condition)
+				}
+			}
+		}
+		for _, arg := range exp.Data {
+			if arg.IsExpression() {
+				walk(arg.Exp)
+			}
+		}
+	}
+	for i := range rules {
+		walk(&rules[i])
+	}
+	return conditions
+}
+
+// CountParticipantConditions counts the participants for each condition used in the rules, to be cached with the study stats
+func CountParticipantConditions(dbService StudyDBService, instanceID string, studyKey string, rules []types.Expression) map[string]int64 {
+	var participantCounts map[string]int64
+	for _, condition := range ParticipantConditionsInRules(rules) {
+		count, err := dbService.CountParticipantsWithCondition(instanceID, studyKey, condition)
+		if err != nil {
+			logger.Error.Printf("DB ERROR for participant counting for study: %s -> %s", studyKey, err.Error())
+			continue
+		}
+		if participantCounts == nil {
+			participantCounts = map[string]int64{}
+		}
+		participantCounts[condition.CounterKey()] = count
+	}
+	return participantCounts
+}
+
 func (ctx EvalContext) responseHasKeysAny(exp types.Expression) (val bool, err error) {
 	if len(exp.Data) < 3 {
 		return val, errors.New("unexpected numbers of arguments")
@@ -1224,6 +1686,26 @@ func (ctx EvalContext) neg(exp types.Expression) (val float64, err error) {
 }
 
 
+// getVar returns the value of a variable defined with LET, undefined variables are an error
+func (ctx EvalContext) getVar(exp types.Expression) (val interface{}, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("should have one argument")
+	}
+	arg, err := ctx.expressionArgResolver(exp.Data[0])
+	if err != nil {
+		return val, err
+	}
+	name, ok := arg.(string)
+	if !ok {
+		return val, errors.New("argument 1 should be resolved as type string")
+	}
+	val, ok = ctx.Variables[name]
+	if !ok {
+		return nil, fmt.Errorf("variable not defined: %s", name)
+	}
+	return
+}
+
 func (ctx EvalContext) timestampWithOffset(exp types.Expression) (t float64, err error) {
 	if len(exp.Data) != 1 && len(exp.Data) != 2 {
 		return t, errors.New("should have one or two arguments")
@@ -1238,7 +1720,7 @@ func (ctx EvalContext) timestampWithOffset(exp types.Expression) (t float64, err
 	}
 	delta := int64(arg1.(float64))
 
-	referenceTime := Now().Unix()
+	referenceTime := ctx.Configs.now().Unix()
 	if len(exp.Data) == 2 {
 		arg2, err2 := ctx.expressionArgResolver(exp.Data[1])
 		if err2 != nil {
@@ -1274,7 +1756,7 @@ func (ctx EvalContext) getTsForNextISOWeek(exp types.Expression) (t float64, err
 		return t, errors.New("argument 1 should be between 1 and 53")
 	}
 
-	referenceTime := Now()
+	referenceTime := ctx.Configs.now()
 	if len(exp.Data) == 2 {
 		arg2, err2 := ctx.expressionArgResolver(exp.Data[1])
 		if err2 != nil {
@@ -1,6 +1,7 @@
 package studyengine
 
 import (
+	"errors"
 	"testing"
 	"time"
 	"fmt"
@@ -43,6 +44,113 @@ func TestEvalCheckEventType(t *testing.T) {
 	})
 }
 
+func TestEvalGetEventName(t *testing.T) {
+	exp := types.Expression{Name: "getEventName"}
+
+	t.Run("for named timer event", func(t *testing.T) {
+		EvalContext := EvalContext{
+			Event: types.StudyEvent{Type: types.TIMER_EVENT_PREFIX + "reminder"},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret.(string) != "reminder" {
+			t.Errorf("unexpected type or value: %s", ret)
+		}
+	})
+
+	t.Run("for expired flag event", func(t *testing.T) {
+		EvalContext := EvalContext{
+			Event: types.StudyEvent{Type: types.FLAG_EXPIRED_EVENT, FlagKey: "episode"},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret.(string) != "episode" {
+			t.Errorf("unexpected type or value: %s", ret)
+		}
+	})
+
+	t.Run("for custom event", func(t *testing.T) {
+		EvalContext := EvalContext{
+			Event: types.StudyEvent{Type: types.CUSTOM_EVENT_PREFIX + "swab_result_received"},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret.(string) != "swab_result_received" {
+			t.Errorf("unexpected type or value: %s", ret)
+		}
+	})
+
+	t.Run("for other event", func(t *testing.T) {
+		EvalContext := EvalContext{
+			Event: types.StudyEvent{Type: "TIMER"},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret.(string) != "" {
+			t.Errorf("unexpected type or value: %s", ret)
+		}
+	})
+}
+
+func TestEvalEventPayloadExpressions(t *testing.T) {
+	EvalContext := EvalContext{
+		Event: types.StudyEvent{
+			Type: types.CUSTOM_EVENT_PREFIX + "swab_result_received",
+			Payload: map[string]types.FlagValue{
+				"result":   types.StringFlag("positive"),
+				"ct_value": types.NumberFlag(24.5),
+				"retest":   types.BoolFlag(false),
+			},
+		},
+	}
+	str := func(v string) types.ExpressionArg { return types.ExpressionArg{DType: "str", Str: v} }
+
+	for _, tc := range []struct {
+		name     string
+		exp      types.Expression
+		expected interface{}
+	}{
+		{name: "string value", exp: types.Expression{Name: "getEventPayloadValue", Data: []types.ExpressionArg{str("result")}}, expected: "positive"},
+		{name: "number value", exp: types.Expression{Name: "getEventPayloadValue", Data: []types.ExpressionArg{str("ct_value")}}, expected: 24.5},
+		{name: "bool value", exp: types.Expression{Name: "getEventPayloadValue", Data: []types.ExpressionArg{str("retest")}}, expected: false},
+		{name: "missing value", exp: types.Expression{Name: "getEventPayloadValue", Data: []types.ExpressionArg{str("other")}}, expected: ""},
+		{name: "has key", exp: types.Expression{Name: "hasEventPayloadKey", Data: []types.ExpressionArg{str("retest")}}, expected: true},
+		{name: "has not key", exp: types.Expression{Name: "hasEventPayloadKey", Data: []types.ExpressionArg{str("other")}}, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := ExpressionEval(tc.exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+
+	t.Run("without payload", func(t *testing.T) {
+		noPayloadContext := EvalContext
+		noPayloadContext.Event = types.StudyEvent{Type: "TIMER"}
+		ret, err := ExpressionEval(types.Expression{Name: "hasEventPayloadKey", Data: []types.ExpressionArg{str("result")}}, noPayloadContext)
+		if err != nil || ret.(bool) {
+			t.Errorf("unexpected result: %v, %v", ret, err)
+		}
+	})
+}
+
 func TestEvalCheckSurveyResponseKey(t *testing.T) {
 	exp := types.Expression{Name: "checkSurveyResponseKey", Data: []types.ExpressionArg{
 		{DType: "str", Str: "weekly"},
@@ -140,7 +248,11 @@ func TestEvalHasStudyStatus(t *testing.T) {
 }
 
 type MockStudyDBService struct {
-	Responses []types.SurveyResponse
+	Responses       []types.SurveyResponse
+	Schemes         []types.RandomisationScheme
+	Allocations     map[string]types.RandomisationAllocation // by participant ID
+	Stats           types.StudyStats
+	ConditionCounts map[string]int64 // current participant counts by ParticipantCondition.CounterKey
 }
 
 func (db MockStudyDBService) FindSurveyResponses(instanceID string, studyKey string, query studydb.ResponseQuery) (responses []types.SurveyResponse, err error) {
@@ -171,6 +283,59 @@ func (db MockStudyDBService) SaveResearcherMessage(instanceID string, studyKey s
 	return nil
 }
 
+func (db MockStudyDBService) GetStudyStats(instanceID string, studyKey string) (types.StudyStats, error) {
+	return db.Stats, nil
+}
+
+func (db MockStudyDBService) CountParticipantsWithCondition(instanceID string, studyKey string, condition types.ParticipantCondition) (int64, error) {
+	count, ok := db.ConditionCounts[condition.CounterKey()]
+	if !ok {
+		return 0, errors.New("condition not found")
+	}
+	return count, nil
+}
+
+func (db MockStudyDBService) GetRandomisationScheme(instanceID string, studyKey string, schemeKey string) (types.RandomisationScheme, error) {
+	scheme, ok := types.StudyConfigs{RandomisationSchemes: db.Schemes}.GetRandomisationScheme(schemeKey)
+	if !ok {
+		return scheme, errors.New("scheme not found")
+	}
+	return scheme, nil
+}
+
+func (db MockStudyDBService) FindRandomisationAllocation(instanceID string, studyKey string, schemeKey string, participantID string) (types.RandomisationAllocation, error) {
+	allocation, ok := db.Allocations[participantID]
+	if !ok || allocation.SchemeKey != schemeKey {
+		return types.RandomisationAllocation{}, errors.New("not allocated")
+	}
+	return allocation, nil
+}
+
+func (db MockStudyDBService) AllocateRandomisationArm(instanceID string, studyKey string, scheme types.RandomisationScheme, stratum string, participantID string, allocatedAt int64) (types.RandomisationAllocation, error) {
+	if allocation, err := db.FindRandomisationAllocation(instanceID, studyKey, scheme.Key, participantID); err == nil {
+		return allocation, nil
+	}
+	n := int64(0)
+	for _, a := range db.Allocations {
+		if a.SchemeKey == scheme.Key && a.Stratum == stratum {
+			n++
+		}
+	}
+	size := scheme.NewBlock(stratum, 0).Size
+	block := scheme.NewBlock(stratum, n/size)
+	allocation := types.RandomisationAllocation{
+		SchemeKey:     scheme.Key,
+		ParticipantID: participantID,
+		Stratum:       stratum,
+		Arm:           block.Arms[n%size],
+		BlockIndex:    block.BlockIndex,
+		Position:      n % size,
+		AllocatedAt:   allocatedAt,
+	}
+	db.Allocations[participantID] = allocation
+	return allocation, nil
+}
+
 func TestEvalCheckConditionForOldResponses(t *testing.T) {
 	testResponses := []types.SurveyResponse{
 		{
@@ -830,8 +995,8 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key2": "value1",
+				Flags: types.ParticipantFlags{
+					"key2": types.StringFlag("value1"),
 				},
 			},
 		}
@@ -853,8 +1018,8 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key1": "value2",
+				Flags: types.ParticipantFlags{
+					"key1": types.StringFlag("value2"),
 				},
 			},
 		}
@@ -876,8 +1041,8 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key1": "value1",
+				Flags: types.ParticipantFlags{
+					"key1": types.StringFlag("value1"),
 				},
 			},
 		}
@@ -896,8 +1061,8 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key1": "value1",
+				Flags: types.ParticipantFlags{
+					"key1": types.StringFlag("value1"),
 				},
 			},
 		}
@@ -916,8 +1081,8 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key1": "value1",
+				Flags: types.ParticipantFlags{
+					"key1": types.StringFlag("value1"),
 				},
 			},
 		}
@@ -928,7 +1093,7 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		}
 	})
 
-	t.Run("missing arguments", func(t *testing.T) {
+	t.Run("number value with string flag", func(t *testing.T) {
 		exp := types.Expression{Name: "hasParticipantFlag", Data: []types.ExpressionArg{
 			{DType: "str", Str: "key1"},
 			{DType: "num", Num: 22},
@@ -936,16 +1101,65 @@ func TestEvalHasParticipantFlag(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key1": "value1",
+				Flags: types.ParticipantFlags{
+					"key1": types.StringFlag("22"),
 				},
 			},
 		}
-		_, err := ExpressionEval(exp, EvalContext)
-		if err == nil {
-			t.Error("should throw error")
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if ret.(bool) {
+			t.Error("should be false")
+		}
+	})
+
+	t.Run("number value with number flag", func(t *testing.T) {
+		exp := types.Expression{Name: "hasParticipantFlag", Data: []types.ExpressionArg{
+			{DType: "str", Str: "key1"},
+			{DType: "num", Num: 22},
+		}}
+		EvalContext := EvalContext{
+			ParticipantState: types.ParticipantState{
+				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+				Flags: types.ParticipantFlags{
+					"key1": types.NumberFlag(22),
+				},
+			},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
 			return
 		}
+		if !ret.(bool) {
+			t.Error("should be true")
+		}
+	})
+
+	t.Run("string value with number flag", func(t *testing.T) {
+		exp := types.Expression{Name: "hasParticipantFlag", Data: []types.ExpressionArg{
+			{DType: "str", Str: "key1"},
+			{DType: "str", Str: "22.000000"},
+		}}
+		EvalContext := EvalContext{
+			ParticipantState: types.ParticipantState{
+				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+				Flags: types.ParticipantFlags{
+					"key1": types.NumberFlag(22),
+				},
+			},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if !ret.(bool) {
+			t.Error("should be true")
+		}
 	})
 }
 
@@ -976,8 +1190,8 @@ func TestEvalHasParticipantFlagKey(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key2": "1",
+				Flags: types.ParticipantFlags{
+					"key2": types.StringFlag("1"),
 				},
 			},
 		}
@@ -998,9 +1212,9 @@ func TestEvalHasParticipantFlagKey(t *testing.T) {
 		EvalContext := EvalContext{
 			ParticipantState: types.ParticipantState{
 				StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
-				Flags: map[string]string{
-					"key2": "1",
-					"key1": "1",
+				Flags: types.ParticipantFlags{
+					"key2": types.StringFlag("1"),
+					"key1": types.StringFlag("1"),
 				},
 			},
 		}
@@ -1015,6 +1229,251 @@ func TestEvalHasParticipantFlagKey(t *testing.T) {
 	})
 }
 
+func TestEvalGetParticipantFlagValue(t *testing.T) {
+	EvalContext := EvalContext{
+		ParticipantState: types.ParticipantState{
+			StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags: types.ParticipantFlags{
+				"str":  types.StringFlag("1"),
+				"num":  types.NumberFlag(2.5),
+				"bool": types.BoolFlag(true),
+				"list": types.ListFlag(types.StringFlag("a"), types.NumberFlag(1)),
+			},
+		},
+	}
+	testCases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "str", expected: "1"},
+		{key: "num", expected: "2.500000"},
+		{key: "bool", expected: "true"},
+		{key: "list", expected: "a,1.000000"},
+		{key: "missing", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			exp := types.Expression{Name: "getParticipantFlagValue", Data: []types.ExpressionArg{
+				{DType: "str", Str: tc.key},
+			}}
+			ret, err := ExpressionEval(exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret != tc.expected {
+				t.Errorf("unexpected value: %v (%T), expected %v", ret, ret, tc.expected)
+			}
+		})
+	}
+
+	t.Run("compared with string", func(t *testing.T) {
+		exp := types.Expression{Name: "eq", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "getParticipantFlagValue", Data: []types.ExpressionArg{
+				{DType: "str", Str: "str"},
+			}}},
+			{DType: "str", Str: "1"},
+		}}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if ret != true {
+			t.Errorf("unexpected value: %v", ret)
+		}
+	})
+}
+
+func TestEvalGetParticipantFlagTypedValue(t *testing.T) {
+	EvalContext := EvalContext{
+		ParticipantState: types.ParticipantState{
+			StudyStatus: types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags: types.ParticipantFlags{
+				"str":   types.StringFlag("a"),
+				"num":   types.NumberFlag(2.5),
+				"bool":  types.BoolFlag(true),
+				"ts":    types.TimestampFlag(1700000000),
+				"list":  types.ListFlag(types.StringFlag("a"), types.NumberFlag(1)),
+				"empty": types.StringFlag(""),
+			},
+		},
+	}
+	testCases := []struct {
+		key      string
+		expected interface{}
+	}{
+		{key: "str", expected: "a"},
+		{key: "num", expected: 2.5},
+		{key: "bool", expected: true},
+		{key: "ts", expected: float64(1700000000)},
+		{key: "missing", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			exp := types.Expression{Name: "getParticipantFlagTypedValue", Data: []types.ExpressionArg{
+				{DType: "str", Str: tc.key},
+			}}
+			ret, err := ExpressionEval(exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret != tc.expected {
+				t.Errorf("unexpected value: %v (%T), expected %v (%T)", ret, ret, tc.expected, tc.expected)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		exp := types.Expression{Name: "getParticipantFlagTypedValue", Data: []types.ExpressionArg{
+			{DType: "str", Str: "list"},
+		}}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		items, ok := ret.([]interface{})
+		if !ok || len(items) != 2 || items[0] != "a" || items[1] != 1.0 {
+			t.Errorf("unexpected value: %v", ret)
+		}
+	})
+}
+
+func TestEvalGetParticipantFlagSetAt(t *testing.T) {
+	EvalContext := EvalContext{
+		ParticipantState: types.ParticipantState{
+			Flags: types.ParticipantFlags{
+				"recent": types.StringFlag("a"),
+				"old":    types.StringFlag("b"),
+			},
+			FlagInfos: types.FlagInfos{
+				{Key: "recent", SetAt: 1000},
+				{Key: "removed", SetAt: 500},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		key      string
+		expected float64
+	}{
+		{key: "recent", expected: 1000},
+		{key: "old", expected: 0},
+		{key: "removed", expected: 0},
+		{key: "missing", expected: 0},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			exp := types.Expression{Name: "getParticipantFlagSetAt", Data: []types.ExpressionArg{
+				{DType: "str", Str: tc.key},
+			}}
+			ret, err := ExpressionEval(exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret.(float64) != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEvalParticipantFlagOlderThan(t *testing.T) {
+	EvalContext := EvalContext{
+		ParticipantState: types.ParticipantState{
+			Flags: types.ParticipantFlags{
+				"recent": types.StringFlag("a"),
+				"old":    types.StringFlag("b"),
+			},
+			FlagInfos: types.FlagInfos{
+				{Key: "recent", SetAt: 1000},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		name     string
+		key      string
+		ts       float64
+		expected bool
+	}{
+		{name: "set after timestamp", key: "recent", ts: 900, expected: false},
+		{name: "set before timestamp", key: "recent", ts: 1100, expected: true},
+		{name: "without set time", key: "old", ts: 900, expected: true},
+		{name: "missing flag", key: "missing", ts: 1100, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exp := types.Expression{Name: "participantFlagOlderThan", Data: []types.ExpressionArg{
+				{DType: "str", Str: tc.key},
+				{DType: "num", Num: tc.ts},
+			}}
+			ret, err := ExpressionEval(exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret.(bool) != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+
+	t.Run("missing argument", func(t *testing.T) {
+		exp := types.Expression{Name: "participantFlagOlderThan", Data: []types.ExpressionArg{
+			{DType: "str", Str: "recent"},
+		}}
+		if _, err := ExpressionEval(exp, EvalContext); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
+
+func TestEvalListFlagExpressions(t *testing.T) {
+	pState := types.ParticipantState{
+		Flags: types.ParticipantFlags{
+			"episodes": types.ListFlag(types.StringFlag("fever"), types.NumberFlag(3), types.BoolFlag(true)),
+			"group":    types.StringFlag("a"),
+		},
+	}
+	EvalContext := EvalContext{
+		ParticipantState: pState,
+		Event:            types.StudyEvent{Type: "MERGE", MergeWithParticipant: pState},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		exp      types.Expression
+		expected interface{}
+	}{
+		{name: "length", exp: types.Expression{Name: "getListFlagLength", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}}}, expected: 3.0},
+		{name: "length of missing flag", exp: types.Expression{Name: "getListFlagLength", Data: []types.ExpressionArg{{DType: "str", Str: "missing"}}}, expected: 0.0},
+		{name: "incoming state length", exp: types.Expression{Name: "incomingState:getListFlagLength", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}}}, expected: 3.0},
+		{name: "contains string", exp: types.Expression{Name: "listFlagContains", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}, {DType: "str", Str: "fever"}}}, expected: true},
+		{name: "contains number", exp: types.Expression{Name: "listFlagContains", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}, {DType: "num", Num: 3}}}, expected: true},
+		{name: "does not contain", exp: types.Expression{Name: "listFlagContains", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}, {DType: "str", Str: "cough"}}}, expected: false},
+		{name: "missing flag does not contain", exp: types.Expression{Name: "listFlagContains", Data: []types.ExpressionArg{{DType: "str", Str: "missing"}, {DType: "str", Str: "fever"}}}, expected: false},
+		{name: "incoming state contains", exp: types.Expression{Name: "incomingState:listFlagContains", Data: []types.ExpressionArg{{DType: "str", Str: "episodes"}, {DType: "str", Str: "fever"}}}, expected: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := ExpressionEval(tc.exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+
+	t.Run("not a list", func(t *testing.T) {
+		exp := types.Expression{Name: "getListFlagLength", Data: []types.ExpressionArg{{DType: "str", Str: "group"}}}
+		if _, err := ExpressionEval(exp, EvalContext); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
+
 func TestEvalHasResponseKey(t *testing.T) {
 	testEvalContext := EvalContext{
 		Event: types.StudyEvent{
@@ -1847,6 +2306,204 @@ func TestEvalLastSubmissionDateOlderThan(t *testing.T) {
 }
 
 // Comparisons
+func TestEvalSubmissionHistoryExpressions(t *testing.T) {
+	pState := types.ParticipantState{
+		LastSubmissions: map[string]int64{"weekly": 300, "intake": 100},
+		SubmissionHistory: types.SubmissionHistories{
+			"weekly": {Count: 25, Recent: []int64{100, 200, 300}},
+		},
+	}
+	EvalContext := EvalContext{
+		ParticipantState: pState,
+		Event:            types.StudyEvent{Type: "MERGE", MergeWithParticipant: pState},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		exp         types.Expression
+		expected    float64
+		expectError bool
+	}{
+		{name: "count", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "weekly"}}}, expected: 25},
+		{name: "count since", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "weekly"}, {DType: "num", Num: 150}}}, expected: 2},
+		{name: "count since before the recent submissions", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "weekly"}, {DType: "num", Num: 50}}}, expectError: true},
+		{name: "count without history", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "intake"}}}, expectError: true},
+		{name: "count since without history", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "intake"}, {DType: "num", Num: 150}}}, expected: 0},
+		{name: "count of other survey", exp: types.Expression{Name: "getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "other"}}}, expected: 0},
+		{name: "incoming state count", exp: types.Expression{Name: "incomingState:getSubmissionCount", Data: []types.ExpressionArg{{DType: "str", Str: "weekly"}}}, expected: 25},
+		{name: "last submission", exp: types.Expression{Name: "getLastSubmissionTs", Data: []types.ExpressionArg{{DType: "str", Str: "intake"}}}, expected: 100},
+		{name: "last submission of any survey", exp: types.Expression{Name: "getLastSubmissionTs"}, expected: 300},
+		{name: "last submission of other survey", exp: types.Expression{Name: "getLastSubmissionTs", Data: []types.ExpressionArg{{DType: "str", Str: "other"}}}, expected: 0},
+		{name: "incoming state last submission", exp: types.Expression{Name: "incomingState:getLastSubmissionTs", Data: []types.ExpressionArg{{DType: "str", Str: "weekly"}}}, expected: 300},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := ExpressionEval(tc.exp, EvalContext)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("should return an error, got %v", ret)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret.(float64) != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEvalSubmissionCountOfLegacyParticipant(t *testing.T) {
+	// submitted before submission histories were recorded
+	pState := types.ParticipantState{
+		ParticipantID:   "p1",
+		LastSubmissions: map[string]int64{"weekly": 300, "intake": 100},
+	}
+	count := func(key string, args ...types.ExpressionArg) types.Expression {
+		return types.Expression{Name: "getSubmissionCount", Data: append([]types.ExpressionArg{{DType: "str", Str: key}}, args...)}
+	}
+
+	t.Run("counted from responses", func(t *testing.T) {
+		EvalContext := EvalContext{
+			ParticipantState: pState,
+			Event:            types.StudyEvent{Type: "TIMER", InstanceID: "inst", StudyKey: "study"},
+			Configs: ActionConfigs{DBService: MockStudyDBService{
+				Responses: []types.SurveyResponse{
+					{Key: "weekly", ParticipantID: "p1", SubmittedAt: 300},
+					{Key: "weekly", ParticipantID: "p1", SubmittedAt: 100},
+					{Key: "weekly", ParticipantID: "p1", SubmittedAt: 200},
+					{Key: "weekly", ParticipantID: "p2", SubmittedAt: 200},
+					{Key: "intake", ParticipantID: "p1", SubmittedAt: 100},
+				},
+			}},
+		}
+		for _, tc := range []struct {
+			exp      types.Expression
+			expected float64
+		}{
+			{exp: count("weekly"), expected: 3},
+			{exp: count("weekly", types.ExpressionArg{DType: "num", Num: 150}), expected: 2},
+			{exp: count("weekly", types.ExpressionArg{DType: "num", Num: 50}), expected: 3},
+			{exp: count("intake"), expected: 1},
+			{exp: count("other"), expected: 0},
+		} {
+			ret, err := ExpressionEval(tc.exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				continue
+			}
+			if ret.(float64) != tc.expected {
+				t.Errorf("unexpected value for %v: %v, expected %v", tc.exp.Data, ret, tc.expected)
+			}
+		}
+	})
+
+	t.Run("without DB service", func(t *testing.T) {
+		if _, err := ExpressionEval(count("weekly"), EvalContext{ParticipantState: pState}); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
+
+func TestEvalStudyStatExpressions(t *testing.T) {
+	str := func(v string) types.ExpressionArg { return types.ExpressionArg{DType: "str", Str: v} }
+	EvalContext := EvalContext{
+		Event: types.StudyEvent{Type: "ENTER", InstanceID: "inst", StudyKey: "study"},
+		Configs: ActionConfigs{DBService: MockStudyDBService{
+			Stats: types.StudyStats{
+				ParticipantCount: 700,
+				ParticipantCounts: map[string]int64{
+					"active:age_group=65+": 500,
+					"exited:age_group=65+": 12,
+				},
+				ParticipantCountsUpdatedAt: time.Now().Unix(),
+			},
+			ConditionCounts: map[string]int64{
+				"active:age_group=65+":   510,
+				"active:age_group=18-64": 80,
+			},
+		}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		exp      types.Expression
+		expected float64
+	}{
+		{name: "study stat", exp: types.Expression{Name: "getStudyStat", Data: []types.ExpressionArg{str("participantCount")}}, expected: 700},
+		{name: "active participants with flag", exp: types.Expression{Name: "countParticipantsWithCondition", Data: []types.ExpressionArg{str("age_group"), str("65+")}}, expected: 500},
+		{name: "participants with status", exp: types.Expression{Name: "countParticipantsWithCondition", Data: []types.ExpressionArg{str("age_group"), str("65+"), str("exited")}}, expected: 12},
+		{name: "condition not cached", exp: types.Expression{Name: "countParticipantsWithCondition", Data: []types.ExpressionArg{str("age_group"), str("18-64")}}, expected: 80},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := ExpressionEval(tc.exp, EvalContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ret.(float64) != tc.expected {
+				t.Errorf("unexpected value: %v, expected %v", ret, tc.expected)
+			}
+		})
+	}
+
+	t.Run("stale count", func(t *testing.T) {
+		staleContext := EvalContext
+		mock := EvalContext.Configs.DBService.(MockStudyDBService)
+		mock.Stats.ParticipantCountsUpdatedAt = time.Now().Unix() - ParticipantCountsMaxAge - 10
+		staleContext.Configs = ActionConfigs{DBService: mock}
+		ret, err := ExpressionEval(types.Expression{Name: "countParticipantsWithCondition", Data: []types.ExpressionArg{str("age_group"), str("65+")}}, staleContext)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if ret.(float64) != 510 {
+			t.Errorf("stale count should be counted again: %v", ret)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, exp := range []types.Expression{
+			{Name: "getStudyStat", Data: []types.ExpressionArg{str("unknown")}},
+			{Name: "countParticipantsWithCondition", Data: []types.ExpressionArg{str("age_group"), str("0-17")}},
+		} {
+			if _, err := ExpressionEval(exp, EvalContext); err == nil {
+				t.Errorf("%s should return an error", exp.Name)
+			}
+		}
+		noDBContext := EvalContext
+		noDBContext.Configs = ActionConfigs{}
+		if _, err := ExpressionEval(types.Expression{Name: "getStudyStat", Data: []types.ExpressionArg{str("participantCount")}}, noDBContext); err == nil {
+			t.Error("should return an error without DB service")
+		}
+	})
+}
+
+func TestParticipantConditionsInRules(t *testing.T) {
+	str := func(v string) types.ExpressionArg { return types.ExpressionArg{DType: "str", Str: v} }
+	count := func(args ...types.ExpressionArg) types.ExpressionArg {
+		return types.ExpressionArg{DType: "exp", Exp: &types.Expression{Name: "countParticipantsWithCondition", Data: args}}
+	}
+	rules := []types.Expression{
+		{Name: "IFTHEN", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "lt", Data: []types.ExpressionArg{count(str("age_group"), str("65+")), {DType: "num", Num: 500}}}},
+		}},
+		{Name: "IFTHEN", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "gt", Data: []types.ExpressionArg{count(str("age_group"), str("65+")), count(str("age_group"), str("65+"), str("exited"))}}},
+		}},
+		{Name: "IFTHEN", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "gt", Data: []types.ExpressionArg{count(str("group"), types.ExpressionArg{DType: "exp", Exp: &types.Expression{Name: "getVar", Data: []types.ExpressionArg{str("g")}}})}}},
+		}},
+	}
+
+	conditions := ParticipantConditionsInRules(rules)
+	if len(conditions) != 2 || conditions[0].CounterKey() != "active:age_group=65+" || conditions[1].CounterKey() != "exited:age_group=65+" {
+		t.Errorf("unexpected conditions: %v", conditions)
+	}
+}
+
 func TestEvalEq(t *testing.T) {
 	t.Run("for eq numbers", func(t *testing.T) {
 		exp := types.Expression{Name: "eq", Data: []types.ExpressionArg{
@@ -2551,6 +3208,33 @@ func TestEvalNeg(t *testing.T) {
 }
 
 
+func TestEvalGetVar(t *testing.T) {
+	exp := types.Expression{Name: "getVar", Data: []types.ExpressionArg{
+		{DType: "str", Str: "age"},
+	}}
+
+	t.Run("with defined variable", func(t *testing.T) {
+		EvalContext := EvalContext{
+			Variables: map[string]interface{}{"age": 42.0},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret.(float64) != 42 {
+			t.Errorf("unexpected value: %v", ret)
+		}
+	})
+
+	t.Run("with undefined variable", func(t *testing.T) {
+		_, err := ExpressionEval(exp, EvalContext{})
+		if err == nil {
+			t.Error("should return error")
+		}
+	})
+}
+
 func TestEvalTimestampWithOffset(t *testing.T) {
 	t.Run("T + 0", func(t *testing.T) {
 		exp := types.Expression{Name: "timestampWithOffset", Data: []types.ExpressionArg{
@@ -2568,6 +3252,23 @@ func TestEvalTimestampWithOffset(t *testing.T) {
 		}
 	})
 
+	t.Run("T + 0 with simulated time", func(t *testing.T) {
+		exp := types.Expression{Name: "timestampWithOffset", Data: []types.ExpressionArg{
+			{DType: "num", Num: 0},
+		}}
+		EvalContext := EvalContext{
+			Configs: ActionConfigs{Now: func() time.Time { return time.Unix(1000000, 0) }},
+		}
+		ret, err := ExpressionEval(exp, EvalContext)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if int64(ret.(float64)) != 1000000 {
+			t.Errorf("unexpected value: %v - expected %d", ret, 1000000)
+		}
+	})
+
 	t.Run("T + 10", func(t *testing.T) {
 		exp := types.Expression{Name: "timestampWithOffset", Data: []types.ExpressionArg{
 			{DType: "num", Num: 10},
@@ -3082,8 +3783,8 @@ func TestEvalGenerateRandomNumber(t *testing.T) {
 
 func TestEvalParseValueAsNum(t *testing.T) {
 	testPState := types.ParticipantState{
-		Flags: map[string]string{
-			"testKey": "3",
+		Flags: types.ParticipantFlags{
+			"testKey": types.StringFlag("3"),
 		},
 	}
 
@@ -0,0 +1,382 @@
+package studyengine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+// Arithmetic, string and date operators.
+// All operators return an error if the number of arguments is wrong, if an argument cannot be resolved,
+// or if an argument does not resolve to the expected type. Numeric operators never return NaN or infinity.
+
+const secondsPerDay = 24 * 60 * 60
+
+func (ctx EvalContext) resolveNumArg(exp types.Expression, index int) (float64, error) {
+	arg, err := ctx.expressionArgResolver(exp.Data[index])
+	if err != nil {
+		return 0, err
+	}
+	v, ok := arg.(float64)
+	if !ok {
+		return 0, fmt.Errorf("argument %d should be resolved as type number (float64)", index+1)
+	}
+	return v, nil
+}
+
+func (ctx EvalContext) resolveStrArg(exp types.Expression, index int) (string, error) {
+	arg, err := ctx.expressionArgResolver(exp.Data[index])
+	if err != nil {
+		return "", err
+	}
+	v, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %d should be resolved as type string", index+1)
+	}
+	return v, nil
+}
+
+func (ctx EvalContext) resolveNumArgs(exp types.Expression) ([]float64, error) {
+	values := make([]float64, len(exp.Data))
+	for i := range exp.Data {
+		v, err := ctx.resolveNumArg(exp, i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func checkNumResult(v float64) (float64, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("result is not a finite number")
+	}
+	return v, nil
+}
+
+// mul returns the product of all arguments (at least two)
+func (ctx EvalContext) mul(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) < 2 {
+		return val, errors.New("should have at least two arguments")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	val = 1
+	for _, v := range values {
+		val = val * v
+	}
+	return checkNumResult(val)
+}
+
+// div returns the first argument divided by the second one, division by zero is an error
+func (ctx EvalContext) div(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 2 {
+		return val, errors.New("should have two arguments")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	if values[1] == 0 {
+		return val, errors.New("division by zero")
+	}
+	return checkNumResult(values[0] / values[1])
+}
+
+// mod returns the remainder of the division of the first argument by the second one, with the sign of the first argument
+func (ctx EvalContext) mod(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 2 {
+		return val, errors.New("should have two arguments")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	if values[1] == 0 {
+		return val, errors.New("division by zero")
+	}
+	return checkNumResult(math.Mod(values[0], values[1]))
+}
+
+// min returns the smallest of the arguments (at least one)
+func (ctx EvalContext) min(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) < 1 {
+		return val, errors.New("should have at least one argument")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	val = values[0]
+	for _, v := range values[1:] {
+		val = math.Min(val, v)
+	}
+	return
+}
+
+// max returns the largest of the arguments (at least one)
+func (ctx EvalContext) max(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) < 1 {
+		return val, errors.New("should have at least one argument")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	val = values[0]
+	for _, v := range values[1:] {
+		val = math.Max(val, v)
+	}
+	return
+}
+
+// round rounds half away from zero, to the number of decimals of the optional second argument (default 0)
+func (ctx EvalContext) round(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 1 && len(exp.Data) != 2 {
+		return val, errors.New("should have one or two arguments")
+	}
+	values, err := ctx.resolveNumArgs(exp)
+	if err != nil {
+		return val, err
+	}
+	if len(values) == 1 {
+		return math.Round(values[0]), nil
+	}
+	decimals := values[1]
+	if decimals < 0 || decimals > 15 || decimals != math.Trunc(decimals) {
+		return val, errors.New("argument 2 should be an integer between 0 and 15")
+	}
+	factor := math.Pow(10, decimals)
+	return checkNumResult(math.Round(values[0]*factor) / factor)
+}
+
+func (ctx EvalContext) floor(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("should have one argument")
+	}
+	v, err := ctx.resolveNumArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	return math.Floor(v), nil
+}
+
+func (ctx EvalContext) abs(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("should have one argument")
+	}
+	v, err := ctx.resolveNumArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	return math.Abs(v), nil
+}
+
+// concat joins strings and numbers (formatted without trailing zeros) into one string
+func (ctx EvalContext) concat(exp types.Expression) (val string, err error) {
+	if len(exp.Data) < 1 {
+		return val, errors.New("should have at least one argument")
+	}
+	var sb strings.Builder
+	for i, dataExp := range exp.Data {
+		arg, err := ctx.expressionArgResolver(dataExp)
+		if err != nil {
+			return val, err
+		}
+		switch v := arg.(type) {
+		case string:
+			sb.WriteString(v)
+		case float64:
+			sb.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return val, fmt.Errorf("argument %d should be resolved as type string or number", i+1)
+		}
+	}
+	return sb.String(), nil
+}
+
+// substr returns the part of the string starting at start (in characters, from 0) with the optional length.
+// Start or length beyond the end of the string are cut at the end, negative values are an error.
+func (ctx EvalContext) substr(exp types.Expression) (val string, err error) {
+	if len(exp.Data) != 2 && len(exp.Data) != 3 {
+		return val, errors.New("should have two or three arguments")
+	}
+	s, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	start, err := ctx.resolveNumArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	if start < 0 {
+		return val, errors.New("start should not be negative")
+	}
+	runes := []rune(s)
+	from := int(math.Min(start, float64(len(runes))))
+	to := len(runes)
+	if len(exp.Data) == 3 {
+		length, err := ctx.resolveNumArg(exp, 2)
+		if err != nil {
+			return val, err
+		}
+		if length < 0 {
+			return val, errors.New("length should not be negative")
+		}
+		to = int(math.Min(float64(from)+length, float64(len(runes))))
+	}
+	return string(runes[from:to]), nil
+}
+
+func (ctx EvalContext) contains(exp types.Expression) (val bool, err error) {
+	if len(exp.Data) != 2 {
+		return val, errors.New("should have two arguments")
+	}
+	s, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	sub, err := ctx.resolveStrArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	return strings.Contains(s, sub), nil
+}
+
+// regexMatch checks if the string matches the regular expression (RE2 syntax), an invalid pattern is an error
+func (ctx EvalContext) regexMatch(exp types.Expression) (val bool, err error) {
+	if len(exp.Data) != 2 {
+		return val, errors.New("should have two arguments")
+	}
+	s, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	pattern, err := ctx.resolveStrArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return val, fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return re.MatchString(s), nil
+}
+
+func (ctx EvalContext) toLower(exp types.Expression) (val string, err error) {
+	if len(exp.Data) != 1 {
+		return val, errors.New("should have one argument")
+	}
+	s, err := ctx.resolveStrArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	return strings.ToLower(s), nil
+}
+
+// resolveLocationArg loads the time zone of the optional argument at index, UTC if missing or empty
+func (ctx EvalContext) resolveLocationArg(exp types.Expression, index int) (*time.Location, error) {
+	if len(exp.Data) <= index {
+		return time.UTC, nil
+	}
+	tz, err := ctx.resolveStrArg(exp, index)
+	if err != nil {
+		return nil, err
+	}
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone: %s", tz)
+	}
+	return loc, nil
+}
+
+// dateDiffDays returns the number of calendar days from the first to the second timestamp (negative if the second is earlier),
+// in the optional time zone (default UTC)
+func (ctx EvalContext) dateDiffDays(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 2 && len(exp.Data) != 3 {
+		return val, errors.New("should have two or three arguments")
+	}
+	from, err := ctx.resolveNumArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	to, err := ctx.resolveNumArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	loc, err := ctx.resolveLocationArg(exp, 2)
+	if err != nil {
+		return val, err
+	}
+	y1, m1, d1 := time.Unix(int64(from), 0).In(loc).Date()
+	y2, m2, d2 := time.Unix(int64(to), 0).In(loc).Date()
+	// calendar dates are compared in UTC, so that daylight saving time changes do not matter
+	diff := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Unix() - time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC).Unix()
+	return float64(diff / secondsPerDay), nil
+}
+
+// startOfDay returns the timestamp of midnight in the time zone (empty for UTC) of the day of the optional timestamp (default now)
+func (ctx EvalContext) startOfDay(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 1 && len(exp.Data) != 2 {
+		return val, errors.New("should have one or two arguments")
+	}
+	loc, err := ctx.resolveLocationArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	t := ctx.Configs.now()
+	if len(exp.Data) == 2 {
+		ts, err := ctx.resolveNumArg(exp, 1)
+		if err != nil {
+			return val, err
+		}
+		t = time.Unix(int64(ts), 0)
+	}
+	y, m, d := t.In(loc).Date()
+	return float64(time.Date(y, m, d, 0, 0, 0, 0, loc).Unix()), nil
+}
+
+// addMonths adds calendar months to the timestamp, keeping the time of day in the optional time zone (default UTC).
+// If the day does not exist in the target month, the last day of the month is used (e.g. 31 January + 1 month = 28/29 February).
+func (ctx EvalContext) addMonths(exp types.Expression) (val float64, err error) {
+	if len(exp.Data) != 2 && len(exp.Data) != 3 {
+		return val, errors.New("should have two or three arguments")
+	}
+	ts, err := ctx.resolveNumArg(exp, 0)
+	if err != nil {
+		return val, err
+	}
+	months, err := ctx.resolveNumArg(exp, 1)
+	if err != nil {
+		return val, err
+	}
+	if months != math.Trunc(months) {
+		return val, errors.New("argument 2 should be an integer")
+	}
+	loc, err := ctx.resolveLocationArg(exp, 2)
+	if err != nil {
+		return val, err
+	}
+
+	t := time.Unix(int64(ts), 0).In(loc)
+	firstOfTargetMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, loc)
+	lastDay := firstOfTargetMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	result := time.Date(firstOfTargetMonth.Year(), firstOfTargetMonth.Month(), day, t.Hour(), t.Minute(), t.Second(), 0, loc)
+	return float64(result.Unix()), nil
+}
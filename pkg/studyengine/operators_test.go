@@ -0,0 +1,156 @@
+package studyengine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func numArg(v float64) types.ExpressionArg {
+	return types.ExpressionArg{DType: "num", Num: v}
+}
+
+func strArg(v string) types.ExpressionArg {
+	return types.ExpressionArg{DType: "str", Str: v}
+}
+
+type operatorTestCase struct {
+	name     string
+	exp      types.Expression
+	expected interface{}
+	hasError bool
+}
+
+func runOperatorTestCases(t *testing.T, testCases []operatorTestCase) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := ExpressionEval(tc.exp, EvalContext{})
+			if tc.hasError {
+				if err == nil {
+					t.Errorf("should return an error, got: %v", ret)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+			if ret != tc.expected {
+				t.Errorf("unexpected value: %v (%T) - expected %v", ret, ret, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEvalArithmeticOperators(t *testing.T) {
+	runOperatorTestCases(t, []operatorTestCase{
+		{name: "mul", exp: types.Expression{Name: "mul", Data: []types.ExpressionArg{numArg(2), numArg(3.5), numArg(-1)}}, expected: -7.0},
+		{name: "mul with one argument", exp: types.Expression{Name: "mul", Data: []types.ExpressionArg{numArg(2)}}, hasError: true},
+		{name: "mul with string", exp: types.Expression{Name: "mul", Data: []types.ExpressionArg{numArg(2), strArg("3")}}, hasError: true},
+		{name: "div", exp: types.Expression{Name: "div", Data: []types.ExpressionArg{numArg(7), numArg(2)}}, expected: 3.5},
+		{name: "div by zero", exp: types.Expression{Name: "div", Data: []types.ExpressionArg{numArg(7), numArg(0)}}, hasError: true},
+		{name: "mod", exp: types.Expression{Name: "mod", Data: []types.ExpressionArg{numArg(7), numArg(3)}}, expected: 1.0},
+		{name: "mod negative", exp: types.Expression{Name: "mod", Data: []types.ExpressionArg{numArg(-7), numArg(3)}}, expected: -1.0},
+		{name: "mod by zero", exp: types.Expression{Name: "mod", Data: []types.ExpressionArg{numArg(7), numArg(0)}}, hasError: true},
+		{name: "min", exp: types.Expression{Name: "min", Data: []types.ExpressionArg{numArg(3), numArg(-2), numArg(5)}}, expected: -2.0},
+		{name: "min without arguments", exp: types.Expression{Name: "min"}, hasError: true},
+		{name: "max", exp: types.Expression{Name: "max", Data: []types.ExpressionArg{numArg(3), numArg(-2), numArg(5)}}, expected: 5.0},
+		{name: "max single", exp: types.Expression{Name: "max", Data: []types.ExpressionArg{numArg(3)}}, expected: 3.0},
+		{name: "round", exp: types.Expression{Name: "round", Data: []types.ExpressionArg{numArg(2.5)}}, expected: 3.0},
+		{name: "round negative half", exp: types.Expression{Name: "round", Data: []types.ExpressionArg{numArg(-2.5)}}, expected: -3.0},
+		{name: "round with decimals", exp: types.Expression{Name: "round", Data: []types.ExpressionArg{numArg(22.8571), numArg(1)}}, expected: 22.9},
+		{name: "round with invalid decimals", exp: types.Expression{Name: "round", Data: []types.ExpressionArg{numArg(2.5), numArg(1.5)}}, hasError: true},
+		{name: "floor", exp: types.Expression{Name: "floor", Data: []types.ExpressionArg{numArg(-2.5)}}, expected: -3.0},
+		{name: "abs", exp: types.Expression{Name: "abs", Data: []types.ExpressionArg{numArg(-2.5)}}, expected: 2.5},
+		{name: "abs with string", exp: types.Expression{Name: "abs", Data: []types.ExpressionArg{strArg("x")}}, hasError: true},
+		{name: "BMI", exp: types.Expression{Name: "round", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "div", Data: []types.ExpressionArg{
+				numArg(70),
+				{DType: "exp", Exp: &types.Expression{Name: "mul", Data: []types.ExpressionArg{numArg(1.75), numArg(1.75)}}},
+			}}},
+			numArg(1),
+		}}, expected: 22.9},
+	})
+}
+
+func TestEvalStringOperators(t *testing.T) {
+	runOperatorTestCases(t, []operatorTestCase{
+		{name: "concat", exp: types.Expression{Name: "concat", Data: []types.ExpressionArg{strArg("week-"), numArg(12), strArg("."), numArg(1.5)}}, expected: "week-12.1.5"},
+		{name: "concat with invalid argument", exp: types.Expression{Name: "concat", Data: []types.ExpressionArg{
+			strArg("a"),
+			{DType: "exp", Exp: &types.Expression{Name: "contains", Data: []types.ExpressionArg{strArg("a"), strArg("a")}}},
+		}}, hasError: true},
+		{name: "substr", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("grippenet"), numArg(0), numArg(6)}}, expected: "grippe"},
+		{name: "substr to end", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("grippenet"), numArg(6)}}, expected: "net"},
+		{name: "substr with multibyte characters", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("fièvre"), numArg(2), numArg(2)}}, expected: "èv"},
+		{name: "substr beyond end", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("abc"), numArg(5), numArg(2)}}, expected: ""},
+		{name: "substr negative start", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("abc"), numArg(-1)}}, hasError: true},
+		{name: "substr negative length", exp: types.Expression{Name: "substr", Data: []types.ExpressionArg{strArg("abc"), numArg(0), numArg(-1)}}, hasError: true},
+		{name: "contains", exp: types.Expression{Name: "contains", Data: []types.ExpressionArg{strArg("a,b,c"), strArg("b")}}, expected: true},
+		{name: "not contains", exp: types.Expression{Name: "contains", Data: []types.ExpressionArg{strArg("a,b,c"), strArg("d")}}, expected: false},
+		{name: "regexMatch", exp: types.Expression{Name: "regexMatch", Data: []types.ExpressionArg{strArg("75013"), strArg("^[0-9]{5}$")}}, expected: true},
+		{name: "regexMatch not matching", exp: types.Expression{Name: "regexMatch", Data: []types.ExpressionArg{strArg("7501"), strArg("^[0-9]{5}$")}}, expected: false},
+		{name: "regexMatch invalid pattern", exp: types.Expression{Name: "regexMatch", Data: []types.ExpressionArg{strArg("a"), strArg("[")}}, hasError: true},
+		{name: "toLower", exp: types.Expression{Name: "toLower", Data: []types.ExpressionArg{strArg("ÉtÉ")}}, expected: "été"},
+		{name: "toLower with number", exp: types.Expression{Name: "toLower", Data: []types.ExpressionArg{numArg(1)}}, hasError: true},
+	})
+}
+
+func TestEvalDateOperators(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	ts := func(loc *time.Location, y int, m time.Month, d int, h int) float64 {
+		return float64(time.Date(y, m, d, h, 0, 0, 0, loc).Unix())
+	}
+
+	runOperatorTestCases(t, []operatorTestCase{
+		{name: "dateDiffDays", exp: types.Expression{Name: "dateDiffDays", Data: []types.ExpressionArg{
+			numArg(ts(time.UTC, 2024, 1, 1, 23)), numArg(ts(time.UTC, 2024, 3, 1, 1)),
+		}}, expected: 60.0},
+		{name: "dateDiffDays negative", exp: types.Expression{Name: "dateDiffDays", Data: []types.ExpressionArg{
+			numArg(ts(time.UTC, 2024, 3, 1, 1)), numArg(ts(time.UTC, 2024, 1, 1, 23)),
+		}}, expected: -60.0},
+		{name: "dateDiffDays with time zone", exp: types.Expression{Name: "dateDiffDays", Data: []types.ExpressionArg{
+			// 23:30 UTC is already the next day in Paris
+			numArg(ts(time.UTC, 2024, 3, 30, 23) + 1800), numArg(ts(time.UTC, 2024, 3, 31, 12)), strArg("Europe/Paris"),
+		}}, expected: 0.0},
+		{name: "dateDiffDays unknown time zone", exp: types.Expression{Name: "dateDiffDays", Data: []types.ExpressionArg{
+			numArg(0), numArg(0), strArg("Europe/Nowhere"),
+		}}, hasError: true},
+		{name: "startOfDay", exp: types.Expression{Name: "startOfDay", Data: []types.ExpressionArg{
+			strArg(""), numArg(ts(time.UTC, 2024, 5, 10, 15)),
+		}}, expected: ts(time.UTC, 2024, 5, 10, 0)},
+		{name: "startOfDay with time zone", exp: types.Expression{Name: "startOfDay", Data: []types.ExpressionArg{
+			strArg("Europe/Paris"), numArg(ts(time.UTC, 2024, 5, 10, 23)),
+		}}, expected: ts(paris, 2024, 5, 11, 0)},
+		{name: "addMonths", exp: types.Expression{Name: "addMonths", Data: []types.ExpressionArg{
+			numArg(ts(time.UTC, 2024, 1, 15, 10)), numArg(2),
+		}}, expected: ts(time.UTC, 2024, 3, 15, 10)},
+		{name: "addMonths end of month", exp: types.Expression{Name: "addMonths", Data: []types.ExpressionArg{
+			numArg(ts(time.UTC, 2024, 1, 31, 10)), numArg(1),
+		}}, expected: ts(time.UTC, 2024, 2, 29, 10)},
+		{name: "addMonths negative", exp: types.Expression{Name: "addMonths", Data: []types.ExpressionArg{
+			numArg(ts(time.UTC, 2024, 3, 31, 10)), numArg(-13),
+		}}, expected: ts(time.UTC, 2023, 2, 28, 10)},
+		{name: "addMonths keeps local time", exp: types.Expression{Name: "addMonths", Data: []types.ExpressionArg{
+			numArg(ts(paris, 2024, 3, 15, 9)), numArg(1), strArg("Europe/Paris"),
+		}}, expected: ts(paris, 2024, 4, 15, 9)},
+		{name: "addMonths with fraction", exp: types.Expression{Name: "addMonths", Data: []types.ExpressionArg{
+			numArg(0), numArg(1.5),
+		}}, hasError: true},
+	})
+
+	t.Run("startOfDay for now", func(t *testing.T) {
+		exp := types.Expression{Name: "startOfDay", Data: []types.ExpressionArg{strArg("UTC")}}
+		ret, err := ExpressionEval(exp, EvalContext{
+			Configs: ActionConfigs{Now: func() time.Time { return time.Unix(int64(ts(time.UTC, 2024, 5, 10, 15)), 0) }},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %s", err.Error())
+			return
+		}
+		if ret != ts(time.UTC, 2024, 5, 10, 0) {
+			t.Errorf("unexpected value: %v", ret)
+		}
+	})
+}
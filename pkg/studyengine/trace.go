@@ -0,0 +1,81 @@
+package studyengine
+
+import (
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+const (
+	TRACE_ENTRY_ACTION     = "action"
+	TRACE_ENTRY_EXPRESSION = "expression"
+)
+
+// TraceEntry describes one evaluated action or expression
+type TraceEntry struct {
+	Depth        int                         `json:"depth"` // nesting level in the rule, 0 for the rule itself
+	Type         string                      `json:"type"`  // see TRACE_ENTRY_* constants
+	Name         string                      `json:"name"`
+	Args         []interface{}               `json:"args,omitempty"` // arguments as resolved during evaluation, in order of resolution
+	Result       interface{}                 `json:"result,omitempty"`
+	Error        string                      `json:"error,omitempty"`
+	StateChanges *types.ParticipantStateDiff `json:"stateChanges,omitempty"` // for actions, if the participant state was modified
+}
+
+// Tracer records the evaluation steps of actions and expressions. Entries are ordered by the start of evaluation,
+// so nested evaluations follow their parent entry with a higher depth. A nil Tracer records nothing.
+type Tracer struct {
+	Entries []TraceEntry
+	open    []int // indexes of the entries currently being evaluated
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{
+		Entries: []TraceEntry{},
+	}
+}
+
+func (t *Tracer) begin(entryType string, name string) int {
+	if t == nil {
+		return -1
+	}
+	t.Entries = append(t.Entries, TraceEntry{
+		Depth: len(t.open),
+		Type:  entryType,
+		Name:  name,
+	})
+	index := len(t.Entries) - 1
+	t.open = append(t.open, index)
+	return index
+}
+
+func (t *Tracer) end(index int, result interface{}, err error) {
+	if t == nil || index < 0 {
+		return
+	}
+	t.Entries[index].Result = result
+	if err != nil {
+		t.Entries[index].Error = err.Error()
+	}
+	if len(t.open) > 0 {
+		t.open = t.open[:len(t.open)-1]
+	}
+}
+
+func (t *Tracer) endAction(index int, oldState types.ParticipantState, newState types.ParticipantState, err error) {
+	if t == nil || index < 0 {
+		return
+	}
+	diff := types.DiffParticipantStates(oldState, newState)
+	if !diff.IsEmpty() {
+		t.Entries[index].StateChanges = &diff
+	}
+	t.end(index, nil, err)
+}
+
+// addArg records a resolved argument for the entry currently being evaluated
+func (t *Tracer) addArg(value interface{}) {
+	if t == nil || len(t.open) == 0 {
+		return
+	}
+	index := t.open[len(t.open)-1]
+	t.Entries[index].Args = append(t.Entries[index].Args, value)
+}
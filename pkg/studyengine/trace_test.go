@@ -0,0 +1,99 @@
+package studyengine
+
+import (
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestTracer(t *testing.T) {
+	actionData := ActionData{
+		PState: types.ParticipantState{
+			ParticipantID: "participant1234",
+			StudyStatus:   types.PARTICIPANT_STUDY_STATUS_ACTIVE,
+			Flags: types.ParticipantFlags{
+				"group": types.StringFlag("a"),
+			},
+		},
+		ReportsToCreate: map[string]types.Report{},
+	}
+	event := types.StudyEvent{Type: "TIMER"}
+
+	rule := types.Expression{
+		Name: "IF",
+		Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "hasParticipantFlag", Data: []types.ExpressionArg{
+				{DType: "str", Str: "group"},
+				{DType: "str", Str: "b"},
+			}}},
+			{DType: "exp", Exp: &types.Expression{Name: "REMOVE_FLAG", Data: []types.ExpressionArg{
+				{DType: "str", Str: "group"},
+			}}},
+			{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{
+				{DType: "str", Str: "group"},
+				{DType: "str", Str: "b"},
+			}}},
+		},
+	}
+
+	t.Run("without tracer", func(t *testing.T) {
+		_, err := ActionEval(rule, actionData, event, ActionConfigs{})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("with tracer", func(t *testing.T) {
+		tracer := NewTracer()
+		newState, err := ActionEval(rule, actionData, event, ActionConfigs{Tracer: tracer})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if newState.PState.Flags["group"].String() != "b" {
+			t.Errorf("unexpected flags: %v", newState.PState.Flags)
+		}
+
+		if len(tracer.Entries) != 3 {
+			t.Errorf("unexpected number of entries: %v", tracer.Entries)
+			return
+		}
+		ifEntry := tracer.Entries[0]
+		if ifEntry.Name != "IF" || ifEntry.Depth != 0 || ifEntry.Type != TRACE_ENTRY_ACTION {
+			t.Errorf("unexpected entry: %v", ifEntry)
+		}
+		if len(ifEntry.Args) != 1 || ifEntry.Args[0] != false {
+			t.Errorf("condition result should be recorded: %v", ifEntry.Args)
+		}
+		if ifEntry.StateChanges == nil || len(ifEntry.StateChanges.UpdatedFlags) != 1 {
+			t.Errorf("unexpected state changes: %v", ifEntry.StateChanges)
+		}
+
+		condEntry := tracer.Entries[1]
+		if condEntry.Name != "hasParticipantFlag" || condEntry.Depth != 1 || condEntry.Type != TRACE_ENTRY_EXPRESSION {
+			t.Errorf("unexpected entry: %v", condEntry)
+		}
+		if condEntry.Result != false {
+			t.Errorf("unexpected result: %v", condEntry.Result)
+		}
+
+		actionEntry := tracer.Entries[2]
+		if actionEntry.Name != "UPDATE_FLAG" || actionEntry.Depth != 1 {
+			t.Errorf("unexpected entry: %v", actionEntry)
+		}
+		if len(actionEntry.Args) != 2 || actionEntry.Args[0] != "group" || actionEntry.Args[1] != "b" {
+			t.Errorf("unexpected args: %v", actionEntry.Args)
+		}
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		tracer := NewTracer()
+		_, err := ActionEval(types.Expression{Name: "UPDATE_FLAG"}, actionData, event, ActionConfigs{Tracer: tracer})
+		if err == nil {
+			t.Error("should return an error")
+		}
+		if len(tracer.Entries) != 1 || tracer.Entries[0].Error == "" {
+			t.Errorf("unexpected entries: %v", tracer.Entries)
+		}
+	})
+}
@@ -0,0 +1,447 @@
+package studyengine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+// ValueType describes the possible types an expression argument can resolve to
+type ValueType uint8
+
+const (
+	TypeStr ValueType = 1 << iota
+	TypeNum
+	TypeBool
+	TypeAction
+
+	TypeAny = TypeStr | TypeNum | TypeBool
+)
+
+func (t ValueType) String() string {
+	if t == TypeAny {
+		return "any"
+	}
+	names := []string{}
+	if t&TypeStr != 0 {
+		names = append(names, "str")
+	}
+	if t&TypeNum != 0 {
+		names = append(names, "num")
+	}
+	if t&TypeBool != 0 {
+		names = append(names, "bool")
+	}
+	if t&TypeAction != 0 {
+		names = append(names, "action")
+	}
+	if len(names) == 0 {
+		return "unknown"
+	}
+	return strings.Join(names, "|")
+}
+
+// ValidationError describes one problem found in a rule expression tree
+type ValidationError struct {
+	Path string // position of the problem in the expression tree, e.g. rules[2].data[1].data[0]
+	Name string // name of the expression or action the problem belongs to
+	Msg  string
+}
+
+func (e ValidationError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("%s (%s): %s", e.Path, e.Name, e.Msg)
+}
+
+// ValidationErrors is the list of all problems found during a validation run
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// signature defines the arguments an expression or action accepts and what it returns
+type signature struct {
+	args       []ValueType // expected type per argument position
+	minArgs    int
+	variadic   bool      // if true, the last argument type can be repeated
+	returns    ValueType // result type, only relevant for expressions
+	strLiteral bool      // if true, arguments must be string literals and not expressions
+}
+
+func (sig signature) maxArgs() int {
+	if sig.variadic {
+		return -1
+	}
+	return len(sig.args)
+}
+
+func (sig signature) argType(index int) ValueType {
+	if index < len(sig.args) {
+		return sig.args[index]
+	}
+	return sig.args[len(sig.args)-1]
+}
+
+// actionSignatures lists all actions handled by ActionEval
+var actionSignatures = map[string]signature{
+	"IF":                                  {args: []ValueType{TypeBool | TypeNum, TypeAction, TypeAction}, minArgs: 2},
+	"DO":                                  {args: []ValueType{TypeAction}, variadic: true},
+	"IFTHEN":                              {args: []ValueType{TypeBool | TypeNum, TypeAction}, minArgs: 1, variadic: true},
+	"LET":                                 {args: []ValueType{TypeStr, TypeAny}, minArgs: 2},
+	"UPDATE_STUDY_STATUS":                 {args: []ValueType{TypeStr}, minArgs: 1},
+	"START_NEW_STUDY_SESSION":             {},
+	"UPDATE_FLAG":                         {args: []ValueType{TypeStr, TypeAny, TypeStr, TypeNum}, minArgs: 2},
+	"REMOVE_FLAG":                         {args: []ValueType{TypeStr}, minArgs: 1},
+	"INCREMENT_FLAG":                      {args: []ValueType{TypeStr, TypeNum}, minArgs: 1},
+	"APPEND_TO_LIST_FLAG":                 {args: []ValueType{TypeStr, TypeAny}, minArgs: 2},
+	"REMOVE_FROM_LIST_FLAG":               {args: []ValueType{TypeStr, TypeAny}, minArgs: 2},
+	"ASSIGN_ARM":                          {args: []ValueType{TypeStr}, minArgs: 1, variadic: true},
+	"ADD_NEW_SURVEY":                      {args: []ValueType{TypeStr, TypeNum, TypeNum, TypeStr}, minArgs: 4},
+	"REMOVE_ALL_SURVEYS":                  {},
+	"REMOVE_SURVEY_BY_KEY":                {args: []ValueType{TypeStr, TypeStr}, minArgs: 2},
+	"REMOVE_SURVEYS_BY_KEY":               {args: []ValueType{TypeStr}, minArgs: 1},
+	"ADD_MESSAGE":                         {args: []ValueType{TypeStr, TypeNum}, minArgs: 2},
+	"REMOVE_ALL_MESSAGES":                 {},
+	"REMOVE_MESSAGES_BY_TYPE":             {args: []ValueType{TypeStr}, minArgs: 1},
+	"SCHEDULE_EVENT":                      {args: []ValueType{TypeStr, TypeNum}, minArgs: 2},
+	"CANCEL_SCHEDULED_EVENT":              {args: []ValueType{TypeStr}, minArgs: 1},
+	"NOTIFY_RESEARCHER":                   {args: []ValueType{TypeStr}, minArgs: 1, variadic: true},
+	"INIT_REPORT":                         {args: []ValueType{TypeStr}, minArgs: 1},
+	"UPDATE_REPORT_DATA":                  {args: []ValueType{TypeStr, TypeStr, TypeAny, TypeStr}, minArgs: 3},
+	"REMOVE_REPORT_DATA":                  {args: []ValueType{TypeStr, TypeStr}, minArgs: 2},
+	"CANCEL_REPORT":                       {args: []ValueType{TypeStr}, minArgs: 1},
+	"REMOVE_CONFIDENTIAL_RESPONSE_BY_KEY": {args: []ValueType{TypeStr}, minArgs: 1},
+	"REMOVE_ALL_CONFIDENTIAL_RESPONSES":   {},
+	"EXTERNAL_EVENT_HANDLER":              {args: []ValueType{TypeStr, TypeStr}, minArgs: 1},
+}
+
+// expressionSignatures lists all expressions handled by ExpressionEval
+var expressionSignatures = map[string]signature{
+	"checkEventType":       {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	"getEventName":         {returns: TypeStr},
+	"getEventPayloadValue": {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeAny},
+	"hasEventPayloadKey":   {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	// Response checkers:
+	"checkSurveyResponseKey":       {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	"responseHasKeysAny":           {args: []ValueType{TypeStr, TypeStr, TypeStr}, minArgs: 3, variadic: true, returns: TypeBool},
+	"responseHasOnlyKeysOtherThan": {args: []ValueType{TypeStr, TypeStr, TypeStr}, minArgs: 3, variadic: true, returns: TypeBool},
+	"getResponseValueAsNum":        {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeNum},
+	"getResponseValueAsStr":        {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeStr},
+	"getSelectedKeys":              {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeStr},
+	"countResponseItems":           {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeNum},
+	"hasResponseKey":               {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeBool},
+	"hasResponseKeyWithValue":      {args: []ValueType{TypeStr, TypeStr, TypeStr}, minArgs: 3, returns: TypeBool},
+	// Old responses:
+	"checkConditionForOldResponses": {args: []ValueType{TypeBool, TypeStr | TypeNum, TypeStr, TypeNum, TypeNum}, minArgs: 1, returns: TypeBool},
+	// Study stats:
+	"getStudyStat":                   {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum},
+	"countParticipantsWithCondition": {args: []ValueType{TypeStr, TypeStr, TypeStr}, minArgs: 2, returns: TypeNum, strLiteral: true},
+	// Participant state:
+	"getStudyEntryTime":            {returns: TypeNum},
+	"hasSurveyKeyAssigned":         {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool, strLiteral: true},
+	"getSurveyKeyAssignedFrom":     {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum, strLiteral: true},
+	"getSurveyKeyAssignedUntil":    {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum, strLiteral: true},
+	"hasStudyStatus":               {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	"hasParticipantFlag":           {args: []ValueType{TypeStr, TypeAny}, minArgs: 2, returns: TypeBool},
+	"hasParticipantFlagKey":        {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	"getParticipantFlagValue":      {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeStr},
+	"getParticipantFlagTypedValue": {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeAny},
+	"getParticipantFlagSetAt":      {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum},
+	"participantFlagOlderThan":     {args: []ValueType{TypeStr, TypeNum}, minArgs: 2, returns: TypeBool},
+	"getListFlagLength":            {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum},
+	"listFlagContains":             {args: []ValueType{TypeStr, TypeAny}, minArgs: 2, returns: TypeBool},
+	"lastSubmissionDateOlderThan":  {args: []ValueType{TypeNum, TypeStr}, minArgs: 1, returns: TypeBool},
+	"getSubmissionCount":           {args: []ValueType{TypeStr, TypeNum}, minArgs: 1, returns: TypeNum},
+	"getLastSubmissionTs":          {args: []ValueType{TypeStr}, returns: TypeNum},
+	"hasMessageTypeAssigned":       {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeBool},
+	"getMessageNextTime":           {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeNum},
+	// Logical and comparisions:
+	"eq":  {args: []ValueType{TypeStr | TypeNum, TypeStr | TypeNum}, minArgs: 2, returns: TypeBool},
+	"lt":  {args: []ValueType{TypeStr | TypeNum, TypeStr | TypeNum}, minArgs: 2, returns: TypeBool},
+	"lte": {args: []ValueType{TypeStr | TypeNum, TypeStr | TypeNum}, minArgs: 2, returns: TypeBool},
+	"gt":  {args: []ValueType{TypeStr | TypeNum, TypeStr | TypeNum}, minArgs: 2, returns: TypeBool},
+	"gte": {args: []ValueType{TypeStr | TypeNum, TypeStr | TypeNum}, minArgs: 2, returns: TypeBool},
+	"and": {args: []ValueType{TypeBool | TypeNum}, minArgs: 2, variadic: true, returns: TypeBool},
+	"or":  {args: []ValueType{TypeBool | TypeNum}, minArgs: 2, variadic: true, returns: TypeBool},
+	"not": {args: []ValueType{TypeBool | TypeNum}, minArgs: 1, returns: TypeBool},
+	// Arithmetics operators
+	"sum":   {args: []ValueType{TypeBool | TypeNum}, variadic: true, returns: TypeNum},
+	"neg":   {args: []ValueType{TypeNum}, minArgs: 1, returns: TypeNum},
+	"mul":   {args: []ValueType{TypeNum}, minArgs: 2, variadic: true, returns: TypeNum},
+	"div":   {args: []ValueType{TypeNum, TypeNum}, minArgs: 2, returns: TypeNum},
+	"mod":   {args: []ValueType{TypeNum, TypeNum}, minArgs: 2, returns: TypeNum},
+	"min":   {args: []ValueType{TypeNum}, minArgs: 1, variadic: true, returns: TypeNum},
+	"max":   {args: []ValueType{TypeNum}, minArgs: 1, variadic: true, returns: TypeNum},
+	"round": {args: []ValueType{TypeNum, TypeNum}, minArgs: 1, returns: TypeNum},
+	"floor": {args: []ValueType{TypeNum}, minArgs: 1, returns: TypeNum},
+	"abs":   {args: []ValueType{TypeNum}, minArgs: 1, returns: TypeNum},
+	// String operators
+	"concat":     {args: []ValueType{TypeStr | TypeNum}, minArgs: 1, variadic: true, returns: TypeStr},
+	"substr":     {args: []ValueType{TypeStr, TypeNum, TypeNum}, minArgs: 2, returns: TypeStr},
+	"contains":   {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeBool},
+	"regexMatch": {args: []ValueType{TypeStr, TypeStr}, minArgs: 2, returns: TypeBool},
+	"toLower":    {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeStr},
+	// Date operators
+	"dateDiffDays": {args: []ValueType{TypeNum, TypeNum, TypeStr}, minArgs: 2, returns: TypeNum},
+	"startOfDay":   {args: []ValueType{TypeStr, TypeNum}, minArgs: 1, returns: TypeNum},
+	"addMonths":    {args: []ValueType{TypeNum, TypeNum, TypeStr}, minArgs: 2, returns: TypeNum},
+	// Variables
+	"getVar": {args: []ValueType{TypeStr}, minArgs: 1, returns: TypeAny},
+	// Other
+	"timestampWithOffset":  {args: []ValueType{TypeNum, TypeNum}, minArgs: 1, returns: TypeNum},
+	"getISOWeekForTs":      {args: []ValueType{TypeNum}, minArgs: 1, returns: TypeNum},
+	"getTsForNextISOWeek":  {args: []ValueType{TypeNum, TypeNum}, minArgs: 1, returns: TypeNum},
+	"parseValueAsNum":      {args: []ValueType{TypeStr | TypeNum}, minArgs: 1, returns: TypeNum},
+	"generateRandomNumber": {args: []ValueType{TypeNum, TypeNum}, minArgs: 2, returns: TypeNum},
+	"externalEventEval":    {args: []ValueType{TypeStr, TypeStr}, minArgs: 1, returns: TypeAny},
+}
+
+// incomingStateExpressions can also be used with the "incomingState:" prefix during MERGE events
+var incomingStateExpressions = []string{
+	"getStudyEntryTime",
+	"hasSurveyKeyAssigned",
+	"getSurveyKeyAssignedFrom",
+	"getSurveyKeyAssignedUntil",
+	"hasStudyStatus",
+	"hasParticipantFlag",
+	"hasParticipantFlagKey",
+	"getParticipantFlagValue",
+	"getParticipantFlagTypedValue",
+	"getParticipantFlagSetAt",
+	"participantFlagOlderThan",
+	"getListFlagLength",
+	"listFlagContains",
+	"lastSubmissionDateOlderThan",
+	"getSubmissionCount",
+	"getLastSubmissionTs",
+	"hasMessageTypeAssigned",
+	"getMessageNextTime",
+}
+
+// comparisonExpressions require both arguments to be of the same type
+var comparisonExpressions = map[string]bool{
+	"eq": true, "lt": true, "lte": true, "gt": true, "gte": true,
+}
+
+// prefillRuleSignatures lists the rules handled by the survey prefill resolver
+var prefillRuleSignatures = map[string]signature{
+	"PREFILL_SLOT_WITH_VALUE": {args: []ValueType{TypeStr, TypeStr, TypeStr | TypeNum}, minArgs: 3},
+	"GET_LAST_SURVEY_ITEM":    {args: []ValueType{TypeStr, TypeStr, TypeNum}, minArgs: 2},
+}
+
+// contextRuleSignatures lists the previous response rules handled by the survey context resolver
+var contextRuleSignatures = map[string]signature{
+	"LAST_RESPONSES_BY_KEY":  {args: []ValueType{TypeStr, TypeNum}, minArgs: 2},
+	"ALL_RESPONSES_SINCE":    {args: []ValueType{TypeNum}, minArgs: 1},
+	"RESPONSES_SINCE_BY_KEY": {args: []ValueType{TypeNum, TypeStr}, minArgs: 2},
+}
+
+func init() {
+	for _, name := range incomingStateExpressions {
+		expressionSignatures["incomingState:"+name] = expressionSignatures[name]
+	}
+}
+
+type ruleValidator struct {
+	errs ValidationErrors
+}
+
+func (v *ruleValidator) addError(path string, name string, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{
+		Path: path,
+		Name: name,
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+// ValidateStudyRules checks the study rules (list of actions) for unknown names, wrong number of arguments and type mismatches
+func ValidateStudyRules(rules []types.Expression) ValidationErrors {
+	v := &ruleValidator{}
+	for i, rule := range rules {
+		v.action(rule, fmt.Sprintf("rules[%d]", i))
+	}
+	return v.errs
+}
+
+// ValidateStudyRule checks a single rule, path is the position of the rule used in the errors (e.g. rules[2])
+func ValidateStudyRule(rule types.Expression, path string) ValidationErrors {
+	v := &ruleValidator{}
+	v.action(rule, path)
+	return v.errs
+}
+
+// ValidateExpression checks a single expression and returns the inferred type of its result
+func ValidateExpression(exp types.Expression) (ValueType, ValidationErrors) {
+	v := &ruleValidator{}
+	t := v.expression(exp, "exp")
+	return t, v.errs
+}
+
+// ValidateSurveyPrefillRules checks the prefill rules of a survey definition
+func ValidateSurveyPrefillRules(rules []types.Expression) ValidationErrors {
+	v := &ruleValidator{}
+	for i, rule := range rules {
+		v.literalRule(rule, fmt.Sprintf("prefillRules[%d]", i), prefillRuleSignatures)
+	}
+	return v.errs
+}
+
+// ValidateSurveyContextRules checks the context rules of a survey definition
+func ValidateSurveyContextRules(rules *types.SurveyContextDef) ValidationErrors {
+	v := &ruleValidator{}
+	if rules == nil {
+		return v.errs
+	}
+	if rules.Mode != nil && rules.Mode.IsExpression() {
+		v.addError("contextRules.mode", "", "expression arg type not supported")
+	}
+	for i, rule := range rules.PreviousResponses {
+		v.literalRule(rule, fmt.Sprintf("contextRules.previousResponses[%d]", i), contextRuleSignatures)
+	}
+	return v.errs
+}
+
+func (v *ruleValidator) action(action types.Expression, path string) {
+	sig, ok := actionSignatures[action.Name]
+	if !ok {
+		if _, isExp := expressionSignatures[action.Name]; isExp {
+			v.addError(path, action.Name, "expected an action, but found an expression")
+			return
+		}
+		v.addError(path, action.Name, "action name not known")
+		return
+	}
+	v.args(action, sig, path)
+}
+
+func (v *ruleValidator) expression(exp types.Expression, path string) ValueType {
+	sig, ok := expressionSignatures[exp.Name]
+	if !ok {
+		if _, isAction := actionSignatures[exp.Name]; isAction {
+			v.addError(path, exp.Name, "expected an expression, but found an action")
+			return TypeAny
+		}
+		v.addError(path, exp.Name, "expression name not known")
+		return TypeAny
+	}
+	argTypes := v.args(exp, sig, path)
+
+	if exp.Name == "checkConditionForOldResponses" && len(exp.Data) > 0 && !exp.Data[0].IsExpression() {
+		v.addError(path+".data[0]", exp.Name, "first argument must be an expression")
+	}
+	if comparisonExpressions[exp.Name] && len(argTypes) == 2 {
+		if argTypes[0]&argTypes[1] == 0 {
+			v.addError(path, exp.Name, "arguments must be of the same type, but got %s and %s", argTypes[0], argTypes[1])
+		}
+	}
+	if exp.Name == "externalEventEval" && exp.ReturnType == "float" {
+		return TypeNum
+	}
+	return sig.returns
+}
+
+// args checks the arguments of an expression or action and returns the types they resolve to
+func (v *ruleValidator) args(exp types.Expression, sig signature, path string) []ValueType {
+	if len(exp.Data) < sig.minArgs || (sig.maxArgs() >= 0 && len(exp.Data) > sig.maxArgs()) {
+		v.addError(path, exp.Name, "unexpected number of arguments: %d (%s)", len(exp.Data), sig.arity())
+	}
+
+	argTypes := make([]ValueType, len(exp.Data))
+	for i, arg := range exp.Data {
+		argPath := fmt.Sprintf("%s.data[%d]", path, i)
+		if sig.maxArgs() >= 0 && i >= sig.maxArgs() {
+			argTypes[i] = TypeAny
+			continue
+		}
+		expected := sig.argType(i)
+		if sig.strLiteral && !arg.IsString() {
+			v.addError(argPath, exp.Name, "argument must be a string literal")
+		}
+		argTypes[i] = v.arg(arg, expected, exp.Name, argPath)
+	}
+	return argTypes
+}
+
+func (v *ruleValidator) arg(arg types.ExpressionArg, expected ValueType, parentName string, path string) ValueType {
+	if expected == TypeAction {
+		if !arg.IsExpression() || arg.Exp == nil {
+			v.addError(path, parentName, "expected an action as argument")
+			return TypeAction
+		}
+		v.action(*arg.Exp, path)
+		return TypeAction
+	}
+
+	var actual ValueType
+	switch arg.DType {
+	case "exp":
+		if arg.Exp == nil {
+			v.addError(path, parentName, "missing argument - expected expression, but was empty")
+			return expected
+		}
+		actual = v.expression(*arg.Exp, path)
+	case "num":
+		actual = TypeNum
+	case "str", "":
+		if arg.Exp != nil {
+			v.addError(path, parentName, "argument contains an expression, but dtype is not 'exp'")
+		}
+		actual = TypeStr
+	default:
+		v.addError(path, parentName, "unknown dtype: %s", arg.DType)
+		return expected
+	}
+
+	if actual&expected == 0 {
+		v.addError(path, parentName, "expected argument of type %s, but got %s", expected, actual)
+	}
+	return actual & expected
+}
+
+// literalRule checks rules that are not evaluated by the expression engine, and can use only literal arguments
+func (v *ruleValidator) literalRule(rule types.Expression, path string, signatures map[string]signature) {
+	sig, ok := signatures[rule.Name]
+	if !ok {
+		v.addError(path, rule.Name, "rule name not known")
+		return
+	}
+	if len(rule.Data) < sig.minArgs || len(rule.Data) > sig.maxArgs() {
+		v.addError(path, rule.Name, "unexpected number of arguments: %d (%s)", len(rule.Data), sig.arity())
+	}
+	for i, arg := range rule.Data {
+		if i >= sig.maxArgs() {
+			break
+		}
+		argPath := fmt.Sprintf("%s.data[%d]", path, i)
+		if arg.IsExpression() {
+			v.addError(argPath, rule.Name, "expression arg type not supported")
+			continue
+		}
+		actual := TypeStr
+		if arg.IsNumber() {
+			actual = TypeNum
+		}
+		if actual&sig.argType(i) == 0 {
+			v.addError(argPath, rule.Name, "expected argument of type %s, but got %s", sig.argType(i), actual)
+		}
+	}
+}
+
+func (sig signature) arity() string {
+	switch {
+	case sig.variadic:
+		return fmt.Sprintf("expected at least %d", sig.minArgs)
+	case sig.minArgs == sig.maxArgs():
+		return fmt.Sprintf("expected %d", sig.minArgs)
+	default:
+		return fmt.Sprintf("expected %d to %d", sig.minArgs, sig.maxArgs())
+	}
+}
@@ -0,0 +1,207 @@
+package studyengine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestValidationSignaturesAreKnownByEngine(t *testing.T) {
+	evalUnknown := func(f func() error) (unknown bool) {
+		defer func() {
+			// wrong arguments can panic, but the name was dispatched
+			_ = recover()
+		}()
+		err := f()
+		return err != nil && strings.Contains(err.Error(), "name not known")
+	}
+
+	for name := range actionSignatures {
+		unknown := evalUnknown(func() error {
+			_, err := ActionEval(types.Expression{Name: name}, ActionData{ReportsToCreate: map[string]types.Report{}}, types.StudyEvent{}, ActionConfigs{})
+			return err
+		})
+		if unknown {
+			t.Errorf("action %s not known by ActionEval", name)
+		}
+	}
+
+	for name := range expressionSignatures {
+		unknown := evalUnknown(func() error {
+			_, err := ExpressionEval(types.Expression{Name: name}, EvalContext{})
+			return err
+		})
+		if unknown {
+			t.Errorf("expression %s not known by ExpressionEval", name)
+		}
+	}
+}
+
+func TestValidateStudyRules(t *testing.T) {
+	t.Run("with valid rules", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "IFTHEN", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "checkEventType", Data: []types.ExpressionArg{{DType: "str", Str: "SUBMIT"}}}},
+				{DType: "exp", Exp: &types.Expression{Name: "UPDATE_FLAG", Data: []types.ExpressionArg{
+					{DType: "str", Str: "lastWeek"},
+					{DType: "exp", Exp: &types.Expression{Name: "getISOWeekForTs", Data: []types.ExpressionArg{
+						{DType: "exp", Exp: &types.Expression{Name: "timestampWithOffset", Data: []types.ExpressionArg{{DType: "num", Num: 0}}}},
+					}}},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "REMOVE_ALL_MESSAGES"}},
+			}},
+			{Name: "LET", Data: []types.ExpressionArg{
+				{DType: "str", Str: "weight"},
+				{DType: "exp", Exp: &types.Expression{Name: "getResponseValueAsNum", Data: []types.ExpressionArg{{DType: "str", Str: "weekly.Q1"}, {DType: "str", Str: "rg.num"}}}},
+			}},
+			{Name: "IFTHEN", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "gt", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "getVar", Data: []types.ExpressionArg{{DType: "str", Str: "weight"}}}},
+					{DType: "num", Num: 100},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "REMOVE_ALL_MESSAGES"}},
+			}},
+			{Name: "IF", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "eq", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "incomingState:getParticipantFlagValue", Data: []types.ExpressionArg{{Str: "key"}}}},
+					{DType: "str", Str: "value"},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "START_NEW_STUDY_SESSION"}},
+			}},
+		}
+		errs := ValidateStudyRules(rules)
+		if len(errs) > 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("with unknown names", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "wrong"},
+			{Name: "IF", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "wrongExp"}},
+				{DType: "exp", Exp: &types.Expression{Name: "checkEventType", Data: []types.ExpressionArg{{Str: "ENTER"}}}},
+			}},
+		}
+		errs := ValidateStudyRules(rules)
+		if len(errs) != 3 {
+			t.Errorf("unexpected number of errors: %v", errs)
+			return
+		}
+		if errs[0].Path != "rules[0]" || errs[0].Msg != "action name not known" {
+			t.Errorf("unexpected error: %v", errs[0])
+		}
+		if errs[1].Path != "rules[1].data[0]" || errs[1].Msg != "expression name not known" {
+			t.Errorf("unexpected error: %v", errs[1])
+		}
+		if errs[2].Path != "rules[1].data[1]" || errs[2].Msg != "expected an action, but found an expression" {
+			t.Errorf("unexpected error: %v", errs[2])
+		}
+	})
+
+	t.Run("with wrong number of arguments", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "ADD_MESSAGE", Data: []types.ExpressionArg{{DType: "str", Str: "msg"}}},
+			{Name: "REMOVE_ALL_SURVEYS", Data: []types.ExpressionArg{{DType: "str", Str: "extra"}}},
+		}
+		errs := ValidateStudyRules(rules)
+		if len(errs) != 2 {
+			t.Errorf("unexpected number of errors: %v", errs)
+			return
+		}
+		if errs[0].Msg != "unexpected number of arguments: 1 (expected 2)" {
+			t.Errorf("unexpected error: %v", errs[0])
+		}
+		if errs[1].Msg != "unexpected number of arguments: 1 (expected 0)" {
+			t.Errorf("unexpected error: %v", errs[1])
+		}
+	})
+
+	t.Run("with type mismatches", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "ADD_MESSAGE", Data: []types.ExpressionArg{
+				{DType: "str", Str: "msg"},
+				{DType: "exp", Exp: &types.Expression{Name: "getResponseValueAsStr", Data: []types.ExpressionArg{{Str: "s.q1"}, {Str: "rg.1"}}}},
+			}},
+			{Name: "IF", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "lt", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "getStudyEntryTime"}},
+					{DType: "str", Str: "100"},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "START_NEW_STUDY_SESSION"}},
+			}},
+		}
+		errs := ValidateStudyRules(rules)
+		if len(errs) != 2 {
+			t.Errorf("unexpected number of errors: %v", errs)
+			return
+		}
+		if errs[0].Path != "rules[0].data[1]" || errs[0].Msg != "expected argument of type num, but got str" {
+			t.Errorf("unexpected error: %v", errs[0])
+		}
+		if errs[1].Path != "rules[1].data[0]" || errs[1].Name != "lt" {
+			t.Errorf("unexpected error: %v", errs[1])
+		}
+	})
+
+	t.Run("with expression instead of string literal", func(t *testing.T) {
+		rules := []types.Expression{
+			{Name: "IF", Data: []types.ExpressionArg{
+				{DType: "exp", Exp: &types.Expression{Name: "hasSurveyKeyAssigned", Data: []types.ExpressionArg{
+					{DType: "exp", Exp: &types.Expression{Name: "getParticipantFlagValue", Data: []types.ExpressionArg{{Str: "survey"}}}},
+				}}},
+				{DType: "exp", Exp: &types.Expression{Name: "REMOVE_ALL_SURVEYS"}},
+			}},
+		}
+		errs := ValidateStudyRules(rules)
+		if len(errs) != 1 || errs[0].Msg != "argument must be a string literal" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("single rule", func(t *testing.T) {
+		errs := ValidateStudyRule(types.Expression{Name: "IF", Data: []types.ExpressionArg{
+			{DType: "exp", Exp: &types.Expression{Name: "wrongExp"}},
+			{DType: "exp", Exp: &types.Expression{Name: "START_NEW_STUDY_SESSION"}},
+		}}, "rules[3]")
+		if len(errs) != 1 || errs[0].Path != "rules[3].data[0]" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+}
+
+func TestValidateSurveyRules(t *testing.T) {
+	t.Run("prefill rules", func(t *testing.T) {
+		errs := ValidateSurveyPrefillRules([]types.Expression{
+			{Name: "GET_LAST_SURVEY_ITEM", Data: []types.ExpressionArg{{Str: "weekly"}, {Str: "weekly.Q1"}}},
+			{Name: "PREFILL_SLOT_WITH_VALUE", Data: []types.ExpressionArg{{Str: "weekly.Q1"}, {Str: "rg.1"}, {DType: "num", Num: 2}}},
+			{Name: "GET_LAST_SURVEY_ITEM", Data: []types.ExpressionArg{{Str: "weekly"}, {DType: "exp", Exp: &types.Expression{Name: "getStudyEntryTime"}}}},
+			{Name: "UNKNOWN"},
+		})
+		if len(errs) != 2 {
+			t.Errorf("unexpected number of errors: %v", errs)
+			return
+		}
+		if errs[0].Path != "prefillRules[2].data[1]" || errs[1].Path != "prefillRules[3]" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("context rules", func(t *testing.T) {
+		errs := ValidateSurveyContextRules(&types.SurveyContextDef{
+			Mode: &types.ExpressionArg{DType: "exp", Exp: &types.Expression{Name: "getStudyEntryTime"}},
+			PreviousResponses: []types.Expression{
+				{Name: "LAST_RESPONSES_BY_KEY", Data: []types.ExpressionArg{{Str: "intake"}, {DType: "num", Num: 1}}},
+				{Name: "ALL_RESPONSES_SINCE", Data: []types.ExpressionArg{{Str: "yesterday"}}},
+			},
+		})
+		if len(errs) != 2 {
+			t.Errorf("unexpected number of errors: %v", errs)
+			return
+		}
+		if errs[0].Path != "contextRules.mode" || errs[1].Path != "contextRules.previousResponses[1].data[0]" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+}
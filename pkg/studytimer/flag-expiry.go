@@ -0,0 +1,73 @@
+package studytimer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/dbs/studydb"
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+// PerformFlagExpiry removes the expired flags of participants and fires a FLAG_EXPIRED event for each of them
+func (s *StudyTimerService) PerformFlagExpiry(ctx context.Context, instanceID string, study types.Study) {
+	rules, rulesVersionID, err := s.studyDBService.GetStudyRulesWithVersionID(instanceID, study.Key)
+	if err != nil {
+		logger.Error.Printf("ERROR in PerformFlagExpiry.GetStudyRules (%s, %s): %v", instanceID, study.Key, err)
+		return
+	}
+
+	now := time.Now().Unix()
+	if err := s.studyDBService.FindAndExecuteOnParticipantsWithExpiredFlags(ctx, instanceID, study.Key, now, s.performFlagExpiryForParticipant, rules, study, rulesVersionID, now); err != nil {
+		logger.Error.Printf("ERROR in PerformFlagExpiry.FindAndExecuteOnParticipantsWithExpiredFlags (%s, %s): %v", instanceID, study.Key, err)
+	}
+}
+
+func (s *StudyTimerService) performFlagExpiryForParticipant(
+	studyDBServ *studydb.StudyDBService,
+	pState types.ParticipantState,
+	instanceID string,
+	studyKey string,
+	args ...interface{},
+) (err error) {
+	if len(args) != 4 {
+		err = errors.New("unexpected number of args")
+		logger.Error.Printf("ERROR in performFlagExpiryForParticipant: %v", err)
+		return
+	}
+	rules := args[0].([]types.Expression)
+	study := args[1].(types.Study)
+	rulesVersionID := args[2].(string)
+	now := args[3].(int64)
+
+	// expired flags are removed before the rules are evaluated, rules can set them again
+	return s.runEventsForParticipant(studyDBServ, pState, instanceID, study, rules, rulesVersionID, func(pState types.ParticipantState) (types.ParticipantState, []types.StudyEvent) {
+		pState, expired := removeExpiredFlags(pState, now)
+		events := make([]types.StudyEvent, len(expired))
+		for i, info := range expired {
+			events[i] = types.StudyEvent{Type: types.FLAG_EXPIRED_EVENT, FlagKey: info.Key}
+		}
+		return pState, events
+	})
+}
+
+// removeExpiredFlags removes the flags expired at now and their infos from the state, and returns the infos of the removed flags
+func removeExpiredFlags(pState types.ParticipantState, now int64) (types.ParticipantState, types.FlagInfos) {
+	expired := pState.FlagInfos.Expired(now)
+	if len(expired) == 0 {
+		return pState, expired
+	}
+	flags := make(types.ParticipantFlags, len(pState.Flags))
+	for k, v := range pState.Flags {
+		flags[k] = v
+	}
+	infos := pState.FlagInfos
+	for _, info := range expired {
+		delete(flags, info.Key)
+		infos = infos.Remove(info.Key)
+	}
+	pState.Flags = flags
+	pState.FlagInfos = infos
+	return pState, expired
+}
@@ -0,0 +1,44 @@
+package studytimer
+
+import (
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestRemoveExpiredFlags(t *testing.T) {
+	pState := types.ParticipantState{
+		Flags: types.ParticipantFlags{
+			"episode": types.BoolFlag(true),
+			"recent":  types.StringFlag("a"),
+			"group":   types.StringFlag("b"),
+		},
+		FlagInfos: types.FlagInfos{
+			{Key: "recent", SetAt: 100, ExpiresAt: 300},
+			{Key: "episode", SetAt: 50, ExpiresAt: 150},
+			{Key: "group", SetAt: 50},
+		},
+	}
+
+	newState, expired := removeExpiredFlags(pState, 200)
+	if len(expired) != 1 || expired[0].Key != "episode" {
+		t.Errorf("unexpected expired flags: %v", expired)
+	}
+	if _, ok := newState.Flags["episode"]; ok || len(newState.Flags) != 2 {
+		t.Errorf("unexpected flags: %v", newState.Flags)
+	}
+	if _, ok := newState.FlagInfos.Get("episode"); ok || len(newState.FlagInfos) != 2 {
+		t.Errorf("unexpected flag infos: %v", newState.FlagInfos)
+	}
+	if _, ok := pState.Flags["episode"]; !ok || len(pState.FlagInfos) != 3 {
+		t.Error("original state should not be modified")
+	}
+
+	newState, expired = removeExpiredFlags(pState, 400)
+	if len(expired) != 2 || expired[0].Key != "episode" || expired[1].Key != "recent" {
+		t.Errorf("unexpected expired flags: %v", expired)
+	}
+	if len(newState.Flags) != 1 || len(newState.FlagInfos) != 1 {
+		t.Errorf("unexpected state: %v, %v", newState.Flags, newState.FlagInfos)
+	}
+}
@@ -0,0 +1,147 @@
+package studytimer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/dbs/studydb"
+	"github.com/influenzanet/study-service/pkg/studyengine"
+	"github.com/influenzanet/study-service/pkg/types"
+	"github.com/influenzanet/study-service/pkg/utils"
+)
+
+// PerformScheduledEvents fires the named timer events (TIMER:<name>) of participants that are due now
+func (s *StudyTimerService) PerformScheduledEvents(ctx context.Context, instanceID string, study types.Study) {
+	rules, rulesVersionID, err := s.studyDBService.GetStudyRulesWithVersionID(instanceID, study.Key)
+	if err != nil {
+		logger.Error.Printf("ERROR in PerformScheduledEvents.GetStudyRules (%s, %s): %v", instanceID, study.Key, err)
+		return
+	}
+
+	now := time.Now().Unix()
+	if err := s.studyDBService.FindAndExecuteOnParticipantsWithDueEvents(ctx, instanceID, study.Key, now, s.performDueEventsForParticipant, rules, study, rulesVersionID, now); err != nil {
+		logger.Error.Printf("ERROR in PerformScheduledEvents.FindAndExecuteOnParticipantsWithDueEvents (%s, %s): %v", instanceID, study.Key, err)
+	}
+}
+
+func (s *StudyTimerService) performDueEventsForParticipant(
+	studyDBServ *studydb.StudyDBService,
+	pState types.ParticipantState,
+	instanceID string,
+	studyKey string,
+	args ...interface{},
+) (err error) {
+	if len(args) != 4 {
+		err = errors.New("unexpected number of args")
+		logger.Error.Printf("ERROR in performDueEventsForParticipant: %v", err)
+		return
+	}
+	rules := args[0].([]types.Expression)
+	study := args[1].(types.Study)
+	rulesVersionID := args[2].(string)
+	now := args[3].(int64)
+
+	// due events are removed from the state before their rules are evaluated, so they are fired only once
+	return s.runEventsForParticipant(studyDBServ, pState, instanceID, study, rules, rulesVersionID, func(pState types.ParticipantState) (types.ParticipantState, []types.StudyEvent) {
+		due, remaining := splitDueEvents(pState.ScheduledEvents, now)
+		pState.ScheduledEvents = remaining
+		events := make([]types.StudyEvent, len(due))
+		for i, scheduledEvent := range due {
+			events[i] = types.StudyEvent{Type: types.TIMER_EVENT_PREFIX + scheduledEvent.Name}
+		}
+		return pState, events
+	})
+}
+
+// runEventsForParticipant takes the events to fire from the participant state, evaluates the rules for each of them in order,
+// and saves the resulting state, the event history and the reports. takeEvents is called again if the state has to be reloaded
+// because of a concurrent update.
+func (s *StudyTimerService) runEventsForParticipant(
+	studyDBServ *studydb.StudyDBService,
+	pState types.ParticipantState,
+	instanceID string,
+	study types.Study,
+	rules []types.Expression,
+	rulesVersionID string,
+	takeEvents func(pState types.ParticipantState) (types.ParticipantState, []types.StudyEvent),
+) (err error) {
+	participantID2, err := utils.ProfileIDtoParticipantID(pState.ParticipantID, s.studyGlobalSecret, study.SecretKey, study.Configs.IdMappingMethod)
+	if err != nil {
+		logger.Error.Printf("unexpected error when computing confidential participant id: %v", err)
+		return
+	}
+
+	var actionState studyengine.ActionData
+	var history []types.ParticipantEvent
+	deferred := studyengine.NewDeferredEffects()
+	_, err = studyDBServ.UpdateParticipantStateWithRetry(instanceID, study.Key, pState, func(pState types.ParticipantState) (types.ParticipantState, error) {
+		pState, events := takeEvents(pState)
+		actionState = studyengine.ActionData{
+			PState:          pState,
+			ReportsToCreate: map[string]types.Report{},
+		}
+		history = []types.ParticipantEvent{}
+
+		for _, studyEvent := range events {
+			studyEvent.InstanceID = instanceID
+			studyEvent.StudyKey = study.Key
+			studyEvent.ParticipantIDForConfidentialResponses = participantID2
+
+			stateBefore := actionState.PState
+			// variables defined with LET are scoped to one event
+			actionState.Variables = nil
+			for _, rule := range rules {
+				var err error
+				actionState, err = studyengine.ActionEval(rule, actionState, studyEvent, studyengine.ActionConfigs{
+					DBService:              s.studyDBService,
+					ExternalServiceConfigs: s.studyEngineExternalServices,
+					Deferred:               deferred,
+				})
+				if err != nil {
+					logger.Error.Printf("ERROR in runEventsForParticipant.ActionEval (%s, %s, %s): %v", instanceID, study.Key, studyEvent.Type, err)
+					continue
+				}
+			}
+			history = append(history, types.NewParticipantEvent(studyEvent, rulesVersionID, stateBefore, actionState.PState))
+		}
+		return actionState.PState, nil
+	})
+	if err != nil {
+		logger.Error.Printf("unexpected error when saving participant state: %v", err)
+		return err
+	}
+	studyengine.PerformDeferredEffects(s.studyDBService, instanceID, study.Key, actionState)
+
+	for _, e := range history {
+		if err := studyDBServ.AddParticipantEvent(instanceID, study.Key, e); err != nil {
+			logger.Error.Printf("unexpected error while saving participant event: %v", err)
+		}
+	}
+	for _, report := range actionState.ReportsToCreate {
+		report.ResponseID = "TIMER"
+		if err := studyDBServ.SaveReport(instanceID, study.Key, report); err != nil {
+			logger.Error.Printf("unexpected error while save report: %v", err)
+		}
+	}
+	return nil
+}
+
+// splitDueEvents returns the events due at now, ordered by their due time, and the remaining events
+func splitDueEvents(events []types.ScheduledEvent, now int64) (due []types.ScheduledEvent, remaining []types.ScheduledEvent) {
+	due = []types.ScheduledEvent{}
+	remaining = []types.ScheduledEvent{}
+	for _, e := range events {
+		if e.DueAt <= now {
+			due = append(due, e)
+		} else {
+			remaining = append(remaining, e)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].DueAt < due[j].DueAt
+	})
+	return
+}
@@ -0,0 +1,28 @@
+package studytimer
+
+import (
+	"testing"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestSplitDueEvents(t *testing.T) {
+	events := []types.ScheduledEvent{
+		{Name: "later", DueAt: 300},
+		{Name: "second", DueAt: 200},
+		{Name: "first", DueAt: 100},
+	}
+
+	due, remaining := splitDueEvents(events, 200)
+	if len(due) != 2 || due[0].Name != "first" || due[1].Name != "second" {
+		t.Errorf("unexpected due events: %v", due)
+	}
+	if len(remaining) != 1 || remaining[0].Name != "later" {
+		t.Errorf("unexpected remaining events: %v", remaining)
+	}
+
+	due, remaining = splitDueEvents(nil, 200)
+	if len(due) != 0 || len(remaining) != 0 {
+		t.Errorf("unexpected result for no events: %v, %v", due, remaining)
+	}
+}
@@ -1,12 +1,17 @@
 package studytimer
 
 import (
+	"context"
 	"math/rand"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/coneno/logger"
 	"github.com/influenzanet/study-service/pkg/dbs/globaldb"
 	"github.com/influenzanet/study-service/pkg/dbs/studydb"
 	"github.com/influenzanet/study-service/pkg/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type StudyTimerService struct {
@@ -14,14 +19,24 @@ type StudyTimerService struct {
 	studyDBService              *studydb.StudyDBService
 	studyEngineExternalServices []types.ExternalService
 	studyGlobalSecret           string
-	TimerEventFrequency         int64 // how often the timer event should be performed (only from one instance of the service) - seconds
-	TimerEventCheckIntervalMin  int   // approx. how often this serice should check if to perform the timer event - seconds
-	TimerEventCheckIntervalVar  int   // range of the uniform random distribution - varying the check interval to avoid a steady collisions
+	TimerEventFrequency         int64  // how often the timer event should be performed (only from one instance of the service) - seconds
+	TimerEventCheckIntervalMin  int    // approx. how often this serice should check if to perform the timer event - seconds
+	TimerEventCheckIntervalVar  int    // range of the uniform random distribution - varying the check interval to avoid a steady collisions
+	TimerLeaseDuration          int64  // how long the lease for a study's timer event is valid without renewal - seconds
+	ownerID                     string // identifies this replica as owner of timer leases
+
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup // in-flight timer runs
+	stopMutex    sync.Mutex     // orders starting runs (wg.Add) before Stop waits for them
+	runningMutex sync.Mutex
+	running      map[string]bool // instanceID/studyKey combinations currently processed
 }
 
 func NewStudyTimerService(config types.StudyConfig, studyDBServ *studydb.StudyDBService, globalDBServ *globaldb.GlobalDBService, studyEngineExternalServices []types.ExternalService,
 	StudyGlobalSecret string,
 ) *StudyTimerService {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &StudyTimerService{
 		globalDBService:             globalDBServ,
 		studyDBService:              studyDBServ,
@@ -29,19 +44,99 @@ func NewStudyTimerService(config types.StudyConfig, studyDBServ *studydb.StudyDB
 		TimerEventFrequency:         config.TimerEventFrequency,
 		TimerEventCheckIntervalMin:  config.TimerEventCheckIntervalMin,
 		TimerEventCheckIntervalVar:  config.TimerEventCheckIntervalVar,
+		TimerLeaseDuration:          config.TimerLeaseDuration,
+		ownerID:                     newOwnerID(),
 		studyEngineExternalServices: studyEngineExternalServices,
+		ctx:                         ctx,
+		cancel:                      cancel,
+		running:                     map[string]bool{},
+	}
+}
+
+// newOwnerID is unique per process, the hostname helps to find the replica holding a lease
+func newOwnerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
 	}
+	return hostname + "-" + primitive.NewObjectID().Hex()
 }
 
 func (s *StudyTimerService) Run() {
 	go s.startTimerThread(s.TimerEventCheckIntervalMin, s.TimerEventCheckIntervalVar)
 }
 
+// Stop cancels the timer thread and the running timer events, and waits until in-flight runs have returned
+// or ctx is done.
+func (s *StudyTimerService) Stop(ctx context.Context) error {
+	logger.Info.Println("stopping study timer service...")
+	s.stopMutex.Lock()
+	s.cancel()
+	s.stopMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info.Println("study timer service stopped")
+		return nil
+	case <-ctx.Done():
+		logger.Error.Printf("study timer service did not stop in time: %v", ctx.Err())
+		return ctx.Err()
+	}
+}
+
 func (s *StudyTimerService) startTimerThread(timeCheckIntervalMin int, timeCheckIntervalRange int) {
-	// TODO: turn of gracefully
 	for {
 		delay := rand.Intn(timeCheckIntervalRange) + timeCheckIntervalMin
-		<-time.After(time.Duration(delay) * time.Second)
-		go s.StudyTimerEvent()
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(time.Duration(delay) * time.Second):
+		}
+
+		if !s.startRun(s.StudyTimerEvent) {
+			return
+		}
 	}
 }
+
+// startRun calls run in a new goroutine tracked by Stop, returns false without calling it if the service is stopped
+func (s *StudyTimerService) startRun(run func(ctx context.Context)) bool {
+	s.stopMutex.Lock()
+	defer s.stopMutex.Unlock()
+
+	if s.ctx.Err() != nil {
+		return false
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		run(s.ctx)
+	}()
+	return true
+}
+
+// tryStartStudyRun marks the study as being processed, returns false if a run for the study is already in progress
+func (s *StudyTimerService) tryStartStudyRun(instanceID string, studyKey string) bool {
+	s.runningMutex.Lock()
+	defer s.runningMutex.Unlock()
+
+	key := instanceID + "/" + studyKey
+	if s.running[key] {
+		return false
+	}
+	s.running[key] = true
+	return true
+}
+
+func (s *StudyTimerService) finishStudyRun(instanceID string, studyKey string) {
+	s.runningMutex.Lock()
+	defer s.runningMutex.Unlock()
+
+	delete(s.running, instanceID+"/"+studyKey)
+}
@@ -0,0 +1,95 @@
+package studytimer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/types"
+)
+
+func TestStudyRunGuard(t *testing.T) {
+	s := NewStudyTimerService(types.StudyConfig{}, nil, nil, nil, "")
+
+	if !s.tryStartStudyRun("instance", "study1") {
+		t.Error("first run should start")
+	}
+	if s.tryStartStudyRun("instance", "study1") {
+		t.Error("second run for same study should be skipped")
+	}
+	if !s.tryStartStudyRun("instance", "study2") {
+		t.Error("run for other study should start")
+	}
+	s.finishStudyRun("instance", "study1")
+	if !s.tryStartStudyRun("instance", "study1") {
+		t.Error("run should start after previous one finished")
+	}
+}
+
+func TestStop(t *testing.T) {
+	t.Run("waits for in-flight runs", func(t *testing.T) {
+		s := NewStudyTimerService(types.StudyConfig{}, nil, nil, nil, "")
+		finished := false
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			<-s.ctx.Done()
+			finished = true
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := s.Stop(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if !finished {
+			t.Error("run should be finished")
+		}
+	})
+
+	t.Run("no runs started after stop", func(t *testing.T) {
+		s := NewStudyTimerService(types.StudyConfig{}, nil, nil, nil, "")
+		if err := s.Stop(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if s.startRun(func(ctx context.Context) {
+			t.Error("run should not be called")
+		}) {
+			t.Error("run should not start")
+		}
+	})
+
+	t.Run("started run is awaited", func(t *testing.T) {
+		s := NewStudyTimerService(types.StudyConfig{}, nil, nil, nil, "")
+		finished := make(chan bool, 1)
+		if !s.startRun(func(ctx context.Context) {
+			<-ctx.Done()
+			finished <- true
+		}) {
+			t.Error("run should start")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := s.Stop(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		select {
+		case <-finished:
+		default:
+			t.Error("run should be finished")
+		}
+	})
+
+	t.Run("with timeout", func(t *testing.T) {
+		s := NewStudyTimerService(types.StudyConfig{}, nil, nil, nil, "")
+		s.wg.Add(1)
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := s.Stop(ctx); err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
@@ -3,15 +3,18 @@ package studytimer
 import (
 	"context"
 	"errors"
+	"reflect"
+	"time"
 
 	"github.com/coneno/logger"
 	"github.com/influenzanet/study-service/pkg/dbs/studydb"
 	"github.com/influenzanet/study-service/pkg/studyengine"
 	"github.com/influenzanet/study-service/pkg/types"
 	"github.com/influenzanet/study-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (s *StudyTimerService) StudyTimerEvent() {
+func (s *StudyTimerService) StudyTimerEvent(ctx context.Context) {
 	instances, err := s.globalDBService.GetAllInstances()
 	if err != nil {
 		logger.Error.Printf("unexpected error: %s", err.Error())
@@ -23,22 +26,68 @@ func (s *StudyTimerService) StudyTimerEvent() {
 			return
 		}
 		for _, study := range studies {
-			if err := s.studyDBService.ShouldPerformTimerEvent(instance.InstanceID, study.Key, s.TimerEventFrequency); err != nil {
-				continue
+			if ctx.Err() != nil {
+				logger.Info.Printf("timer event cancelled: %v", ctx.Err())
+				return
 			}
-			logger.Info.Printf("performing timer event for study: %s - %s", instance.InstanceID, study.Key)
+			s.performTimerEventForStudy(ctx, instance.InstanceID, study)
+		}
+	}
+}
+
+func (s *StudyTimerService) performTimerEventForStudy(ctx context.Context, instanceID string, study types.Study) {
+	if !s.tryStartStudyRun(instanceID, study.Key) {
+		logger.Info.Printf("timer event for study %s - %s is still running, skipped.", instanceID, study.Key)
+		return
+	}
+	defer s.finishStudyRun(instanceID, study.Key)
+
+	// only the replica holding the lease performs the timer event
+	lease, err := s.studyDBService.AcquireTimerLease(instanceID, study.Key, s.ownerID, s.TimerLeaseDuration)
+	if err != nil {
+		if err != studydb.ErrTimerLeaseNotAcquired {
+			logger.Error.Printf("unexpected error when acquiring timer lease for study %s - %s: %v", instanceID, study.Key, err)
+		}
+		return
+	}
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	go s.keepLeaseAlive(runCtx, cancelRun, instanceID, study.Key)
 
-			s.UpdateStudyStats(instance.InstanceID, study.Key)
-			s.UpdateParticipantStates(instance.InstanceID, study)
+	// named timer events and flag expiry are checked on each tick, independently of the study's timer schedule
+	s.PerformScheduledEvents(runCtx, instanceID, study)
+	s.PerformFlagExpiry(runCtx, instanceID, study)
+
+	if lease.HasCheckpoint() {
+		logger.Info.Printf("resuming interrupted timer event for study: %s - %s", instanceID, study.Key)
+	} else {
+		now := time.Now()
+		schedule := study.Configs.TimerSchedule
+		if !schedule.IsInWindow(now) {
+			s.releaseLease(instanceID, study.Key, false)
+			return
 		}
+		nextRun := schedule.NextRunAfter(now, s.TimerEventFrequency)
+		if err := s.studyDBService.ShouldPerformTimerEvent(instanceID, study.Key, nextRun.Unix()); err != nil {
+			s.releaseLease(instanceID, study.Key, false)
+			return
+		}
+		logger.Info.Printf("performing timer event for study: %s - %s", instanceID, study.Key)
 	}
+
+	s.UpdateStudyStats(instanceID, study.Key)
+	completed := s.UpdateParticipantStates(runCtx, cancelRun, instanceID, study, lease.Checkpoint)
+	s.releaseLease(instanceID, study.Key, completed)
 }
 
-func (s *StudyTimerService) UpdateParticipantStates(instanceID string, study types.Study) {
-	rules, err := s.studyDBService.GetStudyRules(instanceID, study.Key)
+// UpdateParticipantStates performs the timer event for all active participants with an _id after checkpoint.
+// After each participant, a new checkpoint is saved. Returns true if all participants were processed.
+func (s *StudyTimerService) UpdateParticipantStates(ctx context.Context, cancelRun context.CancelFunc, instanceID string, study types.Study, checkpoint primitive.ObjectID) bool {
+	rules, rulesVersionID, err := s.studyDBService.GetStudyRulesWithVersionID(instanceID, study.Key)
 	if err != nil {
 		logger.Error.Printf("ERROR in UpdateParticipantStates.GetStudyRules (%s, %s): %v", instanceID, study.Key, err)
-		return
+		return false
 	}
 
 	studyEvent := types.StudyEvent{
@@ -49,13 +98,25 @@ func (s *StudyTimerService) UpdateParticipantStates(instanceID string, study typ
 
 	if !s.hasRuleForEventType(rules, studyEvent) {
 		logger.Info.Printf("UpdateParticipantStates (%s, %s): has no timer related rules, skipped.", instanceID, study.Key)
-		return
+		return true
+	}
+
+	updateAndSaveCheckpoint := func(studyDBServ *studydb.StudyDBService, pState types.ParticipantState, instanceID string, studyKey string, args ...interface{}) error {
+		err := s.getAndUpdateParticipantState(studyDBServ, pState, instanceID, studyKey, args...)
+		if cpErr := studyDBServ.SaveTimerLeaseCheckpoint(instanceID, studyKey, s.ownerID, pState.ID); cpErr != nil {
+			logger.Error.Printf("ERROR in UpdateParticipantStates.SaveTimerLeaseCheckpoint (%s, %s): %v", instanceID, studyKey, cpErr)
+			if cpErr == studydb.ErrTimerLeaseLost {
+				cancelRun()
+			}
+		}
+		return err
 	}
 
-	ctx := context.Background()
-	if err := s.studyDBService.FindAndExecuteOnParticipantsStates(ctx, instanceID, study.Key, types.STUDY_STATUS_ACTIVE, s.getAndUpdateParticipantState, rules, studyEvent, study); err != nil {
+	if err := s.studyDBService.FindAndExecuteOnParticipantsStatesAfter(ctx, instanceID, study.Key, types.STUDY_STATUS_ACTIVE, checkpoint, updateAndSaveCheckpoint, rules, studyEvent, study, rulesVersionID); err != nil {
 		logger.Error.Printf("ERROR in UpdateParticipantStates.FindAndExecuteOnParticipantsStates (%s, %s): %v", instanceID, study.Key, err)
+		return false
 	}
+	return ctx.Err() == nil
 }
 
 func (s *StudyTimerService) getAndUpdateParticipantState(
@@ -65,7 +126,7 @@ func (s *StudyTimerService) getAndUpdateParticipantState(
 	studyKey string,
 	args ...interface{},
 ) (err error) {
-	if len(args) != 3 {
+	if len(args) != 4 {
 		err = errors.New("unexpected number of args")
 		logger.Error.Printf("ERROR in getAndUpdateParticipantState: %v", err)
 		return
@@ -75,6 +136,7 @@ func (s *StudyTimerService) getAndUpdateParticipantState(
 	studyEvent.StudyKey = studyKey
 	studyEvent.InstanceID = instanceID
 	study := args[2].(types.Study)
+	rulesVersionID := args[3].(string)
 
 	participantID2, err := utils.ProfileIDtoParticipantID(pState.ParticipantID, s.studyGlobalSecret, study.SecretKey, study.Configs.IdMappingMethod)
 	if err != nil {
@@ -83,26 +145,39 @@ func (s *StudyTimerService) getAndUpdateParticipantState(
 	}
 	studyEvent.ParticipantIDForConfidentialResponses = participantID2
 
-	actionState := studyengine.ActionData{
-		PState:          pState,
-		ReportsToCreate: map[string]types.Report{},
-	}
-
-	for _, rule := range rules {
-		actionState, err = studyengine.ActionEval(rule, actionState, studyEvent, studyengine.ActionConfigs{
-			DBService:              s.studyDBService,
-			ExternalServiceConfigs: s.studyEngineExternalServices,
-		})
-		if err != nil {
-			logger.Error.Printf("ERROR in getAndUpdateParticipantState.ActionEval (%s, %s): %v", instanceID, studyKey, err)
-			continue
+	var actionState studyengine.ActionData
+	oldState := pState
+	deferred := studyengine.NewDeferredEffects()
+	savedState, err := studyDBServ.UpdateParticipantStateWithRetry(instanceID, studyKey, pState, func(pState types.ParticipantState) (types.ParticipantState, error) {
+		oldState = pState
+		actionState = studyengine.ActionData{
+			PState:          pState,
+			ReportsToCreate: map[string]types.Report{},
 		}
-	}
-
-	// save state back to DB
-	_, err = studyDBServ.SaveParticipantState(instanceID, studyKey, actionState.PState)
+		for _, rule := range rules {
+			var err error
+			actionState, err = studyengine.ActionEval(rule, actionState, studyEvent, studyengine.ActionConfigs{
+				DBService:              s.studyDBService,
+				ExternalServiceConfigs: s.studyEngineExternalServices,
+				Deferred:               deferred,
+			})
+			if err != nil {
+				logger.Error.Printf("ERROR in getAndUpdateParticipantState.ActionEval (%s, %s): %v", instanceID, studyKey, err)
+				continue
+			}
+		}
+		return actionState.PState, nil
+	})
 	if err != nil {
 		logger.Error.Printf("unexpected error when saving participant state: %v", err)
+		return err
+	}
+	studyengine.PerformDeferredEffects(s.studyDBService, instanceID, studyKey, actionState)
+	if !reflect.DeepEqual(oldState, actionState.PState) {
+		// timer events are only added to the participant's history, if they changed the state
+		if err := studyDBServ.AddParticipantEvent(instanceID, studyKey, types.NewParticipantEvent(studyEvent, rulesVersionID, oldState, savedState)); err != nil {
+			logger.Error.Printf("unexpected error while saving participant event: %v", err)
+		}
 	}
 
 	for _, report := range actionState.ReportsToCreate {
@@ -145,10 +220,19 @@ func (s *StudyTimerService) UpdateStudyStats(instanceID string, studyKey string)
 		logger.Error.Printf("DB ERROR for response counting for study: %s -> %s", studyKey, err.Error())
 	}
 
+	// participant counts used by the study rules (countParticipantsWithCondition)
+	rules, _, err := s.studyDBService.GetStudyRulesWithVersionID(instanceID, studyKey)
+	if err != nil {
+		logger.Error.Printf("DB ERROR for fetching rules for study: %s -> %s", studyKey, err.Error())
+	}
+	participantCounts := studyengine.CountParticipantConditions(s.studyDBService, instanceID, studyKey, rules)
+
 	if err := s.studyDBService.UpdateStudyStats(instanceID, studyKey, types.StudyStats{
-		ParticipantCount:     pCount,
-		TempParticipantCount: tpCount,
-		ResponseCount:        rCount,
+		ParticipantCount:           pCount,
+		TempParticipantCount:       tpCount,
+		ResponseCount:              rCount,
+		ParticipantCounts:          participantCounts,
+		ParticipantCountsUpdatedAt: time.Now().Unix(),
 	}); err != nil {
 		logger.Error.Printf("DB ERROR for updating stats for study: %s -> %s", studyKey, err.Error())
 	}
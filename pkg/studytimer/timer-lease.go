@@ -0,0 +1,44 @@
+package studytimer
+
+import (
+	"context"
+	"time"
+
+	"github.com/coneno/logger"
+	"github.com/influenzanet/study-service/pkg/dbs/studydb"
+)
+
+// keepLeaseAlive renews the timer lease of the study until ctx is done. If the lease was lost
+// (e.g. expired and taken over by another replica), the run is cancelled.
+func (s *StudyTimerService) keepLeaseAlive(ctx context.Context, cancelRun context.CancelFunc, instanceID string, studyKey string) {
+	interval := time.Duration(s.TimerLeaseDuration) * time.Second / 3
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.studyDBService.RenewTimerLease(instanceID, studyKey, s.ownerID, s.TimerLeaseDuration)
+			if err == studydb.ErrTimerLeaseLost {
+				logger.Error.Printf("timer lease for study %s - %s lost, cancelling run", instanceID, studyKey)
+				cancelRun()
+				return
+			} else if err != nil {
+				logger.Error.Printf("unexpected error when renewing timer lease for study %s - %s: %v", instanceID, studyKey, err)
+			}
+		}
+	}
+}
+
+// releaseLease gives up the lease of the study. If the run was not completed, the checkpoint is kept
+// to resume the run later.
+func (s *StudyTimerService) releaseLease(instanceID string, studyKey string, runCompleted bool) {
+	if err := s.studyDBService.ReleaseTimerLease(instanceID, studyKey, s.ownerID, runCompleted); err != nil {
+		logger.Error.Printf("unexpected error when releasing timer lease for study %s - %s: %v", instanceID, studyKey, err)
+	}
+}
@@ -13,6 +13,7 @@ type StudyConfig struct {
 	TimerEventFrequency        int64  // how often the timer event should be performed (only from one instance of the service) - seconds
 	TimerEventCheckIntervalMin int    // approx. how often this serice should check if to perform the timer event - seconds
 	TimerEventCheckIntervalVar int    // range of the uniform random distribution - varying the check interval to avoid a steady collisions
+	TimerLeaseDuration         int64  // how long a replica holds the lease for a study's timer event without renewal - seconds
 }
 
 type ExternalService struct {
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/api"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PARTICIPANT_EVENT_TYPE_CUSTOM_RULES = "CUSTOM_RULES" // rules run by researchers, e.g. with RunRules
+)
+
+// ParticipantEvent is an entry of the participant's event history, describing how an event changed the participant state
+type ParticipantEvent struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	ParticipantID  string               `bson:"participantID" json:"participantID"`
+	Timestamp      int64                `bson:"timestamp" json:"timestamp"`
+	EventType      string               `bson:"eventType" json:"eventType"`                               // ENTER, SUBMIT, TIMER, MERGE, LEAVE, or see PARTICIPANT_EVENT_TYPE_* constants
+	EventKey       string               `bson:"eventKey,omitempty" json:"eventKey,omitempty"`             // e.g. survey key for SUBMIT events, flag key for FLAG_EXPIRED events, user ID for CUSTOM_RULES events
+	RulesVersionID string               `bson:"rulesVersionID,omitempty" json:"rulesVersionID,omitempty"` // study rules used to handle the event
+	StateChanges   ParticipantStateDiff `bson:"stateChanges" json:"stateChanges"`
+}
+
+// NewParticipantEvent creates the event history entry for a study event handled with the given rules version
+func NewParticipantEvent(event StudyEvent, rulesVersionID string, oldState ParticipantState, newState ParticipantState) ParticipantEvent {
+	eventType := event.Type
+	if eventType == "" {
+		eventType = PARTICIPANT_EVENT_TYPE_CUSTOM_RULES
+	}
+	eventKey := event.Response.Key
+	if event.FlagKey != "" {
+		eventKey = event.FlagKey
+	}
+	if event.UserID != "" {
+		eventKey = event.UserID
+	}
+	return ParticipantEvent{
+		ParticipantID:  newState.ParticipantID,
+		Timestamp:      time.Now().Unix(),
+		EventType:      eventType,
+		EventKey:       eventKey,
+		RulesVersionID: rulesVersionID,
+		StateChanges:   DiffParticipantStates(oldState, newState),
+	}
+}
+
+func (e ParticipantEvent) ToAPI() *api.ParticipantEvent {
+	return &api.ParticipantEvent{
+		Id:             e.ID.Hex(),
+		ParticipantId:  e.ParticipantID,
+		Timestamp:      e.Timestamp,
+		EventType:      e.EventType,
+		EventKey:       e.EventKey,
+		RulesVersionId: e.RulesVersionID,
+		StateChanges:   e.StateChanges.ToAPI(),
+	}
+}
@@ -0,0 +1,410 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/api"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FLAG_DTYPE_STRING    = "string"
+	FLAG_DTYPE_NUMBER    = "number"
+	FLAG_DTYPE_BOOL      = "bool"
+	FLAG_DTYPE_TIMESTAMP = "timestamp" // POSIX timestamp in seconds, stored as date in the DB
+	FLAG_DTYPE_LIST      = "list"
+)
+
+// FlagValue is the typed value of a participant flag.
+// In the DB, values are stored with their native BSON type (string, double, boolean, date or array),
+// so flags saved as plain strings by previous versions are read as string flags.
+type FlagValue struct {
+	DType string
+	Str   string
+	Num   float64 // value of number and timestamp flags
+	Bool  bool
+	List  []FlagValue // items of list flags, lists cannot be nested
+}
+
+func StringFlag(v string) FlagValue {
+	return FlagValue{DType: FLAG_DTYPE_STRING, Str: v}
+}
+
+func NumberFlag(v float64) FlagValue {
+	return FlagValue{DType: FLAG_DTYPE_NUMBER, Num: v}
+}
+
+func BoolFlag(v bool) FlagValue {
+	return FlagValue{DType: FLAG_DTYPE_BOOL, Bool: v}
+}
+
+func TimestampFlag(ts int64) FlagValue {
+	return FlagValue{DType: FLAG_DTYPE_TIMESTAMP, Num: float64(ts)}
+}
+
+func ListFlag(items ...FlagValue) FlagValue {
+	return FlagValue{DType: FLAG_DTYPE_LIST, List: items}
+}
+
+// FlagValueFromInterface converts a value resolved by the study engine (string, float64 or bool)
+func FlagValueFromInterface(v interface{}) (FlagValue, error) {
+	switch value := v.(type) {
+	case string:
+		return StringFlag(value), nil
+	case float64:
+		return NumberFlag(value), nil
+	case bool:
+		return BoolFlag(value), nil
+	default:
+		return FlagValue{}, fmt.Errorf("unsupported flag value type: %T", v)
+	}
+}
+
+// Value returns the value as used by the study engine: string, float64 (number and timestamp), bool or []interface{} for lists
+func (f FlagValue) Value() interface{} {
+	switch f.DType {
+	case FLAG_DTYPE_NUMBER, FLAG_DTYPE_TIMESTAMP:
+		return f.Num
+	case FLAG_DTYPE_BOOL:
+		return f.Bool
+	case FLAG_DTYPE_LIST:
+		items := make([]interface{}, len(f.List))
+		for i, item := range f.List {
+			items[i] = item.Value()
+		}
+		return items
+	default:
+		return f.Str
+	}
+}
+
+// String returns the value in the format string flags used before typed flags, e.g. "5.000000" for numbers.
+// List items are joined with a comma.
+func (f FlagValue) String() string {
+	switch f.DType {
+	case FLAG_DTYPE_NUMBER, FLAG_DTYPE_TIMESTAMP:
+		return fmt.Sprintf("%f", f.Num)
+	case FLAG_DTYPE_BOOL:
+		return fmt.Sprintf("%t", f.Bool)
+	case FLAG_DTYPE_LIST:
+		items := make([]string, len(f.List))
+		for i, item := range f.List {
+			items[i] = item.String()
+		}
+		return strings.Join(items, ",")
+	default:
+		return f.Str
+	}
+}
+
+func (f FlagValue) dtype() string {
+	if f.DType == "" {
+		return FLAG_DTYPE_STRING
+	}
+	return f.DType
+}
+
+func (f FlagValue) Equal(o FlagValue) bool {
+	if f.dtype() != o.dtype() {
+		return false
+	}
+	switch f.dtype() {
+	case FLAG_DTYPE_NUMBER, FLAG_DTYPE_TIMESTAMP:
+		return f.Num == o.Num
+	case FLAG_DTYPE_BOOL:
+		return f.Bool == o.Bool
+	case FLAG_DTYPE_LIST:
+		if len(f.List) != len(o.List) {
+			return false
+		}
+		for i := range f.List {
+			if !f.List[i].Equal(o.List[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return f.Str == o.Str
+	}
+}
+
+func (f FlagValue) bsonValue() (interface{}, error) {
+	switch f.dtype() {
+	case FLAG_DTYPE_STRING:
+		return f.Str, nil
+	case FLAG_DTYPE_NUMBER:
+		return f.Num, nil
+	case FLAG_DTYPE_BOOL:
+		return f.Bool, nil
+	case FLAG_DTYPE_TIMESTAMP:
+		return primitive.NewDateTimeFromTime(time.Unix(int64(f.Num), 0)), nil
+	case FLAG_DTYPE_LIST:
+		items := bson.A{}
+		for _, item := range f.List {
+			if item.DType == FLAG_DTYPE_LIST {
+				return nil, errors.New("nested list flags are not supported")
+			}
+			v, err := item.bsonValue()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown flag dtype: %s", f.DType)
+	}
+}
+
+func (f FlagValue) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	v, err := f.bsonValue()
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(v)
+}
+
+func (f *FlagValue) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	rv := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.String:
+		*f = StringFlag(rv.StringValue())
+	case bsontype.Double:
+		*f = NumberFlag(rv.Double())
+	case bsontype.Int32:
+		*f = NumberFlag(float64(rv.Int32()))
+	case bsontype.Int64:
+		*f = NumberFlag(float64(rv.Int64()))
+	case bsontype.Boolean:
+		*f = BoolFlag(rv.Boolean())
+	case bsontype.DateTime:
+		*f = TimestampFlag(rv.Time().Unix())
+	case bsontype.Null, bsontype.Undefined:
+		*f = StringFlag("")
+	case bsontype.Array:
+		values, err := rv.Array().Values()
+		if err != nil {
+			return err
+		}
+		items := make([]FlagValue, len(values))
+		for i, v := range values {
+			if err := items[i].UnmarshalBSONValue(v.Type, v.Value); err != nil {
+				return err
+			}
+		}
+		*f = ListFlag(items...)
+	default:
+		return fmt.Errorf("unsupported BSON type for flag value: %s", t)
+	}
+	return nil
+}
+
+// MarshalJSON writes the native JSON value, timestamps are written as numbers
+func (f FlagValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Value())
+}
+
+func (f *FlagValue) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	value, err := flagValueFromJSON(v)
+	if err != nil {
+		return err
+	}
+	*f = value
+	return nil
+}
+
+func flagValueFromJSON(v interface{}) (FlagValue, error) {
+	switch value := v.(type) {
+	case nil:
+		return StringFlag(""), nil
+	case []interface{}:
+		items := make([]FlagValue, len(value))
+		for i, item := range value {
+			if _, isList := item.([]interface{}); isList {
+				return FlagValue{}, errors.New("nested list flags are not supported")
+			}
+			f, err := flagValueFromJSON(item)
+			if err != nil {
+				return FlagValue{}, err
+			}
+			items[i] = f
+		}
+		return ListFlag(items...), nil
+	default:
+		return FlagValueFromInterface(v)
+	}
+}
+
+func (f FlagValue) ToAPI() *api.FlagValue {
+	list := make([]*api.FlagValue, len(f.List))
+	for i, item := range f.List {
+		list[i] = item.ToAPI()
+	}
+	return &api.FlagValue{
+		Dtype:   f.dtype(),
+		Str:     f.Str,
+		Num:     f.Num,
+		Boolean: f.Bool,
+		List:    list,
+	}
+}
+
+func FlagValueFromAPI(f *api.FlagValue) FlagValue {
+	if f == nil {
+		return StringFlag("")
+	}
+	var list []FlagValue
+	if len(f.List) > 0 {
+		list = make([]FlagValue, len(f.List))
+		for i, item := range f.List {
+			list[i] = FlagValueFromAPI(item)
+		}
+	}
+	dtype := f.Dtype
+	if dtype == "" {
+		dtype = FLAG_DTYPE_STRING
+	}
+	return FlagValue{
+		DType: dtype,
+		Str:   f.Str,
+		Num:   f.Num,
+		Bool:  f.Boolean,
+		List:  list,
+	}
+}
+
+// ParticipantFlags maps flag keys to their typed values
+type ParticipantFlags map[string]FlagValue
+
+// StringValues returns the flags in the format of string flags, as used by clients not aware of typed flags
+func (flags ParticipantFlags) StringValues() map[string]string {
+	if flags == nil {
+		return nil
+	}
+	values := make(map[string]string, len(flags))
+	for k, v := range flags {
+		values[k] = v.String()
+	}
+	return values
+}
+
+func (flags ParticipantFlags) ToAPI() map[string]*api.FlagValue {
+	if flags == nil {
+		return nil
+	}
+	values := make(map[string]*api.FlagValue, len(flags))
+	for k, v := range flags {
+		values[k] = v.ToAPI()
+	}
+	return values
+}
+
+// ParticipantFlagsFromAPI uses the typed flags, string flags are used for keys without typed value
+func ParticipantFlagsFromAPI(typedFlags map[string]*api.FlagValue, stringFlags map[string]string) ParticipantFlags {
+	flags := make(ParticipantFlags, len(typedFlags)+len(stringFlags))
+	for k, v := range stringFlags {
+		flags[k] = StringFlag(v)
+	}
+	for k, v := range typedFlags {
+		flags[k] = FlagValueFromAPI(v)
+	}
+	return flags
+}
+
+// FlagInfo records when a flag was last set by UPDATE_FLAG, and when it expires
+type FlagInfo struct {
+	Key       string `bson:"key" json:"key"`
+	SetAt     int64  `bson:"setAt" json:"setAt"`
+	ExpiresAt int64  `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // 0 if the flag does not expire
+}
+
+func (f FlagInfo) ToAPI() *api.FlagInfo {
+	return &api.FlagInfo{
+		Key:       f.Key,
+		SetAt:     f.SetAt,
+		ExpiresAt: f.ExpiresAt,
+	}
+}
+
+func FlagInfoFromAPI(f *api.FlagInfo) FlagInfo {
+	if f == nil {
+		return FlagInfo{}
+	}
+	return FlagInfo{
+		Key:       f.Key,
+		SetAt:     f.SetAt,
+		ExpiresAt: f.ExpiresAt,
+	}
+}
+
+// FlagInfos are stored as a list, so that expiring flags can be found with an index on expiresAt.
+// Set and Remove return a new list, the list of the original state is not modified.
+type FlagInfos []FlagInfo
+
+// Get returns the info of the flag, flags set before flag infos were recorded have none
+func (infos FlagInfos) Get(key string) (FlagInfo, bool) {
+	for _, info := range infos {
+		if info.Key == key {
+			return info, true
+		}
+	}
+	return FlagInfo{}, false
+}
+
+func (infos FlagInfos) Set(info FlagInfo) FlagInfos {
+	return append(infos.Remove(info.Key), info)
+}
+
+func (infos FlagInfos) Remove(key string) FlagInfos {
+	res := make(FlagInfos, 0, len(infos)+1)
+	for _, info := range infos {
+		if info.Key != key {
+			res = append(res, info)
+		}
+	}
+	return res
+}
+
+// Expired returns the infos of flags expired at now, ordered by their expiry time
+func (infos FlagInfos) Expired(now int64) FlagInfos {
+	expired := FlagInfos{}
+	for _, info := range infos {
+		if info.ExpiresAt > 0 && info.ExpiresAt <= now {
+			expired = append(expired, info)
+		}
+	}
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].ExpiresAt < expired[j].ExpiresAt
+	})
+	return expired
+}
+
+func (infos FlagInfos) ToAPI() []*api.FlagInfo {
+	res := make([]*api.FlagInfo, len(infos))
+	for i, info := range infos {
+		res[i] = info.ToAPI()
+	}
+	return res
+}
+
+func FlagInfosFromAPI(infos []*api.FlagInfo) FlagInfos {
+	if len(infos) == 0 {
+		return nil
+	}
+	res := make(FlagInfos, len(infos))
+	for i, info := range infos {
+		res[i] = FlagInfoFromAPI(info)
+	}
+	return res
+}
@@ -0,0 +1,126 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func testFlags() ParticipantFlags {
+	return ParticipantFlags{
+		"str":  StringFlag("a"),
+		"num":  NumberFlag(2.5),
+		"bool": BoolFlag(true),
+		"ts":   TimestampFlag(1700000000),
+		"list": ListFlag(StringFlag("a"), NumberFlag(1), BoolFlag(false)),
+	}
+}
+
+func checkFlags(t *testing.T, expected ParticipantFlags, have ParticipantFlags) {
+	if len(expected) != len(have) {
+		t.Errorf("unexpected number of flags: %d, expected %d", len(have), len(expected))
+	}
+	for k, v := range expected {
+		if !v.Equal(have[k]) {
+			t.Errorf("unexpected value for %s: %v, expected %v", k, have[k], v)
+		}
+	}
+}
+
+func TestParticipantFlagsBSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		pState := ParticipantState{Flags: testFlags()}
+		data, err := bson.Marshal(pState)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		var decoded ParticipantState
+		if err := bson.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		checkFlags(t, pState.Flags, decoded.Flags)
+	})
+
+	t.Run("string flags of previous versions", func(t *testing.T) {
+		data, err := bson.Marshal(bson.M{"flags": bson.M{"a": "1", "b": "value"}})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		var decoded ParticipantState
+		if err := bson.Unmarshal(data, &decoded); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		checkFlags(t, ParticipantFlags{"a": StringFlag("1"), "b": StringFlag("value")}, decoded.Flags)
+	})
+
+	t.Run("nested list", func(t *testing.T) {
+		_, err := bson.Marshal(ParticipantState{Flags: ParticipantFlags{"l": ListFlag(ListFlag())}})
+		if err == nil {
+			t.Error("should return an error")
+		}
+	})
+}
+
+func TestParticipantFlagsJSON(t *testing.T) {
+	flags := testFlags()
+	data, err := json.Marshal(flags)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	var decoded ParticipantFlags
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	// timestamps are written as numbers
+	flags["ts"] = NumberFlag(1700000000)
+	checkFlags(t, flags, decoded)
+}
+
+func TestParticipantFlagsAPI(t *testing.T) {
+	pState := ParticipantState{Flags: testFlags()}
+	apiState := pState.ToAPI()
+	if apiState.Flags["num"] != "2.500000" || apiState.Flags["bool"] != "true" || apiState.Flags["list"] != "a,1.000000,false" {
+		t.Errorf("unexpected string flags: %v", apiState.Flags)
+	}
+	checkFlags(t, pState.Flags, ParticipantStateFromAPI(apiState).Flags)
+
+	t.Run("string flags only", func(t *testing.T) {
+		apiState.TypedFlags = nil
+		flags := ParticipantStateFromAPI(apiState).Flags
+		if !flags["num"].Equal(StringFlag("2.500000")) {
+			t.Errorf("unexpected value: %v", flags["num"])
+		}
+	})
+}
+
+func TestFlagInfos(t *testing.T) {
+	infos := FlagInfos{{Key: "a", SetAt: 1}, {Key: "b", SetAt: 2, ExpiresAt: 20}}
+
+	updated := infos.Set(FlagInfo{Key: "a", SetAt: 3, ExpiresAt: 10})
+	if info, ok := updated.Get("a"); !ok || info.SetAt != 3 || len(updated) != 2 {
+		t.Errorf("unexpected infos: %v", updated)
+	}
+	if info, _ := infos.Get("a"); info.SetAt != 1 {
+		t.Errorf("original infos should not be modified: %v", infos)
+	}
+
+	expired := updated.Expired(30)
+	if len(expired) != 2 || expired[0].Key != "a" || expired[1].Key != "b" {
+		t.Errorf("unexpected expired infos: %v", expired)
+	}
+	if expired := infos.Expired(30); len(expired) != 1 || expired[0].Key != "b" {
+		t.Errorf("flags without expiry should not expire: %v", expired)
+	}
+
+	removed := updated.Remove("b")
+	if _, ok := removed.Get("b"); ok || len(updated) != 2 {
+		t.Errorf("unexpected infos: %v, %v", removed, updated)
+	}
+}
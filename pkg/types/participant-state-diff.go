@@ -0,0 +1,198 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/influenzanet/study-service/pkg/api"
+)
+
+// ValueChange describes the old and new value of a participant state attribute
+type ValueChange struct {
+	Key      string `bson:"key,omitempty" json:"key"`
+	OldValue string `bson:"oldValue,omitempty" json:"oldValue"`
+	NewValue string `bson:"newValue,omitempty" json:"newValue"`
+}
+
+func (vc *ValueChange) ToAPI() *api.ParticipantStateDiff_ValueChange {
+	if vc == nil {
+		return nil
+	}
+	return &api.ParticipantStateDiff_ValueChange{
+		Key:      vc.Key,
+		OldValue: vc.OldValue,
+		NewValue: vc.NewValue,
+	}
+}
+
+// ParticipantStateDiff lists what changed between two versions of a participant state
+type ParticipantStateDiff struct {
+	ParticipantID          string               `bson:"participantID,omitempty" json:"participantID"`
+	StudyStatus            *ValueChange         `bson:"studyStatus,omitempty" json:"studyStatus,omitempty"`
+	CurrentStudySession    *ValueChange         `bson:"currentStudySession,omitempty" json:"currentStudySession,omitempty"`
+	AddedFlags             map[string]string    `bson:"addedFlags,omitempty" json:"addedFlags,omitempty"`
+	UpdatedFlags           []ValueChange        `bson:"updatedFlags,omitempty" json:"updatedFlags,omitempty"`
+	RemovedFlags           map[string]string    `bson:"removedFlags,omitempty" json:"removedFlags,omitempty"` // with the value before removal
+	AddedSurveys           []AssignedSurvey     `bson:"addedSurveys,omitempty" json:"addedSurveys,omitempty"`
+	RemovedSurveys         []AssignedSurvey     `bson:"removedSurveys,omitempty" json:"removedSurveys,omitempty"`
+	AddedMessages          []ParticipantMessage `bson:"addedMessages,omitempty" json:"addedMessages,omitempty"`
+	RemovedMessages        []ParticipantMessage `bson:"removedMessages,omitempty" json:"removedMessages,omitempty"`
+	AddedScheduledEvents   []ScheduledEvent     `bson:"addedScheduledEvents,omitempty" json:"addedScheduledEvents,omitempty"`
+	RemovedScheduledEvents []ScheduledEvent     `bson:"removedScheduledEvents,omitempty" json:"removedScheduledEvents,omitempty"`
+}
+
+// DiffParticipantStates compares study status, study session, flags, assigned surveys, messages and scheduled events of two participant states
+func DiffParticipantStates(oldState ParticipantState, newState ParticipantState) ParticipantStateDiff {
+	diff := ParticipantStateDiff{
+		ParticipantID: newState.ParticipantID,
+		AddedFlags:    map[string]string{},
+		UpdatedFlags:  []ValueChange{},
+		RemovedFlags:  map[string]string{},
+	}
+
+	if oldState.StudyStatus != newState.StudyStatus {
+		diff.StudyStatus = &ValueChange{Key: "studyStatus", OldValue: oldState.StudyStatus, NewValue: newState.StudyStatus}
+	}
+	if oldState.CurrentStudySession != newState.CurrentStudySession {
+		diff.CurrentStudySession = &ValueChange{Key: "currentStudySession", OldValue: oldState.CurrentStudySession, NewValue: newState.CurrentStudySession}
+	}
+
+	// flag values are compared with their type, and listed in the format of string flags
+	for key, newValue := range newState.Flags {
+		oldValue, exists := oldState.Flags[key]
+		if !exists {
+			diff.AddedFlags[key] = newValue.String()
+		} else if !oldValue.Equal(newValue) {
+			diff.UpdatedFlags = append(diff.UpdatedFlags, ValueChange{Key: key, OldValue: oldValue.String(), NewValue: newValue.String()})
+		}
+	}
+	sort.Slice(diff.UpdatedFlags, func(i, j int) bool {
+		return diff.UpdatedFlags[i].Key < diff.UpdatedFlags[j].Key
+	})
+	for key, oldValue := range oldState.Flags {
+		if _, exists := newState.Flags[key]; !exists {
+			diff.RemovedFlags[key] = oldValue.String()
+		}
+	}
+
+	for _, s := range newState.AssignedSurveys {
+		if !containsSurvey(oldState.AssignedSurveys, s) {
+			diff.AddedSurveys = append(diff.AddedSurveys, s)
+		}
+	}
+	for _, s := range oldState.AssignedSurveys {
+		if !containsSurvey(newState.AssignedSurveys, s) {
+			diff.RemovedSurveys = append(diff.RemovedSurveys, s)
+		}
+	}
+
+	for _, m := range newState.Messages {
+		if !containsMessage(oldState.Messages, m) {
+			diff.AddedMessages = append(diff.AddedMessages, m)
+		}
+	}
+	for _, m := range oldState.Messages {
+		if !containsMessage(newState.Messages, m) {
+			diff.RemovedMessages = append(diff.RemovedMessages, m)
+		}
+	}
+
+	for _, e := range newState.ScheduledEvents {
+		if !containsScheduledEvent(oldState.ScheduledEvents, e) {
+			diff.AddedScheduledEvents = append(diff.AddedScheduledEvents, e)
+		}
+	}
+	for _, e := range oldState.ScheduledEvents {
+		if !containsScheduledEvent(newState.ScheduledEvents, e) {
+			diff.RemovedScheduledEvents = append(diff.RemovedScheduledEvents, e)
+		}
+	}
+	return diff
+}
+
+func containsSurvey(list []AssignedSurvey, item AssignedSurvey) bool {
+	for _, e := range list {
+		if e == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMessage(list []ParticipantMessage, item ParticipantMessage) bool {
+	for _, e := range list {
+		if e == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsScheduledEvent(list []ScheduledEvent, item ScheduledEvent) bool {
+	for _, e := range list {
+		if e == item {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty is true if none of the compared attributes changed
+func (d ParticipantStateDiff) IsEmpty() bool {
+	return d.StudyStatus == nil &&
+		d.CurrentStudySession == nil &&
+		len(d.AddedFlags) == 0 &&
+		len(d.UpdatedFlags) == 0 &&
+		len(d.RemovedFlags) == 0 &&
+		len(d.AddedSurveys) == 0 &&
+		len(d.RemovedSurveys) == 0 &&
+		len(d.AddedMessages) == 0 &&
+		len(d.RemovedMessages) == 0 &&
+		len(d.AddedScheduledEvents) == 0 &&
+		len(d.RemovedScheduledEvents) == 0
+}
+
+func (d ParticipantStateDiff) ToAPI() *api.ParticipantStateDiff {
+	updatedFlags := make([]*api.ParticipantStateDiff_ValueChange, len(d.UpdatedFlags))
+	for i, f := range d.UpdatedFlags {
+		updatedFlags[i] = f.ToAPI()
+	}
+	addedSurveys := make([]*api.AssignedSurvey, len(d.AddedSurveys))
+	for i, s := range d.AddedSurveys {
+		addedSurveys[i] = s.ToAPI()
+	}
+	removedSurveys := make([]*api.AssignedSurvey, len(d.RemovedSurveys))
+	for i, s := range d.RemovedSurveys {
+		removedSurveys[i] = s.ToAPI()
+	}
+	addedMessages := make([]*api.ParticipantMessage, len(d.AddedMessages))
+	for i, m := range d.AddedMessages {
+		addedMessages[i] = m.ToAPI()
+	}
+	removedMessages := make([]*api.ParticipantMessage, len(d.RemovedMessages))
+	for i, m := range d.RemovedMessages {
+		removedMessages[i] = m.ToAPI()
+	}
+	addedScheduledEvents := make([]*api.ScheduledEvent, len(d.AddedScheduledEvents))
+	for i, e := range d.AddedScheduledEvents {
+		addedScheduledEvents[i] = e.ToAPI()
+	}
+	removedScheduledEvents := make([]*api.ScheduledEvent, len(d.RemovedScheduledEvents))
+	for i, e := range d.RemovedScheduledEvents {
+		removedScheduledEvents[i] = e.ToAPI()
+	}
+
+	return &api.ParticipantStateDiff{
+		ParticipantId:          d.ParticipantID,
+		StudyStatus:            d.StudyStatus.ToAPI(),
+		CurrentStudySession:    d.CurrentStudySession.ToAPI(),
+		AddedFlags:             d.AddedFlags,
+		UpdatedFlags:           updatedFlags,
+		RemovedFlags:           d.RemovedFlags,
+		AddedSurveys:           addedSurveys,
+		RemovedSurveys:         removedSurveys,
+		AddedMessages:          addedMessages,
+		RemovedMessages:        removedMessages,
+		AddedScheduledEvents:   addedScheduledEvents,
+		RemovedScheduledEvents: removedScheduledEvents,
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"errors"
+	"sort"
+
 	"github.com/influenzanet/study-service/pkg/api"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -19,10 +22,14 @@ type ParticipantState struct {
 	CurrentStudySession string               `bson:"currentStudySession" json:"currentStudySession"`
 	EnteredAt           int64                `bson:"enteredAt" json:"enteredAt"`
 	StudyStatus         string               `bson:"studyStatus" json:"studyStatus"` // shows if participant is active in the study - possible values: "active", "temporary", "exited". Other values are possible and are handled like "exited" on the server.
-	Flags               map[string]string    `bson:"flags" json:"flags"`
+	Flags               ParticipantFlags     `bson:"flags" json:"flags"`
+	FlagInfos           FlagInfos            `bson:"flagInfos,omitempty" json:"flagInfos,omitempty"` // set time and expiry of flags set by UPDATE_FLAG
 	AssignedSurveys     []AssignedSurvey     `bson:"assignedSurveys" json:"assignedSurveys"`
-	LastSubmissions     map[string]int64     `bson:"lastSubmission" json:"lastSubmission"` // surveyKey with timestamp
+	LastSubmissions     map[string]int64     `bson:"lastSubmission" json:"lastSubmission"`                           // surveyKey with timestamp
+	SubmissionHistory   SubmissionHistories  `bson:"submissionHistory,omitempty" json:"submissionHistory,omitempty"` // surveyKey with submission count and recent timestamps
 	Messages            []ParticipantMessage `bson:"messages" json:"messages"`
+	ScheduledEvents     []ScheduledEvent     `bson:"scheduledEvents,omitempty" json:"scheduledEvents,omitempty"` // named timer events, fired by the study timer when due
+	Version             int64                `bson:"version" json:"version"`                                     // incremented on each update, to detect concurrent modifications
 }
 
 type ParticipantMessage struct {
@@ -39,6 +46,165 @@ func (m ParticipantMessage) ToAPI() *api.ParticipantMessage {
 	}
 }
 
+func ParticipantMessageFromAPI(m *api.ParticipantMessage) ParticipantMessage {
+	if m == nil {
+		return ParticipantMessage{}
+	}
+	return ParticipantMessage{
+		ID:           m.Id,
+		Type:         m.Type,
+		ScheduledFor: m.ScheduledFor,
+	}
+}
+
+// SUBMISSION_HISTORY_SIZE is the number of recent submission timestamps kept per survey
+const SUBMISSION_HISTORY_SIZE = 20
+
+// ErrSubmissionCountUnknown is returned for counts including submissions that are not recorded in the submission history
+var ErrSubmissionCountUnknown = errors.New("submission count unknown: submissions not recorded in the submission history")
+
+// SubmissionHistory counts the submissions of a survey, and keeps the timestamps of the most recent ones (oldest first).
+// Histories started from the last submission of a survey submitted before submission histories were recorded are
+// incomplete: Count only includes the submissions since then.
+type SubmissionHistory struct {
+	Count      int64   `bson:"count" json:"count"`
+	Recent     []int64 `bson:"recent" json:"recent"`
+	Incomplete bool    `bson:"incomplete,omitempty" json:"incomplete,omitempty"`
+}
+
+// Add returns the history with a submission at ts, the recent timestamps of the original history are not modified
+func (h SubmissionHistory) Add(ts int64) SubmissionHistory {
+	start := 0
+	if len(h.Recent) >= SUBMISSION_HISTORY_SIZE {
+		start = len(h.Recent) - SUBMISSION_HISTORY_SIZE + 1
+	}
+	recent := make([]int64, 0, len(h.Recent)-start+1)
+	recent = append(recent, h.Recent[start:]...)
+	return SubmissionHistory{
+		Count:      h.Count + 1,
+		Recent:     append(recent, ts),
+		Incomplete: h.Incomplete,
+	}
+}
+
+// SubmissionHistoryFromTimestamps returns the complete history of the given submission times, e.g. of the stored responses
+func SubmissionHistoryFromTimestamps(timestamps []int64) SubmissionHistory {
+	sorted := append([]int64{}, timestamps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if len(sorted) > SUBMISSION_HISTORY_SIZE {
+		sorted = sorted[len(sorted)-SUBMISSION_HISTORY_SIZE:]
+	}
+	return SubmissionHistory{
+		Count:  int64(len(timestamps)),
+		Recent: sorted,
+	}
+}
+
+// TotalCount returns the number of submissions, ErrSubmissionCountUnknown if the history is incomplete
+func (h SubmissionHistory) TotalCount() (int64, error) {
+	if h.Incomplete {
+		return 0, ErrSubmissionCountUnknown
+	}
+	return h.Count, nil
+}
+
+// CountSince returns the number of submissions at or after since. Submissions missing in the recent timestamps are
+// not later than the oldest recent timestamp, so the count is only known if since is after it, or if no submission
+// is missing. Otherwise ErrSubmissionCountUnknown is returned.
+func (h SubmissionHistory) CountSince(since int64) (int64, error) {
+	missing := h.Incomplete || h.Count > int64(len(h.Recent))
+	if missing && (len(h.Recent) == 0 || since <= h.Recent[0]) {
+		return 0, ErrSubmissionCountUnknown
+	}
+	count := int64(0)
+	for _, ts := range h.Recent {
+		if ts >= since {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (h SubmissionHistory) ToAPI() *api.SubmissionHistory {
+	return &api.SubmissionHistory{
+		Count:      h.Count,
+		Recent:     h.Recent,
+		Incomplete: h.Incomplete,
+	}
+}
+
+func SubmissionHistoryFromAPI(h *api.SubmissionHistory) SubmissionHistory {
+	if h == nil {
+		return SubmissionHistory{}
+	}
+	return SubmissionHistory{
+		Count:      h.Count,
+		Recent:     h.Recent,
+		Incomplete: h.Incomplete,
+	}
+}
+
+// SubmissionHistories maps survey keys to their submission history
+type SubmissionHistories map[string]SubmissionHistory
+
+func (hs SubmissionHistories) ToAPI() map[string]*api.SubmissionHistory {
+	if hs == nil {
+		return nil
+	}
+	res := make(map[string]*api.SubmissionHistory, len(hs))
+	for k, h := range hs {
+		res[k] = h.ToAPI()
+	}
+	return res
+}
+
+func SubmissionHistoriesFromAPI(hs map[string]*api.SubmissionHistory) SubmissionHistories {
+	if len(hs) == 0 {
+		return nil
+	}
+	res := make(SubmissionHistories, len(hs))
+	for k, h := range hs {
+		res[k] = SubmissionHistoryFromAPI(h)
+	}
+	return res
+}
+
+// SubmissionHistoryFor returns the submission history of the survey recorded in the participant state. Surveys submitted
+// before submission histories were recorded have an incomplete history with their last submission, see studyengine for
+// the history counted from the stored responses.
+func (p ParticipantState) SubmissionHistoryFor(surveyKey string) SubmissionHistory {
+	if h, ok := p.SubmissionHistory[surveyKey]; ok {
+		return h
+	}
+	if ts, ok := p.LastSubmissions[surveyKey]; ok {
+		return SubmissionHistory{Count: 1, Recent: []int64{ts}, Incomplete: true}
+	}
+	return SubmissionHistory{}
+}
+
+// ScheduledEvent triggers the event TIMER:<Name> for the participant at DueAt
+type ScheduledEvent struct {
+	Name  string `bson:"name" json:"name"`
+	DueAt int64  `bson:"dueAt" json:"dueAt"`
+}
+
+func (e ScheduledEvent) ToAPI() *api.ScheduledEvent {
+	return &api.ScheduledEvent{
+		Name:  e.Name,
+		DueAt: e.DueAt,
+	}
+}
+
+func ScheduledEventFromAPI(e *api.ScheduledEvent) ScheduledEvent {
+	if e == nil {
+		return ScheduledEvent{}
+	}
+	return ScheduledEvent{
+		Name:  e.Name,
+		DueAt: e.DueAt,
+	}
+}
+
 func (p ParticipantState) ToAPI() *api.ParticipantState {
 	assignedSurveys := make([]*api.AssignedSurvey, len(p.AssignedSurveys))
 	for i, s := range p.AssignedSurveys {
@@ -48,6 +214,10 @@ func (p ParticipantState) ToAPI() *api.ParticipantState {
 	for i, s := range p.Messages {
 		messages[i] = s.ToAPI()
 	}
+	scheduledEvents := make([]*api.ScheduledEvent, len(p.ScheduledEvents))
+	for i, e := range p.ScheduledEvents {
+		scheduledEvents[i] = e.ToAPI()
+	}
 
 	return &api.ParticipantState{
 		Id:                  p.ID.Hex(),
@@ -55,9 +225,50 @@ func (p ParticipantState) ToAPI() *api.ParticipantState {
 		EnteredAt:           p.EnteredAt,
 		CurrentStudySession: p.CurrentStudySession,
 		StudyStatus:         p.StudyStatus,
-		Flags:               p.Flags,
+		Flags:               p.Flags.StringValues(),
+		TypedFlags:          p.Flags.ToAPI(),
+		FlagInfos:           p.FlagInfos.ToAPI(),
 		AssignedSurveys:     assignedSurveys,
 		LastSubmissions:     p.LastSubmissions,
+		SubmissionHistory:   p.SubmissionHistory.ToAPI(),
+		Messages:            messages,
+		ScheduledEvents:     scheduledEvents,
+	}
+}
+
+func ParticipantStateFromAPI(p *api.ParticipantState) ParticipantState {
+	if p == nil {
+		return ParticipantState{}
+	}
+	assignedSurveys := make([]AssignedSurvey, len(p.AssignedSurveys))
+	for i, s := range p.AssignedSurveys {
+		assignedSurveys[i] = AssignedSurveyFromAPI(s)
+	}
+	messages := make([]ParticipantMessage, len(p.Messages))
+	for i, m := range p.Messages {
+		messages[i] = ParticipantMessageFromAPI(m)
+	}
+	scheduledEvents := make([]ScheduledEvent, len(p.ScheduledEvents))
+	for i, e := range p.ScheduledEvents {
+		scheduledEvents[i] = ScheduledEventFromAPI(e)
+	}
+	lastSubmissions := p.LastSubmissions
+	if lastSubmissions == nil {
+		lastSubmissions = map[string]int64{}
+	}
+	_id, _ := primitive.ObjectIDFromHex(p.Id)
+	return ParticipantState{
+		ID:                  _id,
+		ParticipantID:       p.ParticipantId,
+		EnteredAt:           p.EnteredAt,
+		CurrentStudySession: p.CurrentStudySession,
+		StudyStatus:         p.StudyStatus,
+		Flags:               ParticipantFlagsFromAPI(p.TypedFlags, p.Flags),
+		FlagInfos:           FlagInfosFromAPI(p.FlagInfos),
+		AssignedSurveys:     assignedSurveys,
+		LastSubmissions:     lastSubmissions,
+		SubmissionHistory:   SubmissionHistoriesFromAPI(p.SubmissionHistory),
 		Messages:            messages,
+		ScheduledEvents:     scheduledEvents,
 	}
 }
@@ -0,0 +1,94 @@
+package types
+
+import "testing"
+
+func TestSubmissionHistory(t *testing.T) {
+	t.Run("add", func(t *testing.T) {
+		h := SubmissionHistory{}
+		for i := 1; i <= SUBMISSION_HISTORY_SIZE+5; i++ {
+			previous := h
+			h = h.Add(int64(i * 100))
+			if len(previous.Recent) > 0 && previous.Recent[len(previous.Recent)-1] != int64((i-1)*100) {
+				t.Errorf("previous history should not be modified: %v", previous.Recent)
+			}
+		}
+		if h.Count != SUBMISSION_HISTORY_SIZE+5 {
+			t.Errorf("unexpected count: %d", h.Count)
+		}
+		if len(h.Recent) != SUBMISSION_HISTORY_SIZE || h.Recent[0] != 600 || h.Recent[len(h.Recent)-1] != int64((SUBMISSION_HISTORY_SIZE+5)*100) {
+			t.Errorf("unexpected recent submissions: %v", h.Recent)
+		}
+	})
+
+	t.Run("from timestamps", func(t *testing.T) {
+		timestamps := []int64{}
+		for i := SUBMISSION_HISTORY_SIZE + 5; i >= 1; i-- {
+			timestamps = append(timestamps, int64(i*100))
+		}
+		h := SubmissionHistoryFromTimestamps(timestamps)
+		if h.Count != SUBMISSION_HISTORY_SIZE+5 || h.Incomplete {
+			t.Errorf("unexpected history: %v", h)
+		}
+		if len(h.Recent) != SUBMISSION_HISTORY_SIZE || h.Recent[0] != 600 || h.Recent[len(h.Recent)-1] != int64((SUBMISSION_HISTORY_SIZE+5)*100) {
+			t.Errorf("unexpected recent submissions: %v", h.Recent)
+		}
+		if timestamps[0] != int64((SUBMISSION_HISTORY_SIZE+5)*100) {
+			t.Error("timestamps should not be modified")
+		}
+		if c, err := h.TotalCount(); err != nil || c != SUBMISSION_HISTORY_SIZE+5 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+	})
+
+	t.Run("count since", func(t *testing.T) {
+		h := SubmissionHistory{Count: 5, Recent: []int64{100, 200, 300}}
+		if c, err := h.CountSince(200); err != nil || c != 2 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+		if c, err := h.CountSince(400); err != nil || c != 0 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+		if _, err := h.CountSince(100); err != ErrSubmissionCountUnknown {
+			t.Errorf("dropped submissions should make the count unknown: %v", err)
+		}
+		h = SubmissionHistory{Count: 3, Recent: []int64{100, 200, 300}}
+		if c, err := h.CountSince(50); err != nil || c != 3 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		h := SubmissionHistory{Count: 1, Recent: []int64{100}, Incomplete: true}.Add(200)
+		if !h.Incomplete || h.Count != 2 {
+			t.Errorf("unexpected history: %v", h)
+		}
+		if _, err := h.TotalCount(); err != ErrSubmissionCountUnknown {
+			t.Errorf("total count should be unknown: %v", err)
+		}
+		if _, err := h.CountSince(100); err != ErrSubmissionCountUnknown {
+			t.Errorf("count should be unknown: %v", err)
+		}
+		if c, err := h.CountSince(150); err != nil || c != 1 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+		if c, err := (SubmissionHistory{Count: 4, Recent: []int64{100}}).TotalCount(); err != nil || c != 4 {
+			t.Errorf("unexpected count: %d, %v", c, err)
+		}
+	})
+
+	t.Run("for survey", func(t *testing.T) {
+		pState := ParticipantState{
+			LastSubmissions:   map[string]int64{"weekly": 300, "intake": 100},
+			SubmissionHistory: SubmissionHistories{"weekly": {Count: 3, Recent: []int64{100, 200, 300}}},
+		}
+		if h := pState.SubmissionHistoryFor("weekly"); h.Count != 3 {
+			t.Errorf("unexpected history: %v", h)
+		}
+		if h := pState.SubmissionHistoryFor("intake"); h.Count != 1 || len(h.Recent) != 1 || h.Recent[0] != 100 || !h.Incomplete {
+			t.Errorf("unexpected history for survey submitted before histories were recorded: %v", h)
+		}
+		if h := pState.SubmissionHistoryFor("other"); h.Count != 0 || len(h.Recent) != 0 {
+			t.Errorf("unexpected history: %v", h)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package types
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+
+	"github.com/influenzanet/study-service/pkg/api"
+)
+
+// RandomisationScheme defines how participants are allocated to the arms of a trial. Allocation is done in blocks:
+// each block contains every arm ratio*(BlockSize/sum of ratios) times in a random order, separately for each stratum.
+type RandomisationScheme struct {
+	Key       string             `bson:"key"`
+	Arms      []RandomisationArm `bson:"arms"`
+	BlockSize int32              `bson:"blockSize"` // if 0, the sum of the ratios is used
+	ArmFlag   string             `bson:"armFlag"`   // flag the arm is written to, the scheme key if empty
+	Seed      string             `bson:"seed"`      // the order of the arms in a block only depends on seed, stratum and block index, never returned by the API
+}
+
+type RandomisationArm struct {
+	Key   string `bson:"key"`
+	Ratio int32  `bson:"ratio"`
+}
+
+// RandomisationBlock is the list of arms for the next participants of a stratum.
+type RandomisationBlock struct {
+	SchemeKey  string   `bson:"schemeKey"`
+	Stratum    string   `bson:"stratum"`
+	BlockIndex int64    `bson:"blockIndex"`
+	Arms       []string `bson:"arms"`
+	Size       int64    `bson:"size"`
+	Position   int64    `bson:"position"` // number of arms of the block already allocated
+}
+
+// RandomisationAllocation records which arm a participant was allocated to, and from which block position.
+type RandomisationAllocation struct {
+	SchemeKey     string `bson:"schemeKey"`
+	ParticipantID string `bson:"participantID"`
+	Stratum       string `bson:"stratum"`
+	Arm           string `bson:"arm"`
+	BlockIndex    int64  `bson:"blockIndex"`
+	Position      int64  `bson:"position"`
+	AllocatedAt   int64  `bson:"allocatedAt"`
+}
+
+type RandomisationAllocationCount struct {
+	Stratum string `bson:"stratum"`
+	Arm     string `bson:"arm"`
+	Count   int64  `bson:"count"`
+}
+
+// Validate checks that the scheme has arms with positive ratios and a block size that fits the ratios
+func (rs RandomisationScheme) Validate() error {
+	if rs.Key == "" {
+		return errors.New("scheme key is missing")
+	}
+	if len(rs.Arms) < 2 {
+		return errors.New("scheme must have at least two arms")
+	}
+	keys := map[string]bool{}
+	for _, arm := range rs.Arms {
+		if arm.Key == "" {
+			return errors.New("arm key is missing")
+		}
+		if keys[arm.Key] {
+			return fmt.Errorf("duplicate arm key '%s'", arm.Key)
+		}
+		keys[arm.Key] = true
+		if arm.Ratio < 1 {
+			return fmt.Errorf("ratio of arm '%s' must be positive", arm.Key)
+		}
+	}
+	if rs.BlockSize < 0 {
+		return errors.New("block size must not be negative")
+	}
+	if rs.BlockSize%rs.ratioSum() != 0 {
+		return fmt.Errorf("block size must be a multiple of the sum of the ratios (%d)", rs.ratioSum())
+	}
+	return nil
+}
+
+// NewRandomisationSeed generates a random seed for a scheme
+func NewRandomisationSeed() (string, error) {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// FlagKey returns the key of the participant flag the arm is written to
+func (rs RandomisationScheme) FlagKey() string {
+	if rs.ArmFlag != "" {
+		return rs.ArmFlag
+	}
+	return rs.Key
+}
+
+func (rs RandomisationScheme) ratioSum() int32 {
+	sum := int32(0)
+	for _, arm := range rs.Arms {
+		sum += arm.Ratio
+	}
+	return sum
+}
+
+// NewBlock creates the block blockIndex of a stratum. The same seed, stratum and index always result in the same block.
+func (rs RandomisationScheme) NewBlock(stratum string, blockIndex int64) RandomisationBlock {
+	blockSize := rs.BlockSize
+	if blockSize == 0 {
+		blockSize = rs.ratioSum()
+	}
+	repeat := int(blockSize / rs.ratioSum())
+
+	arms := make([]string, 0, blockSize)
+	for _, arm := range rs.Arms {
+		for i := 0; i < int(arm.Ratio)*repeat; i++ {
+			arms = append(arms, arm.Key)
+		}
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%s|%d", rs.Seed, rs.Key, stratum, blockIndex)
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	rng.Shuffle(len(arms), func(i, j int) { arms[i], arms[j] = arms[j], arms[i] })
+
+	return RandomisationBlock{
+		SchemeKey:  rs.Key,
+		Stratum:    stratum,
+		BlockIndex: blockIndex,
+		Arms:       arms,
+		Size:       int64(len(arms)),
+	}
+}
+
+// RandomisationStratum builds the stratum key from the flag keys and the participant's values, e.g. "age=65+|sex=f".
+// Missing flags are represented by an empty value.
+func RandomisationStratum(flagKeys []string, flags ParticipantFlags) string {
+	parts := make([]string, len(flagKeys))
+	for i, k := range flagKeys {
+		value := ""
+		if v, ok := flags[k]; ok {
+			value = v.String()
+		}
+		parts[i] = k + "=" + value
+	}
+	return strings.Join(parts, "|")
+}
+
+// ToAPI converts the scheme without the seed, since the seed allows to predict the allocations
+func (rs RandomisationScheme) ToAPI() *api.Study_RandomisationScheme {
+	arms := make([]*api.Study_RandomisationScheme_Arm, len(rs.Arms))
+	for i, arm := range rs.Arms {
+		arms[i] = &api.Study_RandomisationScheme_Arm{Key: arm.Key, Ratio: arm.Ratio}
+	}
+	return &api.Study_RandomisationScheme{
+		Key:       rs.Key,
+		Arms:      arms,
+		BlockSize: rs.BlockSize,
+		ArmFlag:   rs.ArmFlag,
+	}
+}
+
+func RandomisationSchemeFromAPI(rs *api.Study_RandomisationScheme) RandomisationScheme {
+	if rs == nil {
+		return RandomisationScheme{}
+	}
+	arms := make([]RandomisationArm, len(rs.Arms))
+	for i, arm := range rs.Arms {
+		arms[i] = RandomisationArm{Key: arm.Key, Ratio: arm.Ratio}
+	}
+	return RandomisationScheme{
+		Key:       rs.Key,
+		Arms:      arms,
+		BlockSize: rs.BlockSize,
+		ArmFlag:   rs.ArmFlag,
+		Seed:      rs.Seed,
+	}
+}
+
+func (c RandomisationAllocationCount) ToAPI() *api.RandomisationAllocationCounts_Count {
+	return &api.RandomisationAllocationCounts_Count{
+		Stratum: c.Stratum,
+		Arm:     c.Arm,
+		Count:   c.Count,
+	}
+}
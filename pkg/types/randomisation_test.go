@@ -0,0 +1,58 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestRandomisationScheme(t *testing.T) {
+	scheme := RandomisationScheme{
+		Key:       "trial",
+		Arms:      []RandomisationArm{{Key: "A", Ratio: 2}, {Key: "B", Ratio: 1}},
+		BlockSize: 6,
+		Seed:      "abc",
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		if err := scheme.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		invalid := scheme
+		invalid.BlockSize = 4
+		if err := invalid.Validate(); err == nil {
+			t.Error("block size not fitting the ratios should return an error")
+		}
+		invalid = scheme
+		invalid.Arms = []RandomisationArm{{Key: "A", Ratio: 1}, {Key: "A", Ratio: 1}}
+		if err := invalid.Validate(); err == nil {
+			t.Error("duplicate arms should return an error")
+		}
+	})
+
+	t.Run("block respects ratios and is reproducible", func(t *testing.T) {
+		block := scheme.NewBlock("sex=f", 3)
+		counts := map[string]int{}
+		for _, arm := range block.Arms {
+			counts[arm]++
+		}
+		if block.Size != 6 || counts["A"] != 4 || counts["B"] != 2 {
+			t.Errorf("unexpected block: %v", block)
+		}
+		again := scheme.NewBlock("sex=f", 3)
+		for i := range block.Arms {
+			if block.Arms[i] != again.Arms[i] {
+				t.Errorf("blocks differ: %v, %v", block.Arms, again.Arms)
+				break
+			}
+		}
+	})
+
+	t.Run("stratum", func(t *testing.T) {
+		flags := ParticipantFlags{"sex": StringFlag("f"), "age": NumberFlag(2)}
+		if s := RandomisationStratum([]string{"sex", "age", "region"}, flags); s != "sex=f|age=2.000000|region=" {
+			t.Errorf("unexpected stratum: %s", s)
+		}
+		if s := RandomisationStratum(nil, flags); s != "" {
+			t.Errorf("unexpected stratum: %s", s)
+		}
+	})
+}
@@ -1,5 +1,14 @@
 package types
 
+// TIMER_EVENT_PREFIX is followed by the name of the scheduled event in the type of named timer events, e.g. TIMER:followup_day7
+const TIMER_EVENT_PREFIX = "TIMER:"
+
+// CUSTOM_EVENT_PREFIX is followed by the key of the event in the type of events submitted by external systems, e.g. CUSTOM:swab_result_received
+const CUSTOM_EVENT_PREFIX = "CUSTOM:"
+
+// FLAG_EXPIRED_EVENT is the type of the event triggered by the study timer for each expired participant flag
+const FLAG_EXPIRED_EVENT = "FLAG_EXPIRED"
+
 type StudyEvent struct {
 	InstanceID                            string
 	StudyKey                              string
@@ -7,4 +16,7 @@ type StudyEvent struct {
 	Response                              SurveyResponse   // if something is submitted during the event is added here
 	MergeWithParticipant                  ParticipantState // if need to merge with other participant state, is added here
 	ParticipantIDForConfidentialResponses string
+	FlagKey                               string               // key of the expired flag in FLAG_EXPIRED events
+	Payload                               map[string]FlagValue // values submitted with CUSTOM events
+	UserID                                string               // researcher running the rules of CUSTOM_RULES events
 }
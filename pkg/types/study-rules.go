@@ -12,6 +12,7 @@ type StudyRules struct {
 	StudyKey        string             `bson:"studyKey" json:"studyKey"`
 	UploadedAt      int64              `bson:"uploadedAt" json:"uploadedAt"`
 	UploadedBy      string             `bson:"uploadedBy" json:"uploadedBy"`
+	ActiveFrom      int64              `bson:"activeFrom,omitempty" json:"activeFrom,omitempty"` // the version is used for events from this timestamp on, until a version with a later activeFrom becomes active
 	Rules           []Expression       `bson:"rules,omitempty" json:"rules"`
 	SerialisedRules string             `bson:"serialisedRules,omitempty" json:"serialisedRules,omitempty"`
 }
@@ -31,6 +32,7 @@ func (sr StudyRules) ToAPI() *api.StudyRules {
 		StudyKey:   sr.StudyKey,
 		UploadedAt: sr.UploadedAt,
 		UploadedBy: sr.UploadedBy,
+		ActiveFrom: sr.ActiveFrom,
 		Rules:      rules,
 	}
 }
@@ -45,14 +45,54 @@ type StudyProps struct {
 }
 
 type StudyConfigs struct {
-	ParticipantFileUploadRule *Expression `bson:"participantFileUploadRule"`
-	IdMappingMethod           string      `bson:"idMappingMethod"`
+	ParticipantFileUploadRule *Expression           `bson:"participantFileUploadRule"`
+	IdMappingMethod           string                `bson:"idMappingMethod"`
+	TimerSchedule             *TimerSchedule        `bson:"timerSchedule,omitempty"` // if not defined, the service's default timer frequency is used
+	RandomisationSchemes      []RandomisationScheme `bson:"randomisationSchemes,omitempty"`
+}
+
+// GetRandomisationScheme finds the randomisation scheme with the key
+func (s StudyConfigs) GetRandomisationScheme(key string) (RandomisationScheme, bool) {
+	for _, rs := range s.RandomisationSchemes {
+		if rs.Key == key {
+			return rs, true
+		}
+	}
+	return RandomisationScheme{}, false
 }
 
 type StudyStats struct {
-	ParticipantCount     int64 `bson:"participantCount"`
-	TempParticipantCount int64 `bson:"tempParticipantCount"`
-	ResponseCount        int64 `bson:"responseCount"`
+	ParticipantCount     int64            `bson:"participantCount"`
+	TempParticipantCount int64            `bson:"tempParticipantCount"`
+	ResponseCount        int64            `bson:"responseCount"`
+	ParticipantCounts    map[string]int64 `bson:"participantCounts,omitempty"` // by ParticipantCondition.CounterKey
+
+	ParticipantCountsUpdatedAt int64 `bson:"participantCountsUpdatedAt,omitempty"`
+}
+
+// Get returns the value of a stat by its name (participantCount, tempParticipantCount or responseCount)
+func (t StudyStats) Get(name string) (int64, bool) {
+	switch name {
+	case "participantCount":
+		return t.ParticipantCount, true
+	case "tempParticipantCount":
+		return t.TempParticipantCount, true
+	case "responseCount":
+		return t.ResponseCount, true
+	}
+	return 0, false
+}
+
+// ParticipantCondition selects participants by study status and flag value, for cached participant counts
+type ParticipantCondition struct {
+	StudyStatus string
+	FlagKey     string
+	FlagValue   string
+}
+
+// CounterKey is the key of the condition's count in StudyStats.ParticipantCounts
+func (c ParticipantCondition) CounterKey() string {
+	return c.StudyStatus + ":" + c.FlagKey + "=" + c.FlagValue
 }
 
 type Tag struct {
@@ -75,22 +115,29 @@ func (s Study) ToAPI() *api.Study {
 	}
 
 	return &api.Study{
-		Id:        s.ID.Hex(),
-		Key:       s.Key,
-		SecretKey: s.SecretKey,
-		Status:    s.Status,
-		Members:   members,
-		Rules:     rules,
-		Props:     s.Props.ToAPI(),
-		Stats:     s.Stats.ToAPI(),
-		Configs:   s.Configs.ToAPI(),
+		Id:             s.ID.Hex(),
+		Key:            s.Key,
+		SecretKey:      s.SecretKey,
+		Status:         s.Status,
+		Members:        members,
+		Rules:          rules,
+		Props:          s.Props.ToAPI(),
+		Stats:          s.Stats.ToAPI(),
+		Configs:        s.Configs.ToAPI(),
+		NextTimerEvent: s.NextTimerEvent,
 	}
 }
 
 func (s StudyConfigs) ToAPI() *api.Study_Configs {
+	schemes := make([]*api.Study_RandomisationScheme, len(s.RandomisationSchemes))
+	for i, rs := range s.RandomisationSchemes {
+		schemes[i] = rs.ToAPI()
+	}
 	return &api.Study_Configs{
 		ParticipantFileUploadRule: s.ParticipantFileUploadRule.ToAPI(),
 		IdMappingMethod:           s.IdMappingMethod,
+		TimerSchedule:             s.TimerSchedule.ToAPI(),
+		RandomisationSchemes:      schemes,
 	}
 }
 
@@ -98,9 +145,15 @@ func StudyConfigsFromAPI(s *api.Study_Configs) StudyConfigs {
 	if s == nil {
 		return StudyConfigs{}
 	}
+	var schemes []RandomisationScheme
+	for _, rs := range s.RandomisationSchemes {
+		schemes = append(schemes, RandomisationSchemeFromAPI(rs))
+	}
 	return StudyConfigs{
 		ParticipantFileUploadRule: ExpressionFromAPI(s.ParticipantFileUploadRule),
 		IdMappingMethod:           s.IdMappingMethod,
+		TimerSchedule:             TimerScheduleFromAPI(s.TimerSchedule),
+		RandomisationSchemes:      schemes,
 	}
 }
 
@@ -229,6 +282,7 @@ func StudyStatsFromAPI(t *api.Study_Stats) StudyStats {
 		ParticipantCount:     t.ParticipantCount,
 		TempParticipantCount: t.TempParticipantCount,
 		ResponseCount:        t.ResponseCount,
+		ParticipantCounts:    t.ParticipantCounts,
 	}
 }
 
@@ -237,6 +291,7 @@ func (t StudyStats) ToAPI() *api.Study_Stats {
 		ParticipantCount:     t.ParticipantCount,
 		TempParticipantCount: t.TempParticipantCount,
 		ResponseCount:        t.ResponseCount,
+		ParticipantCounts:    t.ParticipantCounts,
 	}
 }
 
@@ -0,0 +1,19 @@
+package types
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// TimerLease is held by one service replica while it performs the timer event of a study
+type TimerLease struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	StudyKey           string             `bson:"studyKey" json:"studyKey"`
+	Owner              string             `bson:"owner" json:"owner"`                     // ID of the replica holding the lease
+	ExpiresAt          int64              `bson:"expiresAt" json:"expiresAt"`             // other replicas can take over the lease after this time
+	RenewedAt          int64              `bson:"renewedAt" json:"renewedAt"`             // last heartbeat of the owner
+	Checkpoint         primitive.ObjectID `bson:"checkpoint,omitempty" json:"checkpoint"` // _id of the last processed participant of an unfinished run
+	LastRunCompletedAt int64              `bson:"lastRunCompletedAt,omitempty" json:"lastRunCompletedAt,omitempty"`
+}
+
+// HasCheckpoint is true if a previous run was interrupted and should be resumed
+func (l TimerLease) HasCheckpoint() bool {
+	return !l.Checkpoint.IsZero()
+}
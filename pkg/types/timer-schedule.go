@@ -0,0 +1,126 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influenzanet/study-service/pkg/api"
+)
+
+// TimerSchedule defines when the timer event of a study is performed: every Interval seconds, but only within
+// the time-of-day window [WindowStart, WindowEnd) in Timezone. A window with WindowStart after WindowEnd spans midnight.
+type TimerSchedule struct {
+	Interval    int64  `bson:"interval"`    // seconds between two runs, if 0 the service's default frequency is used
+	WindowStart string `bson:"windowStart"` // HH:MM, empty for no restriction
+	WindowEnd   string `bson:"windowEnd"`   // HH:MM, empty for no restriction
+	Timezone    string `bson:"timezone"`    // IANA time zone name for the window, UTC if empty
+}
+
+// Validate checks that the interval, window and time zone can be used
+func (ts TimerSchedule) Validate() error {
+	if ts.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if (ts.WindowStart == "") != (ts.WindowEnd == "") {
+		return errors.New("window start and end must be both defined or both empty")
+	}
+	if ts.WindowStart != "" {
+		if _, err := parseTimeOfDay(ts.WindowStart); err != nil {
+			return err
+		}
+		if _, err := parseTimeOfDay(ts.WindowEnd); err != nil {
+			return err
+		}
+	}
+	if _, err := time.LoadLocation(ts.Timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsInWindow checks if t is inside the time-of-day window of the schedule. Always true if no schedule or window is defined.
+func (ts *TimerSchedule) IsInWindow(t time.Time) bool {
+	if ts == nil || ts.WindowStart == "" || ts.WindowEnd == "" {
+		return true
+	}
+	start, errStart := parseTimeOfDay(ts.WindowStart)
+	end, errEnd := parseTimeOfDay(ts.WindowEnd)
+	if errStart != nil || errEnd != nil || start == end {
+		return true
+	}
+
+	lt := t.In(ts.location())
+	minutes := lt.Hour()*60 + lt.Minute()
+	if start < end {
+		return minutes >= start && minutes < end
+	}
+	return minutes >= start || minutes < end
+}
+
+// NextRunAfter returns the earliest time of the next run, if a run was performed at t.
+// defaultInterval (seconds) is used if the schedule has no interval.
+func (ts *TimerSchedule) NextRunAfter(t time.Time, defaultInterval int64) time.Time {
+	interval := defaultInterval
+	if ts != nil && ts.Interval > 0 {
+		interval = ts.Interval
+	}
+	next := t.Add(time.Duration(interval) * time.Second)
+	if ts.IsInWindow(next) {
+		return next
+	}
+
+	// move to the next start of the window
+	start, _ := parseTimeOfDay(ts.WindowStart)
+	loc := ts.location()
+	lt := next.In(loc)
+	windowStart := time.Date(lt.Year(), lt.Month(), lt.Day(), start/60, start%60, 0, 0, loc)
+	if !windowStart.After(lt) {
+		windowStart = windowStart.AddDate(0, 0, 1)
+	}
+	return windowStart
+}
+
+func (ts *TimerSchedule) location() *time.Location {
+	loc, err := time.LoadLocation(ts.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseTimeOfDay converts HH:MM into minutes since midnight
+func parseTimeOfDay(value string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time of day '%s', expected HH:MM", value)
+	}
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time of day '%s', expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+func (ts *TimerSchedule) ToAPI() *api.Study_TimerSchedule {
+	if ts == nil {
+		return nil
+	}
+	return &api.Study_TimerSchedule{
+		Interval:    ts.Interval,
+		WindowStart: ts.WindowStart,
+		WindowEnd:   ts.WindowEnd,
+		Timezone:    ts.Timezone,
+	}
+}
+
+func TimerScheduleFromAPI(ts *api.Study_TimerSchedule) *TimerSchedule {
+	if ts == nil {
+		return nil
+	}
+	return &TimerSchedule{
+		Interval:    ts.Interval,
+		WindowStart: ts.WindowStart,
+		WindowEnd:   ts.WindowEnd,
+		Timezone:    ts.Timezone,
+	}
+}
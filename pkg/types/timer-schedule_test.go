@@ -0,0 +1,82 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerScheduleValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		schedule TimerSchedule
+		valid    bool
+	}{
+		{name: "empty", schedule: TimerSchedule{}, valid: true},
+		{name: "interval only", schedule: TimerSchedule{Interval: 3600}, valid: true},
+		{name: "with window", schedule: TimerSchedule{WindowStart: "22:00", WindowEnd: "02:30", Timezone: "Europe/Berlin"}, valid: true},
+		{name: "negative interval", schedule: TimerSchedule{Interval: -1}, valid: false},
+		{name: "missing window end", schedule: TimerSchedule{WindowStart: "22:00"}, valid: false},
+		{name: "invalid time of day", schedule: TimerSchedule{WindowStart: "25:00", WindowEnd: "02:00"}, valid: false},
+		{name: "unknown time zone", schedule: TimerSchedule{Timezone: "Mars/Olympus"}, valid: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("should return an error")
+			}
+		})
+	}
+}
+
+func TestTimerScheduleNextRun(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("without schedule", func(t *testing.T) {
+		var schedule *TimerSchedule
+		if !schedule.IsInWindow(now) {
+			t.Error("should be in window")
+		}
+		next := schedule.NextRunAfter(now, 60)
+		if !next.Equal(now.Add(time.Minute)) {
+			t.Errorf("unexpected next run: %v", next)
+		}
+	})
+
+	t.Run("interval inside window", func(t *testing.T) {
+		schedule := &TimerSchedule{Interval: 3600, WindowStart: "08:00", WindowEnd: "20:00"}
+		next := schedule.NextRunAfter(now, 60)
+		if !next.Equal(now.Add(time.Hour)) {
+			t.Errorf("unexpected next run: %v", next)
+		}
+	})
+
+	t.Run("next run moved to window start", func(t *testing.T) {
+		schedule := &TimerSchedule{Interval: 3600, WindowStart: "02:00", WindowEnd: "04:00", Timezone: "Europe/Berlin"}
+		if schedule.IsInWindow(now) {
+			t.Error("should not be in window")
+		}
+		next := schedule.NextRunAfter(now, 60)
+		expected := time.Date(2024, 3, 11, 1, 0, 0, 0, time.UTC) // 02:00 CET
+		if !next.Equal(expected) {
+			t.Errorf("unexpected next run: %v", next)
+		}
+	})
+
+	t.Run("window spanning midnight", func(t *testing.T) {
+		schedule := &TimerSchedule{WindowStart: "22:00", WindowEnd: "02:00"}
+		if !schedule.IsInWindow(time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)) {
+			t.Error("should be in window")
+		}
+		if !schedule.IsInWindow(time.Date(2024, 3, 11, 1, 30, 0, 0, time.UTC)) {
+			t.Error("should be in window")
+		}
+		next := schedule.NextRunAfter(time.Date(2024, 3, 11, 1, 30, 0, 0, time.UTC), 3600)
+		expected := time.Date(2024, 3, 11, 22, 0, 0, 0, time.UTC)
+		if !next.Equal(expected) {
+			t.Errorf("unexpected next run: %v", next)
+		}
+	})
+}
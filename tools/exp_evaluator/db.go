@@ -1,6 +1,7 @@
 package main
 
 import(
+	"errors"
 	"github.com/influenzanet/study-service/pkg/dbs/studydb"
 	"github.com/influenzanet/study-service/pkg/types"
 	"sort"
@@ -64,3 +65,23 @@ func (m MemoryDBService) DeleteConfidentialResponses(instanceID string, studyKey
 func (m MemoryDBService) SaveResearcherMessage(instanceID string, studyKey string, message types.StudyMessage) error {
 	return nil
 }
+
+func (m MemoryDBService) GetRandomisationScheme(instanceID string, studyKey string, schemeKey string) (types.RandomisationScheme, error) {
+	return types.RandomisationScheme{}, errors.New("randomisation is not supported by the evaluator")
+}
+
+func (m MemoryDBService) FindRandomisationAllocation(instanceID string, studyKey string, schemeKey string, participantID string) (types.RandomisationAllocation, error) {
+	return types.RandomisationAllocation{}, errors.New("randomisation is not supported by the evaluator")
+}
+
+func (m MemoryDBService) AllocateRandomisationArm(instanceID string, studyKey string, scheme types.RandomisationScheme, stratum string, participantID string, allocatedAt int64) (types.RandomisationAllocation, error) {
+	return types.RandomisationAllocation{}, errors.New("randomisation is not supported by the evaluator")
+}
+
+func (m MemoryDBService) GetStudyStats(instanceID string, studyKey string) (types.StudyStats, error) {
+	return types.StudyStats{}, errors.New("study stats are not supported by the evaluator")
+}
+
+func (m MemoryDBService) CountParticipantsWithCondition(instanceID string, studyKey string, condition types.ParticipantCondition) (int64, error) {
+	return 0, errors.New("participant counts are not supported by the evaluator")
+}
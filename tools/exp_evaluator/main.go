@@ -15,12 +15,14 @@ type ImportStruct struct {
 	State types.ParticipantState`json:"state"`
 	Rules []types.Expression `json:"rules"`
 	Data []types.SurveyResponse `json:"responses"`	
+	Trace bool `json:"trace"` // record evaluation steps for each rule
 }
 
 type EvalResult struct {
 	Index int `json:"index"`
 	Data studyengine.ActionData`json:"result"`
 	Error string `json:"error"`
+	Trace []studyengine.TraceEntry `json:"trace,omitempty"`
 }
 
 func Evaluate(input ImportStruct) []EvalResult {
@@ -43,15 +45,23 @@ func Evaluate(input ImportStruct) []EvalResult {
 	results := make([]EvalResult, 0)
 
 	for index, rule := range input.Rules {
+		var tracer *studyengine.Tracer
+		if(input.Trace) {
+			tracer = studyengine.NewTracer()
+		}
 		newState, err := studyengine.ActionEval(rule, actionData, event, studyengine.ActionConfigs{
 			DBService:            dbService ,
 			ExternalServiceConfigs: nil,
+			Tracer: tracer,
 		})
 
 		r := EvalResult{
 			Index: index,
 			Data: newState,
 		}
+		if(tracer != nil) {
+			r.Trace = tracer.Entries
+		}
 		if(err != nil) {
 			r.Error =  fmt.Sprintf("%s", err)
 		}
@@ -113,6 +123,7 @@ type Options struct {
 	Input string
 	Output string
 	server int
+	Trace bool
 }
 
 
@@ -120,6 +131,7 @@ func handleFlags() Options {
 	inputJSON := flag.String("input", "", "path and name of the input file that should be converted")
 	outputJSON := flag.String("output", "", "path and name of the input file that should be converted")
 	server := flag.Int("server", 0, "Use server port (0 = disable)")
+	trace := flag.Bool("trace", false, "record evaluation steps of each rule in the output (server: use 'trace' attribute of the input)")
 	flag.Parse()
 	
 	inputFile := *inputJSON
@@ -139,6 +151,7 @@ func handleFlags() Options {
 		Input: inputFile,
 		Output: outputFile,
 		server:serverPort,
+		Trace: *trace,
 	}
 }
 
@@ -154,6 +167,9 @@ func main() {
 		fmt.Println("Using files")
 		
 		input := readImportStructFromJSON(opts.Input)
+		if(opts.Trace) {
+			input.Trace = true
+		}
 		r := Evaluate(input)
 		saveResultJSON(r, opts.Output)
 	}